@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	announcementService "github.com/ladmakhi81/learnup/internals/announcement/service"
+	announcementWorkflow "github.com/ladmakhi81/learnup/internals/announcement/workflow"
+	"github.com/ladmakhi81/learnup/internals/assignment"
+	assignmentService "github.com/ladmakhi81/learnup/internals/assignment/service"
 	"github.com/ladmakhi81/learnup/internals/auth"
 	authService "github.com/ladmakhi81/learnup/internals/auth/service"
 	"github.com/ladmakhi81/learnup/internals/cart"
@@ -16,14 +21,22 @@ import (
 	courseService "github.com/ladmakhi81/learnup/internals/course/service"
 	forumService "github.com/ladmakhi81/learnup/internals/forum/service"
 	likeService "github.com/ladmakhi81/learnup/internals/like/service"
+	"github.com/ladmakhi81/learnup/internals/note"
+	noteService "github.com/ladmakhi81/learnup/internals/note/service"
 	"github.com/ladmakhi81/learnup/internals/notification"
 	notificationService "github.com/ladmakhi81/learnup/internals/notification/service"
 	"github.com/ladmakhi81/learnup/internals/order"
 	orderService "github.com/ladmakhi81/learnup/internals/order/service"
 	"github.com/ladmakhi81/learnup/internals/payment"
 	paymentService "github.com/ladmakhi81/learnup/internals/payment/service"
+	publishService "github.com/ladmakhi81/learnup/internals/publish/service"
+	publishWorkflow "github.com/ladmakhi81/learnup/internals/publish/workflow"
 	"github.com/ladmakhi81/learnup/internals/question"
 	questionService "github.com/ladmakhi81/learnup/internals/question/service"
+	"github.com/ladmakhi81/learnup/internals/quiz"
+	quizService "github.com/ladmakhi81/learnup/internals/quiz/service"
+	"github.com/ladmakhi81/learnup/internals/resource"
+	resourceService "github.com/ladmakhi81/learnup/internals/resource/service"
 	"github.com/ladmakhi81/learnup/internals/teacher"
 	teacherService "github.com/ladmakhi81/learnup/internals/teacher/service"
 	"github.com/ladmakhi81/learnup/internals/transaction"
@@ -33,10 +46,11 @@ import (
 	"github.com/ladmakhi81/learnup/internals/user"
 	userService "github.com/ladmakhi81/learnup/internals/user/service"
 	"github.com/ladmakhi81/learnup/internals/video"
+	videoDtoReq "github.com/ladmakhi81/learnup/internals/video/dto/req"
 	videoService "github.com/ladmakhi81/learnup/internals/video/service"
 	"github.com/ladmakhi81/learnup/internals/video/workflow"
 	"github.com/ladmakhi81/learnup/internals/websocket"
-	"github.com/ladmakhi81/learnup/pkg/ffmpeg/v1"
+	"github.com/ladmakhi81/learnup/pkg/fpdf/v0"
 	"github.com/ladmakhi81/learnup/pkg/i18n/v2"
 	"github.com/ladmakhi81/learnup/pkg/jwt/v5"
 	"github.com/ladmakhi81/learnup/pkg/koanf"
@@ -44,10 +58,13 @@ import (
 	"github.com/ladmakhi81/learnup/pkg/minio/v7"
 	"github.com/ladmakhi81/learnup/pkg/redis/v6"
 	restyv2 "github.com/ladmakhi81/learnup/pkg/resty/v2"
+	"github.com/ladmakhi81/learnup/pkg/smtp"
 	stripev82 "github.com/ladmakhi81/learnup/pkg/stripe/v82"
 	"github.com/ladmakhi81/learnup/pkg/temporal"
 	"github.com/ladmakhi81/learnup/pkg/temporal/v1"
+	"github.com/ladmakhi81/learnup/pkg/transcoder"
 	"github.com/ladmakhi81/learnup/pkg/validator/v10"
+	"github.com/ladmakhi81/learnup/pkg/virusscan"
 	zarinpalv1 "github.com/ladmakhi81/learnup/pkg/zarinpal/v1"
 	zibalv1 "github.com/ladmakhi81/learnup/pkg/zibal/v1"
 	"github.com/ladmakhi81/learnup/shared/db"
@@ -109,6 +126,8 @@ func main() {
 		log.Fatalln(i18nErr)
 	}
 	redisSvc := redisv6.NewRedisClientSvc(config)
+	mailSvc := smtp.NewSmtpMailSvc(config)
+	pdfSvc := fpdfv0.NewFpdfSvc(config)
 	tokenSvc := jwtv5.NewJwtSvc(config, redisSvc)
 	userSvc := userService.NewUserSvc(unitOfWork)
 	notificationSvc := notificationService.NewNotificationSvc(unitOfWork)
@@ -116,20 +135,48 @@ func main() {
 	authSvc := authService.NewAuthSvc(redisSvc, tokenSvc, unitOfWork)
 	categorySvc := categoryService.NewCategorySvc(unitOfWork)
 	courseSvc := courseService.NewCourseSvc(unitOfWork)
+	courseVersionSvc := courseService.NewCourseVersionSvc(unitOfWork)
 	forumSvc := forumService.NewForumService(unitOfWork)
-	ffmpegSvc := ffmpegv1.NewFfmpegSvc()
-	videoSvc := videoService.NewVideoSvc(unitOfWork, minioSvc, ffmpegSvc, logrusSvc)
+	transcoderSvc, transcoderSvcErr := transcoder.NewTranscoderSvc(config)
+	if transcoderSvcErr != nil {
+		log.Fatalln(transcoderSvcErr)
+	}
+	videoSvc := videoService.NewVideoSvc(unitOfWork, minioSvc, transcoderSvc, logrusSvc, temporalSvc, config)
+	playbackSvc := videoService.NewPlaybackSvc(unitOfWork, minioSvc, redisSvc, config)
+	watchProgressSvc := videoService.NewWatchProgressSvc(unitOfWork, redisSvc)
+	watchProgressWorkflowSvc := workflow.NewWatchProgressWorkflowImpl(watchProgressSvc, temporalSvc)
 	videoWorkflowSvc := workflow.NewVideoWorkflowImpl(videoSvc, temporalSvc, courseSvc)
-	tusHookSvc := tusHookService.NewTusServiceImpl(videoSvc, logrusSvc, temporalSvc, videoWorkflowSvc)
+	videoProgressWatcher := workflow.NewProgressWatcher(unitOfWork, temporalSvc, wsManager, logrusSvc)
+	tusHookSvc := tusHookService.NewTusServiceImpl(videoSvc, logrusSvc, temporalSvc, videoWorkflowSvc, unitOfWork, tokenSvc, config)
+	publishSvc := publishService.NewPublishSvc(unitOfWork)
+	publishWorkflowSvc := publishWorkflow.NewPublishWorkflowImpl(publishSvc, temporalSvc)
 	teacherCourseSvc := teacherService.NewTeacherCourseService(unitOfWork)
-	teacherVideoSvc := teacherService.NewTeacherVideoSvc(unitOfWork)
+	teacherVideoSvc := teacherService.NewTeacherVideoSvc(unitOfWork, temporalSvc, videoWorkflowSvc, minioSvc)
 	teacherCommentSvc := teacherService.NewTeacherCommentSvc(unitOfWork)
 	commentSvc := commentService.NewCommentSvc(unitOfWork)
+	noteSvc := noteService.NewNoteSvc(unitOfWork, pdfSvc)
 	likeSvc := likeService.NewLikeSvc(unitOfWork)
 	cartSvc := cartService.NewCartSvc(unitOfWork)
 	questionSvc := questionService.NewQuestionSvc(unitOfWork)
 	questionAnswerSvc := questionService.NewQuestionAnswerSvc(unitOfWork)
 	teacherQuestionSvc := teacherService.NewTeacherQuestionSvc(unitOfWork)
+	teacherScheduleSvc := teacherService.NewTeacherScheduleSvc(unitOfWork, temporalSvc, publishWorkflowSvc)
+	teacherAnalyticsSvc := teacherService.NewTeacherAnalyticsSvc(unitOfWork)
+	announcementSvc := announcementService.NewAnnouncementSvc(unitOfWork, mailSvc, logrusSvc)
+	announcementWorkflowSvc := announcementWorkflow.NewAnnouncementWorkflowImpl(announcementSvc, temporalSvc)
+	teacherAnnouncementSvc := teacherService.NewTeacherAnnouncementSvc(unitOfWork, temporalSvc, announcementWorkflowSvc)
+	teacherParticipantSvc := teacherService.NewTeacherParticipantSvc(unitOfWork)
+	adminParticipantSvc := teacherService.NewAdminParticipantSvc(unitOfWork)
+	virusScannerSvc, virusScannerSvcErr := virusscan.NewVirusScannerSvc(config)
+	if virusScannerSvcErr != nil {
+		log.Fatalln(virusScannerSvcErr)
+	}
+	teacherResourceSvc := teacherService.NewTeacherResourceSvc(unitOfWork, minioSvc, virusScannerSvc, config)
+	resourceSvc := resourceService.NewResourceSvc(unitOfWork, minioSvc, config)
+	teacherQuizSvc := teacherService.NewTeacherQuizSvc(unitOfWork)
+	quizSvc := quizService.NewQuizSvc(unitOfWork)
+	teacherAssignmentSvc := teacherService.NewTeacherAssignmentSvc(unitOfWork)
+	assignmentSvc := assignmentService.NewAssignmentSvc(unitOfWork, minioSvc, virusScannerSvc, config)
 	restyHttpClient := restyv2.NewRestyHttpSvc()
 	zarinpalSvc := zarinpalv1.NewZarinpalClient(restyHttpClient, config)
 	zibalSvc := zibalv1.NewZibalClient(restyHttpClient, config)
@@ -148,12 +195,16 @@ func main() {
 	userModule := user.NewModule(middlewares, i18nTranslatorSvc, userSvc, validationSvc)
 	authModule := auth.NewModule(authSvc, validationSvc, i18nTranslatorSvc)
 	categoryModule := category.NewModule(categorySvc, middlewares, i18nTranslatorSvc, validationSvc)
-	courseModule := course.NewModule(courseSvc, validationSvc, videoSvc, likeSvc, commentSvc, questionSvc, userSvc, forumSvc, middlewares, i18nTranslatorSvc)
-	tusModule := tus.NewModule(tusHookSvc, i18nTranslatorSvc)
-	videoModule := video.NewModule(userSvc, videoSvc, validationSvc, middlewares, i18nTranslatorSvc)
+	courseModule := course.NewModule(courseSvc, validationSvc, videoSvc, likeSvc, commentSvc, questionSvc, userSvc, forumSvc, courseVersionSvc, watchProgressSvc, adminParticipantSvc, middlewares, i18nTranslatorSvc)
+	tusModule := tus.NewModule(tusHookSvc, i18nTranslatorSvc, config)
+	videoModule := video.NewModule(userSvc, videoSvc, playbackSvc, watchProgressSvc, validationSvc, middlewares, i18nTranslatorSvc)
 	notificationModule := notification.NewModule(notificationSvc, middlewares, i18nTranslatorSvc)
-	teacherModule := teacher.NewModule(teacherCourseSvc, teacherVideoSvc, teacherCommentSvc, teacherQuestionSvc, validationSvc, userSvc, middlewares, i18nTranslatorSvc)
+	teacherModule := teacher.NewModule(teacherCourseSvc, teacherVideoSvc, teacherCommentSvc, teacherQuestionSvc, teacherScheduleSvc, teacherAnalyticsSvc, teacherAnnouncementSvc, teacherParticipantSvc, teacherResourceSvc, teacherQuizSvc, teacherAssignmentSvc, validationSvc, userSvc, middlewares, i18nTranslatorSvc)
 	commentModule := comment.NewModule(commentSvc, validationSvc, middlewares, i18nTranslatorSvc)
+	resourceModule := resource.NewModule(resourceSvc, userSvc, middlewares, i18nTranslatorSvc)
+	noteModule := note.NewModule(noteSvc, userSvc, validationSvc, middlewares, i18nTranslatorSvc)
+	quizModule := quiz.NewModule(quizSvc, userSvc, validationSvc, middlewares, i18nTranslatorSvc)
+	assignmentModule := assignment.NewModule(assignmentSvc, userSvc, validationSvc, middlewares, i18nTranslatorSvc)
 	questionModule := question.NewModule(questionAnswerSvc, validationSvc, userSvc, middlewares, i18nTranslatorSvc)
 	cartModule := cart.NewModule(userSvc, cartSvc, validationSvc, middlewares, i18nTranslatorSvc)
 	orderModule := order.NewModule(orderSvc, validationSvc, userSvc, middlewares, i18nTranslatorSvc)
@@ -161,25 +212,75 @@ func main() {
 	transactionModule := transaction.NewModule(transactionSvc, middlewares, i18nTranslatorSvc)
 
 	server.GET("/ws", websocket.Handler(wsManager, tokenSvc))
+	go videoProgressWatcher.Watch(context.Background())
+
+	// workers, transcoding queues run in cmd/worker
+	if err := temporalSvc.AddWorker(
+		temporal.CLEANUP_VIDEO_STORAGE_QUEUE,
+		videoWorkflowSvc.CleanupVideoStorageWorkflow,
+		videoSvc.CleanupStorage,
+	); err != nil {
+		log.Printf("Error in add worker: %+v", err)
+	}
+
+	if err := temporalSvc.AddWorker(
+		temporal.SWEEP_ORPHAN_VIDEO_QUEUE,
+		videoWorkflowSvc.SweepOrphanVideoObjectsWorkflow,
+		videoSvc.SweepOrphans,
+	); err != nil {
+		log.Printf("Error in add worker: %+v", err)
+	}
+	orphanSweepCron := config.Video.OrphanSweepCron
+	if orphanSweepCron == "" {
+		orphanSweepCron = "0 3 * * *"
+	}
+	if err := temporalSvc.ScheduleWorker(
+		context.Background(),
+		temporal.SWEEP_ORPHAN_VIDEO_QUEUE,
+		workflow.SweepOrphanVideoObjectsWorkflowID,
+		orphanSweepCron,
+		videoWorkflowSvc.SweepOrphanVideoObjectsWorkflow,
+		videoDtoReq.SweepOrphanObjectsReqDto{GracePeriodHours: config.Video.OrphanGracePeriod},
+	); err != nil {
+		log.Printf("Error in schedule orphan video sweep: %+v", err)
+	}
+
+	if err := temporalSvc.AddWorker(
+		temporal.FLUSH_WATCH_PROGRESS_QUEUE,
+		watchProgressWorkflowSvc.FlushWatchProgressWorkflow,
+		watchProgressSvc.Flush,
+	); err != nil {
+		log.Printf("Error in add worker: %+v", err)
+	}
+	progressFlushCron := config.Video.ProgressFlushCron
+	if progressFlushCron == "" {
+		progressFlushCron = "* * * * *"
+	}
+	if err := temporalSvc.ScheduleWorker(
+		context.Background(),
+		temporal.FLUSH_WATCH_PROGRESS_QUEUE,
+		workflow.FlushWatchProgressWorkflowID,
+		progressFlushCron,
+		watchProgressWorkflowSvc.FlushWatchProgressWorkflow,
+		videoDtoReq.FlushWatchProgressReqDto{BatchSize: config.Video.ProgressFlushBatchSize},
+	); err != nil {
+		log.Printf("Error in schedule watch progress flush: %+v", err)
+	}
 
-	// workers
 	if err := temporalSvc.AddWorker(
-		temporal.ADD_NEW_COURSE_VIDEO_QUEUE,
-		videoWorkflowSvc.AddNewCourseVideoWorkflow,
-		videoSvc.CalculateDuration,
-		videoSvc.Encode,
-		videoSvc.UpdateURLAndDuration,
-		videoSvc.CreateCompleteUploadVideoNotification,
+		temporal.PUBLISH_SCHEDULE_QUEUE,
+		publishWorkflowSvc.PublishScheduleWorkflow,
+		publishSvc.ChangeStatus,
+		publishSvc.CreateStatusNotification,
 	); err != nil {
 		log.Printf("Error in add worker: %+v", err)
 	}
 
 	if err := temporalSvc.AddWorker(
-		temporal.SET_INTRODUCTION_COURSE_QUEUE,
-		videoWorkflowSvc.AddIntroductionVideoWorkflow,
-		videoSvc.Encode,
-		courseSvc.UpdateIntroductionURL,
-		courseSvc.CreateCompleteIntroductionVideoNotification,
+		temporal.COURSE_ANNOUNCEMENT_QUEUE,
+		announcementWorkflowSvc.SendAnnouncementWorkflow,
+		announcementSvc.DeliverNotifications,
+		announcementSvc.DeliverEmails,
 	); err != nil {
 		log.Printf("Error in add worker: %+v", err)
 	}
@@ -194,6 +295,10 @@ func main() {
 	notificationModule.Register(api)
 	teacherModule.Register(api)
 	commentModule.Register(api)
+	noteModule.Register(api)
+	resourceModule.Register(api)
+	quizModule.Register(api)
+	assignmentModule.Register(api)
 	questionModule.Register(api)
 	cartModule.Register(api)
 	orderModule.Register(api)
@@ -0,0 +1,100 @@
+package main
+
+import (
+	courseService "github.com/ladmakhi81/learnup/internals/course/service"
+	videoService "github.com/ladmakhi81/learnup/internals/video/service"
+	"github.com/ladmakhi81/learnup/internals/video/workflow"
+	"github.com/ladmakhi81/learnup/pkg/koanf"
+	"github.com/ladmakhi81/learnup/pkg/logrus/v1"
+	"github.com/ladmakhi81/learnup/pkg/minio/v7"
+	"github.com/ladmakhi81/learnup/pkg/temporal"
+	"github.com/ladmakhi81/learnup/pkg/temporal/v1"
+	"github.com/ladmakhi81/learnup/pkg/transcoder"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// the worker runs the transcoding queues apart from the http server, so encodes get
+// their own machines and scaling them never touches the api
+func main() {
+	// config file loader
+	koanfConfigProvider := koanf.NewKoanfEnvSvc()
+	config, configErr := koanfConfigProvider.LoadLearnUp()
+	if configErr != nil {
+		log.Fatalf("load learn up config failed: %v", configErr)
+	}
+
+	// temporal
+	temporalSvc := temporalv1.NewTemporalSvc(config)
+	if err := temporalSvc.Init(); err != nil {
+		log.Fatalf("temporal throw error: %v", err)
+	}
+
+	// database
+	dbClient := db.NewDatabase(config)
+	if err := dbClient.Connect(); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	unitOfWork := db.NewUnitOfWork(dbClient.Core)
+
+	// svcs
+	logrusSvc := logrusv1.NewLogrusLoggerSvc()
+	minioSvc, minioSvcErr := miniov7.NewMinioClientSvc(config)
+	if minioSvcErr != nil {
+		log.Fatalln(minioSvcErr)
+	}
+	transcoderSvc, transcoderSvcErr := transcoder.NewTranscoderSvc(config)
+	if transcoderSvcErr != nil {
+		log.Fatalln(transcoderSvcErr)
+	}
+	courseSvc := courseService.NewCourseSvc(unitOfWork)
+	videoSvc := videoService.NewVideoSvc(unitOfWork, minioSvc, transcoderSvc, logrusSvc, temporalSvc, config)
+	videoWorkflowSvc := workflow.NewVideoWorkflowImpl(videoSvc, temporalSvc, courseSvc)
+
+	// workers
+	if err := temporalSvc.AddWorker(
+		temporal.ADD_NEW_COURSE_VIDEO_QUEUE,
+		videoWorkflowSvc.AddNewCourseVideoWorkflow,
+		videoSvc.StartProcessing,
+		videoSvc.CalculateDuration,
+		videoSvc.Encode,
+		videoSvc.GenerateThumbnails,
+		videoSvc.UpdateURLAndDuration,
+		videoSvc.DeleteSource,
+		videoSvc.CleanupStorage,
+		videoSvc.CreateCompleteUploadVideoNotification,
+		videoSvc.MarkFailed,
+	); err != nil {
+		log.Fatalf("Error in add worker: %+v", err)
+	}
+
+	if err := temporalSvc.AddWorker(
+		temporal.SET_INTRODUCTION_COURSE_QUEUE,
+		videoWorkflowSvc.AddIntroductionVideoWorkflow,
+		videoSvc.Encode,
+		courseSvc.UpdateIntroductionURL,
+		videoSvc.DeleteSource,
+		courseSvc.CreateCompleteIntroductionVideoNotification,
+	); err != nil {
+		log.Fatalf("Error in add worker: %+v", err)
+	}
+
+	if err := temporalSvc.AddWorker(
+		temporal.UPDATE_VIDEO_THUMBNAIL_QUEUE,
+		videoWorkflowSvc.UpdateVideoThumbnailWorkflow,
+		videoSvc.RegenerateThumbnail,
+	); err != nil {
+		log.Fatalf("Error in add worker: %+v", err)
+	}
+
+	log.Println("the transcoding worker is running")
+
+	// workers poll in the background, running activities are retried elsewhere once their heartbeat stops
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	log.Println("the transcoding worker is stopping")
+}
@@ -0,0 +1,14 @@
+package dtoreq
+
+import (
+	"time"
+)
+
+type SendAnnouncementWorkflowReqDto struct {
+	AnnouncementID uint
+	ScheduledAt    *time.Time
+}
+
+type DeliverAnnouncementReqDto struct {
+	AnnouncementID uint
+}
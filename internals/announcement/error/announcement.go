@@ -0,0 +1,11 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Announcement_NotFound           = types.NewNotFoundError("announcement.errors.not_found")
+	Announcement_InvalidScheduledAt = types.NewBadRequestError("announcement.errors.invalid_scheduled_at")
+	Announcement_AlreadySent        = types.NewBadRequestError("announcement.errors.already_sent")
+)
@@ -0,0 +1,142 @@
+package service
+
+import (
+	dtoreq "github.com/ladmakhi81/learnup/internals/announcement/dto/req"
+	announcementError "github.com/ladmakhi81/learnup/internals/announcement/error"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+)
+
+type AnnouncementService interface {
+	DeliverNotifications(dto dtoreq.DeliverAnnouncementReqDto) error
+	DeliverEmails(dto dtoreq.DeliverAnnouncementReqDto) error
+}
+
+type announcementService struct {
+	unitOfWork db.UnitOfWork
+	mailSvc    contracts.Mail
+	logSvc     contracts.Log
+}
+
+func NewAnnouncementSvc(
+	unitOfWork db.UnitOfWork,
+	mailSvc contracts.Mail,
+	logSvc contracts.Log,
+) AnnouncementService {
+	return &announcementService{
+		unitOfWork: unitOfWork,
+		mailSvc:    mailSvc,
+		logSvc:     logSvc,
+	}
+}
+
+func (svc announcementService) DeliverNotifications(dto dtoreq.DeliverAnnouncementReqDto) error {
+	_, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		const operationName = "announcementService.DeliverNotifications"
+		announcement, err := tx.CourseAnnouncementRepo().GetByID(dto.AnnouncementID, []string{"Course"})
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching announcement by id", operationName, err)
+		}
+		if announcement == nil {
+			return nil, announcementError.Announcement_NotFound
+		}
+		// retried activities and cancelled announcements must not notify students again
+		if announcement.Status != entities.AnnouncementStatus_Scheduled {
+			return nil, nil
+		}
+		participants, err := tx.CourseParticipantRepo().GetAllByCourseID(announcement.CourseID)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching participants of course", operationName, err)
+		}
+		notifications := make([]*entities.Notification, len(participants))
+		for index, participant := range participants {
+			studentID := participant.StudentID
+			notifications[index] = &entities.Notification{
+				Type: entities.NotificationType_CourseAnnouncement,
+				Metadata: map[string]any{
+					"announcementId": announcement.ID,
+					"title":          announcement.Title,
+					"content":        announcement.Content,
+					"courseId":       announcement.CourseID,
+					"courseName":     announcement.Course.Name,
+				},
+				UserID:         &studentID,
+				AnnouncementID: &announcement.ID,
+			}
+		}
+		if len(notifications) > 0 {
+			if err := tx.NotificationRepo().BatchInsert(notifications); err != nil {
+				return nil, types.NewServerError("Error in creating announcement notifications", operationName, err)
+			}
+		}
+		if err := tx.CourseAnnouncementRepo().UpdateFields(announcement, map[string]any{
+			"status":           entities.AnnouncementStatus_Sent,
+			"sent_at":          utils.Now(),
+			"recipients_count": len(notifications),
+		}); err != nil {
+			return nil, types.NewServerError("Error in updating status of announcement", operationName, err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (svc announcementService) DeliverEmails(dto dtoreq.DeliverAnnouncementReqDto) error {
+	const operationName = "announcementService.DeliverEmails"
+	announcement, err := svc.unitOfWork.CourseAnnouncementRepo().GetByID(dto.AnnouncementID, []string{"Course", "Teacher"})
+	if err != nil {
+		return types.NewServerError("Error in fetching announcement by id", operationName, err)
+	}
+	if announcement == nil {
+		return announcementError.Announcement_NotFound
+	}
+	if !announcement.SendEmail || !announcement.IsSent() {
+		return nil
+	}
+	participants, err := svc.unitOfWork.CourseParticipantRepo().GetAllByCourseID(announcement.CourseID)
+	if err != nil {
+		return types.NewServerError("Error in fetching participants of course", operationName, err)
+	}
+	if len(participants) == 0 {
+		return nil
+	}
+	studentIDs := make([]uint, len(participants))
+	for index, participant := range participants {
+		studentIDs[index] = participant.StudentID
+	}
+	students, err := svc.unitOfWork.UserRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"id": studentIDs},
+	})
+	if err != nil {
+		return types.NewServerError("Error in fetching students of course", operationName, err)
+	}
+	templateData := map[string]any{
+		"Title":       announcement.Title,
+		"Content":     announcement.Content,
+		"CourseName":  announcement.Course.Name,
+		"TeacherName": announcement.Teacher.FullName(),
+	}
+	for _, student := range students {
+		if student.Email == nil {
+			continue
+		}
+		// a failed mail is only logged, retrying the activity would send duplicates to everyone else
+		mailReq := dtos.NewSendTemplateMailReq(*student.Email, announcement.Title, "course-announcement.html", templateData)
+		if err := svc.mailSvc.SendTemplate(mailReq); err != nil {
+			svc.logSvc.Error(dtos.LogMessage{
+				Message: "Error in sending announcement email",
+				Metadata: map[string]any{
+					"announcementId": announcement.ID,
+					"studentId":      student.ID,
+					"error":          err.Error(),
+				},
+			})
+		}
+	}
+	return nil
+}
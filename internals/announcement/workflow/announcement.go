@@ -0,0 +1,50 @@
+package workflow
+
+import (
+	"fmt"
+	dtoreq "github.com/ladmakhi81/learnup/internals/announcement/dto/req"
+	announcementService "github.com/ladmakhi81/learnup/internals/announcement/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"go.temporal.io/sdk/workflow"
+)
+
+type AnnouncementWorkflow interface {
+	SendAnnouncementWorkflow(ctx workflow.Context, dto dtoreq.SendAnnouncementWorkflowReqDto) error
+}
+
+type AnnouncementWorkflowImpl struct {
+	announcementSvc announcementService.AnnouncementService
+	temporalSvc     contracts.Temporal
+}
+
+func NewAnnouncementWorkflowImpl(
+	announcementSvc announcementService.AnnouncementService,
+	temporalSvc contracts.Temporal,
+) *AnnouncementWorkflowImpl {
+	return &AnnouncementWorkflowImpl{
+		announcementSvc: announcementSvc,
+		temporalSvc:     temporalSvc,
+	}
+}
+
+func SendAnnouncementWorkflowID(announcementID uint) string {
+	return fmt.Sprintf("send-announcement-%d", announcementID)
+}
+
+func (svc AnnouncementWorkflowImpl) SendAnnouncementWorkflow(ctx workflow.Context, dto dtoreq.SendAnnouncementWorkflowReqDto) error {
+	if dto.ScheduledAt != nil {
+		if err := workflow.Sleep(ctx, dto.ScheduledAt.Sub(workflow.Now(ctx))); err != nil {
+			return err
+		}
+	}
+	deliverDto := dtoreq.DeliverAnnouncementReqDto{AnnouncementID: dto.AnnouncementID}
+	// notification per student
+	if err := svc.temporalSvc.ExecuteTask(ctx, svc.announcementSvc.DeliverNotifications, deliverDto, nil); err != nil {
+		return err
+	}
+	// optional email
+	if err := svc.temporalSvc.ExecuteTask(ctx, svc.announcementSvc.DeliverEmails, deliverDto, nil); err != nil {
+		return err
+	}
+	return nil
+}
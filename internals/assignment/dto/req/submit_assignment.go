@@ -0,0 +1,12 @@
+package dtoreq
+
+import "io"
+
+// SubmitAssignmentReqDto is read from a multipart form, a submission needs the text, the file or both
+type SubmitAssignmentReqDto struct {
+	AssignmentID uint      `json:"-"`
+	Text         string    `json:"text" validate:"max=50000"`
+	FileName     string    `json:"-" validate:"max=255"`
+	Size         int64     `json:"-"`
+	File         io.Reader `json:"-"`
+}
@@ -0,0 +1,82 @@
+package dtores
+
+import (
+	assignmentService "github.com/ladmakhi81/learnup/internals/assignment/service"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type AssignmentCriterionDto struct {
+	ID          uint    `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+type GetAssignmentItemDto struct {
+	ID                   uint                      `json:"id"`
+	Title                string                    `json:"title"`
+	Description          string                    `json:"description"`
+	DueAt                *time.Time                `json:"dueAt"`
+	AllowLateSubmissions bool                      `json:"allowLateSubmissions"`
+	LateUntil            *time.Time                `json:"lateUntil"`
+	LatePenalty          float64                   `json:"latePenalty"`
+	MaxScore             float64                   `json:"maxScore"`
+	Criteria             []*AssignmentCriterionDto `json:"criteria"`
+	// the latest attempt, empty until the first submission
+	Submission *AssignmentSubmissionDto `json:"submission"`
+}
+
+type GetAssignmentResDto struct {
+	GetAssignmentItemDto
+	Submissions []*AssignmentSubmissionDto `json:"submissions"`
+}
+
+func newGetAssignmentItemDto(assignment *entities.Assignment) GetAssignmentItemDto {
+	item := GetAssignmentItemDto{
+		ID:                   assignment.ID,
+		Title:                assignment.Title,
+		Description:          assignment.Description,
+		DueAt:                assignment.DueAt,
+		AllowLateSubmissions: assignment.AllowLateSubmissions,
+		LateUntil:            assignment.LateUntil,
+		LatePenalty:          assignment.LatePenalty,
+		MaxScore:             assignment.MaxScore(),
+		Criteria:             make([]*AssignmentCriterionDto, len(assignment.Criteria)),
+	}
+	for index, criterion := range assignment.Criteria {
+		item.Criteria[index] = &AssignmentCriterionDto{
+			ID:          criterion.ID,
+			Title:       criterion.Title,
+			Description: criterion.Description,
+			Points:      criterion.Points,
+		}
+	}
+	return item
+}
+
+func MapGetAssignmentItemsDto(assignments []*assignmentService.ParticipantAssignment) []*GetAssignmentItemDto {
+	result := make([]*GetAssignmentItemDto, len(assignments))
+	for index, participantAssignment := range assignments {
+		item := newGetAssignmentItemDto(participantAssignment.Assignment)
+		if participantAssignment.Submission != nil {
+			item.Submission = NewAssignmentSubmissionDto(participantAssignment.Submission)
+		}
+		result[index] = &item
+	}
+	return result
+}
+
+func NewGetAssignmentResDto(detail *assignmentService.AssignmentDetail) *GetAssignmentResDto {
+	res := &GetAssignmentResDto{
+		GetAssignmentItemDto: newGetAssignmentItemDto(detail.Assignment),
+		Submissions:          make([]*AssignmentSubmissionDto, len(detail.Submissions)),
+	}
+	for index, submission := range detail.Submissions {
+		res.Submissions[index] = NewAssignmentSubmissionDto(submission)
+	}
+	if len(res.Submissions) > 0 {
+		res.Submission = res.Submissions[0]
+	}
+	return res
+}
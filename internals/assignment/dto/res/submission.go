@@ -0,0 +1,63 @@
+package dtores
+
+import (
+	assignmentService "github.com/ladmakhi81/learnup/internals/assignment/service"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type AssignmentSubmissionDto struct {
+	ID              uint                                `json:"id"`
+	AssignmentID    uint                                `json:"assignmentId"`
+	Attempt         uint                                `json:"attempt"`
+	Text            string                              `json:"text"`
+	FileName        string                              `json:"fileName"`
+	ContentType     string                              `json:"contentType"`
+	Size            int64                               `json:"size"`
+	SubmittedAt     time.Time                           `json:"submittedAt"`
+	LateDays        uint                                `json:"lateDays"`
+	Status          entities.AssignmentSubmissionStatus `json:"status"`
+	CanResubmit     bool                                `json:"canResubmit"`
+	CriterionScores []entities.AssignmentCriterionScore `json:"criterionScores"`
+	Score           *float64                            `json:"score"`
+	Penalty         float64                             `json:"penalty"`
+	FinalScore      *float64                            `json:"finalScore"`
+	MaxScore        float64                             `json:"maxScore"`
+	Feedback        string                              `json:"feedback"`
+	GradedAt        *time.Time                          `json:"gradedAt"`
+}
+
+func NewAssignmentSubmissionDto(submission *entities.AssignmentSubmission) *AssignmentSubmissionDto {
+	return &AssignmentSubmissionDto{
+		ID:              submission.ID,
+		AssignmentID:    submission.AssignmentID,
+		Attempt:         submission.Attempt,
+		Text:            submission.Text,
+		FileName:        submission.FileName,
+		ContentType:     submission.ContentType,
+		Size:            submission.Size,
+		SubmittedAt:     submission.SubmittedAt,
+		LateDays:        submission.LateDays,
+		Status:          submission.Status,
+		CanResubmit:     submission.Status == entities.AssignmentSubmissionStatus_ResubmissionRequested,
+		CriterionScores: submission.CriterionScores,
+		Score:           submission.Score,
+		Penalty:         submission.Penalty,
+		FinalScore:      submission.FinalScore,
+		MaxScore:        submission.MaxScore,
+		Feedback:        submission.Feedback,
+		GradedAt:        submission.GradedAt,
+	}
+}
+
+type GetSubmissionDownloadResDto struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func NewGetSubmissionDownloadResDto(download *assignmentService.SubmissionDownload) GetSubmissionDownloadResDto {
+	return GetSubmissionDownloadResDto{
+		URL:       download.URL,
+		ExpiresAt: download.ExpiresAt,
+	}
+}
@@ -0,0 +1,23 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Assignment_NotFound                = types.NewNotFoundError("assignment.errors.not_found")
+	Assignment_CriterionNotFound       = types.NewNotFoundError("assignment.errors.criterion_not_found")
+	Assignment_SubmissionNotFound      = types.NewNotFoundError("assignment.errors.submission_not_found")
+	Assignment_SubmissionFileNotFound  = types.NewNotFoundError("assignment.errors.submission_file_not_found")
+	Assignment_InvalidLateUntil        = types.NewBadRequestError("assignment.errors.invalid_late_until")
+	Assignment_NoCriteria              = types.NewBadRequestError("assignment.errors.no_criteria")
+	Assignment_DeadlinePassed          = types.NewBadRequestError("assignment.errors.deadline_passed")
+	Assignment_SubmissionEmpty         = types.NewBadRequestError("assignment.errors.submission_empty")
+	Assignment_FileTooLarge            = types.NewBadRequestError("assignment.errors.file_too_large")
+	Assignment_InvalidFileType         = types.NewBadRequestError("assignment.errors.invalid_file_type")
+	Assignment_FileInfected            = types.NewBadRequestError("assignment.errors.file_infected")
+	Assignment_InvalidCriterionScores  = types.NewBadRequestError("assignment.errors.invalid_criterion_scores")
+	Assignment_InvalidSubmissionStatus = types.NewBadRequestError("assignment.errors.invalid_submission_status")
+	Assignment_AlreadySubmitted        = types.NewConflictError("assignment.errors.already_submitted")
+	Assignment_SubmissionSuperseded    = types.NewConflictError("assignment.errors.submission_superseded")
+)
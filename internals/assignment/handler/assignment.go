@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/assignment/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/assignment/dto/res"
+	assignmentService "github.com/ladmakhi81/learnup/internals/assignment/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type Handler struct {
+	assignmentSvc  assignmentService.AssignmentService
+	userSvc        userService.UserSvc
+	validationSvc  contracts.Validation
+	translationSvc contracts.Translator
+}
+
+func NewHandler(
+	assignmentSvc assignmentService.AssignmentService,
+	userSvc userService.UserSvc,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+) *Handler {
+	return &Handler{
+		assignmentSvc:  assignmentSvc,
+		userSvc:        userSvc,
+		validationSvc:  validationSvc,
+		translationSvc: translationSvc,
+	}
+}
+
+// GetCourseAssignments godoc
+//
+//	@Summary	Get assignments of an enrolled course with the latest submission of the user
+//	@Tags		assignments
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.GetAssignmentItemDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/assignments/courses/{course-id} [get]
+//	@Security	BearerAuth
+func (h Handler) GetCourseAssignments(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := h.assignmentSvc.GetCourseAssignments(user, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapGetAssignmentItemsDto(assignments)), nil
+}
+
+// GetAssignment godoc
+//
+//	@Summary	Get an assignment with its rubric and every submission of the user
+//	@Tags		assignments
+//	@Produce	json
+//	@Param		assignment-id	path		int	true	"Assignment ID"
+//	@Success	200				{object}	types.ApiResponse{data=dtores.GetAssignmentResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/assignments/{assignment-id} [get]
+//	@Security	BearerAuth
+func (h Handler) GetAssignment(ctx *gin.Context) (*types.ApiResponse, error) {
+	assignmentID, err := utils.ToUint(ctx.Param("assignment-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := h.assignmentSvc.GetAssignment(user, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetAssignmentResDto(detail)), nil
+}
+
+// Submit godoc
+//
+//	@Summary	Submit a text, a file or both to an assignment, again only when the teacher asked for a resubmission
+//	@Tags		assignments
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		assignment-id	path		int		true	"Assignment ID"
+//	@Param		text			formData	string	false	"Answer text"
+//	@Param		file			formData	file	false	"Documents, archives, images or source files"
+//	@Success	201				{object}	types.ApiResponse{data=dtores.AssignmentSubmissionDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	409				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/assignments/{assignment-id}/submissions [post]
+//	@Security	BearerAuth
+func (h Handler) Submit(ctx *gin.Context) (*types.ApiResponse, error) {
+	assignmentID, err := utils.ToUint(ctx.Param("assignment-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.SubmitAssignmentReqDto{
+		AssignmentID: assignmentID,
+		Text:         ctx.PostForm("text"),
+	}
+	// the file is optional, a text only submission has no file part
+	if fileHeader, err := ctx.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("common.errors.invalid_request_body"),
+			)
+		}
+		defer file.Close()
+		dto.FileName = fileHeader.Filename
+		dto.Size = fileHeader.Size
+		dto.File = file
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	submission, err := h.assignmentSvc.Submit(ctx, user, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewAssignmentSubmissionDto(submission)), nil
+}
+
+// DownloadSubmission godoc
+//
+//	@Summary	Get a short lived download url of a submitted file, for its student and the teacher of the course
+//	@Tags		assignments
+//	@Produce	json
+//	@Param		submission-id	path		int	true	"Submission ID"
+//	@Success	200				{object}	types.ApiResponse{data=dtores.GetSubmissionDownloadResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/assignments/submissions/{submission-id}/download [get]
+//	@Security	BearerAuth
+func (h Handler) DownloadSubmission(ctx *gin.Context) (*types.ApiResponse, error) {
+	submissionID, err := utils.ToUint(ctx.Param("submission-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_submission_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	download, err := h.assignmentSvc.Download(ctx, user, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetSubmissionDownloadResDto(download)), nil
+}
@@ -0,0 +1,40 @@
+package assignment
+
+import (
+	"github.com/gin-gonic/gin"
+	assignmentHandler "github.com/ladmakhi81/learnup/internals/assignment/handler"
+	assignmentService "github.com/ladmakhi81/learnup/internals/assignment/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/middleware"
+	"github.com/ladmakhi81/learnup/shared/utils"
+)
+
+type Module struct {
+	handler        *assignmentHandler.Handler
+	middleware     *middleware.Middleware
+	translationSvc contracts.Translator
+}
+
+func NewModule(
+	assignmentSvc assignmentService.AssignmentService,
+	userSvc userService.UserSvc,
+	validationSvc contracts.Validation,
+	middleware *middleware.Middleware,
+	translationSvc contracts.Translator,
+) *Module {
+	return &Module{
+		middleware:     middleware,
+		translationSvc: translationSvc,
+		handler:        assignmentHandler.NewHandler(assignmentSvc, userSvc, validationSvc, translationSvc),
+	}
+}
+
+func (m *Module) Register(api *gin.RouterGroup) {
+	assignmentsApi := api.Group("/assignments")
+	assignmentsApi.Use(m.middleware.CheckAccessToken())
+	assignmentsApi.GET("/courses/:course-id", utils.JsonHandler(m.translationSvc, m.handler.GetCourseAssignments))
+	assignmentsApi.GET("/:assignment-id", utils.JsonHandler(m.translationSvc, m.handler.GetAssignment))
+	assignmentsApi.POST("/:assignment-id/submissions", utils.JsonHandler(m.translationSvc, m.handler.Submit))
+	assignmentsApi.GET("/submissions/:submission-id/download", utils.JsonHandler(m.translationSvc, m.handler.DownloadSubmission))
+}
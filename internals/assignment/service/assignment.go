@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	dtoreq "github.com/ladmakhi81/learnup/internals/assignment/dto/req"
+	assignmentError "github.com/ladmakhi81/learnup/internals/assignment/error"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	submissionsBucket = "assignments"
+	// megabytes, submissions are held in memory while being validated and scanned
+	defaultMaxSubmissionSize = 20
+	defaultDownloadTTL       = 5 * time.Minute
+)
+
+// ParticipantAssignment is an assignment as a participant sees it, Submission is their latest attempt
+type ParticipantAssignment struct {
+	Assignment *entities.Assignment
+	Submission *entities.AssignmentSubmission
+}
+
+// AssignmentDetail holds every attempt of the participant, the latest one first
+type AssignmentDetail struct {
+	Assignment  *entities.Assignment
+	Submissions []*entities.AssignmentSubmission
+}
+
+type SubmissionDownload struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+type AssignmentService interface {
+	GetCourseAssignments(user *entities.User, courseID uint) ([]*ParticipantAssignment, error)
+	GetAssignment(user *entities.User, assignmentID uint) (*AssignmentDetail, error)
+	Submit(ctx context.Context, user *entities.User, dto dtoreq.SubmitAssignmentReqDto) (*entities.AssignmentSubmission, error)
+	Download(ctx context.Context, user *entities.User, submissionID uint) (*SubmissionDownload, error)
+}
+
+type assignmentService struct {
+	unitOfWork  db.UnitOfWork
+	minioClient contracts.Storage
+	scannerSvc  contracts.VirusScanner
+	config      *dtos.EnvConfig
+}
+
+func NewAssignmentSvc(
+	unitOfWork db.UnitOfWork,
+	minioClient contracts.Storage,
+	scannerSvc contracts.VirusScanner,
+	config *dtos.EnvConfig,
+) AssignmentService {
+	return &assignmentService{
+		unitOfWork:  unitOfWork,
+		minioClient: minioClient,
+		scannerSvc:  scannerSvc,
+		config:      config,
+	}
+}
+
+func (svc assignmentService) GetCourseAssignments(user *entities.User, courseID uint) ([]*ParticipantAssignment, error) {
+	const operationName = "assignmentService.GetCourseAssignments"
+	if err := svc.checkParticipant(user, courseID); err != nil {
+		return nil, err
+	}
+	assignments, err := svc.unitOfWork.AssignmentRepo().GetAllByCourseID(courseID, true)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching assignments of course", operationName, err)
+	}
+	assignmentIDs := make([]uint, len(assignments))
+	for index, assignment := range assignments {
+		assignmentIDs[index] = assignment.ID
+	}
+	submissions, err := svc.unitOfWork.AssignmentSubmissionRepo().GetLatestByUserID(user.ID, assignmentIDs)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching submissions of course", operationName, err)
+	}
+	result := make([]*ParticipantAssignment, len(assignments))
+	for index, assignment := range assignments {
+		result[index] = &ParticipantAssignment{
+			Assignment: assignment,
+			Submission: submissions[assignment.ID],
+		}
+	}
+	return result, nil
+}
+
+func (svc assignmentService) GetAssignment(user *entities.User, assignmentID uint) (*AssignmentDetail, error) {
+	const operationName = "assignmentService.GetAssignment"
+	assignment, err := svc.getPublishedAssignment(user, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := svc.unitOfWork.AssignmentSubmissionRepo().GetAllByUserID(assignment.ID, user.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching submissions of assignment", operationName, err)
+	}
+	return &AssignmentDetail{
+		Assignment:  assignment,
+		Submissions: submissions,
+	}, nil
+}
+
+// Submit hands in the next attempt, only the first one is bound to the due date since a
+// resubmission is asked for by the teacher and keeps the late days of the first attempt
+func (svc assignmentService) Submit(
+	ctx context.Context,
+	user *entities.User,
+	dto dtoreq.SubmitAssignmentReqDto,
+) (*entities.AssignmentSubmission, error) {
+	const operationName = "assignmentService.Submit"
+	assignment, err := svc.getPublishedAssignment(user, dto.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := svc.unitOfWork.AssignmentSubmissionRepo().GetLatest(assignment.ID, user.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching latest submission", operationName, err)
+	}
+	now := time.Now()
+	submission := &entities.AssignmentSubmission{
+		AssignmentID: assignment.ID,
+		UserID:       user.ID,
+		CourseID:     assignment.CourseID,
+		Attempt:      1,
+		Text:         strings.TrimSpace(dto.Text),
+		SubmittedAt:  now,
+		Status:       entities.AssignmentSubmissionStatus_Submitted,
+	}
+	switch {
+	case latest == nil:
+		if !assignment.AcceptsSubmissionAt(now) {
+			return nil, assignmentError.Assignment_DeadlinePassed
+		}
+		submission.LateDays = assignment.LateDays(now)
+	case latest.Status == entities.AssignmentSubmissionStatus_ResubmissionRequested:
+		submission.Attempt = latest.Attempt + 1
+		submission.LateDays = latest.LateDays
+	default:
+		return nil, assignmentError.Assignment_AlreadySubmitted
+	}
+	var content []byte
+	if dto.File != nil {
+		if content, err = svc.readSubmissionFile(ctx, dto, submission); err != nil {
+			return nil, err
+		}
+	}
+	if submission.Text == "" && !submission.HasFile() {
+		return nil, assignmentError.Assignment_SubmissionEmpty
+	}
+	if submission.HasFile() {
+		if err := utils.EnsureBucket(ctx, svc.minioClient, submissionsBucket); err != nil {
+			return nil, types.NewServerError("Error in preparing submissions bucket", operationName, err)
+		}
+		if _, err := svc.minioClient.UploadFileByContent(ctx, submissionsBucket, submission.ObjectPath, submission.ContentType, content); err != nil {
+			return nil, types.NewServerError("Error in uploading submission file", operationName, err)
+		}
+	}
+	if err := svc.unitOfWork.AssignmentSubmissionRepo().Create(submission); err != nil {
+		if submission.HasFile() {
+			_ = svc.minioClient.DeleteObject(ctx, submissionsBucket, submission.ObjectPath)
+		}
+		return nil, types.NewServerError("Error in creating submission", operationName, err)
+	}
+	return submission, nil
+}
+
+// Download hands out the file of a submission to the student who submitted it and the teacher of the course
+func (svc assignmentService) Download(ctx context.Context, user *entities.User, submissionID uint) (*SubmissionDownload, error) {
+	const operationName = "assignmentService.Download"
+	submission, err := svc.unitOfWork.AssignmentSubmissionRepo().GetByID(submissionID, []string{"Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching submission by id", operationName, err)
+	}
+	if submission == nil || submission.Course == nil {
+		return nil, assignmentError.Assignment_SubmissionNotFound
+	}
+	if submission.UserID != user.ID && !submission.Course.IsTeacher(user.ID) {
+		return nil, assignmentError.Assignment_SubmissionNotFound
+	}
+	if !submission.HasFile() {
+		return nil, assignmentError.Assignment_SubmissionFileNotFound
+	}
+	ttl := svc.downloadTTL()
+	url, err := svc.minioClient.GetPresignedDownloadURL(ctx, submissionsBucket, submission.ObjectPath, submission.FileName, ttl)
+	if err != nil {
+		return nil, types.NewServerError("Error in presigning submission url", operationName, err)
+	}
+	return &SubmissionDownload{
+		URL:       url,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// readSubmissionFile validates and scans the file and fills in its fields on the submission,
+// an empty file part is treated as no file at all
+func (svc assignmentService) readSubmissionFile(
+	ctx context.Context,
+	dto dtoreq.SubmitAssignmentReqDto,
+	submission *entities.AssignmentSubmission,
+) ([]byte, error) {
+	const operationName = "assignmentService.readSubmissionFile"
+	maxSize := svc.maxSubmissionSize()
+	if dto.Size > maxSize {
+		return nil, assignmentError.Assignment_FileTooLarge
+	}
+	content, err := io.ReadAll(io.LimitReader(dto.File, maxSize+1))
+	if err != nil {
+		return nil, types.NewServerError("Error in reading submission file", operationName, err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, assignmentError.Assignment_FileTooLarge
+	}
+	if len(content) == 0 {
+		return nil, nil
+	}
+	extension := strings.ToLower(filepath.Ext(dto.FileName))
+	contentType, ok := utils.DetectUploadFileType(extension, content)
+	if !ok {
+		return nil, assignmentError.Assignment_InvalidFileType
+	}
+	scanResult, err := svc.scannerSvc.Scan(ctx, content)
+	if err != nil {
+		return nil, types.NewServerError("Error in scanning submission file", operationName, err)
+	}
+	if scanResult.Infected {
+		return nil, assignmentError.Assignment_FileInfected
+	}
+	submission.FileName = filepath.Base(dto.FileName)
+	submission.ContentType = contentType
+	submission.Size = int64(len(content))
+	submission.ObjectPath = fmt.Sprintf("%d/%d/%d/%s%s", submission.CourseID, submission.AssignmentID, submission.UserID, uuid.NewString(), extension)
+	submission.ScanStatus = entities.ResourceScanStatus_Unscanned
+	if scanResult.Scanned {
+		submission.ScanStatus = entities.ResourceScanStatus_Clean
+	}
+	return content, nil
+}
+
+func (svc assignmentService) getPublishedAssignment(user *entities.User, assignmentID uint) (*entities.Assignment, error) {
+	const operationName = "assignmentService.getPublishedAssignment"
+	assignment, err := svc.unitOfWork.AssignmentRepo().GetWithCriteria(assignmentID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching assignment by id", operationName, err)
+	}
+	if assignment == nil || !assignment.IsPublished {
+		return nil, assignmentError.Assignment_NotFound
+	}
+	if err := svc.checkParticipant(user, assignment.CourseID); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (svc assignmentService) checkParticipant(user *entities.User, courseID uint) error {
+	const operationName = "assignmentService.checkParticipant"
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(courseID, user.ID)
+	if err != nil {
+		return types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	if participant == nil || !participant.IsActive() {
+		return courseError.Course_NotParticipant
+	}
+	return nil
+}
+
+func (svc assignmentService) maxSubmissionSize() int64 {
+	size := svc.config.Assignment.MaxSubmissionSize
+	if size <= 0 {
+		size = defaultMaxSubmissionSize
+	}
+	return int64(size) << 20
+}
+
+func (svc assignmentService) downloadTTL() time.Duration {
+	if svc.config.Assignment.DownloadTTL > 0 {
+		return time.Duration(svc.config.Assignment.DownloadTTL) * time.Second
+	}
+	return defaultDownloadTTL
+}
@@ -0,0 +1,22 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type EnrollFreeResDto struct {
+	CourseID      uint       `json:"courseId"`
+	CourseVersion uint       `json:"courseVersion"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+func NewEnrollFreeResDto(participant *entities.CourseParticipant) EnrollFreeResDto {
+	return EnrollFreeResDto{
+		CourseID:      participant.CourseID,
+		CourseVersion: participant.CourseVersion,
+		ExpiresAt:     participant.ExpiresAt,
+		CreatedAt:     participant.CreatedAt,
+	}
+}
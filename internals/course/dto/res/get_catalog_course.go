@@ -0,0 +1,70 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+)
+
+type catalogPreviewLesson struct {
+	ID           uint    `json:"id"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	Duration     *string `json:"duration"`
+	ThumbnailURL *string `json:"thumbnailUrl"`
+}
+
+type GetCatalogCourseResDto struct {
+	ID                uint                    `json:"id"`
+	Name              string                  `json:"name"`
+	Teacher           *teacherUser            `json:"teacher"`
+	Category          *categoryItem           `json:"category"`
+	Price             float64                 `json:"price"`
+	ThumbnailImage    string                  `json:"thumbnailImage"`
+	Image             string                  `json:"image"`
+	Description       string                  `json:"description"`
+	Prerequisite      string                  `json:"prerequisite"`
+	Level             entities.CourseLevel    `json:"level"`
+	Status            entities.CourseStatus   `json:"status"`
+	Tags              []string                `json:"tags"`
+	IntroductionVideo string                  `json:"introductionVideo"`
+	PreviewLessons    []*catalogPreviewLesson `json:"previewLessons"`
+}
+
+func NewGetCatalogCourseResDto(course *entities.Course, previews []*entities.Video) *GetCatalogCourseResDto {
+	res := &GetCatalogCourseResDto{
+		ID:                course.ID,
+		Name:              course.Name,
+		Price:             course.Price,
+		ThumbnailImage:    course.ThumbnailImage,
+		Image:             course.Image,
+		Description:       course.Description,
+		Prerequisite:      course.Prerequisite,
+		Level:             course.Level,
+		Status:            course.Status,
+		Tags:              course.Tags,
+		IntroductionVideo: course.IntroductionVideo,
+		PreviewLessons:    make([]*catalogPreviewLesson, len(previews)),
+	}
+	if course.Teacher != nil {
+		res.Teacher = &teacherUser{
+			ID:       course.Teacher.ID,
+			FullName: course.Teacher.FullName(),
+		}
+	}
+	if course.Category != nil {
+		res.Category = &categoryItem{
+			ID:          course.Category.ID,
+			Name:        course.Category.Name,
+			IsPublished: course.Category.IsPublished,
+		}
+	}
+	for index, preview := range previews {
+		res.PreviewLessons[index] = &catalogPreviewLesson{
+			ID:           preview.ID,
+			Title:        preview.Title,
+			Description:  preview.Description,
+			Duration:     preview.Duration,
+			ThumbnailURL: preview.ThumbnailURL,
+		}
+	}
+	return res
+}
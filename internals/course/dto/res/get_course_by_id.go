@@ -48,6 +48,8 @@ type GetCourseByItemDto struct {
 	CanHaveDiscount             bool                             `json:"canHaveDiscount"`
 	MaxDiscountAmount           float64                          `json:"maxDiscountAmount"`
 	DiscountFeeAmountPercentage float64                          `json:"discountFeeAmountPercentage"`
+	PublishAt                   *time.Time                       `json:"publishAt"`
+	UnpublishAt                 *time.Time                       `json:"unpublishAt"`
 }
 
 func NewGetCourseByItemDto(course *entities.Course) GetCourseByItemDto {
@@ -84,6 +86,8 @@ func NewGetCourseByItemDto(course *entities.Course) GetCourseByItemDto {
 		MaxDiscountAmount:           course.MaxDiscountAmount,
 		Prerequisite:                course.Prerequisite,
 		Tags:                        course.Tags,
+		PublishAt:                   course.PublishAt,
+		UnpublishAt:                 course.UnpublishAt,
 	}
 
 	if course.VerifiedBy != nil {
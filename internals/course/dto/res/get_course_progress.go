@@ -0,0 +1,58 @@
+package dtores
+
+import (
+	videoService "github.com/ladmakhi81/learnup/internals/video/service"
+	"time"
+)
+
+type GetCourseProgressResDto struct {
+	CourseID             uint       `json:"courseId"`
+	TotalVideos          int        `json:"totalVideos"`
+	CompletedVideos      int        `json:"completedVideos"`
+	TotalQuizzes         int        `json:"totalQuizzes"`
+	PassedQuizzes        int        `json:"passedQuizzes"`
+	CompletionPercentage float64    `json:"completionPercentage"`
+	ResumeVideoID        *uint      `json:"resumeVideoId"`
+	ResumePosition       uint       `json:"resumePosition"`
+	LastVideoWatchDate   *time.Time `json:"lastVideoWatchDate"`
+}
+
+func NewGetCourseProgressResDto(progress *videoService.CourseProgress) GetCourseProgressResDto {
+	return GetCourseProgressResDto{
+		CourseID:             progress.Participant.CourseID,
+		TotalVideos:          progress.TotalVideos,
+		CompletedVideos:      progress.CompletedVideos,
+		TotalQuizzes:         progress.TotalQuizzes,
+		PassedQuizzes:        progress.PassedQuizzes,
+		CompletionPercentage: progress.CompletionPercentage,
+		ResumeVideoID:        progress.ResumeVideoID,
+		ResumePosition:       progress.ResumePosition,
+		LastVideoWatchDate:   progress.Participant.LastVideoWatchDate,
+	}
+}
+
+type GetEnrolledCourseItemDto struct {
+	GetCourseProgressResDto
+	Name           string     `json:"name"`
+	ThumbnailImage string     `json:"thumbnailImage"`
+	CourseVersion  uint       `json:"courseVersion"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+	EnrolledAt     time.Time  `json:"enrolledAt"`
+}
+
+func MapGetEnrolledCourseItemsDto(progresses []*videoService.CourseProgress) []GetEnrolledCourseItemDto {
+	result := make([]GetEnrolledCourseItemDto, len(progresses))
+	for index, progress := range progresses {
+		result[index] = GetEnrolledCourseItemDto{
+			GetCourseProgressResDto: NewGetCourseProgressResDto(progress),
+			CourseVersion:           progress.Participant.CourseVersion,
+			ExpiresAt:               progress.Participant.ExpiresAt,
+			EnrolledAt:              progress.Participant.CreatedAt,
+		}
+		if course := progress.Participant.Course; course != nil {
+			result[index].Name = course.Name
+			result[index].ThumbnailImage = course.ThumbnailImage
+		}
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type publishedByUser struct {
+	ID       uint   `json:"id"`
+	FullName string `json:"fullName"`
+}
+
+type GetCourseVersionItemDto struct {
+	ID          uint             `json:"id"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	Version     uint             `json:"version"`
+	Changelog   string           `json:"changelog"`
+	VideosCount int              `json:"videosCount"`
+	PublishedBy *publishedByUser `json:"publishedBy"`
+}
+
+func MapGetCourseVersionItemsDto(versions []*entities.CourseVersion) []*GetCourseVersionItemDto {
+	res := make([]*GetCourseVersionItemDto, len(versions))
+	for index, version := range versions {
+		res[index] = &GetCourseVersionItemDto{
+			ID:          version.ID,
+			CreatedAt:   version.CreatedAt,
+			Version:     version.Version,
+			Changelog:   version.Changelog,
+			VideosCount: len(version.Snapshot.Videos),
+		}
+		if version.PublishedBy != nil {
+			res[index].PublishedBy = &publishedByUser{
+				ID:       version.PublishedBy.ID,
+				FullName: version.PublishedBy.FullName(),
+			}
+		}
+	}
+	return res
+}
@@ -11,6 +11,13 @@ type verifiedByUser struct {
 	Phone    string `json:"phone"`
 }
 
+type videoProgress struct {
+	WatchedSeconds uint       `json:"watchedSeconds"`
+	ResumePosition uint       `json:"resumePosition"`
+	IsCompleted    bool       `json:"isCompleted"`
+	CompletedAt    *time.Time `json:"completedAt"`
+}
+
 type GetVideoByCourseItemDto struct {
 	ID           uint                       `json:"id"`
 	CreatedAt    time.Time                  `json:"createdAt"`
@@ -18,31 +25,43 @@ type GetVideoByCourseItemDto struct {
 	Title        string                     `json:"title"`
 	Description string                     `json:"description"`
 	AccessLevel entities2.VideoAccessLevel `json:"accessLevel"`
+	IsPreview   bool                       `json:"isPreview"`
 	Duration    *string                    `json:"duration"`
 	URL          string                     `json:"url"`
+	ThumbnailURL *string                    `json:"thumbnailUrl"`
 	IsPublished  bool                       `json:"isPublished"`
 	IsVerified   bool                       `json:"isVerified"`
 	VerifiedDate *time.Time                 `json:"verifiedDate"`
 	VerifiedBy  *verifiedByUser            `json:"verifiedBy"`
 	Status      entities2.VideoStatus      `json:"status"`
+	FailureReason *string                  `json:"failureReason"`
+	PublishAt    *time.Time                 `json:"publishAt"`
+	UnpublishAt  *time.Time                 `json:"unpublishAt"`
+	Progress     *videoProgress             `json:"progress"`
 }
 
-func MapGetVideoByCourseItemsDto(videos []*entities2.Video) []*GetVideoByCourseItemDto {
+// MapGetVideoByCourseItemsDto attaches the progress of the user, videos never watched have none
+func MapGetVideoByCourseItemsDto(videos []*entities2.Video, progresses map[uint]*entities2.VideoProgress) []*GetVideoByCourseItemDto {
 	result := make([]*GetVideoByCourseItemDto, len(videos))
 	for videoIndex, video := range videos {
 		result[videoIndex] = &GetVideoByCourseItemDto{
 			URL:          video.URL,
+			ThumbnailURL: video.ThumbnailURL,
 			AccessLevel:  video.AccessLevel,
+			IsPreview:    video.IsPreview,
 			Description:  video.Description,
 			Title:        video.Title,
 			Duration:     video.Duration,
 			IsPublished:  video.IsPublished,
 			ID:           video.ID,
 			Status:       video.Status,
+			FailureReason: video.FailureReason,
 			IsVerified:   video.IsVerified,
 			UpdatedAt:    video.UpdatedAt,
 			CreatedAt:    video.CreatedAt,
 			VerifiedDate: video.VerifiedDate,
+			PublishAt:    video.PublishAt,
+			UnpublishAt:  video.UnpublishAt,
 		}
 		if video.VerifiedBy != nil {
 			result[videoIndex].VerifiedBy = &verifiedByUser{
@@ -51,6 +70,14 @@ func MapGetVideoByCourseItemsDto(videos []*entities2.Video) []*GetVideoByCourseI
 				FullName: video.VerifiedBy.FullName(),
 			}
 		}
+		if progress, isWatched := progresses[video.ID]; isWatched {
+			result[videoIndex].Progress = &videoProgress{
+				WatchedSeconds: progress.WatchedSeconds,
+				ResumePosition: progress.LastPosition,
+				IsCompleted:    progress.IsCompleted,
+				CompletedAt:    progress.CompletedAt,
+			}
+		}
 	}
 	return result
 }
@@ -13,4 +13,9 @@ var (
 	Course_InvalidFee                   = types.NewBadRequestError("course.errors.invalid_fee")
 	Course_InvalidMaxDiscountPercentage = types.NewBadRequestError("course.errors.invalid_max_discount_percentage")
 	Course_ForbiddenAccess              = types.NewForbiddenAccessError("common.errors.forbidden_access")
+	Course_NotVerified                  = types.NewBadRequestError("course.errors.not_verified")
+	Course_NotParticipant               = types.NewForbiddenAccessError("course.errors.not_participant")
+	Course_VersionNotFound              = types.NewNotFoundError("course.errors.version_not_found")
+	Course_NotFree                      = types.NewBadRequestError("course.errors.not_free")
+	Course_NotAvailable                 = types.NewBadRequestError("course.errors.not_available")
 )
@@ -30,6 +30,8 @@ type Handler struct {
 	questionSvc   questionService.QuestionService
 	userSvc       userService.UserSvc
 	forumSvc      forumService.ForumService
+	versionSvc    courseService.CourseVersionService
+	progressSvc   videoService.WatchProgressService
 }
 
 func NewHandler(
@@ -42,6 +44,8 @@ func NewHandler(
 	questionSvc questionService.QuestionService,
 	userSvc userService.UserSvc,
 	forumSvc forumService.ForumService,
+	versionSvc courseService.CourseVersionService,
+	progressSvc videoService.WatchProgressService,
 ) *Handler {
 	return &Handler{
 		courseSvc:     courseSvc,
@@ -53,6 +57,8 @@ func NewHandler(
 		questionSvc:   questionSvc,
 		userSvc:       userSvc,
 		forumSvc:      forumSvc,
+		versionSvc:    versionSvc,
+		progressSvc:   progressSvc,
 	}
 }
 
@@ -140,11 +146,74 @@ func (h Handler) GetVideosByCourseID(ctx *gin.Context) (*types.ApiResponse, erro
 	if err != nil {
 		return nil, types.NewBadRequestError(h.translateSvc.Translate("course.errors.invalid_course_id"))
 	}
-	videos, err := h.videoSvc.FindVideosByCourseID(courseID)
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	videos, err := h.versionSvc.FindVideosForUser(user, courseID)
+	if err != nil {
+		return nil, err
+	}
+	videoIDs := make([]uint, len(videos))
+	for index, video := range videos {
+		videoIDs[index] = video.ID
+	}
+	progresses, err := h.progressSvc.GetVideoProgresses(user, videoIDs)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, courseDtoRes.MapGetVideoByCourseItemsDto(videos, progresses)), nil
+}
+
+// GetProgress godoc
+//
+//	@Summary	Get completion and resume position of an enrolled course
+//	@Tags		courses
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=courseDtoRes.GetCourseProgressResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/courses/{course-id}/progress [get]
+//
+//	@Security	BearerAuth
+func (h Handler) GetProgress(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(h.translateSvc.Translate("course.errors.invalid_course_id"))
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return types.NewApiResponse(http.StatusOK, courseDtoRes.MapGetVideoByCourseItemsDto(videos)), nil
+	progress, err := h.progressSvc.GetCourseProgress(user, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, courseDtoRes.NewGetCourseProgressResDto(progress)), nil
+}
+
+// GetEnrolledCourses godoc
+//
+//	@Summary	Get the courses of the student with their progress, the course watched last comes first
+//	@Tags		courses
+//	@Success	200	{object}	types.ApiResponse{data=[]courseDtoRes.GetEnrolledCourseItemDto}
+//	@Failure	401	{object}	types.ApiError
+//	@Failure	500	{object}	types.ApiError
+//	@Router		/courses/enrolled [get]
+//
+//	@Security	BearerAuth
+func (h Handler) GetEnrolledCourses(ctx *gin.Context) (*types.ApiResponse, error) {
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	progresses, err := h.progressSvc.GetCourseProgresses(user)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, courseDtoRes.MapGetEnrolledCourseItemsDto(progresses)), nil
 }
 
 // GetCourseById godoc
@@ -171,6 +240,28 @@ func (h Handler) GetCourseById(ctx *gin.Context) (*types.ApiResponse, error) {
 	return types.NewApiResponse(http.StatusOK, courseDtoRes.NewGetCourseByItemDto(course)), nil
 }
 
+// GetCatalogCourse godoc
+//
+//	@Summary	Get public detail of a course on sale with its free preview lessons
+//	@Tags		catalog
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=courseDtoRes.GetCatalogCourseResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/catalog/courses/{course-id} [get]
+func (h Handler) GetCatalogCourse(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(h.translateSvc.Translate("course.errors.invalid_course_id"))
+	}
+	course, previews, err := h.courseSvc.FindCatalogDetail(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, courseDtoRes.NewGetCatalogCourseResDto(course, previews)), nil
+}
+
 // VerifyCourse godoc
 //
 //	@Summary	Verify a course
@@ -455,3 +546,86 @@ func (h Handler) GetForumByCourseID(ctx *gin.Context) (*types.ApiResponse, error
 	}
 	return types.NewApiResponse(http.StatusOK, courseDtoRes.MapGetForumByCourseIDDto(forum)), nil
 }
+
+// GetVersions godoc
+//
+//	@Summary	Get published versions of a course
+//	@Tags		courses
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]courseDtoRes.GetCourseVersionItemDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/courses/{course-id}/versions [get]
+//
+//	@Security	BearerAuth
+func (h Handler) GetVersions(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(h.translateSvc.Translate("course.errors.invalid_course_id"))
+	}
+	versions, err := h.versionSvc.FetchByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, courseDtoRes.MapGetCourseVersionItemsDto(versions)), nil
+}
+
+// UpgradeVersion godoc
+//
+//	@Summary	Opt in to the latest version of a purchased course
+//	@Tags		courses
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/courses/{course-id}/versions/upgrade [patch]
+//
+//	@Security	BearerAuth
+func (h Handler) UpgradeVersion(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(h.translateSvc.Translate("course.errors.invalid_course_id"))
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.versionSvc.UpgradeParticipantVersion(user, courseID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// EnrollFree godoc
+//
+//	@Summary	Enroll in a free course without checkout, calling it again returns the same enrollment
+//	@Tags		courses
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=courseDtoRes.EnrollFreeResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/courses/{course-id}/enroll [post]
+//
+//	@Security	BearerAuth
+func (h Handler) EnrollFree(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(h.translateSvc.Translate("course.errors.invalid_course_id"))
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	participant, err := h.courseSvc.EnrollFree(user, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, courseDtoRes.NewEnrollFreeResDto(participant)), nil
+}
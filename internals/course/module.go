@@ -8,6 +8,8 @@ import (
 	forumService "github.com/ladmakhi81/learnup/internals/forum/service"
 	likeService "github.com/ladmakhi81/learnup/internals/like/service"
 	questionService "github.com/ladmakhi81/learnup/internals/question/service"
+	teacherHandler "github.com/ladmakhi81/learnup/internals/teacher/handler"
+	teacherService "github.com/ladmakhi81/learnup/internals/teacher/service"
 	userService "github.com/ladmakhi81/learnup/internals/user/service"
 	videoService "github.com/ladmakhi81/learnup/internals/video/service"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
@@ -16,9 +18,10 @@ import (
 )
 
 type Module struct {
-	middleware     *middleware.Middleware
-	courseHandler  *courseHandler.Handler
-	translationSvc contracts.Translator
+	middleware         *middleware.Middleware
+	courseHandler      *courseHandler.Handler
+	participantHandler *teacherHandler.ParticipantHandler
+	translationSvc     contracts.Translator
 }
 
 func NewModule(
@@ -30,6 +33,9 @@ func NewModule(
 	questionSvc questionService.QuestionService,
 	userSvc userService.UserSvc,
 	forumSvc forumService.ForumService,
+	versionSvc courseService.CourseVersionService,
+	progressSvc videoService.WatchProgressService,
+	participantSvc teacherService.TeacherParticipantService,
 	middleware *middleware.Middleware,
 	translationSvc contracts.Translator,
 ) *Module {
@@ -46,6 +52,14 @@ func NewModule(
 			questionSvc,
 			userSvc,
 			forumSvc,
+			versionSvc,
+			progressSvc,
+		),
+		participantHandler: teacherHandler.NewParticipantHandler(
+			participantSvc,
+			validationSvc,
+			translationSvc,
+			userSvc,
 		),
 	}
 }
@@ -56,7 +70,9 @@ func (m Module) Register(api *gin.RouterGroup) {
 	coursesApi.Use(m.middleware.CheckAccessToken())
 	coursesApi.POST("/", utils.JsonHandler(m.translationSvc, m.courseHandler.CreateCourse))
 	coursesApi.GET("/page", utils.JsonHandler(m.translationSvc, m.courseHandler.GetCourses))
+	coursesApi.GET("/enrolled", utils.JsonHandler(m.translationSvc, m.courseHandler.GetEnrolledCourses))
 	coursesApi.GET("/:course-id/videos", utils.JsonHandler(m.translationSvc, m.courseHandler.GetVideosByCourseID))
+	coursesApi.GET("/:course-id/progress", utils.JsonHandler(m.translationSvc, m.courseHandler.GetProgress))
 	coursesApi.GET("/:course-id", utils.JsonHandler(m.translationSvc, m.courseHandler.GetCourseById))
 	coursesApi.PATCH("/:course-id/verify", utils.JsonHandler(m.translationSvc, m.courseHandler.VerifyCourse))
 	coursesApi.POST("/:course-id/like", utils.JsonHandler(m.translationSvc, m.courseHandler.Like))
@@ -66,4 +82,16 @@ func (m Module) Register(api *gin.RouterGroup) {
 	coursesApi.POST("/:course-id/question", utils.JsonHandler(m.translationSvc, m.courseHandler.CreateQuestion))
 	coursesApi.GET("/:course-id/questions", utils.JsonHandler(m.translationSvc, m.courseHandler.GetQuestions))
 	coursesApi.GET("/:course-id/forum", utils.JsonHandler(m.translationSvc, m.courseHandler.GetForumByCourseID))
+	coursesApi.GET("/:course-id/versions", utils.JsonHandler(m.translationSvc, m.courseHandler.GetVersions))
+	coursesApi.PATCH("/:course-id/versions/upgrade", utils.JsonHandler(m.translationSvc, m.courseHandler.UpgradeVersion))
+	coursesApi.POST("/:course-id/enroll", utils.JsonHandler(m.translationSvc, m.courseHandler.EnrollFree))
+	coursesApi.GET("/:course-id/participants", utils.JsonHandler(m.translationSvc, m.participantHandler.FetchParticipants))
+	coursesApi.POST("/:course-id/participants", utils.JsonHandler(m.translationSvc, m.participantHandler.AddParticipant))
+	coursesApi.POST("/:course-id/participants/bulk", utils.JsonHandler(m.translationSvc, m.participantHandler.BulkEnroll))
+	coursesApi.GET("/:course-id/participants/audits", utils.JsonHandler(m.translationSvc, m.participantHandler.FetchEnrollmentAudits))
+	coursesApi.PATCH("/:course-id/participants/:student-id", utils.JsonHandler(m.translationSvc, m.participantHandler.UpdateParticipantExpiry))
+	coursesApi.DELETE("/:course-id/participants/:student-id", utils.JsonHandler(m.translationSvc, m.participantHandler.RemoveParticipant))
+
+	catalogApi := api.Group("/catalog")
+	catalogApi.GET("/courses/:course-id", utils.JsonHandler(m.translationSvc, m.courseHandler.GetCatalogCourse))
 }
@@ -14,9 +14,11 @@ type CourseService interface {
 	Create(createdBy *entities.User, dto dtoreq.CreateCourseReqDto) (*entities.Course, error)
 	GetCourses(page, pageSize int) ([]*entities.Course, int, error)
 	FindDetailById(id uint) (*entities.Course, error)
+	FindCatalogDetail(id uint) (*entities.Course, []*entities.Video, error)
 	VerifyCourse(admin *entities.User, dto dtoreq.VerifyCourseReqDto) error
 	UpdateIntroductionURL(dto dtoreq.UpdateIntroductionURLReqDto) error
 	CreateCompleteIntroductionVideoNotification(id uint) error
+	EnrollFree(student *entities.User, courseID uint) (*entities.CourseParticipant, error)
 }
 
 type courseService struct {
@@ -118,6 +120,24 @@ func (svc courseService) FindDetailById(id uint) (*entities.Course, error) {
 	return course, nil
 }
 
+// FindCatalogDetail only exposes courses on sale, their preview lessons are listed so visitors
+// can watch them before buying
+func (svc courseService) FindCatalogDetail(id uint) (*entities.Course, []*entities.Video, error) {
+	const operationName = "courseService.FindCatalogDetail"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(id, []string{"Teacher", "Category"})
+	if err != nil {
+		return nil, nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil || !course.IsPublished || !course.IsVerifiedByAdmin {
+		return nil, nil, courseError.Course_NotFound
+	}
+	previews, err := svc.unitOfWork.VideoRepo().GetPreviewsByCourseID(course.ID)
+	if err != nil {
+		return nil, nil, types.NewServerError("Error in fetching preview videos of course", operationName, err)
+	}
+	return course, previews, nil
+}
+
 func (svc courseService) VerifyCourse(admin *entities.User, dto dtoreq.VerifyCourseReqDto) error {
 	const operationName = "courseService.VerifyCourse"
 	course, err := svc.unitOfWork.CourseRepo().GetByID(dto.ID, nil)
@@ -149,6 +169,11 @@ func (svc courseService) VerifyCourse(admin *entities.User, dto dtoreq.VerifyCou
 	if err := svc.unitOfWork.CourseRepo().Update(course); err != nil {
 		return types.NewServerError("Error in verifying the course by admin", operationName, err)
 	}
+	if course.CurrentVersion == 0 {
+		if err := svc.createInitialVersion(admin, course); err != nil {
+			return err
+		}
+	}
 	notification := &entities.Notification{
 		Type:   entities.NotificationType_CourseVerified,
 		UserID: course.TeacherID,
@@ -208,3 +233,74 @@ func (svc courseService) CreateCompleteIntroductionVideoNotification(id uint) er
 	}
 	return nil
 }
+
+func (svc courseService) createInitialVersion(admin *entities.User, course *entities.Course) error {
+	const operationName = "courseService.createInitialVersion"
+	videos, err := svc.unitOfWork.VideoRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"course_id": course.ID},
+	})
+	if err != nil {
+		return types.NewServerError("Error in fetching videos of course", operationName, err)
+	}
+	courseVersion := entities.NewCourseVersion(course, videos, 1, admin.ID, "")
+	if err := svc.unitOfWork.CourseVersionRepo().Create(courseVersion); err != nil {
+		return types.NewServerError("Error in creating initial version of course", operationName, err)
+	}
+	if err := svc.unitOfWork.CourseRepo().UpdateFields(course, map[string]any{"current_version": courseVersion.Version}); err != nil {
+		return types.NewServerError("Error in updating current version of course", operationName, err)
+	}
+	return nil
+}
+
+// EnrollFree is idempotent, enrolling twice returns the existing lasting participant
+func (svc courseService) EnrollFree(student *entities.User, courseID uint) (*entities.CourseParticipant, error) {
+	return db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*entities.CourseParticipant, error) {
+		const operationName = "courseService.EnrollFree"
+		course, err := tx.CourseRepo().GetByID(courseID, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+		}
+		if course == nil {
+			return nil, courseError.Course_NotFound
+		}
+		if !course.IsPublished || !course.IsVerifiedByAdmin {
+			return nil, courseError.Course_NotAvailable
+		}
+		if course.Price != 0 {
+			return nil, courseError.Course_NotFree
+		}
+		if course.IsTeacher(student.ID) {
+			return nil, courseError.Course_ForbiddenAccess
+		}
+		participant := &entities.CourseParticipant{
+			CourseID:      course.ID,
+			StudentID:     student.ID,
+			TeacherID:     *course.TeacherID,
+			CourseVersion: course.CurrentVersion,
+			Source:        entities.EnrollmentAction_Free,
+		}
+		// an expired seat is renewed rather than handed back as it is
+		isEnrolled, err := tx.CourseParticipantRepo().CreateOrRenew(participant)
+		if err != nil {
+			return nil, types.NewServerError("Error in creating course participant", operationName, err)
+		}
+		if !isEnrolled {
+			existingParticipant, err := tx.CourseParticipantRepo().GetOne(course.ID, student.ID)
+			if err != nil {
+				return nil, types.NewServerError("Error in fetching course participant", operationName, err)
+			}
+			return existingParticipant, nil
+		}
+		audit := &entities.EnrollmentAudit{
+			CourseID:  course.ID,
+			StudentID: student.ID,
+			ActorID:   student.ID,
+			Action:    entities.EnrollmentAction_Free,
+			Reason:    "free course",
+		}
+		if err := tx.EnrollmentAuditRepo().Create(audit); err != nil {
+			return nil, types.NewServerError("Error in creating enrollment audit", operationName, err)
+		}
+		return participant, nil
+	})
+}
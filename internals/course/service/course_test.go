@@ -0,0 +1,163 @@
+package service
+
+import (
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"testing"
+	"time"
+)
+
+// enrollUnitOfWork hands out a single transaction over in memory repos, methods the enrollment
+// never calls are left to the embedded nil interfaces and panic if reached
+type enrollUnitOfWork struct {
+	db.UnitOfWork
+	tx *enrollTx
+}
+
+func (uow enrollUnitOfWork) Begin() (db.UnitOfWorkTx, error) {
+	return uow.tx, nil
+}
+
+type enrollTx struct {
+	db.UnitOfWorkTx
+	courseRepo      *enrollCourseRepo
+	participantRepo *enrollParticipantRepo
+	auditRepo       *enrollAuditRepo
+}
+
+func (tx *enrollTx) CourseRepo() repositories.CourseRepo {
+	return tx.courseRepo
+}
+
+func (tx *enrollTx) CourseParticipantRepo() repositories.CourseParticipantRepo {
+	return tx.participantRepo
+}
+
+func (tx *enrollTx) EnrollmentAuditRepo() repositories.EnrollmentAuditRepo {
+	return tx.auditRepo
+}
+
+func (tx *enrollTx) Commit() error {
+	return nil
+}
+
+func (tx *enrollTx) Rollback() error {
+	return nil
+}
+
+type enrollCourseRepo struct {
+	repositories.CourseRepo
+	course *entities.Course
+}
+
+func (repo *enrollCourseRepo) GetByID(id uint, relations []string) (*entities.Course, error) {
+	if repo.course == nil || repo.course.ID != id {
+		return nil, nil
+	}
+	return repo.course, nil
+}
+
+// enrollParticipantRepo behaves like the upsert on the course and student index
+type enrollParticipantRepo struct {
+	repositories.CourseParticipantRepo
+	participant *entities.CourseParticipant
+}
+
+func (repo *enrollParticipantRepo) CreateOrRenew(courseParticipant *entities.CourseParticipant) (bool, error) {
+	if repo.participant != nil && repo.participant.ExpiresAt == nil {
+		return false, nil
+	}
+	repo.participant = courseParticipant
+	return true, nil
+}
+
+func (repo *enrollParticipantRepo) GetOne(courseID, studentID uint) (*entities.CourseParticipant, error) {
+	return repo.participant, nil
+}
+
+type enrollAuditRepo struct {
+	repositories.EnrollmentAuditRepo
+	audits []*entities.EnrollmentAudit
+}
+
+func (repo *enrollAuditRepo) Create(audit *entities.EnrollmentAudit) error {
+	repo.audits = append(repo.audits, audit)
+	return nil
+}
+
+func TestEnrollFree(t *testing.T) {
+	teacherID := uint(7)
+	expiredAt := time.Now().Add(-time.Hour)
+	freeCourse := entities.Course{TeacherID: &teacherID, IsPublished: true, IsVerifiedByAdmin: true, CurrentVersion: 3}
+	freeCourse.ID = 1
+	paidCourse := freeCourse
+	paidCourse.Price = 100
+	hiddenCourse := freeCourse
+	hiddenCourse.IsPublished = false
+	tests := []struct {
+		name           string
+		course         *entities.Course
+		studentID      uint
+		participant    *entities.CourseParticipant
+		expectedErr    error
+		expectedSource entities.EnrollmentAction
+		expectsAudit   bool
+	}{
+		{
+			name:           "first enrollment",
+			course:         &freeCourse,
+			studentID:      2,
+			expectedSource: entities.EnrollmentAction_Free,
+			expectsAudit:   true,
+		},
+		{
+			name:           "enrolling twice returns the existing seat",
+			course:         &freeCourse,
+			studentID:      2,
+			participant:    &entities.CourseParticipant{CourseID: 1, StudentID: 2, Source: entities.EnrollmentAction_Purchase},
+			expectedSource: entities.EnrollmentAction_Purchase,
+		},
+		{
+			name:           "an expired seat is renewed",
+			course:         &freeCourse,
+			studentID:      2,
+			participant:    &entities.CourseParticipant{CourseID: 1, StudentID: 2, Source: entities.EnrollmentAction_Add, ExpiresAt: &expiredAt},
+			expectedSource: entities.EnrollmentAction_Free,
+			expectsAudit:   true,
+		},
+		{name: "missing course", studentID: 2, expectedErr: courseError.Course_NotFound},
+		{name: "paid course", course: &paidCourse, studentID: 2, expectedErr: courseError.Course_NotFree},
+		{name: "unpublished course", course: &hiddenCourse, studentID: 2, expectedErr: courseError.Course_NotAvailable},
+		{name: "teacher of the course", course: &freeCourse, studentID: teacherID, expectedErr: courseError.Course_ForbiddenAccess},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := &enrollTx{
+				courseRepo:      &enrollCourseRepo{course: test.course},
+				participantRepo: &enrollParticipantRepo{participant: test.participant},
+				auditRepo:       &enrollAuditRepo{},
+			}
+			svc := courseService{unitOfWork: enrollUnitOfWork{tx: tx}}
+			student := &entities.User{}
+			student.ID = test.studentID
+			participant, err := svc.EnrollFree(student, 1)
+			if err != test.expectedErr {
+				t.Fatalf("EnrollFree error = %v, want %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+			if participant.StudentID != test.studentID || participant.Source != test.expectedSource || participant.ExpiresAt != nil {
+				t.Errorf("participant = %+v", participant)
+			}
+			if participant.Source == entities.EnrollmentAction_Free && participant.CourseVersion != freeCourse.CurrentVersion {
+				t.Errorf("course version = %d, want %d", participant.CourseVersion, freeCourse.CurrentVersion)
+			}
+			if isAudited := len(tx.auditRepo.audits) == 1; isAudited != test.expectsAudit || len(tx.auditRepo.audits) > 1 {
+				t.Errorf("audits = %d, want audit %v", len(tx.auditRepo.audits), test.expectsAudit)
+			}
+		})
+	}
+}
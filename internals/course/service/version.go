@@ -0,0 +1,107 @@
+package service
+
+import (
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+type CourseVersionService interface {
+	FetchByCourseID(courseID uint) ([]*entities.CourseVersion, error)
+	UpgradeParticipantVersion(user *entities.User, courseID uint) error
+	FindVideosForUser(user *entities.User, courseID uint) ([]*entities.Video, error)
+}
+
+type courseVersionService struct {
+	unitOfWork db.UnitOfWork
+}
+
+func NewCourseVersionSvc(unitOfWork db.UnitOfWork) CourseVersionService {
+	return &courseVersionService{unitOfWork: unitOfWork}
+}
+
+func (svc courseVersionService) FetchByCourseID(courseID uint) ([]*entities.CourseVersion, error) {
+	const operationName = "courseVersionService.FetchByCourseID"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	versionOrder := "version desc"
+	versions, err := svc.unitOfWork.CourseVersionRepo().GetAll(repositories.GetAllOptions{
+		Order:      &versionOrder,
+		Conditions: map[string]any{"course_id": courseID},
+		Relations:  []string{"PublishedBy"},
+	})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching versions of course", operationName, err)
+	}
+	return versions, nil
+}
+
+func (svc courseVersionService) UpgradeParticipantVersion(user *entities.User, courseID uint) error {
+	const operationName = "courseVersionService.UpgradeParticipantVersion"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return courseError.Course_NotFound
+	}
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(course.ID, user.ID)
+	if err != nil {
+		return types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	if participant == nil || !participant.IsActive() {
+		return courseError.Course_NotParticipant
+	}
+	if participant.CourseVersion == course.CurrentVersion {
+		return nil
+	}
+	if err := svc.unitOfWork.CourseParticipantRepo().UpdateVersion(course.ID, user.ID, course.CurrentVersion); err != nil {
+		return types.NewServerError("Error in upgrading version of course participant", operationName, err)
+	}
+	return nil
+}
+
+// FindVideosForUser returns the frozen video list of the version the student bought,
+// the teacher and users outside the course see the live list of videos
+func (svc courseVersionService) FindVideosForUser(user *entities.User, courseID uint) ([]*entities.Video, error) {
+	const operationName = "courseVersionService.FindVideosForUser"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(course.ID, user.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	if course.IsTeacher(user.ID) || participant == nil || !participant.IsActive() || participant.CourseVersion == 0 {
+		videos, err := svc.unitOfWork.VideoRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"course_id": course.ID},
+			Relations:  []string{"VerifiedBy"},
+		})
+		if err != nil {
+			return nil, types.NewServerError("Finding videos by course id throw error", operationName, err)
+		}
+		return videos, nil
+	}
+	courseVersion, err := svc.unitOfWork.CourseVersionRepo().GetOne(
+		map[string]any{"course_id": course.ID, "version": participant.CourseVersion},
+		nil,
+	)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching version of course", operationName, err)
+	}
+	if courseVersion == nil {
+		return nil, courseError.Course_VersionNotFound
+	}
+	return courseVersion.GetVideos(), nil
+}
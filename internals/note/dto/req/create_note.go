@@ -0,0 +1,10 @@
+package dtoreq
+
+import "github.com/ladmakhi81/learnup/shared/db/entities"
+
+type CreateNoteReqDto struct {
+	VideoID   uint                   `json:"videoId" validate:"required,gte=1"`
+	Timestamp float64                `json:"timestamp" validate:"gte=0"`
+	Type      entities.VideoNoteType `json:"type" validate:"required,oneof=note bookmark"`
+	Content   string                 `json:"content" validate:"max=5000"`
+}
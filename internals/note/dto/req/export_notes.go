@@ -0,0 +1,13 @@
+package dtoreq
+
+type NoteExportFormat string
+
+const (
+	NoteExportFormat_Markdown NoteExportFormat = "markdown"
+	NoteExportFormat_Pdf      NoteExportFormat = "pdf"
+)
+
+type ExportNotesReqDto struct {
+	CourseID uint
+	Format   NoteExportFormat
+}
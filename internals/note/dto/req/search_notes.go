@@ -0,0 +1,13 @@
+package dtoreq
+
+import "github.com/ladmakhi81/learnup/shared/db/entities"
+
+// SearchNotesReqDto is read from the query string, every filter is optional
+type SearchNotesReqDto struct {
+	CourseID *uint
+	VideoID  *uint
+	Type     *entities.VideoNoteType
+	Query    string
+	Page     int
+	PageSize int
+}
@@ -0,0 +1,7 @@
+package dtoreq
+
+type UpdateNoteReqDto struct {
+	ID        uint     `json:"-"`
+	Timestamp *float64 `json:"timestamp" validate:"omitempty,gte=0"`
+	Content   *string  `json:"content" validate:"omitempty,max=5000"`
+}
@@ -0,0 +1,51 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type getNoteVideoItem struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+type GetNoteItemDto struct {
+	ID        uint                   `json:"id"`
+	CreatedAt time.Time              `json:"createdAt"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+	CourseID  uint                   `json:"courseId"`
+	VideoID   uint                   `json:"videoId"`
+	Video     *getNoteVideoItem      `json:"video,omitempty"`
+	Timestamp float64                `json:"timestamp"`
+	Type      entities.VideoNoteType `json:"type"`
+	Content   string                 `json:"content"`
+}
+
+func NewGetNoteItemDto(note *entities.VideoNote) *GetNoteItemDto {
+	res := &GetNoteItemDto{
+		ID:        note.ID,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+		CourseID:  note.CourseID,
+		VideoID:   note.VideoID,
+		Timestamp: note.Timestamp,
+		Type:      note.Type,
+		Content:   note.Content,
+	}
+	if note.Video != nil {
+		res.Video = &getNoteVideoItem{
+			ID:    note.Video.ID,
+			Title: note.Video.Title,
+		}
+	}
+	return res
+}
+
+func MapGetNoteItemsDto(notes []*entities.VideoNote) []*GetNoteItemDto {
+	result := make([]*GetNoteItemDto, len(notes))
+	for index, note := range notes {
+		result[index] = NewGetNoteItemDto(note)
+	}
+	return result
+}
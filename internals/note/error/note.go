@@ -0,0 +1,14 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Note_NotFound            = types.NewNotFoundError("note.errors.not_found")
+	Note_ContentRequired     = types.NewBadRequestError("note.errors.content_required")
+	Note_InvalidType         = types.NewBadRequestError("note.errors.invalid_type")
+	Note_TimestampOutOfRange = types.NewBadRequestError("note.errors.timestamp_out_of_range")
+	Note_InvalidExportFormat = types.NewBadRequestError("note.errors.invalid_export_format")
+	Note_PdfRightToLeftText  = types.NewBadRequestError("note.errors.pdf_right_to_left_text")
+)
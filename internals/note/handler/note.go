@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/note/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/note/dto/res"
+	noteService "github.com/ladmakhi81/learnup/internals/note/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type Handler struct {
+	noteSvc        noteService.NoteService
+	userSvc        userService.UserSvc
+	translationSvc contracts.Translator
+	validationSvc  contracts.Validation
+}
+
+func NewHandler(
+	noteSvc noteService.NoteService,
+	userSvc userService.UserSvc,
+	translationSvc contracts.Translator,
+	validationSvc contracts.Validation,
+) *Handler {
+	return &Handler{
+		noteSvc:        noteSvc,
+		userSvc:        userSvc,
+		translationSvc: translationSvc,
+		validationSvc:  validationSvc,
+	}
+}
+
+// CreateNote godoc
+//
+//	@Summary	Add a private note or bookmark at a timestamp of a video
+//	@Tags		notes
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		dtoreq.CreateNoteReqDto	true	" "
+//	@Success	201		{object}	types.ApiResponse{data=dtores.GetNoteItemDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	403		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/notes/ [post]
+//	@Security	BearerAuth
+func (h Handler) CreateNote(ctx *gin.Context) (*types.ApiResponse, error) {
+	dto := &dtoreq.CreateNoteReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	note, err := h.noteSvc.Create(user, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewGetNoteItemDto(note)), nil
+}
+
+// UpdateNote godoc
+//
+//	@Summary	Update the content or timestamp of a note
+//	@Tags		notes
+//	@Accept		json
+//	@Produce	json
+//	@Param		note-id	path		int						true	"Note ID"
+//	@Param		request	body		dtoreq.UpdateNoteReqDto	true	" "
+//	@Success	200		{object}	types.ApiResponse{data=dtores.GetNoteItemDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/notes/{note-id} [patch]
+//	@Security	BearerAuth
+func (h Handler) UpdateNote(ctx *gin.Context) (*types.ApiResponse, error) {
+	noteID, err := utils.ToUint(ctx.Param("note-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("note.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.UpdateNoteReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	dto.ID = noteID
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	note, err := h.noteSvc.Update(user, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetNoteItemDto(note)), nil
+}
+
+// DeleteNote godoc
+//
+//	@Summary	Delete a note
+//	@Tags		notes
+//	@Produce	json
+//	@Param		note-id	path		int	true	"Note ID"
+//	@Success	200		{object}	types.ApiResponse
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/notes/{note-id} [delete]
+//	@Security	BearerAuth
+func (h Handler) DeleteNote(ctx *gin.Context) (*types.ApiResponse, error) {
+	noteID, err := utils.ToUint(ctx.Param("note-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("note.errors.invalid_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.noteSvc.Delete(user, noteID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// SearchNotes godoc
+//
+//	@Summary	Search notes and bookmarks of the logged in user
+//	@Tags		notes
+//	@Produce	json
+//	@Param		course-id	query		int		false	"Course ID"
+//	@Param		video-id	query		int		false	"Video ID"
+//	@Param		type		query		string	false	"note or bookmark"
+//	@Param		q			query		string	false	"Text searched in the content"
+//	@Param		page		query		int		false	"Page number"	default(0)
+//	@Param		pageSize	query		int		false	"Page size"		default(10)
+//	@Success	200			{object}	types.ApiResponse{data=types.PaginationRes{row=[]dtores.GetNoteItemDto}}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/notes/page [get]
+//	@Security	BearerAuth
+func (h Handler) SearchNotes(ctx *gin.Context) (*types.ApiResponse, error) {
+	page, pageSize := utils.ExtractPaginationMetadata(ctx.Query("page"), ctx.Query("pageSize"))
+	dto := dtoreq.SearchNotesReqDto{
+		Query:    ctx.Query("q"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if courseIDParam := ctx.Query("course-id"); courseIDParam != "" {
+		courseID, err := utils.ToUint(courseIDParam)
+		if err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("course.errors.invalid_course_id"),
+			)
+		}
+		dto.CourseID = &courseID
+	}
+	if videoIDParam := ctx.Query("video-id"); videoIDParam != "" {
+		videoID, err := utils.ToUint(videoIDParam)
+		if err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("video.errors.invalid_id"),
+			)
+		}
+		dto.VideoID = &videoID
+	}
+	if typeParam := ctx.Query("type"); typeParam != "" {
+		noteType := entities.VideoNoteType(typeParam)
+		dto.Type = &noteType
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notes, count, err := h.noteSvc.Search(user, dto)
+	if err != nil {
+		return nil, err
+	}
+	notesRes := types.NewPaginationRes(
+		dtores.MapGetNoteItemsDto(notes),
+		page,
+		utils.CalculatePaginationTotalPage(count, pageSize),
+		count,
+	)
+	return types.NewApiResponse(http.StatusOK, notesRes), nil
+}
+
+// ExportNotes godoc
+//
+//	@Summary	Download every note of a course as markdown or pdf
+//	@Tags		notes
+//	@Produce	text/markdown
+//	@Produce	application/pdf
+//	@Param		course-id	path	int		true	"Course ID"
+//	@Param		format		query	string	false	"markdown or pdf, right to left notes only export as markdown"	default(markdown)
+//	@Success	200
+//	@Failure	400	{object}	types.ApiError
+//	@Failure	401	{object}	types.ApiError
+//	@Failure	404	{object}	types.ApiError
+//	@Failure	500	{object}	types.ApiError
+//	@Router		/notes/courses/{course-id}/export [get]
+//	@Security	BearerAuth
+func (h Handler) ExportNotes(ctx *gin.Context) (*types.FileResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.noteSvc.Export(user, dtoreq.ExportNotesReqDto{
+		CourseID: courseID,
+		Format:   dtoreq.NoteExportFormat(ctx.DefaultQuery("format", string(dtoreq.NoteExportFormat_Markdown))),
+	})
+}
@@ -0,0 +1,41 @@
+package note
+
+import (
+	"github.com/gin-gonic/gin"
+	noteHandler "github.com/ladmakhi81/learnup/internals/note/handler"
+	noteService "github.com/ladmakhi81/learnup/internals/note/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/middleware"
+	"github.com/ladmakhi81/learnup/shared/utils"
+)
+
+type Module struct {
+	handler        *noteHandler.Handler
+	middleware     *middleware.Middleware
+	translationSvc contracts.Translator
+}
+
+func NewModule(
+	noteSvc noteService.NoteService,
+	userSvc userService.UserSvc,
+	validationSvc contracts.Validation,
+	middleware *middleware.Middleware,
+	translationSvc contracts.Translator,
+) *Module {
+	return &Module{
+		middleware:     middleware,
+		translationSvc: translationSvc,
+		handler:        noteHandler.NewHandler(noteSvc, userSvc, translationSvc, validationSvc),
+	}
+}
+
+func (m *Module) Register(api *gin.RouterGroup) {
+	notesApi := api.Group("/notes")
+	notesApi.Use(m.middleware.CheckAccessToken())
+	notesApi.POST("/", utils.JsonHandler(m.translationSvc, m.handler.CreateNote))
+	notesApi.GET("/page", utils.JsonHandler(m.translationSvc, m.handler.SearchNotes))
+	notesApi.GET("/courses/:course-id/export", utils.FileHandler(m.translationSvc, m.handler.ExportNotes))
+	notesApi.PATCH("/:note-id", utils.JsonHandler(m.translationSvc, m.handler.UpdateNote))
+	notesApi.DELETE("/:note-id", utils.JsonHandler(m.translationSvc, m.handler.DeleteNote))
+}
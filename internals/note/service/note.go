@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/note/dto/req"
+	noteError "github.com/ladmakhi81/learnup/internals/note/error"
+	videoError "github.com/ladmakhi81/learnup/internals/video/error"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"strings"
+	"unicode"
+)
+
+type NoteService interface {
+	Create(user *entities.User, dto dtoreq.CreateNoteReqDto) (*entities.VideoNote, error)
+	Update(user *entities.User, dto dtoreq.UpdateNoteReqDto) (*entities.VideoNote, error)
+	Delete(user *entities.User, id uint) error
+	Search(user *entities.User, dto dtoreq.SearchNotesReqDto) ([]*entities.VideoNote, int, error)
+	Export(user *entities.User, dto dtoreq.ExportNotesReqDto) (*types.FileResponse, error)
+}
+
+type noteService struct {
+	unitOfWork db.UnitOfWork
+	pdfSvc     contracts.Pdf
+}
+
+func NewNoteSvc(unitOfWork db.UnitOfWork, pdfSvc contracts.Pdf) NoteService {
+	return &noteService{
+		unitOfWork: unitOfWork,
+		pdfSvc:     pdfSvc,
+	}
+}
+
+func (svc noteService) Create(user *entities.User, dto dtoreq.CreateNoteReqDto) (*entities.VideoNote, error) {
+	const operationName = "noteService.Create"
+	if dto.Type == entities.VideoNoteType_Note && strings.TrimSpace(dto.Content) == "" {
+		return nil, noteError.Note_ContentRequired
+	}
+	video, err := svc.unitOfWork.VideoRepo().GetByID(dto.VideoID, []string{"Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil || video.Course == nil {
+		return nil, videoError.Video_NotFound
+	}
+	if err := svc.checkVideoAccess(user, video); err != nil {
+		return nil, err
+	}
+	if err := checkTimestamp(video, dto.Timestamp); err != nil {
+		return nil, err
+	}
+	note := &entities.VideoNote{
+		UserID:    user.ID,
+		VideoID:   video.ID,
+		CourseID:  video.Course.ID,
+		Timestamp: dto.Timestamp,
+		Type:      dto.Type,
+		Content:   strings.TrimSpace(dto.Content),
+	}
+	if err := svc.unitOfWork.VideoNoteRepo().Create(note); err != nil {
+		return nil, types.NewServerError("Error in creating note", operationName, err)
+	}
+	return note, nil
+}
+
+func (svc noteService) Update(user *entities.User, dto dtoreq.UpdateNoteReqDto) (*entities.VideoNote, error) {
+	const operationName = "noteService.Update"
+	note, err := svc.getOwnNote(user, dto.ID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.Content != nil {
+		note.Content = strings.TrimSpace(*dto.Content)
+	}
+	if note.Type == entities.VideoNoteType_Note && note.Content == "" {
+		return nil, noteError.Note_ContentRequired
+	}
+	if dto.Timestamp != nil {
+		if err := checkTimestamp(note.Video, *dto.Timestamp); err != nil {
+			return nil, err
+		}
+		note.Timestamp = *dto.Timestamp
+	}
+	if err := svc.unitOfWork.VideoNoteRepo().UpdateFields(note, map[string]any{
+		"content":   note.Content,
+		"timestamp": note.Timestamp,
+	}); err != nil {
+		return nil, types.NewServerError("Error in updating note", operationName, err)
+	}
+	return note, nil
+}
+
+func (svc noteService) Delete(user *entities.User, id uint) error {
+	const operationName = "noteService.Delete"
+	note, err := svc.getOwnNote(user, id)
+	if err != nil {
+		return err
+	}
+	if err := svc.unitOfWork.VideoNoteRepo().Delete(note); err != nil {
+		return types.NewServerError("Error in deleting note", operationName, err)
+	}
+	return nil
+}
+
+func (svc noteService) Search(user *entities.User, dto dtoreq.SearchNotesReqDto) ([]*entities.VideoNote, int, error) {
+	const operationName = "noteService.Search"
+	if dto.Type != nil && *dto.Type != entities.VideoNoteType_Note && *dto.Type != entities.VideoNoteType_Bookmark {
+		return nil, 0, noteError.Note_InvalidType
+	}
+	notes, count, err := svc.unitOfWork.VideoNoteRepo().Search(repositories.VideoNoteSearchOptions{
+		UserID:   user.ID,
+		CourseID: dto.CourseID,
+		VideoID:  dto.VideoID,
+		Type:     dto.Type,
+		Query:    dto.Query,
+		Page:     dto.Page,
+		PageSize: dto.PageSize,
+	})
+	if err != nil {
+		return nil, 0, types.NewServerError("Error in searching notes", operationName, err)
+	}
+	return notes, count, nil
+}
+
+// Export doesn't check the enrollment again, the notes belong to the user even after the access expired
+func (svc noteService) Export(user *entities.User, dto dtoreq.ExportNotesReqDto) (*types.FileResponse, error) {
+	const operationName = "noteService.Export"
+	if dto.Format != dtoreq.NoteExportFormat_Markdown && dto.Format != dtoreq.NoteExportFormat_Pdf {
+		return nil, noteError.Note_InvalidExportFormat
+	}
+	course, err := svc.unitOfWork.CourseRepo().GetByID(dto.CourseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	notes, err := svc.unitOfWork.VideoNoteRepo().GetAllByCourseID(user.ID, course.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching notes of course", operationName, err)
+	}
+	document := buildNotesDocument(course, notes)
+	fileName := fmt.Sprintf("course-%d-notes", course.ID)
+	if dto.Format == dtoreq.NoteExportFormat_Pdf {
+		if hasRightToLeftText(document) {
+			return nil, noteError.Note_PdfRightToLeftText
+		}
+		content, err := svc.pdfSvc.GenerateDocument(document)
+		if err != nil {
+			return nil, types.NewServerError("Error in generating pdf of notes", operationName, err)
+		}
+		return types.NewAttachmentFileResponse("application/pdf", fileName+".pdf", content), nil
+	}
+	return types.NewAttachmentFileResponse("text/markdown; charset=utf-8", fileName+".md", renderMarkdown(document)), nil
+}
+
+func (svc noteService) getOwnNote(user *entities.User, id uint) (*entities.VideoNote, error) {
+	const operationName = "noteService.getOwnNote"
+	note, err := svc.unitOfWork.VideoNoteRepo().GetOne(
+		map[string]any{"id": id, "user_id": user.ID},
+		[]string{"Video"},
+	)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching note by id", operationName, err)
+	}
+	if note == nil {
+		return nil, noteError.Note_NotFound
+	}
+	return note, nil
+}
+
+// checkVideoAccess lets the teacher note any video of the course, students need an active
+// enrollment and only see published and verified videos
+func (svc noteService) checkVideoAccess(user *entities.User, video *entities.Video) error {
+	const operationName = "noteService.checkVideoAccess"
+	if video.Course.IsTeacher(user.ID) {
+		return nil
+	}
+	if !video.IsPublished || !video.IsVerified {
+		return videoError.Video_NotFound
+	}
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(video.Course.ID, user.ID)
+	if err != nil {
+		return types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	if participant == nil || !participant.IsActive() {
+		return courseError.Course_NotParticipant
+	}
+	return nil
+}
+
+// checkTimestamp can only bound the timestamp once the duration of the video is known
+func checkTimestamp(video *entities.Video, timestamp float64) error {
+	if video == nil || video.Duration == nil {
+		return nil
+	}
+	duration, err := utils.ClockToSeconds(*video.Duration)
+	if err != nil {
+		return nil
+	}
+	// durations are stored in whole seconds, the last second is still part of the video
+	if timestamp > duration+1 {
+		return noteError.Note_TimestampOutOfRange
+	}
+	return nil
+}
+
+// buildNotesDocument groups the notes by video, both export formats are rendered from it
+func buildNotesDocument(course *entities.Course, notes []*entities.VideoNote) dtos.PdfDocument {
+	document := dtos.PdfDocument{Title: course.Name}
+	for index, note := range notes {
+		if index == 0 || notes[index-1].VideoID != note.VideoID {
+			heading := fmt.Sprintf("Video %d", note.VideoID)
+			if note.Video != nil {
+				heading = note.Video.Title
+			}
+			document.Sections = append(document.Sections, dtos.PdfSection{Heading: heading})
+		}
+		line := fmt.Sprintf("[%s]", utils.SecondsToClock(note.Timestamp))
+		if note.Type == entities.VideoNoteType_Bookmark {
+			line += " (bookmark)"
+		}
+		if note.Content != "" {
+			line += " " + note.Content
+		}
+		section := &document.Sections[len(document.Sections)-1]
+		section.Paragraphs = append(section.Paragraphs, line)
+	}
+	return document
+}
+
+// hasRightToLeftText finds persian, arabic or hebrew text, the pdf writer neither joins their
+// letters nor reorders them, so notes written in them are only exported as markdown
+func hasRightToLeftText(document dtos.PdfDocument) bool {
+	texts := []string{document.Title}
+	for _, section := range document.Sections {
+		texts = append(texts, section.Heading)
+		texts = append(texts, section.Paragraphs...)
+	}
+	for _, text := range texts {
+		if strings.IndexFunc(text, isRightToLeft) != -1 {
+			return true
+		}
+	}
+	return false
+}
+
+func isRightToLeft(char rune) bool {
+	return unicode.In(char, unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko)
+}
+
+func renderMarkdown(document dtos.PdfDocument) []byte {
+	var result bytes.Buffer
+	result.WriteString("# " + document.Title + "\n")
+	for _, section := range document.Sections {
+		result.WriteString("\n## " + section.Heading + "\n\n")
+		for _, paragraph := range section.Paragraphs {
+			// multi line notes stay inside their list item
+			result.WriteString("- " + strings.ReplaceAll(paragraph, "\n", "\n  ") + "\n")
+		}
+	}
+	return result.Bytes()
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"testing"
+)
+
+func TestHasRightToLeftText(t *testing.T) {
+	tests := []struct {
+		name     string
+		document dtos.PdfDocument
+		expected bool
+	}{
+		{
+			name:     "latin notes",
+			document: dtos.PdfDocument{Title: "Go basics", Sections: []dtos.PdfSection{{Heading: "Intro", Paragraphs: []string{"[00:01:10] goroutines", "[00:02:00] (bookmark)"}}}},
+		},
+		{
+			name:     "persian course name",
+			document: dtos.PdfDocument{Title: "آموزش گو"},
+			expected: true,
+		},
+		{
+			name:     "persian note among latin ones",
+			document: dtos.PdfDocument{Title: "Go basics", Sections: []dtos.PdfSection{{Heading: "Intro", Paragraphs: []string{"[00:01:10] goroutines", "[00:03:00] کانال‌ها"}}}},
+			expected: true,
+		},
+		{
+			name:     "hebrew heading",
+			document: dtos.PdfDocument{Title: "Go basics", Sections: []dtos.PdfSection{{Heading: "מבוא"}}},
+			expected: true,
+		},
+		{
+			name:     "accents and emoji stay left to right",
+			document: dtos.PdfDocument{Title: "Café 🚀", Sections: []dtos.PdfSection{{Heading: "Überblick"}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if isRightToLeft := hasRightToLeftText(test.document); isRightToLeft != test.expected {
+				t.Errorf("hasRightToLeftText = %v, want %v", isRightToLeft, test.expected)
+			}
+		})
+	}
+}
@@ -1,5 +1,19 @@
 package dtores
 
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+)
+
 type CreateOrderResDto struct {
-	PayLink string `json:"payLink"`
+	OrderID uint                 `json:"orderId"`
+	Status  entities.OrderStatus `json:"status"`
+	PayLink string               `json:"payLink"`
+}
+
+func NewCreateOrderResDto(order *entities.Order, payLink string) CreateOrderResDto {
+	return CreateOrderResDto{
+		OrderID: order.ID,
+		Status:  order.Status,
+		PayLink: payLink,
+	}
 }
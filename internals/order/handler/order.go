@@ -60,12 +60,11 @@ func (h Handler) CreateOrder(ctx *gin.Context) (*types.ApiResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	payLink, err := h.orderSvc.Create(user, *dto)
+	order, payLink, err := h.orderSvc.Create(user, *dto)
 	if err != nil {
 		return nil, err
 	}
-	res := orderDtoRes.CreateOrderResDto{PayLink: payLink}
-	return types.NewApiResponse(http.StatusCreated, res), nil
+	return types.NewApiResponse(http.StatusCreated, orderDtoRes.NewCreateOrderResDto(order, payLink)), nil
 }
 
 // GetOrdersPage godoc
@@ -12,7 +12,7 @@ import (
 )
 
 type OrderService interface {
-	Create(user *entities.User, dto orderDtoReq.CreateOrderReqDto) (string, error)
+	Create(user *entities.User, dto orderDtoReq.CreateOrderReqDto) (*entities.Order, string, error)
 	FetchPaginated(page, pageSize int) ([]*entities.Order, int, error)
 	FetchDetailById(id uint) (*entities.Order, error)
 }
@@ -26,9 +26,13 @@ func NewOrderService(unitOfWork db.UnitOfWork, paymentSvc paymentService.Payment
 	return &orderService{unitOfWork: unitOfWork, paymentSvc: paymentSvc}
 }
 
-func (svc orderService) Create(user *entities.User, dto orderDtoReq.CreateOrderReqDto) (string, error) {
+func (svc orderService) Create(user *entities.User, dto orderDtoReq.CreateOrderReqDto) (*entities.Order, string, error) {
 	const operationName = "orderService.Create"
-	return db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (string, error) {
+	type createOrderResult struct {
+		order   *entities.Order
+		payLink string
+	}
+	result, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*createOrderResult, error) {
 		carts, err := tx.CartRepo().GetAll(repositories.GetAllOptions{
 			Conditions: map[string]any{
 				"id": dto.Carts,
@@ -36,14 +40,14 @@ func (svc orderService) Create(user *entities.User, dto orderDtoReq.CreateOrderR
 			Relations: []string{"Course"},
 		})
 		if err != nil {
-			return "", types.NewServerError("Error in fetching all carts based on carts ids", operationName, err)
+			return nil, types.NewServerError("Error in fetching all carts based on carts ids", operationName, err)
 		}
 		if len(carts) != len(dto.Carts) || len(carts) == 0 {
-			return "", cartError.Cart_ListNotMatch
+			return nil, cartError.Cart_ListNotMatch
 		}
 		order := entities.NewOrder(user.ID)
 		if err := tx.OrderRepo().Create(order); err != nil {
-			return "", types.NewServerError("Error in creating order", operationName, err)
+			return nil, types.NewServerError("Error in creating order", operationName, err)
 		}
 		var totalAmount float64
 		orderItems := make([]*entities.OrderItem, len(carts))
@@ -57,12 +61,22 @@ func (svc orderService) Create(user *entities.User, dto orderDtoReq.CreateOrderR
 			totalAmount += cart.Course.Price
 		}
 		if err := tx.OrderItemRepo().BatchInsert(orderItems); err != nil {
-			return "", types.NewServerError("Error in batch insert order items", operationName, err)
+			return nil, types.NewServerError("Error in batch insert order items", operationName, err)
 		}
 		order.TotalPrice = totalAmount
 		order.FinalPrice = totalAmount
 		if err := tx.OrderRepo().Update(order); err != nil {
-			return "", types.NewServerError("Error in updating order", operationName, err)
+			return nil, types.NewServerError("Error in updating order", operationName, err)
+		}
+		if err := tx.CartRepo().BatchDelete(carts); err != nil {
+			return nil, types.NewServerError("Error in deleting carts as batching", operationName, err)
+		}
+		if order.FinalPrice == 0 {
+			if err := svc.paymentSvc.CompleteFreeOrder(tx, user.ID, order.ID); err != nil {
+				return nil, err
+			}
+			order.Status = entities.OrderStatus_Success
+			return &createOrderResult{order: order}, nil
 		}
 		payment, err := svc.paymentSvc.Create(
 			tx,
@@ -74,13 +88,14 @@ func (svc orderService) Create(user *entities.User, dto orderDtoReq.CreateOrderR
 			},
 		)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		if err := tx.CartRepo().BatchDelete(carts); err != nil {
-			return "", types.NewServerError("Error in deleting carts as batching", operationName, err)
-		}
-		return payment.PayLink, nil
+		return &createOrderResult{order: order, payLink: payment.PayLink}, nil
 	})
+	if err != nil {
+		return nil, "", err
+	}
+	return result.order, result.payLink, nil
 }
 
 func (svc orderService) FetchPaginated(page, pageSize int) ([]*entities.Order, int, error) {
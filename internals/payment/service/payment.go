@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	paymentDtoReq "github.com/ladmakhi81/learnup/internals/payment/dto/req"
 	paymentError "github.com/ladmakhi81/learnup/internals/payment/error"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
@@ -18,6 +19,7 @@ type PaymentService interface {
 	Create(tx db.UnitOfWorkTx, dto paymentDtoReq.CreatePaymentReqDto) (*entities.Payment, error)
 	Verify(dto paymentDtoReq.VerifyPaymentReqDto) error
 	FetchPageable(page, pageSize int) ([]*entities.Payment, int, error)
+	CompleteFreeOrder(tx db.UnitOfWorkTx, userID, orderID uint) error
 }
 
 type paymentService struct {
@@ -125,6 +127,15 @@ func (svc paymentService) Verify(dto paymentDtoReq.VerifyPaymentReqDto) error {
 	return nil
 }
 
+// CompleteFreeOrder finishes an order with zero final price without any gateway,
+// gateways reject zero amounts so no payment is created for it
+func (svc paymentService) CompleteFreeOrder(tx db.UnitOfWorkTx, userID, orderID uint) error {
+	if err := svc.updateSuccessOrder(tx, orderID); err != nil {
+		return err
+	}
+	return svc.createCourseParticipates(tx, userID, orderID)
+}
+
 func (svc paymentService) selectGateway(paymentGateway entities.PaymentGateway) contracts.PaymentGateway {
 	switch paymentGateway {
 	case entities.PaymentGateway_Zarinpal:
@@ -257,12 +268,30 @@ func (svc paymentService) createCourseParticipates(tx db.UnitOfWorkTx, userID, o
 	}
 	for _, item := range order.Items {
 		courseParticipant := &entities.CourseParticipant{
+			CourseID:      item.CourseID,
+			TeacherID:     *item.Course.TeacherID,
+			StudentID:     userID,
+			CourseVersion: item.Course.CurrentVersion,
+			Source:        entities.EnrollmentAction_Purchase,
+		}
+		// a purchase turns an expired or time limited seat into a lasting one
+		isEnrolled, err := tx.CourseParticipantRepo().CreateOrRenew(courseParticipant)
+		if err != nil {
+			return types.NewServerError("Error in creating course participates", operationName, err)
+		}
+		// the student already had lasting access, e.g. a free seat given before the purchase
+		if !isEnrolled {
+			continue
+		}
+		audit := &entities.EnrollmentAudit{
 			CourseID:  item.CourseID,
-			TeacherID: *item.Course.TeacherID,
 			StudentID: userID,
+			ActorID:   userID,
+			Action:    entities.EnrollmentAction_Purchase,
+			Reason:    fmt.Sprintf("order #%d", order.ID),
 		}
-		if err := tx.CourseParticipantRepo().Create(courseParticipant); err != nil {
-			return types.NewServerError("Error in creating course participates", operationName, err)
+		if err := tx.EnrollmentAuditRepo().Create(audit); err != nil {
+			return types.NewServerError("Error in creating enrollment audit", operationName, err)
 		}
 	}
 	return nil
@@ -0,0 +1,7 @@
+package dtoreq
+
+type ChangePublishStatusReqDto struct {
+	Target      PublishTarget
+	TargetID    uint
+	IsPublished bool
+}
@@ -0,0 +1,19 @@
+package dtoreq
+
+import (
+	"time"
+)
+
+type PublishTarget string
+
+const (
+	PublishTarget_Course PublishTarget = "course"
+	PublishTarget_Video  PublishTarget = "video"
+)
+
+type PublishScheduleWorkflowReqDto struct {
+	Target      PublishTarget
+	TargetID    uint
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+}
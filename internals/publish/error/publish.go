@@ -0,0 +1,11 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Publish_EmptySchedule      = types.NewBadRequestError("publish.errors.empty_schedule")
+	Publish_InvalidPublishAt   = types.NewBadRequestError("publish.errors.invalid_publish_at")
+	Publish_InvalidUnpublishAt = types.NewBadRequestError("publish.errors.invalid_unpublish_at")
+)
@@ -0,0 +1,122 @@
+package service
+
+import (
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/publish/dto/req"
+	videoError "github.com/ladmakhi81/learnup/internals/video/error"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+type PublishService interface {
+	ChangeStatus(dto dtoreq.ChangePublishStatusReqDto) error
+	CreateStatusNotification(dto dtoreq.ChangePublishStatusReqDto) error
+}
+
+type publishService struct {
+	unitOfWork db.UnitOfWork
+}
+
+func NewPublishSvc(unitOfWork db.UnitOfWork) PublishService {
+	return &publishService{unitOfWork: unitOfWork}
+}
+
+func (svc publishService) ChangeStatus(dto dtoreq.ChangePublishStatusReqDto) error {
+	const operationName = "publishService.ChangeStatus"
+	// the schedule that fired is cleared, so only the pending one stays on the row
+	fields := map[string]any{"is_published": dto.IsPublished}
+	if dto.IsPublished {
+		fields["publish_at"] = nil
+	} else {
+		fields["unpublish_at"] = nil
+	}
+	switch dto.Target {
+	case dtoreq.PublishTarget_Course:
+		course, err := svc.unitOfWork.CourseRepo().GetByID(dto.TargetID, nil)
+		if err != nil {
+			return types.NewServerError("Error in fetching course by id", operationName, err)
+		}
+		if course == nil {
+			return courseError.Course_NotFound
+		}
+		if err := svc.unitOfWork.CourseRepo().UpdateFields(course, fields); err != nil {
+			return types.NewServerError("Error in updating publish status of course", operationName, err)
+		}
+	case dtoreq.PublishTarget_Video:
+		video, err := svc.unitOfWork.VideoRepo().GetByID(dto.TargetID, nil)
+		if err != nil {
+			return types.NewServerError("Error in fetching video by id", operationName, err)
+		}
+		if video == nil {
+			return videoError.Video_NotFound
+		}
+		if err := svc.unitOfWork.VideoRepo().UpdateFields(video, fields); err != nil {
+			return types.NewServerError("Error in updating publish status of video", operationName, err)
+		}
+	}
+	return nil
+}
+
+func (svc publishService) CreateStatusNotification(dto dtoreq.ChangePublishStatusReqDto) error {
+	const operationName = "publishService.CreateStatusNotification"
+	var notification *entities.Notification
+	switch dto.Target {
+	case dtoreq.PublishTarget_Course:
+		course, err := svc.unitOfWork.CourseRepo().GetByID(dto.TargetID, nil)
+		if err != nil {
+			return types.NewServerError("Error in fetching course by id", operationName, err)
+		}
+		if course == nil {
+			return courseError.Course_NotFound
+		}
+		notificationType := entities.NotificationType_CoursePublished
+		if !dto.IsPublished {
+			notificationType = entities.NotificationType_CourseUnpublished
+		}
+		notification = &entities.Notification{
+			Type: entities.NotificationType(notificationType),
+			Metadata: map[string]any{
+				"courseId":   course.ID,
+				"courseName": course.Name,
+			},
+			UserID: course.TeacherID,
+		}
+	case dtoreq.PublishTarget_Video:
+		video, err := svc.unitOfWork.VideoRepo().GetByID(dto.TargetID, nil)
+		if err != nil {
+			return types.NewServerError("Error in fetching video by id", operationName, err)
+		}
+		if video == nil {
+			return videoError.Video_NotFound
+		}
+		course, err := svc.unitOfWork.CourseRepo().GetByVideoID(video.ID)
+		if err != nil {
+			return types.NewServerError("Error in fetching course by video id", operationName, err)
+		}
+		if course == nil {
+			return courseError.Course_NotFound
+		}
+		notificationType := entities.NotificationType_VideoPublished
+		if !dto.IsPublished {
+			notificationType = entities.NotificationType_VideoUnpublished
+		}
+		notification = &entities.Notification{
+			Type: entities.NotificationType(notificationType),
+			Metadata: map[string]any{
+				"videoId":     video.ID,
+				"videoTitle":  video.Title,
+				"courseId":    course.ID,
+				"courseTitle": course.Name,
+			},
+			UserID: course.TeacherID,
+		}
+	}
+	if notification == nil {
+		return nil
+	}
+	if err := svc.unitOfWork.NotificationRepo().Create(notification); err != nil {
+		return types.NewServerError("Error in creating notification", operationName, err)
+	}
+	return nil
+}
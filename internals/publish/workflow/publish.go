@@ -0,0 +1,77 @@
+package workflow
+
+import (
+	"fmt"
+	dtoreq "github.com/ladmakhi81/learnup/internals/publish/dto/req"
+	publishService "github.com/ladmakhi81/learnup/internals/publish/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"go.temporal.io/sdk/workflow"
+	"time"
+)
+
+type PublishWorkflow interface {
+	PublishScheduleWorkflow(ctx workflow.Context, dto dtoreq.PublishScheduleWorkflowReqDto) error
+}
+
+type PublishWorkflowImpl struct {
+	publishSvc  publishService.PublishService
+	temporalSvc contracts.Temporal
+}
+
+func NewPublishWorkflowImpl(
+	publishSvc publishService.PublishService,
+	temporalSvc contracts.Temporal,
+) *PublishWorkflowImpl {
+	return &PublishWorkflowImpl{
+		publishSvc:  publishSvc,
+		temporalSvc: temporalSvc,
+	}
+}
+
+// PublishScheduleWorkflowID is stable per course or video, so a new schedule
+// replaces the running one and cancelling does not need any stored run id.
+func PublishScheduleWorkflowID(target dtoreq.PublishTarget, targetID uint) string {
+	return fmt.Sprintf("publish-schedule-%s-%d", target, targetID)
+}
+
+func (svc PublishWorkflowImpl) PublishScheduleWorkflow(ctx workflow.Context, dto dtoreq.PublishScheduleWorkflowReqDto) error {
+	// publish
+	if dto.PublishAt != nil {
+		if err := svc.changeStatusAt(ctx, dto, *dto.PublishAt, true); err != nil {
+			return err
+		}
+	}
+	// unpublish
+	if dto.UnpublishAt != nil {
+		if err := svc.changeStatusAt(ctx, dto, *dto.UnpublishAt, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc PublishWorkflowImpl) changeStatusAt(ctx workflow.Context, dto dtoreq.PublishScheduleWorkflowReqDto, at time.Time, isPublished bool) error {
+	// durable timer, it survives worker restarts and returns a canceled error when the schedule is cancelled
+	if err := workflow.Sleep(ctx, timerDuration(workflow.Now(ctx), at)); err != nil {
+		return err
+	}
+	changeStatusDto := dtoreq.ChangePublishStatusReqDto{
+		Target:      dto.Target,
+		TargetID:    dto.TargetID,
+		IsPublished: isPublished,
+	}
+	if err := svc.temporalSvc.ExecuteTask(ctx, svc.publishSvc.ChangeStatus, changeStatusDto, nil); err != nil {
+		return err
+	}
+	// teacher notification
+	if err := svc.temporalSvc.ExecuteTask(ctx, svc.publishSvc.CreateStatusNotification, changeStatusDto, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// timerDuration never goes negative, a time that passed while the workflow was queued or the
+// worker was down fires right away instead of failing the sleep and leaving the content hidden
+func timerDuration(now time.Time, at time.Time) time.Duration {
+	return max(0, at.Sub(now))
+}
@@ -0,0 +1,27 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerDuration(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected time.Duration
+	}{
+		{name: "future time", at: now.Add(90 * time.Minute), expected: 90 * time.Minute},
+		{name: "right now", at: now, expected: 0},
+		{name: "passed while the workflow was queued", at: now.Add(-time.Second), expected: 0},
+		{name: "passed while the worker was down", at: now.Add(-48 * time.Hour), expected: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if duration := timerDuration(now, test.at); duration != test.expected {
+				t.Errorf("timerDuration = %v, want %v", duration, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package dtoreq
+
+type SubmitQuizAnswerReqDto struct {
+	QuestionID      uint   `json:"questionId" validate:"required,gte=1"`
+	SelectedOptions []int  `json:"selectedOptions" validate:"max=10,dive,gte=0"`
+	TextAnswer      string `json:"textAnswer" validate:"max=1000"`
+}
+
+// SubmitQuizAttemptReqDto grades unanswered questions of the attempt as wrong
+type SubmitQuizAttemptReqDto struct {
+	AttemptID uint                     `json:"-"`
+	Answers   []SubmitQuizAnswerReqDto `json:"answers" validate:"max=200,dive"`
+}
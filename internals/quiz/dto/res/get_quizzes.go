@@ -0,0 +1,51 @@
+package dtores
+
+import (
+	quizService "github.com/ladmakhi81/learnup/internals/quiz/service"
+)
+
+type GetQuizItemDto struct {
+	ID                uint     `json:"id"`
+	VideoID           *uint    `json:"videoId"`
+	VideoTitle        *string  `json:"videoTitle"`
+	Title             string   `json:"title"`
+	Description       string   `json:"description"`
+	TimeLimit         uint     `json:"timeLimit"`
+	MaxAttempts       uint     `json:"maxAttempts"`
+	PassingScore      float64  `json:"passingScore"`
+	Attempts          int      `json:"attempts"`
+	RemainingAttempts *int     `json:"remainingAttempts"`
+	BestPercentage    *float64 `json:"bestPercentage"`
+	IsPassed          bool     `json:"isPassed"`
+}
+
+// MapGetQuizItemsDto leaves the remaining attempts empty when the quiz has no attempt limit
+func MapGetQuizItemsDto(quizzes []*quizService.ParticipantQuiz) []*GetQuizItemDto {
+	result := make([]*GetQuizItemDto, len(quizzes))
+	for index, participantQuiz := range quizzes {
+		quiz := participantQuiz.Quiz
+		item := &GetQuizItemDto{
+			ID:           quiz.ID,
+			VideoID:      quiz.VideoID,
+			Title:        quiz.Title,
+			Description:  quiz.Description,
+			TimeLimit:    quiz.TimeLimit,
+			MaxAttempts:  quiz.MaxAttempts,
+			PassingScore: quiz.PassingScore,
+		}
+		if quiz.Video != nil {
+			item.VideoTitle = &quiz.Video.Title
+		}
+		if summary := participantQuiz.Summary; summary != nil {
+			item.Attempts = summary.Attempts
+			item.BestPercentage = &summary.BestPercentage
+			item.IsPassed = summary.IsPassed
+		}
+		if quiz.MaxAttempts > 0 {
+			remainingAttempts := max(int(quiz.MaxAttempts)-item.Attempts, 0)
+			item.RemainingAttempts = &remainingAttempts
+		}
+		result[index] = item
+	}
+	return result
+}
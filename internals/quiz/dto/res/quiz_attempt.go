@@ -0,0 +1,87 @@
+package dtores
+
+import (
+	quizService "github.com/ladmakhi81/learnup/internals/quiz/service"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+// quizAnswerDto is only sent once the attempt is over, the answer key stays empty until the
+// service allows revealing it
+type quizAnswerDto struct {
+	SelectedOptions []int    `json:"selectedOptions"`
+	TextAnswer      string   `json:"textAnswer"`
+	IsCorrect       bool     `json:"isCorrect"`
+	Points          float64  `json:"points"`
+	CorrectOptions  []int    `json:"correctOptions"`
+	AcceptedAnswers []string `json:"acceptedAnswers"`
+}
+
+type quizAttemptQuestionDto struct {
+	ID      uint                      `json:"id"`
+	Type    entities.QuizQuestionType `json:"type"`
+	Text    string                    `json:"text"`
+	Options []string                  `json:"options"`
+	Points  float64                   `json:"points"`
+	Answer  *quizAnswerDto            `json:"answer"`
+}
+
+type QuizAttemptResDto struct {
+	ID          uint                       `json:"id"`
+	QuizID      uint                       `json:"quizId"`
+	Number      uint                       `json:"number"`
+	Status      entities.QuizAttemptStatus `json:"status"`
+	StartedAt   time.Time                  `json:"startedAt"`
+	ExpiresAt   *time.Time                 `json:"expiresAt"`
+	SubmittedAt *time.Time                 `json:"submittedAt"`
+	Score       float64                    `json:"score"`
+	MaxScore    float64                    `json:"maxScore"`
+	Percentage  float64                    `json:"percentage"`
+	IsPassed    bool                       `json:"isPassed"`
+	Questions   []*quizAttemptQuestionDto  `json:"questions"`
+}
+
+func NewQuizAttemptResDto(session *quizService.QuizAttemptSession) *QuizAttemptResDto {
+	attempt := session.Attempt
+	res := &QuizAttemptResDto{
+		ID:          attempt.ID,
+		QuizID:      attempt.QuizID,
+		Number:      attempt.Number,
+		Status:      attempt.Status,
+		StartedAt:   attempt.StartedAt,
+		ExpiresAt:   attempt.ExpiresAt,
+		SubmittedAt: attempt.SubmittedAt,
+		Score:       attempt.Score,
+		MaxScore:    attempt.MaxScore,
+		Percentage:  attempt.Percentage,
+		IsPassed:    attempt.IsPassed,
+		Questions:   make([]*quizAttemptQuestionDto, len(session.Questions)),
+	}
+	answers := make(map[uint]*entities.QuizAnswer, len(attempt.Answers))
+	for _, answer := range attempt.Answers {
+		answers[answer.QuestionID] = answer
+	}
+	for index, question := range session.Questions {
+		questionDto := &quizAttemptQuestionDto{
+			ID:      question.ID,
+			Type:    question.Type,
+			Text:    question.Text,
+			Options: question.Options,
+			Points:  question.Points,
+		}
+		if answer, isAnswered := answers[question.ID]; isAnswered {
+			questionDto.Answer = &quizAnswerDto{
+				SelectedOptions: answer.SelectedOptions,
+				TextAnswer:      answer.TextAnswer,
+				IsCorrect:       answer.IsCorrect,
+				Points:          answer.Points,
+			}
+			if session.RevealAnswers {
+				questionDto.Answer.CorrectOptions = question.CorrectOptions
+				questionDto.Answer.AcceptedAnswers = question.AcceptedAnswers
+			}
+		}
+		res.Questions[index] = questionDto
+	}
+	return res
+}
@@ -0,0 +1,20 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Quiz_NotFound                = types.NewNotFoundError("quiz.errors.not_found")
+	Quiz_QuestionNotFound        = types.NewNotFoundError("quiz.errors.question_not_found")
+	Quiz_AttemptNotFound         = types.NewNotFoundError("quiz.errors.attempt_not_found")
+	Quiz_VideoNotInCourse        = types.NewBadRequestError("quiz.errors.video_not_in_course")
+	Quiz_InvalidOptions          = types.NewBadRequestError("quiz.errors.invalid_options")
+	Quiz_InvalidCorrectOptions   = types.NewBadRequestError("quiz.errors.invalid_correct_options")
+	Quiz_AcceptedAnswersRequired = types.NewBadRequestError("quiz.errors.accepted_answers_required")
+	Quiz_NoQuestions             = types.NewBadRequestError("quiz.errors.no_questions")
+	Quiz_AttemptLimitReached     = types.NewBadRequestError("quiz.errors.attempt_limit_reached")
+	Quiz_AttemptExpired          = types.NewBadRequestError("quiz.errors.attempt_expired")
+	Quiz_AttemptAlreadySubmitted = types.NewConflictError("quiz.errors.attempt_already_submitted")
+	Quiz_UnknownQuestion         = types.NewBadRequestError("quiz.errors.unknown_question")
+)
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/quiz/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/quiz/dto/res"
+	quizService "github.com/ladmakhi81/learnup/internals/quiz/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type Handler struct {
+	quizSvc        quizService.QuizService
+	userSvc        userService.UserSvc
+	validationSvc  contracts.Validation
+	translationSvc contracts.Translator
+}
+
+func NewHandler(
+	quizSvc quizService.QuizService,
+	userSvc userService.UserSvc,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+) *Handler {
+	return &Handler{
+		quizSvc:        quizSvc,
+		userSvc:        userSvc,
+		validationSvc:  validationSvc,
+		translationSvc: translationSvc,
+	}
+}
+
+// GetCourseQuizzes godoc
+//
+//	@Summary	Get quizzes of an enrolled course with the attempts and best score of the user
+//	@Tags		quizzes
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.GetQuizItemDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/quizzes/courses/{course-id} [get]
+//	@Security	BearerAuth
+func (h Handler) GetCourseQuizzes(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quizzes, err := h.quizSvc.GetCourseQuizzes(user, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapGetQuizItemsDto(quizzes)), nil
+}
+
+// StartAttempt godoc
+//
+//	@Summary	Start an attempt of a quiz, an attempt in progress is resumed instead
+//	@Tags		quizzes
+//	@Produce	json
+//	@Param		quiz-id	path		int	true	"Quiz ID"
+//	@Success	200		{object}	types.ApiResponse{data=dtores.QuizAttemptResDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	403		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/quizzes/{quiz-id}/attempts [post]
+//	@Security	BearerAuth
+func (h Handler) StartAttempt(ctx *gin.Context) (*types.ApiResponse, error) {
+	quizID, err := utils.ToUint(ctx.Param("quiz-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := h.quizSvc.StartAttempt(user, quizID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewQuizAttemptResDto(session)), nil
+}
+
+// SubmitAttempt godoc
+//
+//	@Summary	Submit the answers of an attempt and get it graded
+//	@Tags		quizzes
+//	@Accept		json
+//	@Produce	json
+//	@Param		attempt-id	path		int								true	"Attempt ID"
+//	@Param		request		body		dtoreq.SubmitQuizAttemptReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse{data=dtores.QuizAttemptResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/quizzes/attempts/{attempt-id}/submit [post]
+//	@Security	BearerAuth
+func (h Handler) SubmitAttempt(ctx *gin.Context) (*types.ApiResponse, error) {
+	attemptID, err := utils.ToUint(ctx.Param("attempt-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_attempt_id"),
+		)
+	}
+	dto := &dtoreq.SubmitQuizAttemptReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.AttemptID = attemptID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := h.quizSvc.SubmitAttempt(user, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewQuizAttemptResDto(session)), nil
+}
+
+// GetAttempt godoc
+//
+//	@Summary	Get an attempt, answers are included once it is over and the answer key once the quiz is passed or out of attempts
+//	@Tags		quizzes
+//	@Produce	json
+//	@Param		attempt-id	path		int	true	"Attempt ID"
+//	@Success	200			{object}	types.ApiResponse{data=dtores.QuizAttemptResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/quizzes/attempts/{attempt-id} [get]
+//	@Security	BearerAuth
+func (h Handler) GetAttempt(ctx *gin.Context) (*types.ApiResponse, error) {
+	attemptID, err := utils.ToUint(ctx.Param("attempt-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_attempt_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := h.quizSvc.GetAttempt(user, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewQuizAttemptResDto(session)), nil
+}
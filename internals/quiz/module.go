@@ -0,0 +1,40 @@
+package quiz
+
+import (
+	"github.com/gin-gonic/gin"
+	quizHandler "github.com/ladmakhi81/learnup/internals/quiz/handler"
+	quizService "github.com/ladmakhi81/learnup/internals/quiz/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/middleware"
+	"github.com/ladmakhi81/learnup/shared/utils"
+)
+
+type Module struct {
+	handler        *quizHandler.Handler
+	middleware     *middleware.Middleware
+	translationSvc contracts.Translator
+}
+
+func NewModule(
+	quizSvc quizService.QuizService,
+	userSvc userService.UserSvc,
+	validationSvc contracts.Validation,
+	middleware *middleware.Middleware,
+	translationSvc contracts.Translator,
+) *Module {
+	return &Module{
+		middleware:     middleware,
+		translationSvc: translationSvc,
+		handler:        quizHandler.NewHandler(quizSvc, userSvc, validationSvc, translationSvc),
+	}
+}
+
+func (m *Module) Register(api *gin.RouterGroup) {
+	quizzesApi := api.Group("/quizzes")
+	quizzesApi.Use(m.middleware.CheckAccessToken())
+	quizzesApi.GET("/courses/:course-id", utils.JsonHandler(m.translationSvc, m.handler.GetCourseQuizzes))
+	quizzesApi.POST("/:quiz-id/attempts", utils.JsonHandler(m.translationSvc, m.handler.StartAttempt))
+	quizzesApi.POST("/attempts/:attempt-id/submit", utils.JsonHandler(m.translationSvc, m.handler.SubmitAttempt))
+	quizzesApi.GET("/attempts/:attempt-id", utils.JsonHandler(m.translationSvc, m.handler.GetAttempt))
+}
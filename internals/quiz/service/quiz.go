@@ -0,0 +1,369 @@
+package service
+
+import (
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/quiz/dto/req"
+	quizError "github.com/ladmakhi81/learnup/internals/quiz/error"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// submissions sent right at the deadline are still accepted
+const submitGracePeriod = 30 * time.Second
+
+// ParticipantQuiz is a quiz as a participant sees it, Summary is nil before the first attempt
+type ParticipantQuiz struct {
+	Quiz    *entities.Quiz
+	Summary *repositories.QuizAttemptSummary
+}
+
+// QuizAttemptSession holds the questions of an attempt in the order they were handed out,
+// RevealAnswers tells whether the answer key may be shown with them
+type QuizAttemptSession struct {
+	Attempt       *entities.QuizAttempt
+	Questions     []*entities.QuizQuestion
+	RevealAnswers bool
+}
+
+type QuizService interface {
+	GetCourseQuizzes(user *entities.User, courseID uint) ([]*ParticipantQuiz, error)
+	StartAttempt(user *entities.User, quizID uint) (*QuizAttemptSession, error)
+	SubmitAttempt(user *entities.User, dto dtoreq.SubmitQuizAttemptReqDto) (*QuizAttemptSession, error)
+	GetAttempt(user *entities.User, attemptID uint) (*QuizAttemptSession, error)
+}
+
+type quizService struct {
+	unitOfWork db.UnitOfWork
+}
+
+func NewQuizSvc(unitOfWork db.UnitOfWork) QuizService {
+	return &quizService{
+		unitOfWork: unitOfWork,
+	}
+}
+
+func (svc quizService) GetCourseQuizzes(user *entities.User, courseID uint) ([]*ParticipantQuiz, error) {
+	const operationName = "quizService.GetCourseQuizzes"
+	if err := svc.checkParticipant(user, courseID); err != nil {
+		return nil, err
+	}
+	quizzes, err := svc.unitOfWork.QuizRepo().GetAllByCourseID(courseID, true)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching quizzes of course", operationName, err)
+	}
+	visibleQuizzes := make([]*entities.Quiz, 0, len(quizzes))
+	quizIDs := make([]uint, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		if isQuizVisible(quiz) {
+			visibleQuizzes = append(visibleQuizzes, quiz)
+			quizIDs = append(quizIDs, quiz.ID)
+		}
+	}
+	summaries, err := svc.unitOfWork.QuizAttemptRepo().GetSummaries(user.ID, quizIDs)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching attempts of quizzes", operationName, err)
+	}
+	result := make([]*ParticipantQuiz, len(visibleQuizzes))
+	for index, quiz := range visibleQuizzes {
+		result[index] = &ParticipantQuiz{
+			Quiz:    quiz,
+			Summary: summaries[quiz.ID],
+		}
+	}
+	return result, nil
+}
+
+// StartAttempt resumes the attempt in progress if there is one, so reloading the page doesn't
+// use up another attempt. An attempt left past its time limit is closed before a new one starts
+func (svc quizService) StartAttempt(user *entities.User, quizID uint) (*QuizAttemptSession, error) {
+	const operationName = "quizService.StartAttempt"
+	quiz, err := svc.unitOfWork.QuizRepo().GetByID(quizID, []string{"Video"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching quiz by id", operationName, err)
+	}
+	if quiz == nil || !quiz.IsPublished || !isQuizVisible(quiz) {
+		return nil, quizError.Quiz_NotFound
+	}
+	if err := svc.checkParticipant(user, quiz.CourseID); err != nil {
+		return nil, err
+	}
+	inProgress, err := svc.unitOfWork.QuizAttemptRepo().GetOne(map[string]any{
+		"quiz_id": quiz.ID,
+		"user_id": user.ID,
+		"status":  entities.QuizAttemptStatus_InProgress,
+	}, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching attempt in progress", operationName, err)
+	}
+	if inProgress != nil {
+		if !inProgress.IsExpired(0) {
+			return svc.attemptSession(inProgress)
+		}
+		if err := svc.expireAttempt(inProgress); err != nil {
+			return nil, err
+		}
+	}
+	attempts, err := svc.unitOfWork.QuizAttemptRepo().CountByUserID(quiz.ID, user.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in counting attempts of quiz", operationName, err)
+	}
+	if quiz.MaxAttempts > 0 && uint(attempts) >= quiz.MaxAttempts {
+		return nil, quizError.Quiz_AttemptLimitReached
+	}
+	questions, err := svc.unitOfWork.QuizQuestionRepo().GetAllByQuizID(quiz.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching questions of quiz", operationName, err)
+	}
+	if len(questions) == 0 {
+		return nil, quizError.Quiz_NoQuestions
+	}
+	if quiz.ShuffleQuestions {
+		rand.Shuffle(len(questions), func(i, j int) {
+			questions[i], questions[j] = questions[j], questions[i]
+		})
+	}
+	now := time.Now()
+	attempt := &entities.QuizAttempt{
+		QuizID:        quiz.ID,
+		UserID:        user.ID,
+		CourseID:      quiz.CourseID,
+		Number:        uint(attempts) + 1,
+		Status:        entities.QuizAttemptStatus_InProgress,
+		QuestionOrder: make([]uint, len(questions)),
+		StartedAt:     now,
+	}
+	for index, question := range questions {
+		attempt.QuestionOrder[index] = question.ID
+		attempt.MaxScore += question.Points
+	}
+	if quiz.TimeLimit > 0 {
+		expiresAt := now.Add(time.Duration(quiz.TimeLimit) * time.Second)
+		attempt.ExpiresAt = &expiresAt
+	}
+	isCreated, err := svc.unitOfWork.QuizAttemptRepo().CreateIfNotExists(attempt)
+	if err != nil {
+		return nil, types.NewServerError("Error in creating quiz attempt", operationName, err)
+	}
+	if !isCreated {
+		// a concurrent start took this attempt number first
+		return nil, quizError.Quiz_AttemptLimitReached
+	}
+	return &QuizAttemptSession{Attempt: attempt, Questions: questions}, nil
+}
+
+// SubmitAttempt grades the answers all or nothing per question, questions deleted by the teacher
+// since the attempt started are left out of the score
+func (svc quizService) SubmitAttempt(user *entities.User, dto dtoreq.SubmitQuizAttemptReqDto) (*QuizAttemptSession, error) {
+	const operationName = "quizService.SubmitAttempt"
+	attempt, err := svc.getOwnAttempt(user, dto.AttemptID)
+	if err != nil {
+		return nil, err
+	}
+	switch attempt.Status {
+	case entities.QuizAttemptStatus_Submitted:
+		return nil, quizError.Quiz_AttemptAlreadySubmitted
+	case entities.QuizAttemptStatus_Expired:
+		return nil, quizError.Quiz_AttemptExpired
+	}
+	if attempt.IsExpired(submitGracePeriod) {
+		if err := svc.expireAttempt(attempt); err != nil {
+			return nil, err
+		}
+		return nil, quizError.Quiz_AttemptExpired
+	}
+	if err := svc.checkParticipant(user, attempt.CourseID); err != nil {
+		return nil, err
+	}
+	session, err := svc.attemptSession(attempt)
+	if err != nil {
+		return nil, err
+	}
+	submittedAnswers := make(map[uint]dtoreq.SubmitQuizAnswerReqDto, len(dto.Answers))
+	for _, answer := range dto.Answers {
+		submittedAnswers[answer.QuestionID] = answer
+	}
+	for questionID := range submittedAnswers {
+		if !containsQuestion(session.Questions, questionID) {
+			return nil, quizError.Quiz_UnknownQuestion
+		}
+	}
+	attempt.Score = 0
+	attempt.MaxScore = 0
+	answers := make([]*entities.QuizAnswer, len(session.Questions))
+	for index, question := range session.Questions {
+		submitted := submittedAnswers[question.ID]
+		answer := &entities.QuizAnswer{
+			AttemptID:       attempt.ID,
+			QuestionID:      question.ID,
+			QuizID:          attempt.QuizID,
+			SelectedOptions: []int{},
+		}
+		if question.Type == entities.QuizQuestionType_ShortAnswer {
+			answer.TextAnswer = submitted.TextAnswer
+		} else if submitted.SelectedOptions != nil {
+			answer.SelectedOptions = submitted.SelectedOptions
+		}
+		answer.IsCorrect = question.IsCorrect(answer.SelectedOptions, answer.TextAnswer)
+		if answer.IsCorrect {
+			answer.Points = question.Points
+		}
+		attempt.Score += answer.Points
+		attempt.MaxScore += question.Points
+		answers[index] = answer
+	}
+	submittedAt := time.Now()
+	attempt.Status = entities.QuizAttemptStatus_Submitted
+	attempt.SubmittedAt = &submittedAt
+	attempt.Percentage = 0
+	if attempt.MaxScore > 0 {
+		attempt.Percentage = math.Round(attempt.Score*10000/attempt.MaxScore) / 100
+	}
+	attempt.IsPassed = attempt.Percentage >= attempt.Quiz.PassingScore
+	if _, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		if len(answers) > 0 {
+			if err := tx.QuizAnswerRepo().BatchInsert(answers); err != nil {
+				return nil, err
+			}
+		}
+		return nil, tx.QuizAttemptRepo().UpdateFields(attempt, map[string]any{
+			"status":       attempt.Status,
+			"submitted_at": attempt.SubmittedAt,
+			"score":        attempt.Score,
+			"max_score":    attempt.MaxScore,
+			"percentage":   attempt.Percentage,
+			"is_passed":    attempt.IsPassed,
+		})
+	}); err != nil {
+		return nil, types.NewServerError("Error in submitting quiz attempt", operationName, err)
+	}
+	attempt.Answers = answers
+	if session.RevealAnswers, err = svc.canRevealAnswers(attempt); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (svc quizService) GetAttempt(user *entities.User, attemptID uint) (*QuizAttemptSession, error) {
+	const operationName = "quizService.GetAttempt"
+	attempt, err := svc.getOwnAttempt(user, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != entities.QuizAttemptStatus_InProgress {
+		if attempt.Answers, err = svc.unitOfWork.QuizAnswerRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"attempt_id": attempt.ID},
+		}); err != nil {
+			return nil, types.NewServerError("Error in fetching answers of attempt", operationName, err)
+		}
+	}
+	session, err := svc.attemptSession(attempt)
+	if err != nil {
+		return nil, err
+	}
+	if session.RevealAnswers, err = svc.canRevealAnswers(attempt); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (svc quizService) getOwnAttempt(user *entities.User, attemptID uint) (*entities.QuizAttempt, error) {
+	const operationName = "quizService.getOwnAttempt"
+	attempt, err := svc.unitOfWork.QuizAttemptRepo().GetOne(
+		map[string]any{"id": attemptID, "user_id": user.ID},
+		[]string{"Quiz"},
+	)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching quiz attempt by id", operationName, err)
+	}
+	if attempt == nil || attempt.Quiz == nil {
+		return nil, quizError.Quiz_AttemptNotFound
+	}
+	return attempt, nil
+}
+
+// attemptSession loads the questions handed out by the attempt in their original order
+func (svc quizService) attemptSession(attempt *entities.QuizAttempt) (*QuizAttemptSession, error) {
+	const operationName = "quizService.attemptSession"
+	questions, err := svc.unitOfWork.QuizQuestionRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"id": attempt.QuestionOrder},
+	})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching questions of attempt", operationName, err)
+	}
+	questionsByID := make(map[uint]*entities.QuizQuestion, len(questions))
+	for _, question := range questions {
+		questionsByID[question.ID] = question
+	}
+	session := &QuizAttemptSession{
+		Attempt:   attempt,
+		Questions: make([]*entities.QuizQuestion, 0, len(attempt.QuestionOrder)),
+	}
+	for _, questionID := range attempt.QuestionOrder {
+		if question, isFound := questionsByID[questionID]; isFound {
+			session.Questions = append(session.Questions, question)
+		}
+	}
+	return session, nil
+}
+
+// canRevealAnswers keeps the answer key hidden while it could still be used in another attempt,
+// it is shown once the student passed the quiz or used up every attempt
+func (svc quizService) canRevealAnswers(attempt *entities.QuizAttempt) (bool, error) {
+	const operationName = "quizService.canRevealAnswers"
+	if attempt.Status == entities.QuizAttemptStatus_InProgress || attempt.Quiz == nil {
+		return false, nil
+	}
+	summaries, err := svc.unitOfWork.QuizAttemptRepo().GetSummaries(attempt.UserID, []uint{attempt.QuizID})
+	if err != nil {
+		return false, types.NewServerError("Error in fetching attempts of quiz", operationName, err)
+	}
+	summary := summaries[attempt.QuizID]
+	if summary == nil {
+		return false, nil
+	}
+	maxAttempts := attempt.Quiz.MaxAttempts
+	return summary.IsPassed || (maxAttempts > 0 && uint(summary.Attempts) >= maxAttempts), nil
+}
+
+// expireAttempt closes an attempt left past its time limit, it still counts against the attempt limit
+func (svc quizService) expireAttempt(attempt *entities.QuizAttempt) error {
+	const operationName = "quizService.expireAttempt"
+	attempt.Status = entities.QuizAttemptStatus_Expired
+	if err := svc.unitOfWork.QuizAttemptRepo().UpdateFields(attempt, map[string]any{
+		"status": attempt.Status,
+	}); err != nil {
+		return types.NewServerError("Error in expiring quiz attempt", operationName, err)
+	}
+	return nil
+}
+
+func (svc quizService) checkParticipant(user *entities.User, courseID uint) error {
+	const operationName = "quizService.checkParticipant"
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(courseID, user.ID)
+	if err != nil {
+		return types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	if participant == nil || !participant.IsActive() {
+		return courseError.Course_NotParticipant
+	}
+	return nil
+}
+
+// isQuizVisible hides quizzes of lessons students can't see yet
+func isQuizVisible(quiz *entities.Quiz) bool {
+	return quiz.Video == nil || (quiz.Video.IsPublished && quiz.Video.IsVerified)
+}
+
+func containsQuestion(questions []*entities.QuizQuestion, questionID uint) bool {
+	for _, question := range questions {
+		if question.ID == questionID {
+			return true
+		}
+	}
+	return false
+}
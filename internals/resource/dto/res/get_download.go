@@ -0,0 +1,18 @@
+package dtores
+
+import (
+	resourceService "github.com/ladmakhi81/learnup/internals/resource/service"
+	"time"
+)
+
+type GetResourceDownloadResDto struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func NewGetResourceDownloadResDto(download *resourceService.ResourceDownload) GetResourceDownloadResDto {
+	return GetResourceDownloadResDto{
+		URL:       download.URL,
+		ExpiresAt: download.ExpiresAt,
+	}
+}
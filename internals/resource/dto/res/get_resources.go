@@ -0,0 +1,47 @@
+package dtores
+
+import (
+	resourceService "github.com/ladmakhi81/learnup/internals/resource/service"
+	"time"
+)
+
+type getResourceVideoItem struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+type GetResourceItemDto struct {
+	ID              uint                  `json:"id"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	CourseID        uint                  `json:"courseId"`
+	Video           *getResourceVideoItem `json:"video"`
+	Title           string                `json:"title"`
+	FileName        string                `json:"fileName"`
+	ContentType     string                `json:"contentType"`
+	Size            int64                 `json:"size"`
+	MyDownloadCount int                   `json:"myDownloadCount"`
+}
+
+func MapGetResourceItemsDto(resources []*resourceService.ParticipantResource) []*GetResourceItemDto {
+	result := make([]*GetResourceItemDto, len(resources))
+	for index, item := range resources {
+		resource := item.Resource
+		result[index] = &GetResourceItemDto{
+			ID:              resource.ID,
+			CreatedAt:       resource.CreatedAt,
+			CourseID:        resource.CourseID,
+			Title:           resource.Title,
+			FileName:        resource.FileName,
+			ContentType:     resource.ContentType,
+			Size:            resource.Size,
+			MyDownloadCount: item.DownloadCount,
+		}
+		if resource.Video != nil {
+			result[index].Video = &getResourceVideoItem{
+				ID:    resource.Video.ID,
+				Title: resource.Video.Title,
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Resource_NotFound         = types.NewNotFoundError("resource.errors.not_found")
+	Resource_FileRequired     = types.NewBadRequestError("resource.errors.file_required")
+	Resource_FileTooLarge     = types.NewBadRequestError("resource.errors.file_too_large")
+	Resource_InvalidFileType  = types.NewBadRequestError("resource.errors.invalid_file_type")
+	Resource_Infected         = types.NewBadRequestError("resource.errors.infected")
+	Resource_VideoNotInCourse = types.NewBadRequestError("resource.errors.video_not_in_course")
+)
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtores "github.com/ladmakhi81/learnup/internals/resource/dto/res"
+	resourceService "github.com/ladmakhi81/learnup/internals/resource/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type Handler struct {
+	resourceSvc    resourceService.ResourceService
+	userSvc        userService.UserSvc
+	translationSvc contracts.Translator
+}
+
+func NewHandler(
+	resourceSvc resourceService.ResourceService,
+	userSvc userService.UserSvc,
+	translationSvc contracts.Translator,
+) *Handler {
+	return &Handler{
+		resourceSvc:    resourceSvc,
+		userSvc:        userSvc,
+		translationSvc: translationSvc,
+	}
+}
+
+// GetCourseResources godoc
+//
+//	@Summary	Get downloadable resources of a course and its lessons
+//	@Tags		resources
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.GetResourceItemDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/resources/courses/{course-id} [get]
+//	@Security	BearerAuth
+func (h Handler) GetCourseResources(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := h.resourceSvc.GetCourseResources(user, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapGetResourceItemsDto(resources)), nil
+}
+
+// GetVideoResources godoc
+//
+//	@Summary	Get downloadable resources of a lesson
+//	@Tags		resources
+//	@Produce	json
+//	@Param		video-id	path		int	true	"Video ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.GetResourceItemDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/resources/videos/{video-id} [get]
+//	@Security	BearerAuth
+func (h Handler) GetVideoResources(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := h.resourceSvc.GetVideoResources(user, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapGetResourceItemsDto(resources)), nil
+}
+
+// DownloadResource godoc
+//
+//	@Summary	Get a short lived download url of a resource
+//	@Tags		resources
+//	@Produce	json
+//	@Param		resource-id	path		int	true	"Resource ID"
+//	@Success	200			{object}	types.ApiResponse{data=dtores.GetResourceDownloadResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/resources/{resource-id}/download [get]
+//	@Security	BearerAuth
+func (h Handler) DownloadResource(ctx *gin.Context) (*types.ApiResponse, error) {
+	resourceID, err := utils.ToUint(ctx.Param("resource-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("resource.errors.invalid_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	download, err := h.resourceSvc.Download(ctx, user, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetResourceDownloadResDto(download)), nil
+}
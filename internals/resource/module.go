@@ -0,0 +1,38 @@
+package resource
+
+import (
+	"github.com/gin-gonic/gin"
+	resourceHandler "github.com/ladmakhi81/learnup/internals/resource/handler"
+	resourceService "github.com/ladmakhi81/learnup/internals/resource/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/middleware"
+	"github.com/ladmakhi81/learnup/shared/utils"
+)
+
+type Module struct {
+	handler        *resourceHandler.Handler
+	middleware     *middleware.Middleware
+	translationSvc contracts.Translator
+}
+
+func NewModule(
+	resourceSvc resourceService.ResourceService,
+	userSvc userService.UserSvc,
+	middleware *middleware.Middleware,
+	translationSvc contracts.Translator,
+) *Module {
+	return &Module{
+		middleware:     middleware,
+		translationSvc: translationSvc,
+		handler:        resourceHandler.NewHandler(resourceSvc, userSvc, translationSvc),
+	}
+}
+
+func (m *Module) Register(api *gin.RouterGroup) {
+	resourcesApi := api.Group("/resources")
+	resourcesApi.Use(m.middleware.CheckAccessToken())
+	resourcesApi.GET("/courses/:course-id", utils.JsonHandler(m.translationSvc, m.handler.GetCourseResources))
+	resourcesApi.GET("/videos/:video-id", utils.JsonHandler(m.translationSvc, m.handler.GetVideoResources))
+	resourcesApi.GET("/:resource-id/download", utils.JsonHandler(m.translationSvc, m.handler.DownloadResource))
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	resourceError "github.com/ladmakhi81/learnup/internals/resource/error"
+	videoError "github.com/ladmakhi81/learnup/internals/video/error"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"time"
+)
+
+const (
+	resourcesBucket    = "resources"
+	defaultDownloadTTL = 5 * time.Minute
+)
+
+// ParticipantResource is a resource as a participant sees it, with how often they downloaded it
+type ParticipantResource struct {
+	Resource      *entities.CourseResource
+	DownloadCount int
+}
+
+type ResourceDownload struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+type ResourceService interface {
+	GetCourseResources(user *entities.User, courseID uint) ([]*ParticipantResource, error)
+	GetVideoResources(user *entities.User, videoID uint) ([]*ParticipantResource, error)
+	Download(ctx context.Context, user *entities.User, resourceID uint) (*ResourceDownload, error)
+}
+
+type resourceService struct {
+	unitOfWork  db.UnitOfWork
+	minioClient contracts.Storage
+	config      *dtos.EnvConfig
+}
+
+func NewResourceSvc(unitOfWork db.UnitOfWork, minioClient contracts.Storage, config *dtos.EnvConfig) ResourceService {
+	return &resourceService{
+		unitOfWork:  unitOfWork,
+		minioClient: minioClient,
+		config:      config,
+	}
+}
+
+func (svc resourceService) GetCourseResources(user *entities.User, courseID uint) ([]*ParticipantResource, error) {
+	const operationName = "resourceService.GetCourseResources"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	isTeacher, err := svc.checkCourseAccess(user, course)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := svc.unitOfWork.CourseResourceRepo().GetAllByCourseID(course.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching resources of course", operationName, err)
+	}
+	visibleResources := make([]*entities.CourseResource, 0, len(resources))
+	for _, resource := range resources {
+		if isTeacher || resource.Video == nil || isVideoVisible(resource.Video) {
+			visibleResources = append(visibleResources, resource)
+		}
+	}
+	return svc.withDownloadCounts(user, visibleResources)
+}
+
+func (svc resourceService) GetVideoResources(user *entities.User, videoID uint) ([]*ParticipantResource, error) {
+	const operationName = "resourceService.GetVideoResources"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(videoID, []string{"Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil || video.Course == nil {
+		return nil, videoError.Video_NotFound
+	}
+	if err := svc.checkVideoAccess(user, video); err != nil {
+		return nil, err
+	}
+	order := "created_at asc"
+	resources, err := svc.unitOfWork.CourseResourceRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"video_id": video.ID},
+		Order:      &order,
+	})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching resources of video", operationName, err)
+	}
+	return svc.withDownloadCounts(user, resources)
+}
+
+// Download counts every url handed out, what the browser does with it can't be seen from here
+func (svc resourceService) Download(ctx context.Context, user *entities.User, resourceID uint) (*ResourceDownload, error) {
+	const operationName = "resourceService.Download"
+	resource, err := svc.unitOfWork.CourseResourceRepo().GetByID(resourceID, []string{"Course", "Video"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching resource by id", operationName, err)
+	}
+	if resource == nil || resource.Course == nil {
+		return nil, resourceError.Resource_NotFound
+	}
+	if resource.Video != nil {
+		resource.Video.Course = resource.Course
+		if err := svc.checkVideoAccess(user, resource.Video); err != nil {
+			return nil, err
+		}
+	} else if _, err := svc.checkCourseAccess(user, resource.Course); err != nil {
+		return nil, err
+	}
+	ttl := svc.downloadTTL()
+	url, err := svc.minioClient.GetPresignedDownloadURL(ctx, resourcesBucket, resource.ObjectPath, resource.FileName, ttl)
+	if err != nil {
+		return nil, types.NewServerError("Error in presigning resource url", operationName, err)
+	}
+	now := time.Now()
+	if _, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		if err := tx.CourseResourceDownloadRepo().Increment(resource.ID, user.ID, now); err != nil {
+			return nil, err
+		}
+		return nil, tx.CourseResourceRepo().IncrementDownloadCount(resource.ID)
+	}); err != nil {
+		return nil, types.NewServerError("Error in counting resource download", operationName, err)
+	}
+	return &ResourceDownload{
+		URL:       url,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// checkCourseAccess lets the teacher in and requires an active enrollment from everyone else
+func (svc resourceService) checkCourseAccess(user *entities.User, course *entities.Course) (bool, error) {
+	const operationName = "resourceService.checkCourseAccess"
+	if course.IsTeacher(user.ID) {
+		return true, nil
+	}
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(course.ID, user.ID)
+	if err != nil {
+		return false, types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	if participant == nil || !participant.IsActive() {
+		return false, courseError.Course_NotParticipant
+	}
+	return false, nil
+}
+
+func (svc resourceService) checkVideoAccess(user *entities.User, video *entities.Video) error {
+	isTeacher, err := svc.checkCourseAccess(user, video.Course)
+	if err != nil {
+		return err
+	}
+	if !isTeacher && !isVideoVisible(video) {
+		return videoError.Video_NotFound
+	}
+	return nil
+}
+
+func (svc resourceService) withDownloadCounts(user *entities.User, resources []*entities.CourseResource) ([]*ParticipantResource, error) {
+	const operationName = "resourceService.withDownloadCounts"
+	resourceIDs := make([]uint, len(resources))
+	for index, resource := range resources {
+		resourceIDs[index] = resource.ID
+	}
+	counts, err := svc.unitOfWork.CourseResourceDownloadRepo().GetCountsByUserID(user.ID, resourceIDs)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching download counts", operationName, err)
+	}
+	result := make([]*ParticipantResource, len(resources))
+	for index, resource := range resources {
+		result[index] = &ParticipantResource{
+			Resource:      resource,
+			DownloadCount: counts[resource.ID],
+		}
+	}
+	return result, nil
+}
+
+func (svc resourceService) downloadTTL() time.Duration {
+	if svc.config.Resource.DownloadTTL > 0 {
+		return time.Duration(svc.config.Resource.DownloadTTL) * time.Second
+	}
+	return defaultDownloadTTL
+}
+
+// isVideoVisible hides resources of lessons students can't see yet
+func isVideoVisible(video *entities.Video) bool {
+	return video.IsPublished && video.IsVerified
+}
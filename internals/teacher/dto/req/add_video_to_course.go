@@ -10,4 +10,6 @@ type AddVideoToCourseReqDto struct {
 	Description string                    `json:"description" validate:"required,min=10"`
 	AccessLevel entities.VideoAccessLevel `json:"accessLevel" validate:"required,oneof=private public"`
 	IsPublished bool                      `json:"isPublished" validate:"required,boolean"`
+	IsPreview   bool                      `json:"isPreview" validate:"boolean"`
+	ThumbnailAt *float64                  `json:"thumbnailAt" validate:"omitempty,gte=0"`
 }
@@ -0,0 +1,72 @@
+package dtoreq
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type CreateAssignmentReqDto struct {
+	CourseID    uint       `json:"-"`
+	Title       string     `json:"title" validate:"required,min=3,max=255"`
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"dueAt"`
+	// late submissions lose LatePenalty percent of the max score for every started day, until LateUntil
+	AllowLateSubmissions bool                        `json:"allowLateSubmissions" validate:"boolean"`
+	LateUntil            *time.Time                  `json:"lateUntil"`
+	LatePenalty          float64                     `json:"latePenalty" validate:"gte=0,lte=100"`
+	IsPublished          bool                        `json:"isPublished" validate:"boolean"`
+	Criteria             []AssignmentCriterionReqDto `json:"criteria" validate:"max=20,dive"`
+}
+
+// UpdateAssignmentReqDto leaves the rubric alone, criteria have their own endpoints
+type UpdateAssignmentReqDto struct {
+	AssignmentID         uint       `json:"-"`
+	Title                string     `json:"title" validate:"required,min=3,max=255"`
+	Description          string     `json:"description"`
+	DueAt                *time.Time `json:"dueAt"`
+	AllowLateSubmissions bool       `json:"allowLateSubmissions" validate:"boolean"`
+	LateUntil            *time.Time `json:"lateUntil"`
+	LatePenalty          float64    `json:"latePenalty" validate:"gte=0,lte=100"`
+	IsPublished          bool       `json:"isPublished" validate:"boolean"`
+}
+
+type AssignmentCriterionReqDto struct {
+	Title       string  `json:"title" validate:"required,max=255"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points" validate:"required,gt=0"`
+	Position    *uint   `json:"position"`
+}
+
+type AddAssignmentCriterionReqDto struct {
+	AssignmentID uint `json:"-"`
+	AssignmentCriterionReqDto
+}
+
+type UpdateAssignmentCriterionReqDto struct {
+	CriterionID uint `json:"-"`
+	AssignmentCriterionReqDto
+}
+
+// GetGradingQueueReqDto is read from the query string, the queue shows submissions waiting for a grade
+// unless another status is asked for
+type GetGradingQueueReqDto struct {
+	CourseID     *uint
+	AssignmentID *uint
+	Status       *entities.AssignmentSubmissionStatus
+	Page         int
+	PageSize     int
+}
+
+type GradeCriterionReqDto struct {
+	CriterionID uint    `json:"criterionId" validate:"required"`
+	Score       float64 `json:"score" validate:"gte=0"`
+	Comment     string  `json:"comment" validate:"max=2000"`
+}
+
+type GradeSubmissionReqDto struct {
+	SubmissionID uint                   `json:"-"`
+	Criteria     []GradeCriterionReqDto `json:"criteria" validate:"required,min=1,max=20,dive"`
+	Feedback     string                 `json:"feedback" validate:"max=10000"`
+	// the student may hand in another attempt, the grade of this one stays visible
+	AllowResubmission bool `json:"allowResubmission" validate:"boolean"`
+}
@@ -0,0 +1,6 @@
+package dtoreq
+
+type CloneCourseReqDto struct {
+	ID   uint   `json:"-"`
+	Name string `json:"name" validate:"required,min=3,max=255"`
+}
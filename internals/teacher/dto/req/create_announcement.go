@@ -0,0 +1,13 @@
+package dtoreq
+
+import (
+	"time"
+)
+
+type CreateAnnouncementReqDto struct {
+	CourseID    uint       `json:"-"`
+	Title       string     `json:"title" validate:"required,min=3,max=255"`
+	Content     string     `json:"content" validate:"required,min=3"`
+	SendEmail   bool       `json:"sendEmail"`
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dtoreq
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"time"
+)
+
+type GetCourseAnalyticsReqDto struct {
+	ID     uint                         `json:"-"`
+	Bucket repositories.AnalyticsBucket `json:"bucket" validate:"required,oneof=day week month"`
+	From   time.Time                    `json:"from" validate:"required"`
+	To     time.Time                    `json:"to" validate:"required"`
+}
@@ -0,0 +1,33 @@
+package dtoreq
+
+import (
+	"io"
+	"time"
+)
+
+type AddParticipantReqDto struct {
+	CourseID  uint       `json:"-"`
+	Phone     string     `json:"phone" validate:"required,numeric,len=11"`
+	Reason    string     `json:"reason" validate:"required,min=3"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type RemoveParticipantReqDto struct {
+	CourseID  uint   `json:"-"`
+	StudentID uint   `json:"-"`
+	Reason    string `json:"reason" validate:"required,min=3"`
+}
+
+type UpdateParticipantExpiryReqDto struct {
+	CourseID  uint       `json:"-"`
+	StudentID uint       `json:"-"`
+	Reason    string     `json:"reason" validate:"required,min=3"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type BulkEnrollReqDto struct {
+	CourseID  uint       `json:"-"`
+	Reason    string     `json:"reason" validate:"required,min=3"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	File      io.Reader  `json:"-" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package dtoreq
+
+type PublishCourseVersionReqDto struct {
+	ID        uint   `json:"-"`
+	Changelog string `json:"changelog" validate:"required,min=10"`
+}
@@ -0,0 +1,52 @@
+package dtoreq
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+)
+
+type CreateQuizReqDto struct {
+	CourseID    uint   `json:"-"`
+	VideoID     *uint  `json:"videoId" validate:"omitempty,gte=1"`
+	Title       string `json:"title" validate:"required,min=3"`
+	Description string `json:"description"`
+	// seconds, zero means no limit
+	TimeLimit        uint    `json:"timeLimit"`
+	MaxAttempts      uint    `json:"maxAttempts"`
+	PassingScore     float64 `json:"passingScore" validate:"gte=0,lte=100"`
+	ShuffleQuestions bool    `json:"shuffleQuestions" validate:"boolean"`
+	IsPublished      bool    `json:"isPublished" validate:"boolean"`
+}
+
+type UpdateQuizReqDto struct {
+	QuizID           uint    `json:"-"`
+	VideoID          *uint   `json:"videoId" validate:"omitempty,gte=1"`
+	Title            string  `json:"title" validate:"required,min=3"`
+	Description      string  `json:"description"`
+	TimeLimit        uint    `json:"timeLimit"`
+	MaxAttempts      uint    `json:"maxAttempts"`
+	PassingScore     float64 `json:"passingScore" validate:"gte=0,lte=100"`
+	ShuffleQuestions bool    `json:"shuffleQuestions" validate:"boolean"`
+	IsPublished      bool    `json:"isPublished" validate:"boolean"`
+}
+
+// QuizQuestionReqDto is the same for adding and updating, correct options are indexes into the
+// options and true/false questions get their options filled in
+type QuizQuestionReqDto struct {
+	Type            entities.QuizQuestionType `json:"type" validate:"required,oneof=single_choice multiple_choice true_false short_answer"`
+	Text            string                    `json:"text" validate:"required,min=3"`
+	Options         []string                  `json:"options" validate:"max=10,dive,required"`
+	CorrectOptions  []int                     `json:"correctOptions" validate:"dive,gte=0"`
+	AcceptedAnswers []string                  `json:"acceptedAnswers" validate:"max=20,dive,required"`
+	Points          float64                   `json:"points" validate:"omitempty,gt=0"`
+	Position        *uint                     `json:"position"`
+}
+
+type AddQuizQuestionReqDto struct {
+	QuizID uint `json:"-"`
+	QuizQuestionReqDto
+}
+
+type UpdateQuizQuestionReqDto struct {
+	QuestionID uint `json:"-"`
+	QuizQuestionReqDto
+}
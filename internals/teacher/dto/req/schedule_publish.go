@@ -0,0 +1,11 @@
+package dtoreq
+
+import (
+	"time"
+)
+
+type SchedulePublishReqDto struct {
+	ID          uint       `json:"-"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	UnpublishAt *time.Time `json:"unpublishAt,omitempty"`
+}
@@ -0,0 +1,8 @@
+package dtoreq
+
+import "github.com/ladmakhi81/learnup/shared/db/entities"
+
+type UpdateCourseWatermarkReqDto struct {
+	ID   uint                         `json:"-"`
+	Mode entities.CourseWatermarkMode `json:"mode" validate:"required,oneof=none static forensic"`
+}
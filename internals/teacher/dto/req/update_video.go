@@ -0,0 +1,14 @@
+package dtoreq
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+)
+
+type UpdateVideoReqDto struct {
+	VideoID     uint                      `json:"-"`
+	Title       string                    `json:"title" validate:"required,min=3"`
+	Description string                    `json:"description" validate:"required,min=10"`
+	AccessLevel entities.VideoAccessLevel `json:"accessLevel" validate:"required,oneof=private public"`
+	IsPublished bool                      `json:"isPublished" validate:"boolean"`
+	IsPreview   bool                      `json:"isPreview" validate:"boolean"`
+}
@@ -0,0 +1,6 @@
+package dtoreq
+
+type UpdateVideoThumbnailReqDto struct {
+	VideoID     uint    `json:"-"`
+	ThumbnailAt float64 `json:"thumbnailAt" validate:"gte=0"`
+}
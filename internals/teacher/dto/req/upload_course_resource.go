@@ -0,0 +1,12 @@
+package dtoreq
+
+import "io"
+
+type UploadCourseResourceReqDto struct {
+	CourseID uint      `json:"-"`
+	VideoID  *uint     `json:"videoId" validate:"omitempty,gte=1"`
+	Title    string    `json:"title" validate:"required,max=255"`
+	FileName string    `json:"-" validate:"required,max=255"`
+	Size     int64     `json:"-"`
+	File     io.Reader `json:"-" validate:"required"`
+}
@@ -0,0 +1,15 @@
+package dtoreq
+
+import "io"
+
+type UploadVideoCaptionReqDto struct {
+	VideoID  uint      `json:"-"`
+	Language string    `json:"language" validate:"required,alpha,lowercase,min=2,max=3"`
+	Label    string    `json:"label" validate:"required,max=64"`
+	File     io.Reader `json:"-" validate:"required"`
+}
+
+type DeleteVideoCaptionReqDto struct {
+	VideoID  uint   `json:"-"`
+	Language string `json:"-" validate:"required"`
+}
@@ -11,6 +11,7 @@ type AddVideoToCourseResDto struct {
 	Description string                    `json:"description"`
 	AccessLevel entities.VideoAccessLevel `json:"accessLevel"`
 	IsPublished bool                      `json:"isPublished"`
+	IsPreview   bool                      `json:"isPreview"`
 }
 
 func NewAddVideoToCourseResDto(video *entities.Video) *AddVideoToCourseResDto {
@@ -21,5 +22,6 @@ func NewAddVideoToCourseResDto(video *entities.Video) *AddVideoToCourseResDto {
 		Description: video.Description,
 		AccessLevel: video.AccessLevel,
 		IsPublished: video.IsPublished,
+		IsPreview:   video.IsPreview,
 	}
 }
@@ -0,0 +1,62 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"time"
+)
+
+type CreateAnnouncementResDto struct {
+	ID          uint                        `json:"id"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+	Status      entities.AnnouncementStatus `json:"status"`
+	ScheduledAt *time.Time                  `json:"scheduledAt"`
+}
+
+func NewCreateAnnouncementResDto(announcement *entities.CourseAnnouncement) CreateAnnouncementResDto {
+	return CreateAnnouncementResDto{
+		ID:          announcement.ID,
+		CreatedAt:   announcement.CreatedAt,
+		Status:      announcement.Status,
+		ScheduledAt: announcement.ScheduledAt,
+	}
+}
+
+type FetchAnnouncementItemDto struct {
+	ID          uint                        `json:"id"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+	Title       string                      `json:"title"`
+	Content     string                      `json:"content"`
+	SendEmail   bool                        `json:"sendEmail"`
+	Status      entities.AnnouncementStatus `json:"status"`
+	ScheduledAt *time.Time                  `json:"scheduledAt"`
+	SentAt      *time.Time                  `json:"sentAt"`
+	Recipients  int                         `json:"recipients"`
+	Seen        int                         `json:"seen"`
+	ReadRate    float64                     `json:"readRate"`
+}
+
+func MapFetchAnnouncementItemsDto(announcements []*entities.CourseAnnouncement, stats map[uint]*repositories.AnnouncementReadStat) []*FetchAnnouncementItemDto {
+	res := make([]*FetchAnnouncementItemDto, len(announcements))
+	for index, announcement := range announcements {
+		item := &FetchAnnouncementItemDto{
+			ID:          announcement.ID,
+			CreatedAt:   announcement.CreatedAt,
+			Title:       announcement.Title,
+			Content:     announcement.Content,
+			SendEmail:   announcement.SendEmail,
+			Status:      announcement.Status,
+			ScheduledAt: announcement.ScheduledAt,
+			SentAt:      announcement.SentAt,
+			Recipients:  announcement.RecipientsCount,
+		}
+		if stat, ok := stats[announcement.ID]; ok {
+			item.Seen = stat.Seen
+			if stat.Recipients > 0 {
+				item.ReadRate = float64(stat.Seen) / float64(stat.Recipients)
+			}
+		}
+		res[index] = item
+	}
+	return res
+}
@@ -0,0 +1,181 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type AssignmentCriterionResDto struct {
+	ID          uint    `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+	Position    uint    `json:"position"`
+}
+
+type AssignmentResDto struct {
+	ID                   uint                         `json:"id"`
+	CreatedAt            time.Time                    `json:"createdAt"`
+	UpdatedAt            time.Time                    `json:"updatedAt"`
+	CourseID             uint                         `json:"courseId"`
+	Title                string                       `json:"title"`
+	Description          string                       `json:"description"`
+	DueAt                *time.Time                   `json:"dueAt"`
+	AllowLateSubmissions bool                         `json:"allowLateSubmissions"`
+	LateUntil            *time.Time                   `json:"lateUntil"`
+	LatePenalty          float64                      `json:"latePenalty"`
+	IsPublished          bool                         `json:"isPublished"`
+	MaxScore             float64                      `json:"maxScore"`
+	Criteria             []*AssignmentCriterionResDto `json:"criteria"`
+}
+
+func NewAssignmentCriterionResDto(criterion *entities.AssignmentCriterion) *AssignmentCriterionResDto {
+	return &AssignmentCriterionResDto{
+		ID:          criterion.ID,
+		Title:       criterion.Title,
+		Description: criterion.Description,
+		Points:      criterion.Points,
+		Position:    criterion.Position,
+	}
+}
+
+func NewAssignmentResDto(assignment *entities.Assignment) *AssignmentResDto {
+	res := &AssignmentResDto{
+		ID:                   assignment.ID,
+		CreatedAt:            assignment.CreatedAt,
+		UpdatedAt:            assignment.UpdatedAt,
+		CourseID:             assignment.CourseID,
+		Title:                assignment.Title,
+		Description:          assignment.Description,
+		DueAt:                assignment.DueAt,
+		AllowLateSubmissions: assignment.AllowLateSubmissions,
+		LateUntil:            assignment.LateUntil,
+		LatePenalty:          assignment.LatePenalty,
+		IsPublished:          assignment.IsPublished,
+		MaxScore:             assignment.MaxScore(),
+		Criteria:             make([]*AssignmentCriterionResDto, len(assignment.Criteria)),
+	}
+	for index, criterion := range assignment.Criteria {
+		res.Criteria[index] = NewAssignmentCriterionResDto(criterion)
+	}
+	return res
+}
+
+func MapAssignmentsResDto(assignments []*entities.Assignment) []*AssignmentResDto {
+	result := make([]*AssignmentResDto, len(assignments))
+	for index, assignment := range assignments {
+		result[index] = NewAssignmentResDto(assignment)
+	}
+	return result
+}
+
+type GradingQueueItemDto struct {
+	ID              uint                                `json:"id"`
+	AssignmentID    uint                                `json:"assignmentId"`
+	AssignmentTitle string                              `json:"assignmentTitle"`
+	CourseID        uint                                `json:"courseId"`
+	Student         *getParticipantUserItem             `json:"student"`
+	Attempt         uint                                `json:"attempt"`
+	SubmittedAt     time.Time                           `json:"submittedAt"`
+	LateDays        uint                                `json:"lateDays"`
+	HasFile         bool                                `json:"hasFile"`
+	Status          entities.AssignmentSubmissionStatus `json:"status"`
+	FinalScore      *float64                            `json:"finalScore"`
+	MaxScore        float64                             `json:"maxScore"`
+}
+
+func MapGradingQueueItemsDto(submissions []*entities.AssignmentSubmission) []*GradingQueueItemDto {
+	result := make([]*GradingQueueItemDto, len(submissions))
+	for index, submission := range submissions {
+		result[index] = &GradingQueueItemDto{
+			ID:           submission.ID,
+			AssignmentID: submission.AssignmentID,
+			CourseID:     submission.CourseID,
+			Student:      newGetParticipantUserItem(submission.User),
+			Attempt:      submission.Attempt,
+			SubmittedAt:  submission.SubmittedAt,
+			LateDays:     submission.LateDays,
+			HasFile:      submission.HasFile(),
+			Status:       submission.Status,
+			FinalScore:   submission.FinalScore,
+			MaxScore:     submission.MaxScore,
+		}
+		if submission.Assignment != nil {
+			result[index].AssignmentTitle = submission.Assignment.Title
+		}
+	}
+	return result
+}
+
+type AssignmentCriterionScoreResDto struct {
+	CriterionID uint    `json:"criterionId"`
+	Title       string  `json:"title"`
+	Points      float64 `json:"points"`
+	Score       float64 `json:"score"`
+	Comment     string  `json:"comment"`
+}
+
+type AssignmentSubmissionResDto struct {
+	ID              uint                                `json:"id"`
+	Assignment      *AssignmentResDto                   `json:"assignment"`
+	Student         *getParticipantUserItem             `json:"student"`
+	Attempt         uint                                `json:"attempt"`
+	Text            string                              `json:"text"`
+	FileName        string                              `json:"fileName"`
+	ContentType     string                              `json:"contentType"`
+	Size            int64                               `json:"size"`
+	ScanStatus      entities.ResourceScanStatus         `json:"scanStatus"`
+	SubmittedAt     time.Time                           `json:"submittedAt"`
+	LateDays        uint                                `json:"lateDays"`
+	Status          entities.AssignmentSubmissionStatus `json:"status"`
+	CriterionScores []*AssignmentCriterionScoreResDto   `json:"criterionScores"`
+	Score           *float64                            `json:"score"`
+	Penalty         float64                             `json:"penalty"`
+	FinalScore      *float64                            `json:"finalScore"`
+	MaxScore        float64                             `json:"maxScore"`
+	Feedback        string                              `json:"feedback"`
+	GradedAt        *time.Time                          `json:"gradedAt"`
+}
+
+func NewAssignmentSubmissionResDto(submission *entities.AssignmentSubmission) *AssignmentSubmissionResDto {
+	res := &AssignmentSubmissionResDto{
+		ID:              submission.ID,
+		Student:         newGetParticipantUserItem(submission.User),
+		Attempt:         submission.Attempt,
+		Text:            submission.Text,
+		FileName:        submission.FileName,
+		ContentType:     submission.ContentType,
+		Size:            submission.Size,
+		ScanStatus:      submission.ScanStatus,
+		SubmittedAt:     submission.SubmittedAt,
+		LateDays:        submission.LateDays,
+		Status:          submission.Status,
+		CriterionScores: make([]*AssignmentCriterionScoreResDto, len(submission.CriterionScores)),
+		Score:           submission.Score,
+		Penalty:         submission.Penalty,
+		FinalScore:      submission.FinalScore,
+		MaxScore:        submission.MaxScore,
+		Feedback:        submission.Feedback,
+		GradedAt:        submission.GradedAt,
+	}
+	criteria := make(map[uint]*entities.AssignmentCriterion)
+	if submission.Assignment != nil {
+		res.Assignment = NewAssignmentResDto(submission.Assignment)
+		for _, criterion := range submission.Assignment.Criteria {
+			criteria[criterion.ID] = criterion
+		}
+	}
+	for index, score := range submission.CriterionScores {
+		res.CriterionScores[index] = &AssignmentCriterionScoreResDto{
+			CriterionID: score.CriterionID,
+			Score:       score.Score,
+			Comment:     score.Comment,
+		}
+		// criteria removed after grading only keep their score
+		if criterion, isFound := criteria[score.CriterionID]; isFound {
+			res.CriterionScores[index].Title = criterion.Title
+			res.CriterionScores[index].Points = criterion.Points
+		}
+	}
+	return res
+}
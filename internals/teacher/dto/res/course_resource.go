@@ -0,0 +1,62 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type CourseResourceResDto struct {
+	ID            uint                        `json:"id"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+	CourseID      uint                        `json:"courseId"`
+	VideoID       *uint                       `json:"videoId"`
+	Title         string                      `json:"title"`
+	FileName      string                      `json:"fileName"`
+	ContentType   string                      `json:"contentType"`
+	Size          int64                       `json:"size"`
+	ScanStatus    entities.ResourceScanStatus `json:"scanStatus"`
+	ScannedAt     *time.Time                  `json:"scannedAt"`
+	DownloadCount int                         `json:"downloadCount"`
+}
+
+func NewCourseResourceResDto(resource *entities.CourseResource) *CourseResourceResDto {
+	return &CourseResourceResDto{
+		ID:            resource.ID,
+		CreatedAt:     resource.CreatedAt,
+		CourseID:      resource.CourseID,
+		VideoID:       resource.VideoID,
+		Title:         resource.Title,
+		FileName:      resource.FileName,
+		ContentType:   resource.ContentType,
+		Size:          resource.Size,
+		ScanStatus:    resource.ScanStatus,
+		ScannedAt:     resource.ScannedAt,
+		DownloadCount: resource.DownloadCount,
+	}
+}
+
+func MapCourseResourcesResDto(resources []*entities.CourseResource) []*CourseResourceResDto {
+	result := make([]*CourseResourceResDto, len(resources))
+	for index, resource := range resources {
+		result[index] = NewCourseResourceResDto(resource)
+	}
+	return result
+}
+
+type ResourceDownloadItemDto struct {
+	User             *getParticipantUserItem `json:"user"`
+	Count            int                     `json:"count"`
+	LastDownloadedAt time.Time               `json:"lastDownloadedAt"`
+}
+
+func MapResourceDownloadItemsDto(downloads []*entities.CourseResourceDownload) []*ResourceDownloadItemDto {
+	result := make([]*ResourceDownloadItemDto, len(downloads))
+	for index, download := range downloads {
+		result[index] = &ResourceDownloadItemDto{
+			User:             newGetParticipantUserItem(download.User),
+			Count:            download.Count,
+			LastDownloadedAt: download.LastDownloadedAt,
+		}
+	}
+	return result
+}
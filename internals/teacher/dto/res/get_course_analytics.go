@@ -0,0 +1,129 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"time"
+)
+
+type AnalyticsPointDto struct {
+	Bucket time.Time `json:"bucket"`
+	Value  float64   `json:"value"`
+}
+
+type AnalyticsSeriesDto struct {
+	Total  float64             `json:"total"`
+	Points []AnalyticsPointDto `json:"points"`
+}
+
+type AnalyticsLikesDto struct {
+	Likes     AnalyticsSeriesDto `json:"likes"`
+	Dislikes  AnalyticsSeriesDto `json:"dislikes"`
+	LikeRatio float64            `json:"likeRatio"`
+}
+
+type AnalyticsFunnelStepDto struct {
+	VideoID             uint    `json:"videoId"`
+	Title               string  `json:"title"`
+	Viewers             int     `json:"viewers"`
+	Completions         int     `json:"completions"`
+	CompletionRate      float64 `json:"completionRate"`
+	AverageWatchSeconds float64 `json:"averageWatchSeconds"`
+}
+
+type AnalyticsPreviewsDto struct {
+	Views          AnalyticsSeriesDto `json:"views"`
+	AnonymousViews int                `json:"anonymousViews"`
+	Viewers        int                `json:"viewers"`
+	Conversions    int                `json:"conversions"`
+	ConversionRate float64            `json:"conversionRate"`
+}
+
+type GetCourseAnalyticsResDto struct {
+	CourseID         uint                     `json:"courseId"`
+	Bucket           string                   `json:"bucket"`
+	From             time.Time                `json:"from"`
+	To               time.Time                `json:"to"`
+	ParticipantCount int                      `json:"participantCount"`
+	Enrollments      AnalyticsSeriesDto       `json:"enrollments"`
+	Revenue          AnalyticsSeriesDto       `json:"revenue"`
+	Likes            AnalyticsLikesDto        `json:"likes"`
+	Comments         AnalyticsSeriesDto       `json:"comments"`
+	Questions        AnalyticsSeriesDto       `json:"questions"`
+	Funnel           []AnalyticsFunnelStepDto `json:"funnel"`
+	Previews         AnalyticsPreviewsDto     `json:"previews"`
+}
+
+func NewGetCourseAnalyticsResDto(analytics *service.CourseAnalytics) GetCourseAnalyticsResDto {
+	likes := mapAnalyticsSeries(analytics.Likes)
+	dislikes := mapAnalyticsSeries(analytics.Dislikes)
+	likeRatio := float64(0)
+	if votes := likes.Total + dislikes.Total; votes > 0 {
+		likeRatio = likes.Total / votes
+	}
+	return GetCourseAnalyticsResDto{
+		CourseID:         analytics.Filter.CourseID,
+		Bucket:           string(analytics.Filter.Bucket),
+		From:             analytics.Filter.From,
+		To:               analytics.Filter.To,
+		ParticipantCount: analytics.ParticipantCount,
+		Enrollments:      mapAnalyticsSeries(analytics.Enrollments),
+		Revenue:          mapAnalyticsSeries(analytics.Revenue),
+		Likes: AnalyticsLikesDto{
+			Likes:     likes,
+			Dislikes:  dislikes,
+			LikeRatio: likeRatio,
+		},
+		Comments:  mapAnalyticsSeries(analytics.Comments),
+		Questions: mapAnalyticsSeries(analytics.Questions),
+		Funnel:    mapAnalyticsFunnel(analytics.Videos, analytics.ParticipantCount),
+		Previews:  mapAnalyticsPreviews(analytics.PreviewViews, analytics.Previews),
+	}
+}
+
+func mapAnalyticsSeries(points []*repositories.TimeSeriesPoint) AnalyticsSeriesDto {
+	series := AnalyticsSeriesDto{
+		Points: make([]AnalyticsPointDto, len(points)),
+	}
+	for index, point := range points {
+		series.Total += point.Value
+		series.Points[index] = AnalyticsPointDto{
+			Bucket: point.Bucket,
+			Value:  point.Value,
+		}
+	}
+	return series
+}
+
+func mapAnalyticsFunnel(videos []*repositories.VideoEngagement, participantCount int) []AnalyticsFunnelStepDto {
+	funnel := make([]AnalyticsFunnelStepDto, len(videos))
+	for index, video := range videos {
+		completionRate := float64(0)
+		if participantCount > 0 {
+			completionRate = float64(video.Completions) / float64(participantCount)
+		}
+		funnel[index] = AnalyticsFunnelStepDto{
+			VideoID:             video.VideoID,
+			Title:               video.Title,
+			Viewers:             video.Viewers,
+			Completions:         video.Completions,
+			CompletionRate:      completionRate,
+			AverageWatchSeconds: video.AverageWatchSeconds,
+		}
+	}
+	return funnel
+}
+
+// mapAnalyticsPreviews rates conversions against logged in viewers, anonymous views can't be followed
+func mapAnalyticsPreviews(views []*repositories.TimeSeriesPoint, conversion *repositories.PreviewConversion) AnalyticsPreviewsDto {
+	previews := AnalyticsPreviewsDto{
+		Views:          mapAnalyticsSeries(views),
+		AnonymousViews: conversion.AnonymousViews,
+		Viewers:        conversion.Viewers,
+		Conversions:    conversion.Conversions,
+	}
+	if conversion.Viewers > 0 {
+		previews.ConversionRate = float64(conversion.Conversions) / float64(conversion.Viewers)
+	}
+	return previews
+}
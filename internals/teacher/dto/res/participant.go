@@ -0,0 +1,102 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type getParticipantUserItem struct {
+	ID       uint   `json:"id"`
+	FullName string `json:"fullName"`
+	Phone    string `json:"phone"`
+}
+
+func newGetParticipantUserItem(user *entities.User) *getParticipantUserItem {
+	if user == nil {
+		return nil
+	}
+	return &getParticipantUserItem{
+		ID:       user.ID,
+		FullName: user.FullName(),
+		Phone:    user.Phone,
+	}
+}
+
+type AddParticipantResDto struct {
+	CourseID  uint       `json:"courseId"`
+	StudentID uint       `json:"studentId"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func NewAddParticipantResDto(participant *entities.CourseParticipant) AddParticipantResDto {
+	return AddParticipantResDto{
+		CourseID:  participant.CourseID,
+		StudentID: participant.StudentID,
+		ExpiresAt: participant.ExpiresAt,
+	}
+}
+
+type FetchParticipantItemDto struct {
+	Student       *getParticipantUserItem   `json:"student"`
+	Source        entities.EnrollmentAction `json:"source"`
+	CourseVersion uint                      `json:"courseVersion"`
+	ExpiresAt     *time.Time                `json:"expiresAt"`
+	IsActive      bool                      `json:"isActive"`
+	CreatedAt     time.Time                 `json:"createdAt"`
+}
+
+func MapFetchParticipantItemsDto(participants []*entities.CourseParticipant) []*FetchParticipantItemDto {
+	res := make([]*FetchParticipantItemDto, len(participants))
+	for index, participant := range participants {
+		res[index] = &FetchParticipantItemDto{
+			Student:       newGetParticipantUserItem(participant.Student),
+			Source:        participant.Source,
+			CourseVersion: participant.CourseVersion,
+			ExpiresAt:     participant.ExpiresAt,
+			IsActive:      participant.IsActive(),
+			CreatedAt:     participant.CreatedAt,
+		}
+	}
+	return res
+}
+
+type FetchEnrollmentAuditItemDto struct {
+	ID        uint                      `json:"id"`
+	CreatedAt time.Time                 `json:"createdAt"`
+	Student   *getParticipantUserItem   `json:"student"`
+	Actor     *getParticipantUserItem   `json:"actor"`
+	Action    entities.EnrollmentAction `json:"action"`
+	Reason    string                    `json:"reason"`
+	ExpiresAt *time.Time                `json:"expiresAt"`
+}
+
+func MapFetchEnrollmentAuditItemsDto(audits []*entities.EnrollmentAudit) []*FetchEnrollmentAuditItemDto {
+	res := make([]*FetchEnrollmentAuditItemDto, len(audits))
+	for index, audit := range audits {
+		res[index] = &FetchEnrollmentAuditItemDto{
+			ID:        audit.ID,
+			CreatedAt: audit.CreatedAt,
+			Student:   newGetParticipantUserItem(audit.Student),
+			Actor:     newGetParticipantUserItem(audit.Actor),
+			Action:    audit.Action,
+			Reason:    audit.Reason,
+			ExpiresAt: audit.ExpiresAt,
+		}
+	}
+	return res
+}
+
+type BulkEnrollResDto struct {
+	Enrolled        []string `json:"enrolled"`
+	AlreadyEnrolled []string `json:"alreadyEnrolled"`
+	NotFound        []string `json:"notFound"`
+}
+
+func NewBulkEnrollResDto(result *service.BulkEnrollResult) BulkEnrollResDto {
+	return BulkEnrollResDto{
+		Enrolled:        result.Enrolled,
+		AlreadyEnrolled: result.AlreadyEnrolled,
+		NotFound:        result.NotFound,
+	}
+}
@@ -0,0 +1,22 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type PublishCourseVersionResDto struct {
+	ID          uint      `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Version     uint      `json:"version"`
+	VideosCount int       `json:"videosCount"`
+}
+
+func NewPublishCourseVersionResDto(version *entities.CourseVersion) PublishCourseVersionResDto {
+	return PublishCourseVersionResDto{
+		ID:          version.ID,
+		CreatedAt:   version.CreatedAt,
+		Version:     version.Version,
+		VideosCount: len(version.Snapshot.Videos),
+	}
+}
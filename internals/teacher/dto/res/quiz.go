@@ -0,0 +1,155 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"math"
+	"slices"
+	"time"
+)
+
+type QuizQuestionResDto struct {
+	ID              uint                      `json:"id"`
+	Type            entities.QuizQuestionType `json:"type"`
+	Text            string                    `json:"text"`
+	Options         []string                  `json:"options"`
+	CorrectOptions  []int                     `json:"correctOptions"`
+	AcceptedAnswers []string                  `json:"acceptedAnswers"`
+	Points          float64                   `json:"points"`
+	Position        uint                      `json:"position"`
+}
+
+type QuizResDto struct {
+	ID               uint                  `json:"id"`
+	CreatedAt        time.Time             `json:"createdAt"`
+	UpdatedAt        time.Time             `json:"updatedAt"`
+	CourseID         uint                  `json:"courseId"`
+	VideoID          *uint                 `json:"videoId"`
+	VideoTitle       *string               `json:"videoTitle"`
+	Title            string                `json:"title"`
+	Description      string                `json:"description"`
+	TimeLimit        uint                  `json:"timeLimit"`
+	MaxAttempts      uint                  `json:"maxAttempts"`
+	PassingScore     float64               `json:"passingScore"`
+	ShuffleQuestions bool                  `json:"shuffleQuestions"`
+	IsPublished      bool                  `json:"isPublished"`
+	Questions        []*QuizQuestionResDto `json:"questions,omitempty"`
+}
+
+func NewQuizQuestionResDto(question *entities.QuizQuestion) *QuizQuestionResDto {
+	return &QuizQuestionResDto{
+		ID:              question.ID,
+		Type:            question.Type,
+		Text:            question.Text,
+		Options:         question.Options,
+		CorrectOptions:  question.CorrectOptions,
+		AcceptedAnswers: question.AcceptedAnswers,
+		Points:          question.Points,
+		Position:        question.Position,
+	}
+}
+
+func NewQuizResDto(quiz *entities.Quiz) *QuizResDto {
+	res := &QuizResDto{
+		ID:               quiz.ID,
+		CreatedAt:        quiz.CreatedAt,
+		UpdatedAt:        quiz.UpdatedAt,
+		CourseID:         quiz.CourseID,
+		VideoID:          quiz.VideoID,
+		Title:            quiz.Title,
+		Description:      quiz.Description,
+		TimeLimit:        quiz.TimeLimit,
+		MaxAttempts:      quiz.MaxAttempts,
+		PassingScore:     quiz.PassingScore,
+		ShuffleQuestions: quiz.ShuffleQuestions,
+		IsPublished:      quiz.IsPublished,
+	}
+	if quiz.Video != nil {
+		res.VideoTitle = &quiz.Video.Title
+	}
+	if quiz.Questions != nil {
+		res.Questions = make([]*QuizQuestionResDto, len(quiz.Questions))
+		for index, question := range quiz.Questions {
+			res.Questions[index] = NewQuizQuestionResDto(question)
+		}
+	}
+	return res
+}
+
+func MapQuizzesResDto(quizzes []*entities.Quiz) []*QuizResDto {
+	result := make([]*QuizResDto, len(quizzes))
+	for index, quiz := range quizzes {
+		result[index] = NewQuizResDto(quiz)
+	}
+	return result
+}
+
+type QuizOptionAnalyticsDto struct {
+	Index     int    `json:"index"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"isCorrect"`
+	Count     int    `json:"count"`
+}
+
+type QuizQuestionAnalyticsDto struct {
+	QuestionID     uint                      `json:"questionId"`
+	Type           entities.QuizQuestionType `json:"type"`
+	Text           string                    `json:"text"`
+	Answers        int                       `json:"answers"`
+	CorrectAnswers int                       `json:"correctAnswers"`
+	CorrectRate    float64                   `json:"correctRate"`
+	Options        []QuizOptionAnalyticsDto  `json:"options"`
+}
+
+type GetQuizAnalyticsResDto struct {
+	QuizID            uint                       `json:"quizId"`
+	Title             string                     `json:"title"`
+	Attempts          int                        `json:"attempts"`
+	Students          int                        `json:"students"`
+	PassedStudents    int                        `json:"passedStudents"`
+	PassRate          float64                    `json:"passRate"`
+	AveragePercentage float64                    `json:"averagePercentage"`
+	Questions         []QuizQuestionAnalyticsDto `json:"questions"`
+}
+
+func NewGetQuizAnalyticsResDto(analytics *service.QuizAnalytics) GetQuizAnalyticsResDto {
+	res := GetQuizAnalyticsResDto{
+		QuizID:            analytics.Quiz.ID,
+		Title:             analytics.Quiz.Title,
+		Attempts:          analytics.Stats.Attempts,
+		Students:          analytics.Stats.Students,
+		PassedStudents:    analytics.Stats.PassedStudents,
+		PassRate:          ratio(analytics.Stats.PassedStudents, analytics.Stats.Students),
+		AveragePercentage: math.Round(analytics.Stats.AveragePercentage*100) / 100,
+		Questions:         make([]QuizQuestionAnalyticsDto, len(analytics.Questions)),
+	}
+	for index, questionAnalytics := range analytics.Questions {
+		question := questionAnalytics.Question
+		options := make([]QuizOptionAnalyticsDto, len(question.Options))
+		for optionIndex, option := range question.Options {
+			options[optionIndex] = QuizOptionAnalyticsDto{
+				Index:     optionIndex,
+				Text:      option,
+				IsCorrect: slices.Contains(question.CorrectOptions, optionIndex),
+				Count:     questionAnalytics.OptionCounts[optionIndex],
+			}
+		}
+		res.Questions[index] = QuizQuestionAnalyticsDto{
+			QuestionID:     question.ID,
+			Type:           question.Type,
+			Text:           question.Text,
+			Answers:        questionAnalytics.Answers,
+			CorrectAnswers: questionAnalytics.CorrectAnswers,
+			CorrectRate:    ratio(questionAnalytics.CorrectAnswers, questionAnalytics.Answers),
+			Options:        options,
+		}
+	}
+	return res
+}
+
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,29 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+)
+
+type UpdateVideoResDto struct {
+	ID          uint                      `json:"id"`
+	CourseID    uint                      `json:"courseId"`
+	Title       string                    `json:"title"`
+	Description string                    `json:"description"`
+	AccessLevel entities.VideoAccessLevel `json:"accessLevel"`
+	IsPublished bool                      `json:"isPublished"`
+	IsPreview   bool                      `json:"isPreview"`
+	Status      entities.VideoStatus      `json:"status"`
+}
+
+func NewUpdateVideoResDto(video *entities.Video) *UpdateVideoResDto {
+	return &UpdateVideoResDto{
+		ID:          video.ID,
+		CourseID:    *video.CourseId,
+		Title:       video.Title,
+		Description: video.Description,
+		AccessLevel: video.AccessLevel,
+		IsPublished: video.IsPublished,
+		IsPreview:   video.IsPreview,
+		Status:      video.Status,
+	}
+}
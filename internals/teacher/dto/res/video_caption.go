@@ -0,0 +1,30 @@
+package dtores
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type VideoCaptionResDto struct {
+	ID        uint      `json:"id"`
+	Language  string    `json:"language"`
+	Label     string    `json:"label"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func NewVideoCaptionResDto(caption *entities.VideoCaption) VideoCaptionResDto {
+	return VideoCaptionResDto{
+		ID:        caption.ID,
+		Language:  caption.Language,
+		Label:     caption.Label,
+		UpdatedAt: caption.UpdatedAt,
+	}
+}
+
+func MapVideoCaptionsResDto(captions []*entities.VideoCaption) []VideoCaptionResDto {
+	res := make([]VideoCaptionResDto, len(captions))
+	for index, caption := range captions {
+		res[index] = NewVideoCaptionResDto(caption)
+	}
+	return res
+}
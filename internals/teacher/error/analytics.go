@@ -0,0 +1,10 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Analytics_InvalidDateRange = types.NewBadRequestError("analytics.errors.invalid_date_range")
+	Analytics_DateRangeTooLong = types.NewBadRequestError("analytics.errors.date_range_too_long")
+)
@@ -0,0 +1,13 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Participant_NotFound         = types.NewNotFoundError("participant.errors.not_found")
+	Participant_AlreadyEnrolled  = types.NewConflictError("participant.errors.already_enrolled")
+	Participant_InvalidExpiresAt = types.NewBadRequestError("participant.errors.invalid_expires_at")
+	Participant_InvalidCsv       = types.NewBadRequestError("participant.errors.invalid_csv")
+	Participant_CsvTooLarge      = types.NewBadRequestError("participant.errors.csv_too_large")
+)
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/teacher/dto/res"
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+	"time"
+)
+
+const (
+	analyticsDateLayout       = "2006-01-02"
+	analyticsDefaultDateRange = 30 * 24 * time.Hour
+)
+
+type AnalyticsHandler struct {
+	analyticsSvc   service.TeacherAnalyticsService
+	validationSvc  contracts.Validation
+	translationSvc contracts.Translator
+	userSvc        userService.UserSvc
+}
+
+func NewAnalyticsHandler(
+	analyticsSvc service.TeacherAnalyticsService,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+	userSvc userService.UserSvc,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsSvc:   analyticsSvc,
+		validationSvc:  validationSvc,
+		translationSvc: translationSvc,
+		userSvc:        userSvc,
+	}
+}
+
+// GetCourseAnalytics godoc
+//
+//	@Summary	Get time bucketed analytics of teacher's course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int		true	"Course ID"
+//	@Param		bucket		query		string	false	"Bucket size (day, week, month)"	default(day)
+//	@Param		from		query		string	false	"Start date (YYYY-MM-DD), defaults to 30 days ago"
+//	@Param		to			query		string	false	"End date inclusive (YYYY-MM-DD), defaults to today"
+//	@Success	200			{object}	types.ApiResponse{data=dtores.GetCourseAnalyticsResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/analytics [get]
+//
+//	@Security	BearerAuth
+func (h AnalyticsHandler) GetCourseAnalytics(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	dto := &dtoreq.GetCourseAnalyticsReqDto{
+		ID:     courseID,
+		Bucket: repositories.AnalyticsBucket(ctx.DefaultQuery("bucket", string(repositories.AnalyticsBucket_Day))),
+		From:   today.Add(-analyticsDefaultDateRange),
+		To:     today,
+	}
+	if from := ctx.Query("from"); from != "" {
+		if dto.From, err = time.Parse(analyticsDateLayout, from); err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("analytics.errors.invalid_date"),
+			)
+		}
+	}
+	if to := ctx.Query("to"); to != "" {
+		if dto.To, err = time.Parse(analyticsDateLayout, to); err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("analytics.errors.invalid_date"),
+			)
+		}
+	}
+	// the end date is inclusive, so the whole day is part of the range
+	dto.To = dto.To.Add(24 * time.Hour)
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	analytics, err := h.analyticsSvc.GetCourseAnalytics(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetCourseAnalyticsResDto(analytics)), nil
+}
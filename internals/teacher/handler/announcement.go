@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/teacher/dto/res"
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type AnnouncementHandler struct {
+	announcementSvc service.TeacherAnnouncementService
+	validationSvc   contracts.Validation
+	translationSvc  contracts.Translator
+	userSvc         userService.UserSvc
+}
+
+func NewAnnouncementHandler(
+	announcementSvc service.TeacherAnnouncementService,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+	userSvc userService.UserSvc,
+) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementSvc: announcementSvc,
+		validationSvc:   validationSvc,
+		translationSvc:  translationSvc,
+		userSvc:         userSvc,
+	}
+}
+
+// CreateAnnouncement godoc
+//
+//	@Summary	Send or schedule an announcement to students of course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int								true	"Course ID"
+//	@Param		request		body		dtoreq.CreateAnnouncementReqDto	true	" "
+//	@Success	201			{object}	types.ApiResponse{data=dtores.CreateAnnouncementResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/announcements [post]
+//
+//	@Security	BearerAuth
+func (h AnnouncementHandler) CreateAnnouncement(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.CreateAnnouncementReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.CourseID = courseID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	announcement, err := h.announcementSvc.Create(ctx, teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewCreateAnnouncementResDto(announcement)), nil
+}
+
+// FetchAnnouncements godoc
+//
+//	@Summary	Get announcements of course with read stats
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Param		page		query		int	false	"Page number"	default(0)
+//	@Param		pageSize	query		int	false	"Page size"		default(10)
+//	@Success	200			{object}	types.ApiResponse{data=types.PaginationRes{row=[]dtores.FetchAnnouncementItemDto}}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/announcements [get]
+//
+//	@Security	BearerAuth
+func (h AnnouncementHandler) FetchAnnouncements(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	page, pageSize := utils.ExtractPaginationMetadata(ctx.Query("page"), ctx.Query("pageSize"))
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	announcements, stats, count, err := h.announcementSvc.FetchByCourseID(teacher, courseID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	announcementsRes := types.NewPaginationRes(
+		dtores.MapFetchAnnouncementItemsDto(announcements, stats),
+		page,
+		utils.CalculatePaginationTotalPage(count, pageSize),
+		count,
+	)
+	return types.NewApiResponse(http.StatusOK, announcementsRes), nil
+}
+
+// CancelAnnouncement godoc
+//
+//	@Summary	Cancel a scheduled announcement
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		announcement-id	path		int	true	"Announcement ID"
+//	@Success	200				{object}	types.ApiResponse
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/announcements/{announcement-id} [delete]
+//
+//	@Security	BearerAuth
+func (h AnnouncementHandler) CancelAnnouncement(ctx *gin.Context) (*types.ApiResponse, error) {
+	announcementID, err := utils.ToUint(ctx.Param("announcement-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("announcement.errors.invalid_announcement_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.announcementSvc.Cancel(ctx, teacher, announcementID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
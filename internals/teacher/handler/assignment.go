@@ -0,0 +1,483 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/teacher/dto/res"
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+// gradingQueueStatusAll lifts the status filter of the grading queue
+const gradingQueueStatusAll = "all"
+
+type AssignmentHandler struct {
+	assignmentSvc  service.TeacherAssignmentService
+	validationSvc  contracts.Validation
+	translationSvc contracts.Translator
+	userSvc        userService.UserSvc
+}
+
+func NewAssignmentHandler(
+	assignmentSvc service.TeacherAssignmentService,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+	userSvc userService.UserSvc,
+) *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentSvc:  assignmentSvc,
+		validationSvc:  validationSvc,
+		translationSvc: translationSvc,
+		userSvc:        userSvc,
+	}
+}
+
+// CreateAssignment godoc
+//
+//	@Summary	Create an assignment with its rubric for a course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int								true	"Course ID"
+//	@Param		request		body		dtoreq.CreateAssignmentReqDto	true	" "
+//	@Success	201			{object}	types.ApiResponse{data=dtores.AssignmentResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/assignments [post]
+//	@Security	BearerAuth
+func (h AssignmentHandler) CreateAssignment(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.CreateAssignmentReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.CourseID = courseID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := h.assignmentSvc.CreateAssignment(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewAssignmentResDto(assignment)), nil
+}
+
+// FetchAssignments godoc
+//
+//	@Summary	Get assignments of a course, unpublished ones included
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.AssignmentResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/assignments [get]
+//	@Security	BearerAuth
+func (h AssignmentHandler) FetchAssignments(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := h.assignmentSvc.GetAssignments(teacher, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapAssignmentsResDto(assignments)), nil
+}
+
+// GetAssignment godoc
+//
+//	@Summary	Get an assignment with its rubric
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		assignment-id	path		int	true	"Assignment ID"
+//	@Success	200				{object}	types.ApiResponse{data=dtores.AssignmentResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignments/{assignment-id} [get]
+//	@Security	BearerAuth
+func (h AssignmentHandler) GetAssignment(ctx *gin.Context) (*types.ApiResponse, error) {
+	assignmentID, err := utils.ToUint(ctx.Param("assignment-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := h.assignmentSvc.GetAssignment(teacher, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewAssignmentResDto(assignment)), nil
+}
+
+// UpdateAssignment godoc
+//
+//	@Summary	Update an assignment, the rubric is changed through the criteria endpoints
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		assignment-id	path		int								true	"Assignment ID"
+//	@Param		request			body		dtoreq.UpdateAssignmentReqDto	true	" "
+//	@Success	200				{object}	types.ApiResponse{data=dtores.AssignmentResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignments/{assignment-id} [patch]
+//	@Security	BearerAuth
+func (h AssignmentHandler) UpdateAssignment(ctx *gin.Context) (*types.ApiResponse, error) {
+	assignmentID, err := utils.ToUint(ctx.Param("assignment-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.UpdateAssignmentReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.AssignmentID = assignmentID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := h.assignmentSvc.UpdateAssignment(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewAssignmentResDto(assignment)), nil
+}
+
+// DeleteAssignment godoc
+//
+//	@Summary	Delete an assignment, submissions are kept
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		assignment-id	path		int	true	"Assignment ID"
+//	@Success	200				{object}	types.ApiResponse
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignments/{assignment-id} [delete]
+//	@Security	BearerAuth
+func (h AssignmentHandler) DeleteAssignment(ctx *gin.Context) (*types.ApiResponse, error) {
+	assignmentID, err := utils.ToUint(ctx.Param("assignment-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.assignmentSvc.DeleteAssignment(teacher, assignmentID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// AddCriterion godoc
+//
+//	@Summary	Add a criterion to the rubric of an assignment
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		assignment-id	path		int									true	"Assignment ID"
+//	@Param		request			body		dtoreq.AssignmentCriterionReqDto	true	" "
+//	@Success	201				{object}	types.ApiResponse{data=dtores.AssignmentCriterionResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignments/{assignment-id}/criteria [post]
+//	@Security	BearerAuth
+func (h AssignmentHandler) AddCriterion(ctx *gin.Context) (*types.ApiResponse, error) {
+	assignmentID, err := utils.ToUint(ctx.Param("assignment-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.AddAssignmentCriterionReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.AssignmentID = assignmentID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	criterion, err := h.assignmentSvc.AddCriterion(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewAssignmentCriterionResDto(criterion)), nil
+}
+
+// UpdateCriterion godoc
+//
+//	@Summary	Update a rubric criterion, graded submissions keep their scores
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		criterion-id	path		int									true	"Criterion ID"
+//	@Param		request			body		dtoreq.AssignmentCriterionReqDto	true	" "
+//	@Success	200				{object}	types.ApiResponse{data=dtores.AssignmentCriterionResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignment-criteria/{criterion-id} [patch]
+//	@Security	BearerAuth
+func (h AssignmentHandler) UpdateCriterion(ctx *gin.Context) (*types.ApiResponse, error) {
+	criterionID, err := utils.ToUint(ctx.Param("criterion-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_criterion_id"),
+		)
+	}
+	dto := &dtoreq.UpdateAssignmentCriterionReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.CriterionID = criterionID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	criterion, err := h.assignmentSvc.UpdateCriterion(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewAssignmentCriterionResDto(criterion)), nil
+}
+
+// DeleteCriterion godoc
+//
+//	@Summary	Remove a criterion from the rubric of an assignment
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		criterion-id	path		int	true	"Criterion ID"
+//	@Success	200				{object}	types.ApiResponse
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignment-criteria/{criterion-id} [delete]
+//	@Security	BearerAuth
+func (h AssignmentHandler) DeleteCriterion(ctx *gin.Context) (*types.ApiResponse, error) {
+	criterionID, err := utils.ToUint(ctx.Param("criterion-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_criterion_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.assignmentSvc.DeleteCriterion(teacher, criterionID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// FetchGradingQueue godoc
+//
+//	@Summary	Get submissions of the courses of the teacher, oldest first and waiting for a grade by default
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		course-id		query		int		false	"Course ID"
+//	@Param		assignment-id	query		int		false	"Assignment ID"
+//	@Param		status			query		string	false	"submitted, graded, resubmission_requested or all"	default(submitted)
+//	@Param		page			query		int		false	"Page number"										default(0)
+//	@Param		pageSize		query		int		false	"Page size"											default(10)
+//	@Success	200				{object}	types.ApiResponse{data=types.PaginationRes{row=[]dtores.GradingQueueItemDto}}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignment-submissions [get]
+//	@Security	BearerAuth
+func (h AssignmentHandler) FetchGradingQueue(ctx *gin.Context) (*types.ApiResponse, error) {
+	page, pageSize := utils.ExtractPaginationMetadata(ctx.Query("page"), ctx.Query("pageSize"))
+	dto := dtoreq.GetGradingQueueReqDto{
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if courseIDParam := ctx.Query("course-id"); courseIDParam != "" {
+		courseID, err := utils.ToUint(courseIDParam)
+		if err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("course.errors.invalid_course_id"),
+			)
+		}
+		dto.CourseID = &courseID
+	}
+	if assignmentIDParam := ctx.Query("assignment-id"); assignmentIDParam != "" {
+		assignmentID, err := utils.ToUint(assignmentIDParam)
+		if err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("assignment.errors.invalid_id"),
+			)
+		}
+		dto.AssignmentID = &assignmentID
+	}
+	status := entities.AssignmentSubmissionStatus_Submitted
+	if statusParam := ctx.Query("status"); statusParam != "" {
+		status = entities.AssignmentSubmissionStatus(statusParam)
+	}
+	if status != gradingQueueStatusAll {
+		dto.Status = &status
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	submissions, count, err := h.assignmentSvc.GetGradingQueue(teacher, dto)
+	if err != nil {
+		return nil, err
+	}
+	submissionsRes := types.NewPaginationRes(
+		dtores.MapGradingQueueItemsDto(submissions),
+		page,
+		utils.CalculatePaginationTotalPage(count, pageSize),
+		count,
+	)
+	return types.NewApiResponse(http.StatusOK, submissionsRes), nil
+}
+
+// GetSubmission godoc
+//
+//	@Summary	Get a submission with the rubric of its assignment, the file is downloaded through the assignments api
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		submission-id	path		int	true	"Submission ID"
+//	@Success	200				{object}	types.ApiResponse{data=dtores.AssignmentSubmissionResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignment-submissions/{submission-id} [get]
+//	@Security	BearerAuth
+func (h AssignmentHandler) GetSubmission(ctx *gin.Context) (*types.ApiResponse, error) {
+	submissionID, err := utils.ToUint(ctx.Param("submission-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_submission_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	submission, err := h.assignmentSvc.GetSubmission(teacher, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewAssignmentSubmissionResDto(submission)), nil
+}
+
+// GradeSubmission godoc
+//
+//	@Summary	Grade a submission on every rubric criterion, the student is notified
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		submission-id	path		int								true	"Submission ID"
+//	@Param		request			body		dtoreq.GradeSubmissionReqDto	true	" "
+//	@Success	200				{object}	types.ApiResponse{data=dtores.AssignmentSubmissionResDto}
+//	@Failure	400				{object}	types.ApiError
+//	@Failure	401				{object}	types.ApiError
+//	@Failure	403				{object}	types.ApiError
+//	@Failure	404				{object}	types.ApiError
+//	@Failure	409				{object}	types.ApiError
+//	@Failure	500				{object}	types.ApiError
+//	@Router		/teacher/assignment-submissions/{submission-id}/grade [post]
+//	@Security	BearerAuth
+func (h AssignmentHandler) GradeSubmission(ctx *gin.Context) (*types.ApiResponse, error) {
+	submissionID, err := utils.ToUint(ctx.Param("submission-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("assignment.errors.invalid_submission_id"),
+		)
+	}
+	dto := &dtoreq.GradeSubmissionReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.SubmissionID = submissionID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	submission, err := h.assignmentSvc.GradeSubmission(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewAssignmentSubmissionResDto(submission)), nil
+}
@@ -101,3 +101,138 @@ func (h CourseHandler) FetchCourses(ctx *gin.Context) (*types.ApiResponse, error
 	)
 	return types.NewApiResponse(http.StatusOK, coursesRes), nil
 }
+
+// CloneCourse godoc
+//
+//	@Summary	Clone course as a new draft course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int							true	"Course ID"
+//	@Param		request		body		dtoreq.CloneCourseReqDto	true	" "
+//	@Success	201			{object}	types.ApiResponse{data=dtores.CreateCourseResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/clone [post]
+//
+//	@Security	BearerAuth
+func (h CourseHandler) CloneCourse(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.CloneCourseReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.ID = courseID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	course, err := h.courseSvc.Clone(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewCreateCourseResDto(course)), nil
+}
+
+// PublishCourseVersion godoc
+//
+//	@Summary	Publish current content of course as a new version
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int									true	"Course ID"
+//	@Param		request		body		dtoreq.PublishCourseVersionReqDto	true	" "
+//	@Success	201			{object}	types.ApiResponse{data=dtores.PublishCourseVersionResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/versions [post]
+//
+//	@Security	BearerAuth
+func (h CourseHandler) PublishCourseVersion(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.PublishCourseVersionReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.ID = courseID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := h.courseSvc.PublishVersion(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewPublishCourseVersionResDto(version)), nil
+}
+
+// UpdateCourseWatermark godoc
+//
+//	@Summary	Update watermark mode used for newly encoded videos of course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int									true	"Course ID"
+//	@Param		request		body		dtoreq.UpdateCourseWatermarkReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/watermark [patch]
+//
+//	@Security	BearerAuth
+func (h CourseHandler) UpdateCourseWatermark(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.UpdateCourseWatermarkReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.ID = courseID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.courseSvc.UpdateWatermark(teacher, *dto); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
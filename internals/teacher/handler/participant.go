@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/teacher/dto/res"
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+	"time"
+)
+
+type ParticipantHandler struct {
+	participantSvc service.TeacherParticipantService
+	validationSvc  contracts.Validation
+	translationSvc contracts.Translator
+	userSvc        userService.UserSvc
+}
+
+func NewParticipantHandler(
+	participantSvc service.TeacherParticipantService,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+	userSvc userService.UserSvc,
+) *ParticipantHandler {
+	return &ParticipantHandler{
+		participantSvc: participantSvc,
+		validationSvc:  validationSvc,
+		translationSvc: translationSvc,
+		userSvc:        userSvc,
+	}
+}
+
+// AddParticipant godoc
+//
+//	@Summary	Enroll a student to course without payment
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int							true	"Course ID"
+//	@Param		request		body		dtoreq.AddParticipantReqDto	true	" "
+//	@Success	201			{object}	types.ApiResponse{data=dtores.AddParticipantResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/participants [post]
+//
+//	@Security	BearerAuth
+func (h ParticipantHandler) AddParticipant(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.AddParticipantReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.CourseID = courseID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	participant, err := h.participantSvc.Add(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewAddParticipantResDto(participant)), nil
+}
+
+// BulkEnroll godoc
+//
+//	@Summary	Enroll students of a csv file to course, the file needs a phone column
+//	@Tags		teacher
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		course-id	path		int		true	"Course ID"
+//	@Param		file		formData	file	true	"CSV file"
+//	@Param		reason		formData	string	true	"Reason"
+//	@Param		expiresAt	formData	string	false	"Expiry date (RFC3339)"
+//	@Success	201			{object}	types.ApiResponse{data=dtores.BulkEnrollResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/participants/bulk [post]
+//
+//	@Security	BearerAuth
+func (h ParticipantHandler) BulkEnroll(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("participant.errors.file_required"),
+		)
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("participant.errors.file_required"),
+		)
+	}
+	defer file.Close()
+	dto := &dtoreq.BulkEnrollReqDto{
+		CourseID: courseID,
+		Reason:   ctx.PostForm("reason"),
+		File:     file,
+	}
+	if expiresAt := ctx.PostForm("expiresAt"); expiresAt != "" {
+		parsedExpiresAt, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("participant.errors.invalid_expires_at"),
+			)
+		}
+		dto.ExpiresAt = &parsedExpiresAt
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := h.participantSvc.BulkEnroll(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewBulkEnrollResDto(result)), nil
+}
+
+// UpdateParticipantExpiry godoc
+//
+//	@Summary	Change or clear expiry date of a course participant
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int										true	"Course ID"
+//	@Param		student-id	path		int										true	"Student ID"
+//	@Param		request		body		dtoreq.UpdateParticipantExpiryReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/participants/{student-id} [patch]
+//
+//	@Security	BearerAuth
+func (h ParticipantHandler) UpdateParticipantExpiry(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, studentID, err := h.parseParticipantParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dto := &dtoreq.UpdateParticipantExpiryReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.CourseID = courseID
+	dto.StudentID = studentID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.participantSvc.UpdateExpiry(teacher, *dto); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// RemoveParticipant godoc
+//
+//	@Summary	Remove a student from course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int								true	"Course ID"
+//	@Param		student-id	path		int								true	"Student ID"
+//	@Param		request		body		dtoreq.RemoveParticipantReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/participants/{student-id} [delete]
+//
+//	@Security	BearerAuth
+func (h ParticipantHandler) RemoveParticipant(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, studentID, err := h.parseParticipantParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dto := &dtoreq.RemoveParticipantReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.CourseID = courseID
+	dto.StudentID = studentID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.participantSvc.Remove(teacher, *dto); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// FetchParticipants godoc
+//
+//	@Summary	Get participants of course including expired ones
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Param		page		query		int	false	"Page number"	default(0)
+//	@Param		pageSize	query		int	false	"Page size"		default(10)
+//	@Success	200			{object}	types.ApiResponse{data=types.PaginationRes{row=[]dtores.FetchParticipantItemDto}}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/participants [get]
+//
+//	@Security	BearerAuth
+func (h ParticipantHandler) FetchParticipants(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	page, pageSize := utils.ExtractPaginationMetadata(ctx.Query("page"), ctx.Query("pageSize"))
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	participants, count, err := h.participantSvc.FetchByCourseID(teacher, courseID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	participantsRes := types.NewPaginationRes(
+		dtores.MapFetchParticipantItemsDto(participants),
+		page,
+		utils.CalculatePaginationTotalPage(count, pageSize),
+		count,
+	)
+	return types.NewApiResponse(http.StatusOK, participantsRes), nil
+}
+
+// FetchEnrollmentAudits godoc
+//
+//	@Summary	Get enrollment audit trail of course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Param		page		query		int	false	"Page number"	default(0)
+//	@Param		pageSize	query		int	false	"Page size"		default(10)
+//	@Success	200			{object}	types.ApiResponse{data=types.PaginationRes{row=[]dtores.FetchEnrollmentAuditItemDto}}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/participants/audits [get]
+//
+//	@Security	BearerAuth
+func (h ParticipantHandler) FetchEnrollmentAudits(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	page, pageSize := utils.ExtractPaginationMetadata(ctx.Query("page"), ctx.Query("pageSize"))
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	audits, count, err := h.participantSvc.FetchAudits(teacher, courseID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	auditsRes := types.NewPaginationRes(
+		dtores.MapFetchEnrollmentAuditItemsDto(audits),
+		page,
+		utils.CalculatePaginationTotalPage(count, pageSize),
+		count,
+	)
+	return types.NewApiResponse(http.StatusOK, auditsRes), nil
+}
+
+func (h ParticipantHandler) parseParticipantParams(ctx *gin.Context) (uint, uint, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return 0, 0, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	studentID, err := utils.ToUint(ctx.Param("student-id"))
+	if err != nil {
+		return 0, 0, types.NewBadRequestError(
+			h.translationSvc.Translate("participant.errors.invalid_student_id"),
+		)
+	}
+	return courseID, studentID, nil
+}
@@ -0,0 +1,368 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/teacher/dto/res"
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type QuizHandler struct {
+	quizSvc        service.TeacherQuizService
+	validationSvc  contracts.Validation
+	translationSvc contracts.Translator
+	userSvc        userService.UserSvc
+}
+
+func NewQuizHandler(
+	quizSvc service.TeacherQuizService,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+	userSvc userService.UserSvc,
+) *QuizHandler {
+	return &QuizHandler{
+		quizSvc:        quizSvc,
+		validationSvc:  validationSvc,
+		translationSvc: translationSvc,
+		userSvc:        userSvc,
+	}
+}
+
+// CreateQuiz godoc
+//
+//	@Summary	Create a quiz for a course or one of its lessons
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int							true	"Course ID"
+//	@Param		request		body		dtoreq.CreateQuizReqDto		true	" "
+//	@Success	201			{object}	types.ApiResponse{data=dtores.QuizResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/quizzes [post]
+//	@Security	BearerAuth
+func (h QuizHandler) CreateQuiz(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.CreateQuizReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.CourseID = courseID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := h.quizSvc.CreateQuiz(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewQuizResDto(quiz)), nil
+}
+
+// FetchQuizzes godoc
+//
+//	@Summary	Get quizzes of a course, unpublished ones included
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.QuizResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/quizzes [get]
+//	@Security	BearerAuth
+func (h QuizHandler) FetchQuizzes(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quizzes, err := h.quizSvc.GetQuizzes(teacher, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapQuizzesResDto(quizzes)), nil
+}
+
+// GetQuiz godoc
+//
+//	@Summary	Get a quiz with its questions and answer keys
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		quiz-id	path		int	true	"Quiz ID"
+//	@Success	200		{object}	types.ApiResponse{data=dtores.QuizResDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	403		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/teacher/quizzes/{quiz-id} [get]
+//	@Security	BearerAuth
+func (h QuizHandler) GetQuiz(ctx *gin.Context) (*types.ApiResponse, error) {
+	quizID, err := utils.ToUint(ctx.Param("quiz-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := h.quizSvc.GetQuiz(teacher, quizID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewQuizResDto(quiz)), nil
+}
+
+// UpdateQuiz godoc
+//
+//	@Summary	Edit settings of a quiz
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		quiz-id	path		int						true	"Quiz ID"
+//	@Param		request	body		dtoreq.UpdateQuizReqDto	true	" "
+//	@Success	200		{object}	types.ApiResponse{data=dtores.QuizResDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	403		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/teacher/quizzes/{quiz-id} [patch]
+//	@Security	BearerAuth
+func (h QuizHandler) UpdateQuiz(ctx *gin.Context) (*types.ApiResponse, error) {
+	quizID, err := utils.ToUint(ctx.Param("quiz-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.UpdateQuizReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.QuizID = quizID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := h.quizSvc.UpdateQuiz(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewQuizResDto(quiz)), nil
+}
+
+// DeleteQuiz godoc
+//
+//	@Summary	Delete a quiz, attempts already made are kept
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		quiz-id	path		int	true	"Quiz ID"
+//	@Success	200		{object}	types.ApiResponse
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	403		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/teacher/quizzes/{quiz-id} [delete]
+//	@Security	BearerAuth
+func (h QuizHandler) DeleteQuiz(ctx *gin.Context) (*types.ApiResponse, error) {
+	quizID, err := utils.ToUint(ctx.Param("quiz-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.quizSvc.DeleteQuiz(teacher, quizID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// AddQuestion godoc
+//
+//	@Summary	Add a single choice, multiple choice, true/false or short answer question to a quiz
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		quiz-id	path		int							true	"Quiz ID"
+//	@Param		request	body		dtoreq.QuizQuestionReqDto	true	" "
+//	@Success	201		{object}	types.ApiResponse{data=dtores.QuizQuestionResDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	403		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/teacher/quizzes/{quiz-id}/questions [post]
+//	@Security	BearerAuth
+func (h QuizHandler) AddQuestion(ctx *gin.Context) (*types.ApiResponse, error) {
+	quizID, err := utils.ToUint(ctx.Param("quiz-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.AddQuizQuestionReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.QuizID = quizID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	question, err := h.quizSvc.AddQuestion(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewQuizQuestionResDto(question)), nil
+}
+
+// UpdateQuestion godoc
+//
+//	@Summary	Edit a quiz question and its answer key
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		question-id	path		int							true	"Question ID"
+//	@Param		request		body		dtoreq.QuizQuestionReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse{data=dtores.QuizQuestionResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/quiz-questions/{question-id} [patch]
+//	@Security	BearerAuth
+func (h QuizHandler) UpdateQuestion(ctx *gin.Context) (*types.ApiResponse, error) {
+	questionID, err := utils.ToUint(ctx.Param("question-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_question_id"),
+		)
+	}
+	dto := &dtoreq.UpdateQuizQuestionReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.QuestionID = questionID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	question, err := h.quizSvc.UpdateQuestion(teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewQuizQuestionResDto(question)), nil
+}
+
+// DeleteQuestion godoc
+//
+//	@Summary	Delete a quiz question
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		question-id	path		int	true	"Question ID"
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/quiz-questions/{question-id} [delete]
+//	@Security	BearerAuth
+func (h QuizHandler) DeleteQuestion(ctx *gin.Context) (*types.ApiResponse, error) {
+	questionID, err := utils.ToUint(ctx.Param("question-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_question_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.quizSvc.DeleteQuestion(teacher, questionID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// GetQuizAnalytics godoc
+//
+//	@Summary	Get pass rate of a quiz and how each of its questions was answered
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		quiz-id	path		int	true	"Quiz ID"
+//	@Success	200		{object}	types.ApiResponse{data=dtores.GetQuizAnalyticsResDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	403		{object}	types.ApiError
+//	@Failure	404		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/teacher/quizzes/{quiz-id}/analytics [get]
+//	@Security	BearerAuth
+func (h QuizHandler) GetQuizAnalytics(ctx *gin.Context) (*types.ApiResponse, error) {
+	quizID, err := utils.ToUint(ctx.Param("quiz-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("quiz.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	analytics, err := h.quizSvc.GetAnalytics(teacher, quizID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetQuizAnalyticsResDto(analytics)), nil
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/teacher/dto/res"
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type ResourceHandler struct {
+	resourceSvc    service.TeacherResourceService
+	validationSvc  contracts.Validation
+	translationSvc contracts.Translator
+	userSvc        userService.UserSvc
+}
+
+func NewResourceHandler(
+	resourceSvc service.TeacherResourceService,
+	validationSvc contracts.Validation,
+	translationSvc contracts.Translator,
+	userSvc userService.UserSvc,
+) *ResourceHandler {
+	return &ResourceHandler{
+		resourceSvc:    resourceSvc,
+		validationSvc:  validationSvc,
+		translationSvc: translationSvc,
+		userSvc:        userSvc,
+	}
+}
+
+// UploadResource godoc
+//
+//	@Summary	Upload a downloadable resource to a course or one of its lessons
+//	@Tags		teacher
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		course-id	path		int		true	"Course ID"
+//	@Param		file		formData	file	true	"Slides, archives, documents or source files"
+//	@Param		title		formData	string	true	"Title shown to students"
+//	@Param		videoId		formData	int		false	"Lesson the resource belongs to"
+//	@Success	201			{object}	types.ApiResponse{data=dtores.CourseResourceResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/resources [post]
+//	@Security	BearerAuth
+func (h ResourceHandler) UploadResource(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("resource.errors.file_required"),
+		)
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("resource.errors.file_required"),
+		)
+	}
+	defer file.Close()
+	dto := &dtoreq.UploadCourseResourceReqDto{
+		CourseID: courseID,
+		Title:    ctx.PostForm("title"),
+		FileName: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		File:     file,
+	}
+	if videoIDParam := ctx.PostForm("videoId"); videoIDParam != "" {
+		videoID, err := utils.ToUint(videoIDParam)
+		if err != nil {
+			return nil, types.NewBadRequestError(
+				h.translationSvc.Translate("video.errors.invalid_id"),
+			)
+		}
+		dto.VideoID = &videoID
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := h.resourceSvc.Upload(ctx, teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusCreated, dtores.NewCourseResourceResDto(resource)), nil
+}
+
+// FetchResources godoc
+//
+//	@Summary	Get resources of a course with their download counts
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.CourseResourceResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/resources [get]
+//	@Security	BearerAuth
+func (h ResourceHandler) FetchResources(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := h.resourceSvc.GetResources(teacher, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapCourseResourcesResDto(resources)), nil
+}
+
+// FetchResourceDownloads godoc
+//
+//	@Summary	Get how often each student downloaded a resource
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		resource-id	path		int	true	"Resource ID"
+//	@Param		page		query		int	false	"Page number"		default(0)
+//	@Param		pageSize	query		int	false	"Items per page"	default(10)
+//	@Success	200			{object}	types.ApiResponse{data=types.PaginationRes{row=[]dtores.ResourceDownloadItemDto}}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/resources/{resource-id}/downloads [get]
+//	@Security	BearerAuth
+func (h ResourceHandler) FetchResourceDownloads(ctx *gin.Context) (*types.ApiResponse, error) {
+	resourceID, err := utils.ToUint(ctx.Param("resource-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("resource.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := utils.ExtractPaginationMetadata(ctx.Query("page"), ctx.Query("pageSize"))
+	downloads, count, err := h.resourceSvc.GetDownloads(teacher, resourceID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	downloadsRes := types.NewPaginationRes(
+		dtores.MapResourceDownloadItemsDto(downloads),
+		page,
+		utils.CalculatePaginationTotalPage(count, pageSize),
+		count,
+	)
+	return types.NewApiResponse(http.StatusOK, downloadsRes), nil
+}
+
+// DeleteResource godoc
+//
+//	@Summary	Delete a resource and its file
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		resource-id	path		int	true	"Resource ID"
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/resources/{resource-id} [delete]
+//	@Security	BearerAuth
+func (h ResourceHandler) DeleteResource(ctx *gin.Context) (*types.ApiResponse, error) {
+	resourceID, err := utils.ToUint(ctx.Param("resource-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("resource.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.resourceSvc.Delete(ctx, teacher, resourceID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"net/http"
+)
+
+type ScheduleHandler struct {
+	scheduleSvc    service.TeacherScheduleService
+	translationSvc contracts.Translator
+	userSvc        userService.UserSvc
+}
+
+func NewScheduleHandler(
+	scheduleSvc service.TeacherScheduleService,
+	translationSvc contracts.Translator,
+	userSvc userService.UserSvc,
+) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleSvc:    scheduleSvc,
+		translationSvc: translationSvc,
+		userSvc:        userSvc,
+	}
+}
+
+// ScheduleCourse godoc
+//
+//	@Summary	Schedule publish and unpublish time of a course
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		course-id	path		int							true	"Course ID"
+//	@Param		request		body		dtoreq.SchedulePublishReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/schedule [put]
+//	@Security	BearerAuth
+func (h ScheduleHandler) ScheduleCourse(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	dto := &dtoreq.SchedulePublishReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.ID = courseID
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.scheduleSvc.ScheduleCourse(ctx, teacher, *dto); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// CancelCourseSchedule godoc
+//
+//	@Summary	Cancel publish schedule of a course
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		course-id	path		int	true	"Course ID"
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/courses/{course-id}/schedule [delete]
+//	@Security	BearerAuth
+func (h ScheduleHandler) CancelCourseSchedule(ctx *gin.Context) (*types.ApiResponse, error) {
+	courseID, err := utils.ToUint(ctx.Param("course-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("course.errors.invalid_course_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.scheduleSvc.CancelCourseSchedule(ctx, teacher, courseID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// ScheduleVideo godoc
+//
+//	@Summary	Schedule publish and unpublish time of a video
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		video-id	path		int							true	"Video ID"
+//	@Param		request		body		dtoreq.SchedulePublishReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id}/schedule [put]
+//	@Security	BearerAuth
+func (h ScheduleHandler) ScheduleVideo(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.SchedulePublishReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.ID = videoID
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.scheduleSvc.ScheduleVideo(ctx, teacher, *dto); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// CancelVideoSchedule godoc
+//
+//	@Summary	Cancel publish schedule of a video
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		video-id	path		int	true	"Video ID"
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id}/schedule [delete]
+//	@Security	BearerAuth
+func (h ScheduleHandler) CancelVideoSchedule(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.scheduleSvc.CancelVideoSchedule(ctx, teacher, videoID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
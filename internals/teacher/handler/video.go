@@ -5,8 +5,10 @@ import (
 	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
 	dtores "github.com/ladmakhi81/learnup/internals/teacher/dto/res"
 	"github.com/ladmakhi81/learnup/internals/teacher/service"
+	userService "github.com/ladmakhi81/learnup/internals/user/service"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
 	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
 	"net/http"
 )
 
@@ -14,17 +16,20 @@ type VideoHandler struct {
 	videoSvc       service.TeacherVideoService
 	translationSvc contracts.Translator
 	validationSvc  contracts.Validation
+	userSvc        userService.UserSvc
 }
 
 func NewVideoHandler(
 	videoSvc service.TeacherVideoService,
 	translationSvc contracts.Translator,
 	validationSvc contracts.Validation,
+	userSvc userService.UserSvc,
 ) *VideoHandler {
 	return &VideoHandler{
 		videoSvc:       videoSvc,
 		translationSvc: translationSvc,
 		validationSvc:  validationSvc,
+		userSvc:        userSvc,
 	}
 }
 
@@ -59,3 +64,284 @@ func (h VideoHandler) AddVideoToCourse(ctx *gin.Context) (*types.ApiResponse, er
 	}
 	return types.NewApiResponse(http.StatusCreated, dtores.NewAddVideoToCourseResDto(video)), nil
 }
+
+// UpdateVideoThumbnail godoc
+//
+//	@Summary	Pick the poster frame of an encoded video by teacher
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		video-id	path		int									true	"Video ID"
+//	@Param		request		body		dtoreq.UpdateVideoThumbnailReqDto	true	" "
+//	@Success	202			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id}/thumbnail [patch]
+//	@Security	BearerAuth
+func (h VideoHandler) UpdateVideoThumbnail(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.UpdateVideoThumbnailReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.VideoID = videoID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.videoSvc.UpdateThumbnail(ctx, teacher, *dto); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusAccepted, nil), nil
+}
+
+// GetVideoCaptions godoc
+//
+//	@Summary	List caption tracks of a video by teacher
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		video-id	path		int	true	"Video ID"
+//	@Success	200			{object}	types.ApiResponse{data=[]dtores.VideoCaptionResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id}/captions [get]
+//	@Security	BearerAuth
+func (h VideoHandler) GetVideoCaptions(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	captions, err := h.videoSvc.GetCaptions(teacher, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.MapVideoCaptionsResDto(captions)), nil
+}
+
+// UploadVideoCaption godoc
+//
+//	@Summary	Upload a srt or webvtt caption track of a video by teacher
+//	@Tags		teacher
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		video-id	path		int		true	"Video ID"
+//	@Param		language	path		string	true	"Language code, e.g. fa or en"
+//	@Param		file		formData	file	true	"SRT or WebVTT file"
+//	@Param		label		formData	string	true	"Label shown in the player"
+//	@Success	200			{object}	types.ApiResponse{data=dtores.VideoCaptionResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id}/captions/{language} [put]
+//	@Security	BearerAuth
+func (h VideoHandler) UploadVideoCaption(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.caption_file_required"),
+		)
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.caption_file_required"),
+		)
+	}
+	defer file.Close()
+	dto := &dtoreq.UploadVideoCaptionReqDto{
+		VideoID:  videoID,
+		Language: ctx.Param("language"),
+		Label:    ctx.PostForm("label"),
+		File:     file,
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	caption, err := h.videoSvc.UploadCaption(ctx, teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewVideoCaptionResDto(caption)), nil
+}
+
+// DeleteVideoCaption godoc
+//
+//	@Summary	Delete a caption track of a video by teacher
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		video-id	path		int		true	"Video ID"
+//	@Param		language	path		string	true	"Language code"
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id}/captions/{language} [delete]
+//	@Security	BearerAuth
+func (h VideoHandler) DeleteVideoCaption(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.DeleteVideoCaptionReqDto{
+		VideoID:  videoID,
+		Language: ctx.Param("language"),
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.videoSvc.DeleteCaption(ctx, teacher, *dto); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
+
+// ReprocessVideo godoc
+//
+//	@Summary	Run processing of a failed or pending video again by teacher
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		video-id	path		int	true	"Video ID"
+//	@Success	202			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id}/reprocess [post]
+//	@Security	BearerAuth
+func (h VideoHandler) ReprocessVideo(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.videoSvc.Reprocess(ctx, teacher, videoID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusAccepted, nil), nil
+}
+
+// UpdateVideo godoc
+//
+//	@Summary	Edit details of a video by teacher
+//	@Tags		teacher
+//	@Accept		json
+//	@Produce	json
+//	@Param		video-id	path		int							true	"Video ID"
+//	@Param		request		body		dtoreq.UpdateVideoReqDto	true	" "
+//	@Success	200			{object}	types.ApiResponse{data=dtores.UpdateVideoResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id} [patch]
+//	@Security	BearerAuth
+func (h VideoHandler) UpdateVideo(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	dto := &dtoreq.UpdateVideoReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	dto.VideoID = videoID
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	video, err := h.videoSvc.UpdateVideo(ctx, teacher, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewUpdateVideoResDto(video)), nil
+}
+
+// DeleteVideo godoc
+//
+//	@Summary	Delete a video and its files by teacher
+//	@Tags		teacher
+//	@Produce	json
+//	@Param		video-id	path		int	true	"Video ID"
+//	@Success	200			{object}	types.ApiResponse
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/teacher/videos/{video-id} [delete]
+//	@Security	BearerAuth
+func (h VideoHandler) DeleteVideo(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	teacher, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.videoSvc.DeleteVideo(ctx, teacher, videoID); err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, nil), nil
+}
@@ -11,12 +11,19 @@ import (
 )
 
 type Module struct {
-	courseHandler   *teacherHandler.CourseHandler
-	middleware      *middleware.Middleware
-	videoHandler    *teacherHandler.VideoHandler
-	commentHandler  *teacherHandler.CommentHandler
-	questionHandler *teacherHandler.QuestionHandler
-	translationSvc  contracts.Translator
+	courseHandler       *teacherHandler.CourseHandler
+	middleware          *middleware.Middleware
+	videoHandler        *teacherHandler.VideoHandler
+	commentHandler      *teacherHandler.CommentHandler
+	questionHandler     *teacherHandler.QuestionHandler
+	scheduleHandler     *teacherHandler.ScheduleHandler
+	analyticsHandler    *teacherHandler.AnalyticsHandler
+	announcementHandler *teacherHandler.AnnouncementHandler
+	participantHandler  *teacherHandler.ParticipantHandler
+	resourceHandler     *teacherHandler.ResourceHandler
+	quizHandler         *teacherHandler.QuizHandler
+	assignmentHandler   *teacherHandler.AssignmentHandler
+	translationSvc      contracts.Translator
 }
 
 func NewModule(
@@ -24,6 +31,13 @@ func NewModule(
 	teacherVideoSvc teacherService.TeacherVideoService,
 	teacherCommentSvc teacherService.TeacherCommentService,
 	teacherQuestionSvc teacherService.TeacherQuestionService,
+	teacherScheduleSvc teacherService.TeacherScheduleService,
+	teacherAnalyticsSvc teacherService.TeacherAnalyticsService,
+	teacherAnnouncementSvc teacherService.TeacherAnnouncementService,
+	teacherParticipantSvc teacherService.TeacherParticipantService,
+	teacherResourceSvc teacherService.TeacherResourceService,
+	teacherQuizSvc teacherService.TeacherQuizService,
+	teacherAssignmentSvc teacherService.TeacherAssignmentService,
 	validationSvc contracts.Validation,
 	userSvc userService.UserSvc,
 	middleware *middleware.Middleware,
@@ -41,6 +55,7 @@ func NewModule(
 			teacherVideoSvc,
 			translationSvc,
 			validationSvc,
+			userSvc,
 		),
 		commentHandler: teacherHandler.NewCommentHandler(
 			teacherCommentSvc,
@@ -52,6 +67,47 @@ func NewModule(
 			teacherQuestionSvc,
 			userSvc,
 		),
+		scheduleHandler: teacherHandler.NewScheduleHandler(
+			teacherScheduleSvc,
+			translationSvc,
+			userSvc,
+		),
+		analyticsHandler: teacherHandler.NewAnalyticsHandler(
+			teacherAnalyticsSvc,
+			validationSvc,
+			translationSvc,
+			userSvc,
+		),
+		announcementHandler: teacherHandler.NewAnnouncementHandler(
+			teacherAnnouncementSvc,
+			validationSvc,
+			translationSvc,
+			userSvc,
+		),
+		participantHandler: teacherHandler.NewParticipantHandler(
+			teacherParticipantSvc,
+			validationSvc,
+			translationSvc,
+			userSvc,
+		),
+		resourceHandler: teacherHandler.NewResourceHandler(
+			teacherResourceSvc,
+			validationSvc,
+			translationSvc,
+			userSvc,
+		),
+		quizHandler: teacherHandler.NewQuizHandler(
+			teacherQuizSvc,
+			validationSvc,
+			translationSvc,
+			userSvc,
+		),
+		assignmentHandler: teacherHandler.NewAssignmentHandler(
+			teacherAssignmentSvc,
+			validationSvc,
+			translationSvc,
+			userSvc,
+		),
 		translationSvc: translationSvc,
 	}
 }
@@ -63,7 +119,55 @@ func (m Module) Register(api *gin.RouterGroup) {
 
 	teacherApi.POST("/course", utils.JsonHandler(m.translationSvc, m.courseHandler.CreateCourse))
 	teacherApi.GET("/courses", utils.JsonHandler(m.translationSvc, m.courseHandler.FetchCourses))
+	teacherApi.POST("/courses/:course-id/clone", utils.JsonHandler(m.translationSvc, m.courseHandler.CloneCourse))
+	teacherApi.POST("/courses/:course-id/versions", utils.JsonHandler(m.translationSvc, m.courseHandler.PublishCourseVersion))
+	teacherApi.PATCH("/courses/:course-id/watermark", utils.JsonHandler(m.translationSvc, m.courseHandler.UpdateCourseWatermark))
+	teacherApi.GET("/courses/:course-id/analytics", utils.JsonHandler(m.translationSvc, m.analyticsHandler.GetCourseAnalytics))
+	teacherApi.POST("/courses/:course-id/announcements", utils.JsonHandler(m.translationSvc, m.announcementHandler.CreateAnnouncement))
+	teacherApi.GET("/courses/:course-id/announcements", utils.JsonHandler(m.translationSvc, m.announcementHandler.FetchAnnouncements))
+	teacherApi.DELETE("/announcements/:announcement-id", utils.JsonHandler(m.translationSvc, m.announcementHandler.CancelAnnouncement))
+	teacherApi.GET("/courses/:course-id/participants", utils.JsonHandler(m.translationSvc, m.participantHandler.FetchParticipants))
+	teacherApi.POST("/courses/:course-id/participants", utils.JsonHandler(m.translationSvc, m.participantHandler.AddParticipant))
+	teacherApi.POST("/courses/:course-id/participants/bulk", utils.JsonHandler(m.translationSvc, m.participantHandler.BulkEnroll))
+	teacherApi.GET("/courses/:course-id/participants/audits", utils.JsonHandler(m.translationSvc, m.participantHandler.FetchEnrollmentAudits))
+	teacherApi.PATCH("/courses/:course-id/participants/:student-id", utils.JsonHandler(m.translationSvc, m.participantHandler.UpdateParticipantExpiry))
+	teacherApi.DELETE("/courses/:course-id/participants/:student-id", utils.JsonHandler(m.translationSvc, m.participantHandler.RemoveParticipant))
+	teacherApi.POST("/courses/:course-id/resources", utils.JsonHandler(m.translationSvc, m.resourceHandler.UploadResource))
+	teacherApi.GET("/courses/:course-id/resources", utils.JsonHandler(m.translationSvc, m.resourceHandler.FetchResources))
+	teacherApi.GET("/resources/:resource-id/downloads", utils.JsonHandler(m.translationSvc, m.resourceHandler.FetchResourceDownloads))
+	teacherApi.DELETE("/resources/:resource-id", utils.JsonHandler(m.translationSvc, m.resourceHandler.DeleteResource))
+	teacherApi.POST("/courses/:course-id/quizzes", utils.JsonHandler(m.translationSvc, m.quizHandler.CreateQuiz))
+	teacherApi.GET("/courses/:course-id/quizzes", utils.JsonHandler(m.translationSvc, m.quizHandler.FetchQuizzes))
+	teacherApi.GET("/quizzes/:quiz-id", utils.JsonHandler(m.translationSvc, m.quizHandler.GetQuiz))
+	teacherApi.PATCH("/quizzes/:quiz-id", utils.JsonHandler(m.translationSvc, m.quizHandler.UpdateQuiz))
+	teacherApi.DELETE("/quizzes/:quiz-id", utils.JsonHandler(m.translationSvc, m.quizHandler.DeleteQuiz))
+	teacherApi.GET("/quizzes/:quiz-id/analytics", utils.JsonHandler(m.translationSvc, m.quizHandler.GetQuizAnalytics))
+	teacherApi.POST("/quizzes/:quiz-id/questions", utils.JsonHandler(m.translationSvc, m.quizHandler.AddQuestion))
+	teacherApi.PATCH("/quiz-questions/:question-id", utils.JsonHandler(m.translationSvc, m.quizHandler.UpdateQuestion))
+	teacherApi.DELETE("/quiz-questions/:question-id", utils.JsonHandler(m.translationSvc, m.quizHandler.DeleteQuestion))
+	teacherApi.POST("/courses/:course-id/assignments", utils.JsonHandler(m.translationSvc, m.assignmentHandler.CreateAssignment))
+	teacherApi.GET("/courses/:course-id/assignments", utils.JsonHandler(m.translationSvc, m.assignmentHandler.FetchAssignments))
+	teacherApi.GET("/assignments/:assignment-id", utils.JsonHandler(m.translationSvc, m.assignmentHandler.GetAssignment))
+	teacherApi.PATCH("/assignments/:assignment-id", utils.JsonHandler(m.translationSvc, m.assignmentHandler.UpdateAssignment))
+	teacherApi.DELETE("/assignments/:assignment-id", utils.JsonHandler(m.translationSvc, m.assignmentHandler.DeleteAssignment))
+	teacherApi.POST("/assignments/:assignment-id/criteria", utils.JsonHandler(m.translationSvc, m.assignmentHandler.AddCriterion))
+	teacherApi.PATCH("/assignment-criteria/:criterion-id", utils.JsonHandler(m.translationSvc, m.assignmentHandler.UpdateCriterion))
+	teacherApi.DELETE("/assignment-criteria/:criterion-id", utils.JsonHandler(m.translationSvc, m.assignmentHandler.DeleteCriterion))
+	teacherApi.GET("/assignment-submissions", utils.JsonHandler(m.translationSvc, m.assignmentHandler.FetchGradingQueue))
+	teacherApi.GET("/assignment-submissions/:submission-id", utils.JsonHandler(m.translationSvc, m.assignmentHandler.GetSubmission))
+	teacherApi.POST("/assignment-submissions/:submission-id/grade", utils.JsonHandler(m.translationSvc, m.assignmentHandler.GradeSubmission))
 	teacherApi.POST("/video", utils.JsonHandler(m.translationSvc, m.videoHandler.AddVideoToCourse))
+	teacherApi.PATCH("/videos/:video-id", utils.JsonHandler(m.translationSvc, m.videoHandler.UpdateVideo))
+	teacherApi.DELETE("/videos/:video-id", utils.JsonHandler(m.translationSvc, m.videoHandler.DeleteVideo))
+	teacherApi.PATCH("/videos/:video-id/thumbnail", utils.JsonHandler(m.translationSvc, m.videoHandler.UpdateVideoThumbnail))
+	teacherApi.POST("/videos/:video-id/reprocess", utils.JsonHandler(m.translationSvc, m.videoHandler.ReprocessVideo))
+	teacherApi.GET("/videos/:video-id/captions", utils.JsonHandler(m.translationSvc, m.videoHandler.GetVideoCaptions))
+	teacherApi.PUT("/videos/:video-id/captions/:language", utils.JsonHandler(m.translationSvc, m.videoHandler.UploadVideoCaption))
+	teacherApi.DELETE("/videos/:video-id/captions/:language", utils.JsonHandler(m.translationSvc, m.videoHandler.DeleteVideoCaption))
 	teacherApi.GET("/comments/:course-id", utils.JsonHandler(m.translationSvc, m.commentHandler.GetPageableCommentByCourseId))
 	teacherApi.GET("/questions", utils.JsonHandler(m.translationSvc, m.questionHandler.GetQuestions))
+	teacherApi.PUT("/courses/:course-id/schedule", utils.JsonHandler(m.translationSvc, m.scheduleHandler.ScheduleCourse))
+	teacherApi.DELETE("/courses/:course-id/schedule", utils.JsonHandler(m.translationSvc, m.scheduleHandler.CancelCourseSchedule))
+	teacherApi.PUT("/videos/:video-id/schedule", utils.JsonHandler(m.translationSvc, m.scheduleHandler.ScheduleVideo))
+	teacherApi.DELETE("/videos/:video-id/schedule", utils.JsonHandler(m.translationSvc, m.scheduleHandler.CancelVideoSchedule))
 }
@@ -0,0 +1,101 @@
+package service
+
+import (
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	teacherError "github.com/ladmakhi81/learnup/internals/teacher/error"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"time"
+)
+
+const maxAnalyticsDateRange = 2 * 365 * 24 * time.Hour
+
+type CourseAnalytics struct {
+	Filter           repositories.AnalyticsFilter
+	ParticipantCount int
+	Enrollments      []*repositories.TimeSeriesPoint
+	Revenue          []*repositories.TimeSeriesPoint
+	Likes            []*repositories.TimeSeriesPoint
+	Dislikes         []*repositories.TimeSeriesPoint
+	Comments         []*repositories.TimeSeriesPoint
+	Questions        []*repositories.TimeSeriesPoint
+	Videos           []*repositories.VideoEngagement
+	PreviewViews     []*repositories.TimeSeriesPoint
+	Previews         *repositories.PreviewConversion
+}
+
+type TeacherAnalyticsService interface {
+	GetCourseAnalytics(teacher *entities.User, dto dtoreq.GetCourseAnalyticsReqDto) (*CourseAnalytics, error)
+}
+
+type teacherAnalyticsService struct {
+	unitOfWork db.UnitOfWork
+}
+
+func NewTeacherAnalyticsSvc(unitOfWork db.UnitOfWork) TeacherAnalyticsService {
+	return &teacherAnalyticsService{
+		unitOfWork: unitOfWork,
+	}
+}
+
+func (svc teacherAnalyticsService) GetCourseAnalytics(teacher *entities.User, dto dtoreq.GetCourseAnalyticsReqDto) (*CourseAnalytics, error) {
+	const operationName = "teacherAnalyticsService.GetCourseAnalytics"
+	if !dto.To.After(dto.From) {
+		return nil, teacherError.Analytics_InvalidDateRange
+	}
+	if dto.To.Sub(dto.From) > maxAnalyticsDateRange {
+		return nil, teacherError.Analytics_DateRangeTooLong
+	}
+	course, err := svc.unitOfWork.CourseRepo().GetByID(dto.ID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	analyticsRepo := svc.unitOfWork.CourseAnalyticsRepo()
+	filter := repositories.AnalyticsFilter{
+		CourseID: course.ID,
+		Bucket:   dto.Bucket,
+		From:     dto.From,
+		To:       dto.To,
+	}
+	analytics := &CourseAnalytics{Filter: filter}
+	if analytics.ParticipantCount, err = analyticsRepo.CountParticipants(course.ID); err != nil {
+		return nil, types.NewServerError("Error in counting participants of course", operationName, err)
+	}
+	if analytics.Enrollments, err = analyticsRepo.EnrollmentSeries(filter); err != nil {
+		return nil, types.NewServerError("Error in aggregating enrollments of course", operationName, err)
+	}
+	if analytics.Revenue, err = analyticsRepo.RevenueSeries(filter); err != nil {
+		return nil, types.NewServerError("Error in aggregating revenue of course", operationName, err)
+	}
+	if analytics.Likes, err = analyticsRepo.LikeSeries(filter, entities.LikeType_Like); err != nil {
+		return nil, types.NewServerError("Error in aggregating likes of course", operationName, err)
+	}
+	if analytics.Dislikes, err = analyticsRepo.LikeSeries(filter, entities.LikeType_Dislike); err != nil {
+		return nil, types.NewServerError("Error in aggregating dislikes of course", operationName, err)
+	}
+	if analytics.Comments, err = analyticsRepo.CommentSeries(filter); err != nil {
+		return nil, types.NewServerError("Error in aggregating comments of course", operationName, err)
+	}
+	if analytics.Questions, err = analyticsRepo.QuestionSeries(filter); err != nil {
+		return nil, types.NewServerError("Error in aggregating questions of course", operationName, err)
+	}
+	if analytics.Videos, err = analyticsRepo.VideoEngagements(filter); err != nil {
+		return nil, types.NewServerError("Error in aggregating video engagements of course", operationName, err)
+	}
+	if analytics.PreviewViews, err = analyticsRepo.PreviewViewSeries(filter); err != nil {
+		return nil, types.NewServerError("Error in aggregating preview views of course", operationName, err)
+	}
+	if analytics.Previews, err = analyticsRepo.PreviewConversion(filter); err != nil {
+		return nil, types.NewServerError("Error in aggregating preview conversion of course", operationName, err)
+	}
+	return analytics, nil
+}
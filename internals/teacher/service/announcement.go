@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	announcementDtoReq "github.com/ladmakhi81/learnup/internals/announcement/dto/req"
+	announcementError "github.com/ladmakhi81/learnup/internals/announcement/error"
+	announcementWorkflow "github.com/ladmakhi81/learnup/internals/announcement/workflow"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/temporal"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"time"
+)
+
+type TeacherAnnouncementService interface {
+	Create(ctx context.Context, teacher *entities.User, dto dtoreq.CreateAnnouncementReqDto) (*entities.CourseAnnouncement, error)
+	Cancel(ctx context.Context, teacher *entities.User, announcementID uint) error
+	FetchByCourseID(teacher *entities.User, courseID uint, page, pageSize int) ([]*entities.CourseAnnouncement, map[uint]*repositories.AnnouncementReadStat, int, error)
+}
+
+type teacherAnnouncementService struct {
+	unitOfWork              db.UnitOfWork
+	temporalSvc             contracts.Temporal
+	announcementWorkflowSvc announcementWorkflow.AnnouncementWorkflow
+}
+
+func NewTeacherAnnouncementSvc(
+	unitOfWork db.UnitOfWork,
+	temporalSvc contracts.Temporal,
+	announcementWorkflowSvc announcementWorkflow.AnnouncementWorkflow,
+) TeacherAnnouncementService {
+	return &teacherAnnouncementService{
+		unitOfWork:              unitOfWork,
+		temporalSvc:             temporalSvc,
+		announcementWorkflowSvc: announcementWorkflowSvc,
+	}
+}
+
+func (svc teacherAnnouncementService) Create(ctx context.Context, teacher *entities.User, dto dtoreq.CreateAnnouncementReqDto) (*entities.CourseAnnouncement, error) {
+	const operationName = "teacherAnnouncementService.Create"
+	if dto.ScheduledAt != nil && !dto.ScheduledAt.After(time.Now()) {
+		return nil, announcementError.Announcement_InvalidScheduledAt
+	}
+	return db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*entities.CourseAnnouncement, error) {
+		course, err := tx.CourseRepo().GetByID(dto.CourseID, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+		}
+		if course == nil {
+			return nil, courseError.Course_NotFound
+		}
+		if !course.IsTeacher(teacher.ID) {
+			return nil, courseError.Course_ForbiddenAccess
+		}
+		announcement := &entities.CourseAnnouncement{
+			CourseID:    course.ID,
+			TeacherID:   teacher.ID,
+			Title:       dto.Title,
+			Content:     dto.Content,
+			SendEmail:   dto.SendEmail,
+			Status:      entities.AnnouncementStatus_Scheduled,
+			ScheduledAt: dto.ScheduledAt,
+		}
+		if err := tx.CourseAnnouncementRepo().Create(announcement); err != nil {
+			return nil, types.NewServerError("Error in creating announcement", operationName, err)
+		}
+		// delivery runs in the worker even without schedule, large classes do not block the request
+		workflowDto := announcementDtoReq.SendAnnouncementWorkflowReqDto{
+			AnnouncementID: announcement.ID,
+			ScheduledAt:    dto.ScheduledAt,
+		}
+		if err := svc.temporalSvc.ExecuteWorkerWithID(
+			ctx,
+			temporal.COURSE_ANNOUNCEMENT_QUEUE,
+			announcementWorkflow.SendAnnouncementWorkflowID(announcement.ID),
+			svc.announcementWorkflowSvc.SendAnnouncementWorkflow,
+			workflowDto,
+		); err != nil {
+			return nil, types.NewServerError("Error in starting send announcement workflow", operationName, err)
+		}
+		return announcement, nil
+	})
+}
+
+func (svc teacherAnnouncementService) Cancel(ctx context.Context, teacher *entities.User, announcementID uint) error {
+	const operationName = "teacherAnnouncementService.Cancel"
+	announcement, err := svc.unitOfWork.CourseAnnouncementRepo().GetByID(announcementID, nil)
+	if err != nil {
+		return types.NewServerError("Error in fetching announcement by id", operationName, err)
+	}
+	if announcement == nil {
+		return announcementError.Announcement_NotFound
+	}
+	if announcement.TeacherID != teacher.ID {
+		return courseError.Course_ForbiddenAccess
+	}
+	if announcement.IsSent() {
+		return announcementError.Announcement_AlreadySent
+	}
+	if err := svc.temporalSvc.CancelWorker(ctx, announcementWorkflow.SendAnnouncementWorkflowID(announcement.ID)); err != nil {
+		return types.NewServerError("Error in cancelling send announcement workflow", operationName, err)
+	}
+	if err := svc.unitOfWork.CourseAnnouncementRepo().UpdateFields(announcement, map[string]any{
+		"status": entities.AnnouncementStatus_Cancelled,
+	}); err != nil {
+		return types.NewServerError("Error in cancelling announcement", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherAnnouncementService) FetchByCourseID(teacher *entities.User, courseID uint, page, pageSize int) ([]*entities.CourseAnnouncement, map[uint]*repositories.AnnouncementReadStat, int, error) {
+	const operationName = "teacherAnnouncementService.FetchByCourseID"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, nil, 0, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, nil, 0, courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return nil, nil, 0, courseError.Course_ForbiddenAccess
+	}
+	announcements, count, err := svc.unitOfWork.CourseAnnouncementRepo().GetPaginated(repositories.GetPaginatedOptions{
+		Offset:     &page,
+		Limit:      &pageSize,
+		Conditions: map[string]any{"course_id": course.ID},
+	})
+	if err != nil {
+		return nil, nil, 0, types.NewServerError("Error in fetching announcements of course", operationName, err)
+	}
+	announcementIDs := make([]uint, len(announcements))
+	for index, announcement := range announcements {
+		announcementIDs[index] = announcement.ID
+	}
+	stats, err := svc.unitOfWork.NotificationRepo().GetAnnouncementReadStats(announcementIDs)
+	if err != nil {
+		return nil, nil, 0, types.NewServerError("Error in fetching read stats of announcements", operationName, err)
+	}
+	return announcements, stats, count, nil
+}
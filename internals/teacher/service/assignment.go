@@ -0,0 +1,464 @@
+package service
+
+import (
+	assignmentError "github.com/ladmakhi81/learnup/internals/assignment/error"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"gorm.io/gorm"
+	"math"
+	"strings"
+	"time"
+)
+
+type TeacherAssignmentService interface {
+	CreateAssignment(teacher *entities.User, dto dtoreq.CreateAssignmentReqDto) (*entities.Assignment, error)
+	UpdateAssignment(teacher *entities.User, dto dtoreq.UpdateAssignmentReqDto) (*entities.Assignment, error)
+	DeleteAssignment(teacher *entities.User, assignmentID uint) error
+	GetAssignments(teacher *entities.User, courseID uint) ([]*entities.Assignment, error)
+	GetAssignment(teacher *entities.User, assignmentID uint) (*entities.Assignment, error)
+	AddCriterion(teacher *entities.User, dto dtoreq.AddAssignmentCriterionReqDto) (*entities.AssignmentCriterion, error)
+	UpdateCriterion(teacher *entities.User, dto dtoreq.UpdateAssignmentCriterionReqDto) (*entities.AssignmentCriterion, error)
+	DeleteCriterion(teacher *entities.User, criterionID uint) error
+	GetGradingQueue(teacher *entities.User, dto dtoreq.GetGradingQueueReqDto) ([]*entities.AssignmentSubmission, int, error)
+	GetSubmission(teacher *entities.User, submissionID uint) (*entities.AssignmentSubmission, error)
+	GradeSubmission(teacher *entities.User, dto dtoreq.GradeSubmissionReqDto) (*entities.AssignmentSubmission, error)
+}
+
+type teacherAssignmentService struct {
+	unitOfWork db.UnitOfWork
+}
+
+func NewTeacherAssignmentSvc(unitOfWork db.UnitOfWork) TeacherAssignmentService {
+	return &teacherAssignmentService{
+		unitOfWork: unitOfWork,
+	}
+}
+
+func (svc teacherAssignmentService) CreateAssignment(teacher *entities.User, dto dtoreq.CreateAssignmentReqDto) (*entities.Assignment, error) {
+	const operationName = "teacherAssignmentService.CreateAssignment"
+	course, err := svc.getTeacherCourse(teacher, dto.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLateRules(dto.DueAt, dto.LateUntil); err != nil {
+		return nil, err
+	}
+	if dto.IsPublished && len(dto.Criteria) == 0 {
+		return nil, assignmentError.Assignment_NoCriteria
+	}
+	assignment := &entities.Assignment{
+		CourseID:             course.ID,
+		Title:                strings.TrimSpace(dto.Title),
+		Description:          dto.Description,
+		DueAt:                dto.DueAt,
+		AllowLateSubmissions: dto.AllowLateSubmissions,
+		LateUntil:            dto.LateUntil,
+		LatePenalty:          dto.LatePenalty,
+		IsPublished:          dto.IsPublished,
+	}
+	criteria := make([]*entities.AssignmentCriterion, len(dto.Criteria))
+	for index, criterionDto := range dto.Criteria {
+		criteria[index] = &entities.AssignmentCriterion{Position: uint(index + 1)}
+		applyAssignmentCriterion(criteria[index], criterionDto)
+	}
+	if _, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		if err := tx.AssignmentRepo().Create(assignment); err != nil {
+			return nil, types.NewServerError("Error in creating assignment", operationName, err)
+		}
+		if len(criteria) == 0 {
+			return nil, nil
+		}
+		for _, criterion := range criteria {
+			criterion.AssignmentID = assignment.ID
+		}
+		if err := tx.AssignmentCriterionRepo().BatchInsert(criteria); err != nil {
+			return nil, types.NewServerError("Error in creating criteria of assignment", operationName, err)
+		}
+		return nil, nil
+	}); err != nil {
+		return nil, err
+	}
+	assignment.Criteria = criteria
+	return assignment, nil
+}
+
+// UpdateAssignment doesn't touch the submissions handed in, a new due date only applies to later ones
+func (svc teacherAssignmentService) UpdateAssignment(teacher *entities.User, dto dtoreq.UpdateAssignmentReqDto) (*entities.Assignment, error) {
+	const operationName = "teacherAssignmentService.UpdateAssignment"
+	assignment, err := svc.getTeacherAssignment(teacher, dto.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLateRules(dto.DueAt, dto.LateUntil); err != nil {
+		return nil, err
+	}
+	if dto.IsPublished && len(assignment.Criteria) == 0 {
+		return nil, assignmentError.Assignment_NoCriteria
+	}
+	criteria := assignment.Criteria
+	assignment.Criteria = nil
+	if err := svc.unitOfWork.AssignmentRepo().UpdateFields(assignment, map[string]any{
+		"title":                  strings.TrimSpace(dto.Title),
+		"description":            dto.Description,
+		"due_at":                 dto.DueAt,
+		"allow_late_submissions": dto.AllowLateSubmissions,
+		"late_until":             dto.LateUntil,
+		"late_penalty":           dto.LatePenalty,
+		"is_published":           dto.IsPublished,
+	}); err != nil {
+		return nil, types.NewServerError("Error in updating assignment", operationName, err)
+	}
+	assignment.Title = strings.TrimSpace(dto.Title)
+	assignment.Description = dto.Description
+	assignment.DueAt = dto.DueAt
+	assignment.AllowLateSubmissions = dto.AllowLateSubmissions
+	assignment.LateUntil = dto.LateUntil
+	assignment.LatePenalty = dto.LatePenalty
+	assignment.IsPublished = dto.IsPublished
+	assignment.Criteria = criteria
+	return assignment, nil
+}
+
+// DeleteAssignment soft deletes the assignment, submissions stay for the history of the students
+func (svc teacherAssignmentService) DeleteAssignment(teacher *entities.User, assignmentID uint) error {
+	const operationName = "teacherAssignmentService.DeleteAssignment"
+	assignment, err := svc.getTeacherAssignment(teacher, assignmentID)
+	if err != nil {
+		return err
+	}
+	assignment.Criteria = nil
+	if err := svc.unitOfWork.AssignmentRepo().Delete(assignment); err != nil {
+		return types.NewServerError("Error in deleting assignment", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherAssignmentService) GetAssignments(teacher *entities.User, courseID uint) ([]*entities.Assignment, error) {
+	const operationName = "teacherAssignmentService.GetAssignments"
+	course, err := svc.getTeacherCourse(teacher, courseID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := svc.unitOfWork.AssignmentRepo().GetAllByCourseID(course.ID, false)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching assignments of course", operationName, err)
+	}
+	return assignments, nil
+}
+
+func (svc teacherAssignmentService) GetAssignment(teacher *entities.User, assignmentID uint) (*entities.Assignment, error) {
+	return svc.getTeacherAssignment(teacher, assignmentID)
+}
+
+func (svc teacherAssignmentService) AddCriterion(teacher *entities.User, dto dtoreq.AddAssignmentCriterionReqDto) (*entities.AssignmentCriterion, error) {
+	const operationName = "teacherAssignmentService.AddCriterion"
+	assignment, err := svc.getTeacherAssignment(teacher, dto.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	criterion := &entities.AssignmentCriterion{AssignmentID: assignment.ID}
+	applyAssignmentCriterion(criterion, dto.AssignmentCriterionReqDto)
+	if dto.Position == nil {
+		if criterion.Position, err = svc.unitOfWork.AssignmentCriterionRepo().NextPosition(assignment.ID); err != nil {
+			return nil, types.NewServerError("Error in fetching next position of criterion", operationName, err)
+		}
+	}
+	if err := svc.unitOfWork.AssignmentCriterionRepo().Create(criterion); err != nil {
+		return nil, types.NewServerError("Error in creating assignment criterion", operationName, err)
+	}
+	return criterion, nil
+}
+
+// UpdateCriterion doesn't regrade, submissions graded before keep the points they were given
+func (svc teacherAssignmentService) UpdateCriterion(teacher *entities.User, dto dtoreq.UpdateAssignmentCriterionReqDto) (*entities.AssignmentCriterion, error) {
+	const operationName = "teacherAssignmentService.UpdateCriterion"
+	criterion, err := svc.getTeacherCriterion(teacher, dto.CriterionID)
+	if err != nil {
+		return nil, err
+	}
+	applyAssignmentCriterion(criterion, dto.AssignmentCriterionReqDto)
+	if err := svc.unitOfWork.AssignmentCriterionRepo().UpdateFields(criterion, map[string]any{
+		"title":       criterion.Title,
+		"description": criterion.Description,
+		"points":      criterion.Points,
+		"position":    criterion.Position,
+	}); err != nil {
+		return nil, types.NewServerError("Error in updating assignment criterion", operationName, err)
+	}
+	criterion.Assignment = nil
+	return criterion, nil
+}
+
+// DeleteCriterion keeps at least one criterion on published assignments, they couldn't be graded otherwise
+func (svc teacherAssignmentService) DeleteCriterion(teacher *entities.User, criterionID uint) error {
+	const operationName = "teacherAssignmentService.DeleteCriterion"
+	criterion, err := svc.getTeacherCriterion(teacher, criterionID)
+	if err != nil {
+		return err
+	}
+	if criterion.Assignment.IsPublished {
+		criteria, err := svc.unitOfWork.AssignmentCriterionRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"assignment_id": criterion.AssignmentID},
+		})
+		if err != nil {
+			return types.NewServerError("Error in fetching criteria of assignment", operationName, err)
+		}
+		if len(criteria) <= 1 {
+			return assignmentError.Assignment_NoCriteria
+		}
+	}
+	criterion.Assignment = nil
+	if err := svc.unitOfWork.AssignmentCriterionRepo().Delete(criterion); err != nil {
+		return types.NewServerError("Error in deleting assignment criterion", operationName, err)
+	}
+	return nil
+}
+
+// GetGradingQueue lists the submissions of every course of the teacher unless a course is given
+func (svc teacherAssignmentService) GetGradingQueue(teacher *entities.User, dto dtoreq.GetGradingQueueReqDto) ([]*entities.AssignmentSubmission, int, error) {
+	const operationName = "teacherAssignmentService.GetGradingQueue"
+	if dto.Status != nil && !isSubmissionStatus(*dto.Status) {
+		return nil, 0, assignmentError.Assignment_InvalidSubmissionStatus
+	}
+	if dto.CourseID != nil {
+		if _, err := svc.getTeacherCourse(teacher, *dto.CourseID); err != nil {
+			return nil, 0, err
+		}
+	}
+	submissions, count, err := svc.unitOfWork.AssignmentSubmissionRepo().GetGradingQueue(repositories.GradingQueueOptions{
+		TeacherID:    teacher.ID,
+		CourseID:     dto.CourseID,
+		AssignmentID: dto.AssignmentID,
+		Status:       dto.Status,
+		Page:         dto.Page,
+		PageSize:     dto.PageSize,
+	})
+	if err != nil {
+		return nil, 0, types.NewServerError("Error in fetching grading queue", operationName, err)
+	}
+	return submissions, count, nil
+}
+
+func (svc teacherAssignmentService) GetSubmission(teacher *entities.User, submissionID uint) (*entities.AssignmentSubmission, error) {
+	return svc.getTeacherSubmission(teacher, submissionID)
+}
+
+// GradeSubmission scores every criterion of the rubric, the late penalty is taken from the total
+// and the student is notified in the same transaction
+func (svc teacherAssignmentService) GradeSubmission(teacher *entities.User, dto dtoreq.GradeSubmissionReqDto) (*entities.AssignmentSubmission, error) {
+	const operationName = "teacherAssignmentService.GradeSubmission"
+	submission, err := svc.getTeacherSubmission(teacher, dto.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+	assignment := submission.Assignment
+	latest, err := svc.unitOfWork.AssignmentSubmissionRepo().GetLatest(submission.AssignmentID, submission.UserID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching latest submission", operationName, err)
+	}
+	if latest != nil && latest.ID != submission.ID {
+		return nil, assignmentError.Assignment_SubmissionSuperseded
+	}
+	scores, score, err := gradeCriteria(assignment.Criteria, dto.Criteria)
+	if err != nil {
+		return nil, err
+	}
+	maxScore := assignment.MaxScore()
+	penalty := math.Min(score, roundScore(maxScore*assignment.LatePenalty/100*float64(submission.LateDays)))
+	finalScore := roundScore(score - penalty)
+	status := entities.AssignmentSubmissionStatus_Graded
+	if dto.AllowResubmission {
+		status = entities.AssignmentSubmissionStatus_ResubmissionRequested
+	}
+	gradedAt := time.Now()
+	feedback := strings.TrimSpace(dto.Feedback)
+	notification := &entities.Notification{
+		Type: entities.NotificationType_AssignmentGraded,
+		Metadata: map[string]any{
+			"courseId":          assignment.CourseID,
+			"courseName":        assignment.Course.Name,
+			"assignmentId":      assignment.ID,
+			"assignmentTitle":   assignment.Title,
+			"submissionId":      submission.ID,
+			"finalScore":        finalScore,
+			"maxScore":          maxScore,
+			"allowResubmission": dto.AllowResubmission,
+		},
+		UserID: &submission.UserID,
+	}
+	if _, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		if err := tx.AssignmentSubmissionRepo().UpdateFields(&entities.AssignmentSubmission{Model: gorm.Model{ID: submission.ID}}, map[string]any{
+			"status":       status,
+			"score":        score,
+			"penalty":      penalty,
+			"final_score":  finalScore,
+			"max_score":    maxScore,
+			"feedback":     feedback,
+			"graded_at":    gradedAt,
+			"graded_by_id": teacher.ID,
+		}); err != nil {
+			return nil, types.NewServerError("Error in grading submission", operationName, err)
+		}
+		// saved as a struct, the json serializer of the scores doesn't apply to map updates
+		if err := tx.AssignmentSubmissionRepo().Update(&entities.AssignmentSubmission{
+			Model:           gorm.Model{ID: submission.ID},
+			CriterionScores: scores,
+		}); err != nil {
+			return nil, types.NewServerError("Error in saving criterion scores of submission", operationName, err)
+		}
+		if err := tx.NotificationRepo().Create(notification); err != nil {
+			return nil, types.NewServerError("Error in creating assignment graded notification", operationName, err)
+		}
+		return nil, nil
+	}); err != nil {
+		return nil, err
+	}
+	submission.Status = status
+	submission.CriterionScores = scores
+	submission.Score = &score
+	submission.Penalty = penalty
+	submission.FinalScore = &finalScore
+	submission.MaxScore = maxScore
+	submission.Feedback = feedback
+	submission.GradedAt = &gradedAt
+	submission.GradedByID = &teacher.ID
+	return submission, nil
+}
+
+func (svc teacherAssignmentService) getTeacherCourse(teacher *entities.User, courseID uint) (*entities.Course, error) {
+	const operationName = "teacherAssignmentService.getTeacherCourse"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return course, nil
+}
+
+func (svc teacherAssignmentService) getTeacherAssignment(teacher *entities.User, assignmentID uint) (*entities.Assignment, error) {
+	const operationName = "teacherAssignmentService.getTeacherAssignment"
+	assignment, err := svc.unitOfWork.AssignmentRepo().GetWithCriteria(assignmentID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching assignment by id", operationName, err)
+	}
+	if assignment == nil || assignment.Course == nil {
+		return nil, assignmentError.Assignment_NotFound
+	}
+	if !assignment.Course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return assignment, nil
+}
+
+func (svc teacherAssignmentService) getTeacherCriterion(teacher *entities.User, criterionID uint) (*entities.AssignmentCriterion, error) {
+	const operationName = "teacherAssignmentService.getTeacherCriterion"
+	criterion, err := svc.unitOfWork.AssignmentCriterionRepo().GetByID(criterionID, []string{"Assignment", "Assignment.Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching assignment criterion by id", operationName, err)
+	}
+	if criterion == nil || criterion.Assignment == nil || criterion.Assignment.Course == nil {
+		return nil, assignmentError.Assignment_CriterionNotFound
+	}
+	if !criterion.Assignment.Course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return criterion, nil
+}
+
+// getTeacherSubmission loads the assignment with its rubric, submissions of deleted assignments can't be reached
+func (svc teacherAssignmentService) getTeacherSubmission(teacher *entities.User, submissionID uint) (*entities.AssignmentSubmission, error) {
+	const operationName = "teacherAssignmentService.getTeacherSubmission"
+	submission, err := svc.unitOfWork.AssignmentSubmissionRepo().GetByID(submissionID, []string{"User"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching assignment submission by id", operationName, err)
+	}
+	if submission == nil {
+		return nil, assignmentError.Assignment_SubmissionNotFound
+	}
+	assignment, err := svc.unitOfWork.AssignmentRepo().GetWithCriteria(submission.AssignmentID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching assignment by id", operationName, err)
+	}
+	if assignment == nil || assignment.Course == nil {
+		return nil, assignmentError.Assignment_SubmissionNotFound
+	}
+	if !assignment.Course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	submission.Assignment = assignment
+	return submission, nil
+}
+
+// checkLateRules only allows a late cutoff after the due date it extends
+func checkLateRules(dueAt, lateUntil *time.Time) error {
+	if lateUntil == nil {
+		return nil
+	}
+	if dueAt == nil || lateUntil.Before(*dueAt) {
+		return assignmentError.Assignment_InvalidLateUntil
+	}
+	return nil
+}
+
+func applyAssignmentCriterion(criterion *entities.AssignmentCriterion, dto dtoreq.AssignmentCriterionReqDto) {
+	criterion.Title = strings.TrimSpace(dto.Title)
+	criterion.Description = dto.Description
+	criterion.Points = dto.Points
+	if dto.Position != nil {
+		criterion.Position = *dto.Position
+	}
+}
+
+// gradeCriteria needs exactly one score per criterion of the rubric, none above its points
+func gradeCriteria(
+	criteria []*entities.AssignmentCriterion,
+	grades []dtoreq.GradeCriterionReqDto,
+) ([]entities.AssignmentCriterionScore, float64, error) {
+	if len(criteria) == 0 || len(grades) != len(criteria) {
+		return nil, 0, assignmentError.Assignment_InvalidCriterionScores
+	}
+	criterionPoints := make(map[uint]float64, len(criteria))
+	for _, criterion := range criteria {
+		criterionPoints[criterion.ID] = criterion.Points
+	}
+	scores := make([]entities.AssignmentCriterionScore, 0, len(grades))
+	var total float64
+	for _, grade := range grades {
+		points, isFound := criterionPoints[grade.CriterionID]
+		if !isFound || grade.Score < 0 || grade.Score > points {
+			return nil, 0, assignmentError.Assignment_InvalidCriterionScores
+		}
+		// a criterion graded twice is removed on the first pass and not found on the second
+		delete(criterionPoints, grade.CriterionID)
+		scores = append(scores, entities.AssignmentCriterionScore{
+			CriterionID: grade.CriterionID,
+			Score:       grade.Score,
+			Comment:     strings.TrimSpace(grade.Comment),
+		})
+		total += grade.Score
+	}
+	return scores, roundScore(total), nil
+}
+
+func isSubmissionStatus(status entities.AssignmentSubmissionStatus) bool {
+	switch status {
+	case entities.AssignmentSubmissionStatus_Submitted,
+		entities.AssignmentSubmissionStatus_Graded,
+		entities.AssignmentSubmissionStatus_ResubmissionRequested:
+		return true
+	}
+	return false
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -12,6 +12,9 @@ import (
 type TeacherCourseService interface {
 	Create(teacher *entities.User, dto teacherDtoReq.CreateCourseReqDto) (*entities.Course, error)
 	FetchByTeacherId(teacher *entities.User, page, pageSize int) ([]*entities.Course, int, error)
+	Clone(teacher *entities.User, dto teacherDtoReq.CloneCourseReqDto) (*entities.Course, error)
+	PublishVersion(teacher *entities.User, dto teacherDtoReq.PublishCourseVersionReqDto) (*entities.CourseVersion, error)
+	UpdateWatermark(teacher *entities.User, dto teacherDtoReq.UpdateCourseWatermarkReqDto) error
 }
 
 type teacherCourseService struct {
@@ -92,3 +95,205 @@ func (svc teacherCourseService) FetchByTeacherId(teacher *entities.User, page, p
 	}
 	return courses, count, nil
 }
+
+func (svc teacherCourseService) Clone(teacher *entities.User, dto teacherDtoReq.CloneCourseReqDto) (*entities.Course, error) {
+	return db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*entities.Course, error) {
+		const operationName = "teacherCourseService.Clone"
+		source, err := tx.CourseRepo().GetByID(dto.ID, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+		}
+		if source == nil {
+			return nil, courseError.Course_NotFound
+		}
+		if !source.IsTeacher(teacher.ID) {
+			return nil, courseError.Course_ForbiddenAccess
+		}
+		isDuplicate, err := tx.CourseRepo().Exist(map[string]any{"name": dto.Name})
+		if err != nil {
+			return nil, types.NewServerError("Error in checking existence of course name", operationName, err)
+		}
+		if isDuplicate {
+			return nil, courseError.Course_NameDuplicated
+		}
+		course := &entities.Course{
+			Name:                        dto.Name,
+			TeacherID:                   source.TeacherID,
+			CategoryID:                  source.CategoryID,
+			Price:                       source.Price,
+			ThumbnailImage:              source.ThumbnailImage,
+			Image:                       source.Image,
+			Description:                 source.Description,
+			Prerequisite:                source.Prerequisite,
+			Level:                       source.Level,
+			Status:                      entities.CourseStatus_InProgress,
+			Tags:                        source.Tags,
+			AbilityToAddComment:         source.AbilityToAddComment,
+			CommentAccessMode:           source.CommentAccessMode,
+			IsPublished:                 false,
+			IsVerifiedByAdmin:           false,
+			IntroductionVideo:           source.IntroductionVideo,
+			CanHaveDiscount:             source.CanHaveDiscount,
+			MaxDiscountAmount:           source.MaxDiscountAmount,
+			DiscountFeeAmountPercentage: source.DiscountFeeAmountPercentage,
+			WatermarkMode:               source.WatermarkMode,
+		}
+		if err := tx.CourseRepo().Create(course); err != nil {
+			return nil, types.NewServerError("Error in creating cloned course", operationName, err)
+		}
+		sourceForum, err := tx.CourseForumRepo().GetOne(map[string]any{"course_id": source.ID}, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching forum of course", operationName, err)
+		}
+		forum := &entities.CourseForum{
+			TeacherID: teacher.ID,
+			CourseID:  course.ID,
+		}
+		if sourceForum != nil {
+			forum.AccessMode = sourceForum.AccessMode
+			forum.Status = sourceForum.Status
+			forum.IsPublic = sourceForum.IsPublic
+		}
+		if err := tx.CourseForumRepo().Create(forum); err != nil {
+			return nil, types.NewServerError("Error in creating course forum", operationName, err)
+		}
+		sourceVideos, err := tx.VideoRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"course_id": source.ID},
+		})
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching videos of course", operationName, err)
+		}
+		if len(sourceVideos) == 0 {
+			return course, nil
+		}
+		// encoded files are shared with the source course, so nothing is re-encoded
+		videos := make([]*entities.Video, len(sourceVideos))
+		for index, sourceVideo := range sourceVideos {
+			videos[index] = &entities.Video{
+				CourseId:      &course.ID,
+				Title:         sourceVideo.Title,
+				Description:   sourceVideo.Description,
+				AccessLevel:   sourceVideo.AccessLevel,
+				IsPreview:     sourceVideo.IsPreview,
+				IsPublished:   false,
+				IsVerified:    false,
+				Duration:      sourceVideo.Duration,
+				Status:        sourceVideo.Status,
+				URL:           sourceVideo.URL,
+				ThumbnailURL:  sourceVideo.ThumbnailURL,
+				ThumbnailAt:   sourceVideo.ThumbnailAt,
+				WatermarkMode: sourceVideo.WatermarkMode,
+			}
+		}
+		if err := tx.VideoRepo().BatchInsert(videos); err != nil {
+			return nil, types.NewServerError("Error in copying videos of course", operationName, err)
+		}
+		sourceVideoIDs := make([]uint, len(sourceVideos))
+		clonedVideoIDs := make(map[uint]uint, len(sourceVideos))
+		for index, sourceVideo := range sourceVideos {
+			sourceVideoIDs[index] = sourceVideo.ID
+			clonedVideoIDs[sourceVideo.ID] = videos[index].ID
+		}
+		sourceCaptions, err := tx.VideoCaptionRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"video_id": sourceVideoIDs},
+		})
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching captions of videos", operationName, err)
+		}
+		if len(sourceCaptions) == 0 {
+			return course, nil
+		}
+		captions := make([]*entities.VideoCaption, len(sourceCaptions))
+		for index, sourceCaption := range sourceCaptions {
+			captions[index] = &entities.VideoCaption{
+				VideoID:  clonedVideoIDs[sourceCaption.VideoID],
+				Language: sourceCaption.Language,
+				Label:    sourceCaption.Label,
+				URL:      sourceCaption.URL,
+			}
+		}
+		if err := tx.VideoCaptionRepo().BatchInsert(captions); err != nil {
+			return nil, types.NewServerError("Error in copying captions of videos", operationName, err)
+		}
+		return course, nil
+	})
+}
+
+func (svc teacherCourseService) PublishVersion(teacher *entities.User, dto teacherDtoReq.PublishCourseVersionReqDto) (*entities.CourseVersion, error) {
+	return db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*entities.CourseVersion, error) {
+		const operationName = "teacherCourseService.PublishVersion"
+		course, err := tx.CourseRepo().GetByID(dto.ID, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+		}
+		if course == nil {
+			return nil, courseError.Course_NotFound
+		}
+		if !course.IsTeacher(teacher.ID) {
+			return nil, courseError.Course_ForbiddenAccess
+		}
+		if !course.IsVerifiedByAdmin {
+			return nil, courseError.Course_NotVerified
+		}
+		videos, err := tx.VideoRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"course_id": course.ID},
+		})
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching videos of course", operationName, err)
+		}
+		courseVersion := entities.NewCourseVersion(course, videos, course.CurrentVersion+1, teacher.ID, dto.Changelog)
+		if err := tx.CourseVersionRepo().Create(courseVersion); err != nil {
+			return nil, types.NewServerError("Error in creating course version", operationName, err)
+		}
+		if err := tx.CourseRepo().UpdateFields(course, map[string]any{"current_version": courseVersion.Version}); err != nil {
+			return nil, types.NewServerError("Error in updating current version of course", operationName, err)
+		}
+		participants, err := tx.CourseParticipantRepo().GetAllByCourseID(course.ID)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching participants of course", operationName, err)
+		}
+		// students stay on their bought version, they are only told that a new one exists
+		notifications := make([]*entities.Notification, 0, len(participants))
+		for _, participant := range participants {
+			if participant.CourseVersion == 0 {
+				continue
+			}
+			studentID := participant.StudentID
+			notifications = append(notifications, &entities.Notification{
+				Type: entities.NotificationType_CourseVersionPublished,
+				Metadata: map[string]any{
+					"courseId":       course.ID,
+					"courseName":     course.Name,
+					"version":        courseVersion.Version,
+					"currentVersion": participant.CourseVersion,
+				},
+				UserID: &studentID,
+			})
+		}
+		if len(notifications) > 0 {
+			if err := tx.NotificationRepo().BatchInsert(notifications); err != nil {
+				return nil, types.NewServerError("Error in creating new version notifications", operationName, err)
+			}
+		}
+		return courseVersion, nil
+	})
+}
+
+// UpdateWatermark only affects videos encoded afterwards, existing files keep the mode they were made with
+func (svc teacherCourseService) UpdateWatermark(teacher *entities.User, dto teacherDtoReq.UpdateCourseWatermarkReqDto) error {
+	const operationName = "teacherCourseService.UpdateWatermark"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(dto.ID, nil)
+	if err != nil {
+		return types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return courseError.Course_ForbiddenAccess
+	}
+	if err := svc.unitOfWork.CourseRepo().UpdateFields(course, map[string]any{"watermark_mode": dto.Mode}); err != nil {
+		return types.NewServerError("Error in updating watermark mode of course", operationName, err)
+	}
+	return nil
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	teacherError "github.com/ladmakhi81/learnup/internals/teacher/error"
+	userError "github.com/ladmakhi81/learnup/internals/user/error"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"io"
+	"strings"
+	"time"
+)
+
+const maxBulkEnrollRows = 1000
+
+type BulkEnrollResult struct {
+	Enrolled        []string
+	AlreadyEnrolled []string
+	NotFound        []string
+}
+
+type TeacherParticipantService interface {
+	Add(teacher *entities.User, dto dtoreq.AddParticipantReqDto) (*entities.CourseParticipant, error)
+	Remove(teacher *entities.User, dto dtoreq.RemoveParticipantReqDto) error
+	UpdateExpiry(teacher *entities.User, dto dtoreq.UpdateParticipantExpiryReqDto) error
+	BulkEnroll(teacher *entities.User, dto dtoreq.BulkEnrollReqDto) (*BulkEnrollResult, error)
+	FetchByCourseID(teacher *entities.User, courseID uint, page, pageSize int) ([]*entities.CourseParticipant, int, error)
+	FetchAudits(teacher *entities.User, courseID uint, page, pageSize int) ([]*entities.EnrollmentAudit, int, error)
+}
+
+type teacherParticipantService struct {
+	unitOfWork db.UnitOfWork
+	// admins manage the participants of every course, teachers only of their own
+	isAdmin bool
+}
+
+func NewTeacherParticipantSvc(unitOfWork db.UnitOfWork) TeacherParticipantService {
+	return &teacherParticipantService{
+		unitOfWork: unitOfWork,
+	}
+}
+
+func NewAdminParticipantSvc(unitOfWork db.UnitOfWork) TeacherParticipantService {
+	return &teacherParticipantService{
+		unitOfWork: unitOfWork,
+		isAdmin:    true,
+	}
+}
+
+func (svc teacherParticipantService) Add(teacher *entities.User, dto dtoreq.AddParticipantReqDto) (*entities.CourseParticipant, error) {
+	const operationName = "teacherParticipantService.Add"
+	if err := svc.checkExpiresAt(dto.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*entities.CourseParticipant, error) {
+		course, err := svc.fetchTeacherCourse(tx, teacher, dto.CourseID, operationName)
+		if err != nil {
+			return nil, err
+		}
+		student, err := tx.UserRepo().GetOne(map[string]any{"phone_number": dto.Phone}, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching user by phone number", operationName, err)
+		}
+		if student == nil {
+			return nil, userError.User_NotFound
+		}
+		participant, err := tx.CourseParticipantRepo().GetOne(course.ID, student.ID)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course participant", operationName, err)
+		}
+		if participant != nil {
+			return nil, teacherError.Participant_AlreadyEnrolled
+		}
+		participants, err := svc.enroll(tx, teacher, course, []*entities.User{student}, entities.EnrollmentAction_Add, dto.Reason, dto.ExpiresAt)
+		if err != nil {
+			return nil, types.NewServerError("Error in enrolling student to course", operationName, err)
+		}
+		return participants[0], nil
+	})
+}
+
+func (svc teacherParticipantService) Remove(teacher *entities.User, dto dtoreq.RemoveParticipantReqDto) error {
+	const operationName = "teacherParticipantService.Remove"
+	_, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		course, err := svc.fetchTeacherCourse(tx, teacher, dto.CourseID, operationName)
+		if err != nil {
+			return nil, err
+		}
+		participant, err := tx.CourseParticipantRepo().GetOne(course.ID, dto.StudentID)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course participant", operationName, err)
+		}
+		if participant == nil {
+			return nil, teacherError.Participant_NotFound
+		}
+		if err := tx.CourseParticipantRepo().Delete(course.ID, dto.StudentID); err != nil {
+			return nil, types.NewServerError("Error in removing course participant", operationName, err)
+		}
+		audit := &entities.EnrollmentAudit{
+			CourseID:  course.ID,
+			StudentID: dto.StudentID,
+			ActorID:   teacher.ID,
+			Action:    entities.EnrollmentAction_Remove,
+			Reason:    dto.Reason,
+		}
+		if err := tx.EnrollmentAuditRepo().Create(audit); err != nil {
+			return nil, types.NewServerError("Error in creating enrollment audit", operationName, err)
+		}
+		studentID := dto.StudentID
+		notification := &entities.Notification{
+			Type: entities.NotificationType_EnrollmentRevoked,
+			Metadata: map[string]any{
+				"courseId":   course.ID,
+				"courseName": course.Name,
+				"reason":     dto.Reason,
+			},
+			UserID: &studentID,
+		}
+		if err := tx.NotificationRepo().Create(notification); err != nil {
+			return nil, types.NewServerError("Error in creating enrollment revoked notification", operationName, err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (svc teacherParticipantService) UpdateExpiry(teacher *entities.User, dto dtoreq.UpdateParticipantExpiryReqDto) error {
+	const operationName = "teacherParticipantService.UpdateExpiry"
+	if err := svc.checkExpiresAt(dto.ExpiresAt); err != nil {
+		return err
+	}
+	_, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		course, err := svc.fetchTeacherCourse(tx, teacher, dto.CourseID, operationName)
+		if err != nil {
+			return nil, err
+		}
+		participant, err := tx.CourseParticipantRepo().GetOne(course.ID, dto.StudentID)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course participant", operationName, err)
+		}
+		if participant == nil {
+			return nil, teacherError.Participant_NotFound
+		}
+		if err := tx.CourseParticipantRepo().UpdateExpiresAt(course.ID, dto.StudentID, dto.ExpiresAt); err != nil {
+			return nil, types.NewServerError("Error in updating expiry of course participant", operationName, err)
+		}
+		audit := &entities.EnrollmentAudit{
+			CourseID:  course.ID,
+			StudentID: dto.StudentID,
+			ActorID:   teacher.ID,
+			Action:    entities.EnrollmentAction_UpdateExpiry,
+			Reason:    dto.Reason,
+			ExpiresAt: dto.ExpiresAt,
+		}
+		if err := tx.EnrollmentAuditRepo().Create(audit); err != nil {
+			return nil, types.NewServerError("Error in creating enrollment audit", operationName, err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (svc teacherParticipantService) BulkEnroll(teacher *entities.User, dto dtoreq.BulkEnrollReqDto) (*BulkEnrollResult, error) {
+	const operationName = "teacherParticipantService.BulkEnroll"
+	if err := svc.checkExpiresAt(dto.ExpiresAt); err != nil {
+		return nil, err
+	}
+	phones, err := svc.readCsvPhones(dto.File)
+	if err != nil {
+		return nil, err
+	}
+	return db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*BulkEnrollResult, error) {
+		course, err := svc.fetchTeacherCourse(tx, teacher, dto.CourseID, operationName)
+		if err != nil {
+			return nil, err
+		}
+		result := &BulkEnrollResult{
+			Enrolled:        make([]string, 0),
+			AlreadyEnrolled: make([]string, 0),
+			NotFound:        make([]string, 0),
+		}
+		users, err := tx.UserRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"phone_number": phones},
+		})
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching users by phone numbers", operationName, err)
+		}
+		usersByPhone := make(map[string]*entities.User, len(users))
+		userIDs := make([]uint, len(users))
+		for index, user := range users {
+			usersByPhone[user.Phone] = user
+			userIDs[index] = user.ID
+		}
+		enrolledIDs := make(map[uint]bool)
+		if len(userIDs) > 0 {
+			participantIDs, err := tx.CourseParticipantRepo().GetStudentIDsByCourseID(course.ID, userIDs)
+			if err != nil {
+				return nil, types.NewServerError("Error in fetching participants of course", operationName, err)
+			}
+			for _, participantID := range participantIDs {
+				enrolledIDs[participantID] = true
+			}
+		}
+		students := make([]*entities.User, 0, len(users))
+		for _, phone := range phones {
+			user, ok := usersByPhone[phone]
+			switch {
+			case !ok:
+				result.NotFound = append(result.NotFound, phone)
+			case enrolledIDs[user.ID]:
+				result.AlreadyEnrolled = append(result.AlreadyEnrolled, phone)
+			default:
+				students = append(students, user)
+				result.Enrolled = append(result.Enrolled, phone)
+			}
+		}
+		if len(students) == 0 {
+			return result, nil
+		}
+		if _, err := svc.enroll(tx, teacher, course, students, entities.EnrollmentAction_BulkAdd, dto.Reason, dto.ExpiresAt); err != nil {
+			return nil, types.NewServerError("Error in enrolling students to course", operationName, err)
+		}
+		return result, nil
+	})
+}
+
+func (svc teacherParticipantService) FetchByCourseID(teacher *entities.User, courseID uint, page, pageSize int) ([]*entities.CourseParticipant, int, error) {
+	const operationName = "teacherParticipantService.FetchByCourseID"
+	course, err := svc.fetchTeacherCourse(svc.unitOfWork, teacher, courseID, operationName)
+	if err != nil {
+		return nil, 0, err
+	}
+	participants, count, err := svc.unitOfWork.CourseParticipantRepo().GetPaginatedByCourseID(course.ID, page, pageSize)
+	if err != nil {
+		return nil, 0, types.NewServerError("Error in fetching participants of course", operationName, err)
+	}
+	return participants, count, nil
+}
+
+func (svc teacherParticipantService) FetchAudits(teacher *entities.User, courseID uint, page, pageSize int) ([]*entities.EnrollmentAudit, int, error) {
+	const operationName = "teacherParticipantService.FetchAudits"
+	course, err := svc.fetchTeacherCourse(svc.unitOfWork, teacher, courseID, operationName)
+	if err != nil {
+		return nil, 0, err
+	}
+	audits, count, err := svc.unitOfWork.EnrollmentAuditRepo().GetPaginated(repositories.GetPaginatedOptions{
+		Offset:     &page,
+		Limit:      &pageSize,
+		Conditions: map[string]any{"course_id": course.ID},
+		Relations:  []string{"Student", "Actor"},
+	})
+	if err != nil {
+		return nil, 0, types.NewServerError("Error in fetching enrollment audits of course", operationName, err)
+	}
+	return audits, count, nil
+}
+
+func (svc teacherParticipantService) fetchTeacherCourse(repo db.Repo, teacher *entities.User, courseID uint, operationName string) (*entities.Course, error) {
+	course, err := repo.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	if !svc.isAdmin && !course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return course, nil
+}
+
+func (svc teacherParticipantService) enroll(
+	tx db.UnitOfWorkTx,
+	teacher *entities.User,
+	course *entities.Course,
+	students []*entities.User,
+	action entities.EnrollmentAction,
+	reason string,
+	expiresAt *time.Time,
+) ([]*entities.CourseParticipant, error) {
+	participants := make([]*entities.CourseParticipant, len(students))
+	audits := make([]*entities.EnrollmentAudit, len(students))
+	notifications := make([]*entities.Notification, len(students))
+	for index, student := range students {
+		studentID := student.ID
+		participants[index] = &entities.CourseParticipant{
+			CourseID:      course.ID,
+			StudentID:     student.ID,
+			TeacherID:     *course.TeacherID,
+			CourseVersion: course.CurrentVersion,
+			Source:        action,
+			ExpiresAt:     expiresAt,
+		}
+		audits[index] = &entities.EnrollmentAudit{
+			CourseID:  course.ID,
+			StudentID: student.ID,
+			ActorID:   teacher.ID,
+			Action:    action,
+			Reason:    reason,
+			ExpiresAt: expiresAt,
+		}
+		notifications[index] = &entities.Notification{
+			Type: entities.NotificationType_EnrollmentGranted,
+			Metadata: map[string]any{
+				"courseId":   course.ID,
+				"courseName": course.Name,
+				"expiresAt":  expiresAt,
+			},
+			UserID: &studentID,
+		}
+	}
+	for _, participant := range participants {
+		if err := tx.CourseParticipantRepo().Create(participant); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.EnrollmentAuditRepo().BatchInsert(audits); err != nil {
+		return nil, err
+	}
+	if err := tx.NotificationRepo().BatchInsert(notifications); err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+func (svc teacherParticipantService) checkExpiresAt(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return teacherError.Participant_InvalidExpiresAt
+	}
+	return nil
+}
+
+// readCsvPhones reads the phone column of the uploaded csv, the header row is required
+// and duplicated phone numbers are only enrolled once
+func (svc teacherParticipantService) readCsvPhones(file io.Reader) ([]string, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, teacherError.Participant_InvalidCsv
+	}
+	phoneColumn := -1
+	for index, column := range header {
+		if strings.EqualFold(strings.TrimSpace(column), "phone") {
+			phoneColumn = index
+			break
+		}
+	}
+	if phoneColumn == -1 {
+		return nil, teacherError.Participant_InvalidCsv
+	}
+	phones := make([]string, 0)
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil || phoneColumn >= len(record) {
+			return nil, teacherError.Participant_InvalidCsv
+		}
+		phone := strings.TrimSpace(record[phoneColumn])
+		if phone == "" || seen[phone] {
+			continue
+		}
+		seen[phone] = true
+		phones = append(phones, phone)
+		if len(phones) > maxBulkEnrollRows {
+			return nil, teacherError.Participant_CsvTooLarge
+		}
+	}
+	if len(phones) == 0 {
+		return nil, teacherError.Participant_InvalidCsv
+	}
+	return phones, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"testing"
+)
+
+type courseRepoProvider struct {
+	db.Repo
+	course *entities.Course
+}
+
+func (provider courseRepoProvider) CourseRepo() repositories.CourseRepo {
+	return courseByIDRepo{course: provider.course}
+}
+
+type courseByIDRepo struct {
+	repositories.CourseRepo
+	course *entities.Course
+}
+
+func (repo courseByIDRepo) GetByID(id uint, relations []string) (*entities.Course, error) {
+	if repo.course == nil || repo.course.ID != id {
+		return nil, nil
+	}
+	return repo.course, nil
+}
+
+func TestFetchTeacherCourse(t *testing.T) {
+	teacherID := uint(3)
+	course := &entities.Course{TeacherID: &teacherID}
+	course.ID = 10
+	teacher := &entities.User{}
+	teacher.ID = teacherID
+	otherTeacher := &entities.User{}
+	otherTeacher.ID = 4
+	tests := []struct {
+		name        string
+		svc         TeacherParticipantService
+		user        *entities.User
+		courseID    uint
+		expectedErr error
+	}{
+		{name: "teacher of the course", svc: NewTeacherParticipantSvc(nil), user: teacher, courseID: 10},
+		{name: "another teacher", svc: NewTeacherParticipantSvc(nil), user: otherTeacher, courseID: 10, expectedErr: courseError.Course_ForbiddenAccess},
+		{name: "admin manages any course", svc: NewAdminParticipantSvc(nil), user: otherTeacher, courseID: 10},
+		{name: "admin and a missing course", svc: NewAdminParticipantSvc(nil), user: otherTeacher, courseID: 11, expectedErr: courseError.Course_NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := test.svc.(*teacherParticipantService)
+			fetchedCourse, err := svc.fetchTeacherCourse(courseRepoProvider{course: course}, test.user, test.courseID, "test")
+			if err != test.expectedErr {
+				t.Fatalf("fetchTeacherCourse error = %v, want %v", err, test.expectedErr)
+			}
+			if test.expectedErr == nil && fetchedCourse != course {
+				t.Errorf("fetchTeacherCourse = %+v, want course %d", fetchedCourse, course.ID)
+			}
+		})
+	}
+}
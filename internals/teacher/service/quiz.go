@@ -0,0 +1,371 @@
+package service
+
+import (
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	quizError "github.com/ladmakhi81/learnup/internals/quiz/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"slices"
+	"strings"
+)
+
+const (
+	minQuizOptions = 2
+	maxQuizOptions = 10
+)
+
+// QuizQuestionAnalytics shows how a question was answered, OptionCounts follows the options of the question
+type QuizQuestionAnalytics struct {
+	Question       *entities.QuizQuestion
+	Answers        int
+	CorrectAnswers int
+	OptionCounts   []int
+}
+
+type QuizAnalytics struct {
+	Quiz      *entities.Quiz
+	Stats     *repositories.QuizAttemptStats
+	Questions []*QuizQuestionAnalytics
+}
+
+type TeacherQuizService interface {
+	CreateQuiz(teacher *entities.User, dto dtoreq.CreateQuizReqDto) (*entities.Quiz, error)
+	UpdateQuiz(teacher *entities.User, dto dtoreq.UpdateQuizReqDto) (*entities.Quiz, error)
+	DeleteQuiz(teacher *entities.User, quizID uint) error
+	GetQuizzes(teacher *entities.User, courseID uint) ([]*entities.Quiz, error)
+	GetQuiz(teacher *entities.User, quizID uint) (*entities.Quiz, error)
+	AddQuestion(teacher *entities.User, dto dtoreq.AddQuizQuestionReqDto) (*entities.QuizQuestion, error)
+	UpdateQuestion(teacher *entities.User, dto dtoreq.UpdateQuizQuestionReqDto) (*entities.QuizQuestion, error)
+	DeleteQuestion(teacher *entities.User, questionID uint) error
+	GetAnalytics(teacher *entities.User, quizID uint) (*QuizAnalytics, error)
+}
+
+type teacherQuizService struct {
+	unitOfWork db.UnitOfWork
+}
+
+func NewTeacherQuizSvc(unitOfWork db.UnitOfWork) TeacherQuizService {
+	return &teacherQuizService{
+		unitOfWork: unitOfWork,
+	}
+}
+
+func (svc teacherQuizService) CreateQuiz(teacher *entities.User, dto dtoreq.CreateQuizReqDto) (*entities.Quiz, error) {
+	const operationName = "teacherQuizService.CreateQuiz"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(dto.CourseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	video, err := svc.getCourseVideo(course.ID, dto.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	quiz := &entities.Quiz{
+		CourseID:         course.ID,
+		VideoID:          dto.VideoID,
+		Video:            video,
+		Title:            dto.Title,
+		Description:      dto.Description,
+		TimeLimit:        dto.TimeLimit,
+		MaxAttempts:      dto.MaxAttempts,
+		PassingScore:     dto.PassingScore,
+		ShuffleQuestions: dto.ShuffleQuestions,
+		IsPublished:      dto.IsPublished,
+	}
+	if err := svc.unitOfWork.QuizRepo().Create(quiz); err != nil {
+		return nil, types.NewServerError("Error in creating quiz", operationName, err)
+	}
+	return quiz, nil
+}
+
+// UpdateQuiz keeps the attempts already graded, a new passing score only applies to later attempts
+func (svc teacherQuizService) UpdateQuiz(teacher *entities.User, dto dtoreq.UpdateQuizReqDto) (*entities.Quiz, error) {
+	const operationName = "teacherQuizService.UpdateQuiz"
+	quiz, err := svc.getTeacherQuiz(teacher, dto.QuizID)
+	if err != nil {
+		return nil, err
+	}
+	video, err := svc.getCourseVideo(quiz.CourseID, dto.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.unitOfWork.QuizRepo().UpdateFields(quiz, map[string]any{
+		"video_id":          dto.VideoID,
+		"title":             dto.Title,
+		"description":       dto.Description,
+		"time_limit":        dto.TimeLimit,
+		"max_attempts":      dto.MaxAttempts,
+		"passing_score":     dto.PassingScore,
+		"shuffle_questions": dto.ShuffleQuestions,
+		"is_published":      dto.IsPublished,
+	}); err != nil {
+		return nil, types.NewServerError("Error in updating quiz", operationName, err)
+	}
+	quiz.VideoID = dto.VideoID
+	quiz.Video = video
+	quiz.Title = dto.Title
+	quiz.Description = dto.Description
+	quiz.TimeLimit = dto.TimeLimit
+	quiz.MaxAttempts = dto.MaxAttempts
+	quiz.PassingScore = dto.PassingScore
+	quiz.ShuffleQuestions = dto.ShuffleQuestions
+	quiz.IsPublished = dto.IsPublished
+	return quiz, nil
+}
+
+// DeleteQuiz soft deletes the quiz, attempts stay for the history of the students but no longer count
+func (svc teacherQuizService) DeleteQuiz(teacher *entities.User, quizID uint) error {
+	const operationName = "teacherQuizService.DeleteQuiz"
+	quiz, err := svc.getTeacherQuiz(teacher, quizID)
+	if err != nil {
+		return err
+	}
+	if err := svc.unitOfWork.QuizRepo().Delete(quiz); err != nil {
+		return types.NewServerError("Error in deleting quiz", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherQuizService) GetQuizzes(teacher *entities.User, courseID uint) ([]*entities.Quiz, error) {
+	const operationName = "teacherQuizService.GetQuizzes"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	quizzes, err := svc.unitOfWork.QuizRepo().GetAllByCourseID(course.ID, false)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching quizzes of course", operationName, err)
+	}
+	return quizzes, nil
+}
+
+func (svc teacherQuizService) GetQuiz(teacher *entities.User, quizID uint) (*entities.Quiz, error) {
+	const operationName = "teacherQuizService.GetQuiz"
+	quiz, err := svc.getTeacherQuiz(teacher, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.Questions, err = svc.unitOfWork.QuizQuestionRepo().GetAllByQuizID(quiz.ID); err != nil {
+		return nil, types.NewServerError("Error in fetching questions of quiz", operationName, err)
+	}
+	return quiz, nil
+}
+
+func (svc teacherQuizService) AddQuestion(teacher *entities.User, dto dtoreq.AddQuizQuestionReqDto) (*entities.QuizQuestion, error) {
+	const operationName = "teacherQuizService.AddQuestion"
+	quiz, err := svc.getTeacherQuiz(teacher, dto.QuizID)
+	if err != nil {
+		return nil, err
+	}
+	question := &entities.QuizQuestion{QuizID: quiz.ID}
+	if err := applyQuizQuestion(question, dto.QuizQuestionReqDto); err != nil {
+		return nil, err
+	}
+	if dto.Position != nil {
+		question.Position = *dto.Position
+	} else if question.Position, err = svc.unitOfWork.QuizQuestionRepo().NextPosition(quiz.ID); err != nil {
+		return nil, types.NewServerError("Error in fetching next position of question", operationName, err)
+	}
+	if err := svc.unitOfWork.QuizQuestionRepo().Create(question); err != nil {
+		return nil, types.NewServerError("Error in creating quiz question", operationName, err)
+	}
+	return question, nil
+}
+
+// UpdateQuestion doesn't regrade submitted attempts, fixing an answer key only affects later attempts
+func (svc teacherQuizService) UpdateQuestion(teacher *entities.User, dto dtoreq.UpdateQuizQuestionReqDto) (*entities.QuizQuestion, error) {
+	const operationName = "teacherQuizService.UpdateQuestion"
+	question, err := svc.getTeacherQuestion(teacher, dto.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyQuizQuestion(question, dto.QuizQuestionReqDto); err != nil {
+		return nil, err
+	}
+	if dto.Position != nil {
+		question.Position = *dto.Position
+	}
+	// saved as a struct, the json serializer of the answer key doesn't apply to map updates
+	question.Quiz = nil
+	if err := svc.unitOfWork.QuizQuestionRepo().Update(question); err != nil {
+		return nil, types.NewServerError("Error in updating quiz question", operationName, err)
+	}
+	return question, nil
+}
+
+func (svc teacherQuizService) DeleteQuestion(teacher *entities.User, questionID uint) error {
+	const operationName = "teacherQuizService.DeleteQuestion"
+	question, err := svc.getTeacherQuestion(teacher, questionID)
+	if err != nil {
+		return err
+	}
+	if err := svc.unitOfWork.QuizQuestionRepo().Delete(question); err != nil {
+		return types.NewServerError("Error in deleting quiz question", operationName, err)
+	}
+	return nil
+}
+
+// GetAnalytics reports every question of the quiz, a low correct rate or a wrong option picked
+// by many students usually means the question is confusing
+func (svc teacherQuizService) GetAnalytics(teacher *entities.User, quizID uint) (*QuizAnalytics, error) {
+	const operationName = "teacherQuizService.GetAnalytics"
+	quiz, err := svc.getTeacherQuiz(teacher, quizID)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := svc.unitOfWork.QuizQuestionRepo().GetAllByQuizID(quiz.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching questions of quiz", operationName, err)
+	}
+	stats, err := svc.unitOfWork.QuizAttemptRepo().GetStats(quiz.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in aggregating attempts of quiz", operationName, err)
+	}
+	questionStats, err := svc.unitOfWork.QuizAnswerRepo().GetQuestionStats(quiz.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in aggregating answers of quiz", operationName, err)
+	}
+	optionCounts, err := svc.unitOfWork.QuizAnswerRepo().GetOptionCounts(quiz.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in aggregating options of quiz", operationName, err)
+	}
+	questionAnalytics := make(map[uint]*QuizQuestionAnalytics, len(questions))
+	analytics := &QuizAnalytics{
+		Quiz:      quiz,
+		Stats:     stats,
+		Questions: make([]*QuizQuestionAnalytics, len(questions)),
+	}
+	for index, question := range questions {
+		analytics.Questions[index] = &QuizQuestionAnalytics{
+			Question:     question,
+			OptionCounts: make([]int, len(question.Options)),
+		}
+		questionAnalytics[question.ID] = analytics.Questions[index]
+	}
+	for _, stat := range questionStats {
+		if question, isFound := questionAnalytics[stat.QuestionID]; isFound {
+			question.Answers = stat.Answers
+			question.CorrectAnswers = stat.CorrectAnswers
+		}
+	}
+	for _, optionCount := range optionCounts {
+		question, isFound := questionAnalytics[optionCount.QuestionID]
+		// options removed by an edit after the answer was given are left out
+		if isFound && optionCount.Option >= 0 && optionCount.Option < len(question.OptionCounts) {
+			question.OptionCounts[optionCount.Option] = optionCount.Count
+		}
+	}
+	return analytics, nil
+}
+
+func (svc teacherQuizService) getTeacherQuiz(teacher *entities.User, quizID uint) (*entities.Quiz, error) {
+	const operationName = "teacherQuizService.getTeacherQuiz"
+	quiz, err := svc.unitOfWork.QuizRepo().GetByID(quizID, []string{"Course", "Video"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching quiz by id", operationName, err)
+	}
+	if quiz == nil || quiz.Course == nil {
+		return nil, quizError.Quiz_NotFound
+	}
+	if !quiz.Course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return quiz, nil
+}
+
+func (svc teacherQuizService) getTeacherQuestion(teacher *entities.User, questionID uint) (*entities.QuizQuestion, error) {
+	const operationName = "teacherQuizService.getTeacherQuestion"
+	question, err := svc.unitOfWork.QuizQuestionRepo().GetByID(questionID, []string{"Quiz", "Quiz.Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching quiz question by id", operationName, err)
+	}
+	if question == nil || question.Quiz == nil || question.Quiz.Course == nil {
+		return nil, quizError.Quiz_QuestionNotFound
+	}
+	if !question.Quiz.Course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return question, nil
+}
+
+// getCourseVideo checks the lesson a quiz is attached to, course wide quizzes have none
+func (svc teacherQuizService) getCourseVideo(courseID uint, videoID *uint) (*entities.Video, error) {
+	const operationName = "teacherQuizService.getCourseVideo"
+	if videoID == nil {
+		return nil, nil
+	}
+	video, err := svc.unitOfWork.VideoRepo().GetByID(*videoID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil || video.CourseId == nil || *video.CourseId != courseID {
+		return nil, quizError.Quiz_VideoNotInCourse
+	}
+	return video, nil
+}
+
+// applyQuizQuestion validates the answer key against the question type, fields that don't
+// belong to the type are cleared so grading never looks at them
+func applyQuizQuestion(question *entities.QuizQuestion, dto dtoreq.QuizQuestionReqDto) error {
+	question.Type = dto.Type
+	question.Text = strings.TrimSpace(dto.Text)
+	question.Points = dto.Points
+	if question.Points == 0 {
+		question.Points = 1
+	}
+	question.Options = []string{}
+	question.CorrectOptions = []int{}
+	question.AcceptedAnswers = []string{}
+	switch dto.Type {
+	case entities.QuizQuestionType_ShortAnswer:
+		for _, answer := range dto.AcceptedAnswers {
+			if answer = strings.TrimSpace(answer); answer != "" {
+				question.AcceptedAnswers = append(question.AcceptedAnswers, answer)
+			}
+		}
+		if len(question.AcceptedAnswers) == 0 {
+			return quizError.Quiz_AcceptedAnswersRequired
+		}
+		return nil
+	case entities.QuizQuestionType_TrueFalse:
+		question.Options = slices.Clone(entities.TrueFalseOptions)
+	default:
+		for _, option := range dto.Options {
+			question.Options = append(question.Options, strings.TrimSpace(option))
+		}
+		if len(question.Options) < minQuizOptions || len(question.Options) > maxQuizOptions {
+			return quizError.Quiz_InvalidOptions
+		}
+	}
+	correctOptions := slices.Clone(dto.CorrectOptions)
+	slices.Sort(correctOptions)
+	correctOptions = slices.Compact(correctOptions)
+	if len(correctOptions) == 0 {
+		return quizError.Quiz_InvalidCorrectOptions
+	}
+	if dto.Type != entities.QuizQuestionType_MultipleChoice && len(correctOptions) != 1 {
+		return quizError.Quiz_InvalidCorrectOptions
+	}
+	for _, option := range correctOptions {
+		if option < 0 || option >= len(question.Options) {
+			return quizError.Quiz_InvalidCorrectOptions
+		}
+	}
+	question.CorrectOptions = correctOptions
+	return nil
+}
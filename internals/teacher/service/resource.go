@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	resourceError "github.com/ladmakhi81/learnup/internals/resource/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	resourcesBucket = "resources"
+	// megabytes, resources are held in memory while being validated and scanned
+	defaultMaxResourceSize = 100
+)
+
+type TeacherResourceService interface {
+	Upload(ctx context.Context, teacher *entities.User, dto dtoreq.UploadCourseResourceReqDto) (*entities.CourseResource, error)
+	GetResources(teacher *entities.User, courseID uint) ([]*entities.CourseResource, error)
+	GetDownloads(teacher *entities.User, resourceID uint, page, pageSize int) ([]*entities.CourseResourceDownload, int, error)
+	Delete(ctx context.Context, teacher *entities.User, resourceID uint) error
+}
+
+type teacherResourceService struct {
+	unitOfWork  db.UnitOfWork
+	minioClient contracts.Storage
+	scannerSvc  contracts.VirusScanner
+	config      *dtos.EnvConfig
+}
+
+func NewTeacherResourceSvc(
+	unitOfWork db.UnitOfWork,
+	minioClient contracts.Storage,
+	scannerSvc contracts.VirusScanner,
+	config *dtos.EnvConfig,
+) TeacherResourceService {
+	return &teacherResourceService{
+		unitOfWork:  unitOfWork,
+		minioClient: minioClient,
+		scannerSvc:  scannerSvc,
+		config:      config,
+	}
+}
+
+func (svc teacherResourceService) Upload(
+	ctx context.Context,
+	teacher *entities.User,
+	dto dtoreq.UploadCourseResourceReqDto,
+) (*entities.CourseResource, error) {
+	const operationName = "teacherResourceService.Upload"
+	course, err := svc.getTeacherCourse(teacher, dto.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.VideoID != nil {
+		video, err := svc.unitOfWork.VideoRepo().GetByID(*dto.VideoID, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+		}
+		if video == nil || video.CourseId == nil || *video.CourseId != course.ID {
+			return nil, resourceError.Resource_VideoNotInCourse
+		}
+	}
+	maxSize := svc.maxResourceSize()
+	if dto.Size > maxSize {
+		return nil, resourceError.Resource_FileTooLarge
+	}
+	content, err := io.ReadAll(io.LimitReader(dto.File, maxSize+1))
+	if err != nil {
+		return nil, types.NewServerError("Error in reading resource file", operationName, err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, resourceError.Resource_FileTooLarge
+	}
+	if len(content) == 0 {
+		return nil, resourceError.Resource_FileRequired
+	}
+	extension := strings.ToLower(filepath.Ext(dto.FileName))
+	contentType, ok := utils.DetectUploadFileType(extension, content)
+	if !ok {
+		return nil, resourceError.Resource_InvalidFileType
+	}
+	// the scanner failing rejects the upload, an unscanned file is only stored when no scanner is configured
+	scanResult, err := svc.scannerSvc.Scan(ctx, content)
+	if err != nil {
+		return nil, types.NewServerError("Error in scanning resource file", operationName, err)
+	}
+	if scanResult.Infected {
+		return nil, resourceError.Resource_Infected
+	}
+	resource := &entities.CourseResource{
+		CourseID:    course.ID,
+		VideoID:     dto.VideoID,
+		Title:       dto.Title,
+		FileName:    filepath.Base(dto.FileName),
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		ObjectPath:  fmt.Sprintf("%d/%s%s", course.ID, uuid.NewString(), extension),
+		ScanStatus:  entities.ResourceScanStatus_Unscanned,
+	}
+	if scanResult.Scanned {
+		resource.ScanStatus = entities.ResourceScanStatus_Clean
+		resource.ScannedAt = utils.Now()
+	}
+	// resources are kept apart from the videos bucket so the orphan sweeper never sees them
+	if err := utils.EnsureBucket(ctx, svc.minioClient, resourcesBucket); err != nil {
+		return nil, types.NewServerError("Error in preparing resources bucket", operationName, err)
+	}
+	if _, err := svc.minioClient.UploadFileByContent(ctx, resourcesBucket, resource.ObjectPath, contentType, content); err != nil {
+		return nil, types.NewServerError("Error in uploading resource", operationName, err)
+	}
+	if err := svc.unitOfWork.CourseResourceRepo().Create(resource); err != nil {
+		_ = svc.minioClient.DeleteObject(ctx, resourcesBucket, resource.ObjectPath)
+		return nil, types.NewServerError("Error in creating resource", operationName, err)
+	}
+	return resource, nil
+}
+
+func (svc teacherResourceService) GetResources(teacher *entities.User, courseID uint) ([]*entities.CourseResource, error) {
+	const operationName = "teacherResourceService.GetResources"
+	course, err := svc.getTeacherCourse(teacher, courseID)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := svc.unitOfWork.CourseResourceRepo().GetAllByCourseID(course.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching resources of course", operationName, err)
+	}
+	return resources, nil
+}
+
+func (svc teacherResourceService) GetDownloads(
+	teacher *entities.User,
+	resourceID uint,
+	page, pageSize int,
+) ([]*entities.CourseResourceDownload, int, error) {
+	const operationName = "teacherResourceService.GetDownloads"
+	resource, err := svc.getTeacherResource(teacher, resourceID)
+	if err != nil {
+		return nil, 0, err
+	}
+	downloads, count, err := svc.unitOfWork.CourseResourceDownloadRepo().GetPaginatedByResourceID(resource.ID, page, pageSize)
+	if err != nil {
+		return nil, 0, types.NewServerError("Error in fetching downloads of resource", operationName, err)
+	}
+	return downloads, count, nil
+}
+
+func (svc teacherResourceService) Delete(ctx context.Context, teacher *entities.User, resourceID uint) error {
+	const operationName = "teacherResourceService.Delete"
+	resource, err := svc.getTeacherResource(teacher, resourceID)
+	if err != nil {
+		return err
+	}
+	if err := svc.unitOfWork.CourseResourceRepo().Delete(resource); err != nil {
+		return types.NewServerError("Error in deleting resource", operationName, err)
+	}
+	if err := svc.minioClient.DeleteObject(ctx, resourcesBucket, resource.ObjectPath); err != nil {
+		return types.NewServerError("Error in deleting resource file", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherResourceService) getTeacherCourse(teacher *entities.User, courseID uint) (*entities.Course, error) {
+	const operationName = "teacherResourceService.getTeacherCourse"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return course, nil
+}
+
+func (svc teacherResourceService) getTeacherResource(teacher *entities.User, resourceID uint) (*entities.CourseResource, error) {
+	const operationName = "teacherResourceService.getTeacherResource"
+	resource, err := svc.unitOfWork.CourseResourceRepo().GetByID(resourceID, []string{"Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching resource by id", operationName, err)
+	}
+	if resource == nil || resource.Course == nil {
+		return nil, resourceError.Resource_NotFound
+	}
+	if !resource.Course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return resource, nil
+}
+
+func (svc teacherResourceService) maxResourceSize() int64 {
+	size := svc.config.Resource.MaxUploadSize
+	if size <= 0 {
+		size = defaultMaxResourceSize
+	}
+	return int64(size) << 20
+}
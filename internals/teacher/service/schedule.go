@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	publishDtoReq "github.com/ladmakhi81/learnup/internals/publish/dto/req"
+	publishError "github.com/ladmakhi81/learnup/internals/publish/error"
+	publishWorkflow "github.com/ladmakhi81/learnup/internals/publish/workflow"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	videoError "github.com/ladmakhi81/learnup/internals/video/error"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/temporal"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"time"
+)
+
+type TeacherScheduleService interface {
+	ScheduleCourse(ctx context.Context, teacher *entities.User, dto dtoreq.SchedulePublishReqDto) error
+	CancelCourseSchedule(ctx context.Context, teacher *entities.User, courseID uint) error
+	ScheduleVideo(ctx context.Context, teacher *entities.User, dto dtoreq.SchedulePublishReqDto) error
+	CancelVideoSchedule(ctx context.Context, teacher *entities.User, videoID uint) error
+}
+
+type teacherScheduleService struct {
+	unitOfWork         db.UnitOfWork
+	temporalSvc        contracts.Temporal
+	publishWorkflowSvc publishWorkflow.PublishWorkflow
+}
+
+func NewTeacherScheduleSvc(
+	unitOfWork db.UnitOfWork,
+	temporalSvc contracts.Temporal,
+	publishWorkflowSvc publishWorkflow.PublishWorkflow,
+) TeacherScheduleService {
+	return &teacherScheduleService{
+		unitOfWork:         unitOfWork,
+		temporalSvc:        temporalSvc,
+		publishWorkflowSvc: publishWorkflowSvc,
+	}
+}
+
+func (svc teacherScheduleService) ScheduleCourse(ctx context.Context, teacher *entities.User, dto dtoreq.SchedulePublishReqDto) error {
+	const operationName = "teacherScheduleService.ScheduleCourse"
+	if err := svc.checkSchedule(dto); err != nil {
+		return err
+	}
+	var previousFields map[string]any
+	course, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*entities.Course, error) {
+		course, err := tx.CourseRepo().GetByID(dto.ID, nil)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+		}
+		if course == nil {
+			return nil, courseError.Course_NotFound
+		}
+		if !course.IsTeacher(teacher.ID) {
+			return nil, courseError.Course_ForbiddenAccess
+		}
+		previousFields = svc.previousScheduleFields(course.IsPublished, course.PublishAt, course.UnpublishAt)
+		if err := tx.CourseRepo().UpdateFields(course, svc.scheduleFields(dto)); err != nil {
+			return nil, types.NewServerError("Error in updating course publish schedule", operationName, err)
+		}
+		return course, nil
+	})
+	if err != nil {
+		return err
+	}
+	// the workflow replaces the running one, so it only starts once the new schedule is committed
+	if err := svc.startSchedule(ctx, publishDtoReq.PublishTarget_Course, course.ID, dto); err != nil {
+		if restoreErr := svc.unitOfWork.CourseRepo().UpdateFields(course, previousFields); restoreErr != nil {
+			return types.NewServerError("Error in restoring course publish schedule", operationName, restoreErr)
+		}
+		return types.NewServerError("Error in starting course publish schedule workflow", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherScheduleService) CancelCourseSchedule(ctx context.Context, teacher *entities.User, courseID uint) error {
+	const operationName = "teacherScheduleService.CancelCourseSchedule"
+	course, err := svc.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return courseError.Course_NotFound
+	}
+	if !course.IsTeacher(teacher.ID) {
+		return courseError.Course_ForbiddenAccess
+	}
+	workflowID := publishWorkflow.PublishScheduleWorkflowID(publishDtoReq.PublishTarget_Course, course.ID)
+	if err := svc.temporalSvc.CancelWorker(ctx, workflowID); err != nil {
+		return types.NewServerError("Error in cancelling course publish schedule workflow", operationName, err)
+	}
+	if err := svc.unitOfWork.CourseRepo().UpdateFields(course, map[string]any{"publish_at": nil, "unpublish_at": nil}); err != nil {
+		return types.NewServerError("Error in clearing course publish schedule", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherScheduleService) ScheduleVideo(ctx context.Context, teacher *entities.User, dto dtoreq.SchedulePublishReqDto) error {
+	const operationName = "teacherScheduleService.ScheduleVideo"
+	if err := svc.checkSchedule(dto); err != nil {
+		return err
+	}
+	var previousFields map[string]any
+	video, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (*entities.Video, error) {
+		video, err := tx.VideoRepo().GetByID(dto.ID, []string{"Course"})
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+		}
+		if video == nil {
+			return nil, videoError.Video_NotFound
+		}
+		if video.Course == nil || !video.Course.IsTeacher(teacher.ID) {
+			return nil, courseError.Course_ForbiddenAccess
+		}
+		previousFields = svc.previousScheduleFields(video.IsPublished, video.PublishAt, video.UnpublishAt)
+		if err := tx.VideoRepo().UpdateFields(video, svc.scheduleFields(dto)); err != nil {
+			return nil, types.NewServerError("Error in updating video publish schedule", operationName, err)
+		}
+		return video, nil
+	})
+	if err != nil {
+		return err
+	}
+	// the workflow replaces the running one, so it only starts once the new schedule is committed
+	if err := svc.startSchedule(ctx, publishDtoReq.PublishTarget_Video, video.ID, dto); err != nil {
+		if restoreErr := svc.unitOfWork.VideoRepo().UpdateFields(video, previousFields); restoreErr != nil {
+			return types.NewServerError("Error in restoring video publish schedule", operationName, restoreErr)
+		}
+		return types.NewServerError("Error in starting video publish schedule workflow", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherScheduleService) CancelVideoSchedule(ctx context.Context, teacher *entities.User, videoID uint) error {
+	const operationName = "teacherScheduleService.CancelVideoSchedule"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(videoID, []string{"Course"})
+	if err != nil {
+		return types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil {
+		return videoError.Video_NotFound
+	}
+	if video.Course == nil || !video.Course.IsTeacher(teacher.ID) {
+		return courseError.Course_ForbiddenAccess
+	}
+	workflowID := publishWorkflow.PublishScheduleWorkflowID(publishDtoReq.PublishTarget_Video, video.ID)
+	if err := svc.temporalSvc.CancelWorker(ctx, workflowID); err != nil {
+		return types.NewServerError("Error in cancelling video publish schedule workflow", operationName, err)
+	}
+	if err := svc.unitOfWork.VideoRepo().UpdateFields(video, map[string]any{"publish_at": nil, "unpublish_at": nil}); err != nil {
+		return types.NewServerError("Error in clearing video publish schedule", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherScheduleService) checkSchedule(dto dtoreq.SchedulePublishReqDto) error {
+	if dto.PublishAt == nil && dto.UnpublishAt == nil {
+		return publishError.Publish_EmptySchedule
+	}
+	now := time.Now()
+	if dto.PublishAt != nil && !dto.PublishAt.After(now) {
+		return publishError.Publish_InvalidPublishAt
+	}
+	if dto.UnpublishAt != nil {
+		if !dto.UnpublishAt.After(now) {
+			return publishError.Publish_InvalidUnpublishAt
+		}
+		if dto.PublishAt != nil && !dto.UnpublishAt.After(*dto.PublishAt) {
+			return publishError.Publish_InvalidUnpublishAt
+		}
+	}
+	return nil
+}
+
+func (svc teacherScheduleService) scheduleFields(dto dtoreq.SchedulePublishReqDto) map[string]any {
+	fields := map[string]any{
+		"publish_at":   dto.PublishAt,
+		"unpublish_at": dto.UnpublishAt,
+	}
+	// content waiting for its launch stays hidden until the timer fires
+	if dto.PublishAt != nil {
+		fields["is_published"] = false
+	}
+	return fields
+}
+
+// previousScheduleFields puts the columns back when the workflow of a new schedule can't start,
+// the running workflow was not replaced so it still matches them
+func (svc teacherScheduleService) previousScheduleFields(isPublished bool, publishAt *time.Time, unpublishAt *time.Time) map[string]any {
+	return map[string]any{
+		"is_published": isPublished,
+		"publish_at":   publishAt,
+		"unpublish_at": unpublishAt,
+	}
+}
+
+func (svc teacherScheduleService) startSchedule(ctx context.Context, target publishDtoReq.PublishTarget, targetID uint, dto dtoreq.SchedulePublishReqDto) error {
+	workflowDto := publishDtoReq.PublishScheduleWorkflowReqDto{
+		Target:      target,
+		TargetID:    targetID,
+		PublishAt:   dto.PublishAt,
+		UnpublishAt: dto.UnpublishAt,
+	}
+	return svc.temporalSvc.ExecuteWorkerWithID(
+		ctx,
+		temporal.PUBLISH_SCHEDULE_QUEUE,
+		publishWorkflow.PublishScheduleWorkflowID(target, targetID),
+		svc.publishWorkflowSvc.PublishScheduleWorkflow,
+		workflowDto,
+	)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	publishError "github.com/ladmakhi81/learnup/internals/publish/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	"testing"
+	"time"
+)
+
+func TestCheckSchedule(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	soon := time.Now().Add(time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+	tests := []struct {
+		name        string
+		dto         dtoreq.SchedulePublishReqDto
+		expectedErr error
+	}{
+		{name: "nothing to schedule", expectedErr: publishError.Publish_EmptySchedule},
+		{name: "publish only", dto: dtoreq.SchedulePublishReqDto{PublishAt: &soon}},
+		{name: "unpublish only", dto: dtoreq.SchedulePublishReqDto{UnpublishAt: &soon}},
+		{name: "publish then unpublish", dto: dtoreq.SchedulePublishReqDto{PublishAt: &soon, UnpublishAt: &later}},
+		{name: "publish in the past", dto: dtoreq.SchedulePublishReqDto{PublishAt: &past}, expectedErr: publishError.Publish_InvalidPublishAt},
+		{name: "unpublish in the past", dto: dtoreq.SchedulePublishReqDto{UnpublishAt: &past}, expectedErr: publishError.Publish_InvalidUnpublishAt},
+		{name: "unpublish before publish", dto: dtoreq.SchedulePublishReqDto{PublishAt: &later, UnpublishAt: &soon}, expectedErr: publishError.Publish_InvalidUnpublishAt},
+		{name: "unpublish at the publish time", dto: dtoreq.SchedulePublishReqDto{PublishAt: &soon, UnpublishAt: &soon}, expectedErr: publishError.Publish_InvalidUnpublishAt},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := (teacherScheduleService{}).checkSchedule(test.dto); err != test.expectedErr {
+				t.Errorf("checkSchedule = %v, want %v", err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestScheduleFields(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour)
+	unpublishAt := publishAt.Add(time.Hour)
+	tests := []struct {
+		name          string
+		dto           dtoreq.SchedulePublishReqDto
+		expectsHidden bool
+	}{
+		{name: "a pending launch hides the content", dto: dtoreq.SchedulePublishReqDto{PublishAt: &publishAt, UnpublishAt: &unpublishAt}, expectsHidden: true},
+		{name: "unpublish only keeps the current status", dto: dtoreq.SchedulePublishReqDto{UnpublishAt: &unpublishAt}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields := (teacherScheduleService{}).scheduleFields(test.dto)
+			if fields["publish_at"] != test.dto.PublishAt || fields["unpublish_at"] != test.dto.UnpublishAt {
+				t.Errorf("schedule columns = %v", fields)
+			}
+			isPublished, isSet := fields["is_published"]
+			if isSet != test.expectsHidden || (isSet && isPublished != false) {
+				t.Errorf("is_published = %v, set %v, want hidden %v", isPublished, isSet, test.expectsHidden)
+			}
+		})
+	}
+}
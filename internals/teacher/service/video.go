@@ -1,24 +1,59 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
 	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	publishDtoReq "github.com/ladmakhi81/learnup/internals/publish/dto/req"
+	publishWorkflow "github.com/ladmakhi81/learnup/internals/publish/workflow"
 	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	videoDtoReq "github.com/ladmakhi81/learnup/internals/video/dto/req"
 	videoError "github.com/ladmakhi81/learnup/internals/video/error"
+	videoWorkflow "github.com/ladmakhi81/learnup/internals/video/workflow"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/temporal"
 	"github.com/ladmakhi81/learnup/shared/db"
 	entities2 "github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
 	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"io"
 )
 
 type TeacherVideoService interface {
 	AddVideo(dto dtoreq.AddVideoToCourseReqDto) (*entities2.Video, error)
+	UpdateThumbnail(ctx context.Context, teacher *entities2.User, dto dtoreq.UpdateVideoThumbnailReqDto) error
+	GetCaptions(teacher *entities2.User, videoID uint) ([]*entities2.VideoCaption, error)
+	UploadCaption(ctx context.Context, teacher *entities2.User, dto dtoreq.UploadVideoCaptionReqDto) (*entities2.VideoCaption, error)
+	DeleteCaption(ctx context.Context, teacher *entities2.User, dto dtoreq.DeleteVideoCaptionReqDto) error
+	Reprocess(ctx context.Context, teacher *entities2.User, videoID uint) error
+	UpdateVideo(ctx context.Context, teacher *entities2.User, dto dtoreq.UpdateVideoReqDto) (*entities2.Video, error)
+	DeleteVideo(ctx context.Context, teacher *entities2.User, videoID uint) error
 }
 
+// maxCaptionSize keeps subtitle files in memory safely, an hour of dense subtitles is far below it
+const maxCaptionSize = 2 << 20
+
 type teacherVideoService struct {
-	unitOfWork db.UnitOfWork
+	unitOfWork       db.UnitOfWork
+	temporalSvc      contracts.Temporal
+	videoWorkflowSvc videoWorkflow.VideoWorkflow
+	minioClient      contracts.Storage
 }
 
-func NewTeacherVideoSvc(unitOfWork db.UnitOfWork) TeacherVideoService {
-	return &teacherVideoService{unitOfWork: unitOfWork}
+func NewTeacherVideoSvc(
+	unitOfWork db.UnitOfWork,
+	temporalSvc contracts.Temporal,
+	videoWorkflowSvc videoWorkflow.VideoWorkflow,
+	minioClient contracts.Storage,
+) TeacherVideoService {
+	return &teacherVideoService{
+		unitOfWork:       unitOfWork,
+		temporalSvc:      temporalSvc,
+		videoWorkflowSvc: videoWorkflowSvc,
+		minioClient:      minioClient,
+	}
 }
 
 func (svc teacherVideoService) AddVideo(dto dtoreq.AddVideoToCourseReqDto) (*entities2.Video, error) {
@@ -42,12 +77,341 @@ func (svc teacherVideoService) AddVideo(dto dtoreq.AddVideoToCourseReqDto) (*ent
 		IsPublished: dto.IsPublished,
 		Description: dto.Description,
 		AccessLevel: dto.AccessLevel,
+		IsPreview:   dto.IsPreview,
 		CourseId:    &course.ID,
 		IsVerified:  false,
 		Status:      entities2.VideoStatus_Pending,
+		ThumbnailAt: dto.ThumbnailAt,
 	}
 	if err := svc.unitOfWork.VideoRepo().Create(video); err != nil {
 		return nil, types.NewServerError("Create course throw error", operationName, err)
 	}
 	return video, nil
 }
+
+func (svc teacherVideoService) UpdateThumbnail(ctx context.Context, teacher *entities2.User, dto dtoreq.UpdateVideoThumbnailReqDto) error {
+	const operationName = "teacherVideoService.UpdateThumbnail"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(dto.VideoID, []string{"Course"})
+	if err != nil {
+		return types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil {
+		return videoError.Video_NotFound
+	}
+	if video.Course == nil || !video.Course.IsTeacher(teacher.ID) {
+		return courseError.Course_ForbiddenAccess
+	}
+	if video.Status != entities2.VideoStatus_Done || video.Duration == nil {
+		return videoError.Video_NotReady
+	}
+	duration, err := utils.ClockToSeconds(*video.Duration)
+	if err != nil {
+		return types.NewServerError("Error in converting duration of video", operationName, err)
+	}
+	if dto.ThumbnailAt >= duration {
+		return videoError.Video_InvalidThumbnailAt
+	}
+	if err := svc.unitOfWork.VideoRepo().UpdateFields(video, map[string]any{"thumbnail_at": dto.ThumbnailAt}); err != nil {
+		return types.NewServerError("Error in updating thumbnail timestamp of video", operationName, err)
+	}
+	if err := svc.temporalSvc.ExecuteWorkerWithID(
+		ctx,
+		temporal.UPDATE_VIDEO_THUMBNAIL_QUEUE,
+		videoWorkflow.UpdateVideoThumbnailWorkflowID(video.ID),
+		svc.videoWorkflowSvc.UpdateVideoThumbnailWorkflow,
+		videoDtoReq.UpdateVideoThumbnailWorkflowReqDto{VideoID: video.ID},
+	); err != nil {
+		return types.NewServerError("Error in starting update video thumbnail workflow", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherVideoService) GetCaptions(teacher *entities2.User, videoID uint) ([]*entities2.VideoCaption, error) {
+	const operationName = "teacherVideoService.GetCaptions"
+	if _, err := svc.getTeacherVideo(teacher, videoID); err != nil {
+		return nil, err
+	}
+	order := "language asc"
+	captions, err := svc.unitOfWork.VideoCaptionRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"video_id": videoID},
+		Order:      &order,
+	})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching captions of video", operationName, err)
+	}
+	return captions, nil
+}
+
+// UploadCaption stores the subtitle as webvtt, uploading the same language again replaces its track
+func (svc teacherVideoService) UploadCaption(
+	ctx context.Context,
+	teacher *entities2.User,
+	dto dtoreq.UploadVideoCaptionReqDto,
+) (*entities2.VideoCaption, error) {
+	const operationName = "teacherVideoService.UploadCaption"
+	video, err := svc.getTeacherVideo(teacher, dto.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(io.LimitReader(dto.File, maxCaptionSize+1))
+	if err != nil {
+		return nil, types.NewServerError("Error in reading caption file", operationName, err)
+	}
+	if len(content) > maxCaptionSize {
+		return nil, videoError.Video_InvalidCaption
+	}
+	webVTT, err := utils.ToWebVTT(content)
+	if err != nil {
+		return nil, videoError.Video_InvalidCaption
+	}
+	// every upload gets its own object since cloned videos keep pointing to the previous one
+	objectPath := fmt.Sprintf("captions/%d/%s-%s.vtt", video.ID, dto.Language, uuid.NewString())
+	if _, err := svc.minioClient.UploadFileByContent(ctx, "videos", objectPath, "text/vtt", webVTT); err != nil {
+		return nil, types.NewServerError("Error in uploading caption", operationName, err)
+	}
+	caption, err := svc.unitOfWork.VideoCaptionRepo().GetOne(
+		map[string]any{"video_id": video.ID, "language": dto.Language},
+		nil,
+	)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching caption of video", operationName, err)
+	}
+	if caption == nil {
+		caption = &entities2.VideoCaption{
+			VideoID:  video.ID,
+			Language: dto.Language,
+			Label:    dto.Label,
+			URL:      objectPath,
+		}
+		if err := svc.unitOfWork.VideoCaptionRepo().Create(caption); err != nil {
+			return nil, types.NewServerError("Error in creating caption", operationName, err)
+		}
+		return caption, nil
+	}
+	previousURL := caption.URL
+	caption.Label = dto.Label
+	caption.URL = objectPath
+	if err := svc.unitOfWork.VideoCaptionRepo().Update(caption); err != nil {
+		return nil, types.NewServerError("Error in updating caption", operationName, err)
+	}
+	if err := svc.deleteCaptionObject(ctx, previousURL); err != nil {
+		return nil, types.NewServerError("Error in deleting previous caption file", operationName, err)
+	}
+	return caption, nil
+}
+
+func (svc teacherVideoService) DeleteCaption(ctx context.Context, teacher *entities2.User, dto dtoreq.DeleteVideoCaptionReqDto) error {
+	const operationName = "teacherVideoService.DeleteCaption"
+	video, err := svc.getTeacherVideo(teacher, dto.VideoID)
+	if err != nil {
+		return err
+	}
+	caption, err := svc.unitOfWork.VideoCaptionRepo().GetOne(
+		map[string]any{"video_id": video.ID, "language": dto.Language},
+		nil,
+	)
+	if err != nil {
+		return types.NewServerError("Error in fetching caption of video", operationName, err)
+	}
+	if caption == nil {
+		return videoError.Video_CaptionNotFound
+	}
+	if err := svc.unitOfWork.VideoCaptionRepo().Delete(caption); err != nil {
+		return types.NewServerError("Error in deleting caption", operationName, err)
+	}
+	if err := svc.deleteCaptionObject(ctx, caption.URL); err != nil {
+		return types.NewServerError("Error in deleting caption file", operationName, err)
+	}
+	return nil
+}
+
+// Reprocess runs the processing workflow again from the uploaded source, which is
+// only deleted once a video is processed successfully
+func (svc teacherVideoService) Reprocess(ctx context.Context, teacher *entities2.User, videoID uint) error {
+	const operationName = "teacherVideoService.Reprocess"
+	video, err := svc.getTeacherVideo(teacher, videoID)
+	if err != nil {
+		return err
+	}
+	// a failed replacement is done but still has the new source to process
+	if video.Status == entities2.VideoStatus_Done && video.FailureReason == nil {
+		return videoError.Video_AlreadyProcessed
+	}
+	if video.SourceObjectID == nil {
+		return videoError.Video_SourceNotFound
+	}
+	// an upload workflow is named after its upload and wouldn't be replaced by the one started below
+	workflowID := videoWorkflow.ProcessCourseVideoWorkflowID(video.ID)
+	if runningWorkflowID := videoWorkflow.VideoProcessingWorkflowID(video); runningWorkflowID != workflowID {
+		progress, err := svc.temporalSvc.DescribeWorkflowProgress(ctx, runningWorkflowID)
+		if err != nil {
+			return types.NewServerError("Error in describing video processing workflow", operationName, err)
+		}
+		if progress != nil && progress.IsRunning {
+			return videoError.Video_Processing
+		}
+	}
+	if err := svc.temporalSvc.ExecuteWorkerWithID(
+		ctx,
+		temporal.ADD_NEW_COURSE_VIDEO_QUEUE,
+		workflowID,
+		svc.videoWorkflowSvc.AddNewCourseVideoWorkflow,
+		videoDtoReq.AddNewCourseVideoWorkflowReqDto{
+			CourseID: *video.CourseId,
+			ObjectID: *video.SourceObjectID,
+			VideoID:  video.ID,
+		},
+	); err != nil {
+		return types.NewServerError("Error in starting video processing workflow", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherVideoService) UpdateVideo(ctx context.Context, teacher *entities2.User, dto dtoreq.UpdateVideoReqDto) (*entities2.Video, error) {
+	const operationName = "teacherVideoService.UpdateVideo"
+	video, err := svc.getTeacherVideo(teacher, dto.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	sameTitleVideo, err := svc.unitOfWork.VideoRepo().GetOne(map[string]any{"title": dto.Title}, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in checking existence of video title", operationName, err)
+	}
+	if sameTitleVideo != nil && sameTitleVideo.ID != video.ID {
+		return nil, videoError.Video_TitleDuplicated
+	}
+	fields := map[string]any{
+		"title":        dto.Title,
+		"description":  dto.Description,
+		"access_level": dto.AccessLevel,
+		"is_published": dto.IsPublished,
+		"is_preview":   dto.IsPreview,
+	}
+	// publishing by hand replaces a pending schedule, otherwise its timer flips the video back later
+	isScheduled := video.PublishAt != nil || video.UnpublishAt != nil
+	if isScheduled && dto.IsPublished != video.IsPublished {
+		workflowID := publishWorkflow.PublishScheduleWorkflowID(publishDtoReq.PublishTarget_Video, video.ID)
+		if err := svc.temporalSvc.CancelWorker(ctx, workflowID); err != nil {
+			return nil, types.NewServerError("Error in cancelling video publish schedule workflow", operationName, err)
+		}
+		fields["publish_at"] = nil
+		fields["unpublish_at"] = nil
+		video.PublishAt = nil
+		video.UnpublishAt = nil
+	}
+	if err := svc.unitOfWork.VideoRepo().UpdateFields(video, fields); err != nil {
+		return nil, types.NewServerError("Error in updating video", operationName, err)
+	}
+	video.Title = dto.Title
+	video.Description = dto.Description
+	video.AccessLevel = dto.AccessLevel
+	video.IsPublished = dto.IsPublished
+	video.IsPreview = dto.IsPreview
+	return video, nil
+}
+
+// DeleteVideo removes the video and its captions right away, the files are deleted by a
+// workflow since published versions and cloned courses can still play them
+func (svc teacherVideoService) DeleteVideo(ctx context.Context, teacher *entities2.User, videoID uint) error {
+	const operationName = "teacherVideoService.DeleteVideo"
+	video, err := svc.getTeacherVideo(teacher, videoID)
+	if err != nil {
+		return err
+	}
+	captions, err := svc.unitOfWork.VideoCaptionRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"video_id": video.ID},
+	})
+	if err != nil {
+		return types.NewServerError("Error in fetching captions of video", operationName, err)
+	}
+	resources, err := svc.unitOfWork.CourseResourceRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"video_id": video.ID},
+	})
+	if err != nil {
+		return types.NewServerError("Error in fetching resources of video", operationName, err)
+	}
+	quizzes, err := svc.unitOfWork.QuizRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"video_id": video.ID},
+	})
+	if err != nil {
+		return types.NewServerError("Error in fetching quizzes of video", operationName, err)
+	}
+	if _, err := db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		if len(captions) > 0 {
+			if err := tx.VideoCaptionRepo().BatchDelete(captions); err != nil {
+				return nil, types.NewServerError("Error in deleting captions of video", operationName, err)
+			}
+		}
+		if len(resources) > 0 {
+			if err := tx.CourseResourceRepo().BatchDelete(resources); err != nil {
+				return nil, types.NewServerError("Error in deleting resources of video", operationName, err)
+			}
+		}
+		if len(quizzes) > 0 {
+			if err := tx.QuizRepo().BatchDelete(quizzes); err != nil {
+				return nil, types.NewServerError("Error in deleting quizzes of video", operationName, err)
+			}
+		}
+		if err := tx.VideoRepo().Delete(video); err != nil {
+			return nil, types.NewServerError("Error in deleting video", operationName, err)
+		}
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		if err := svc.minioClient.DeleteObject(ctx, resourcesBucket, resource.ObjectPath); err != nil {
+			return types.NewServerError("Error in deleting resource file of video", operationName, err)
+		}
+	}
+	// a running processing would otherwise keep writing files of a deleted video
+	if err := svc.temporalSvc.CancelWorker(ctx, videoWorkflow.VideoProcessingWorkflowID(video)); err != nil {
+		return types.NewServerError("Error in cancelling video processing workflow", operationName, err)
+	}
+	cleanupDto := videoDtoReq.CleanupVideoStorageReqDto{
+		Prefixes: []string{video.URL},
+	}
+	for _, caption := range captions {
+		cleanupDto.CaptionURLs = append(cleanupDto.CaptionURLs, caption.URL)
+	}
+	if video.SourceObjectID != nil {
+		cleanupDto.SourceObjectIDs = []string{*video.SourceObjectID}
+	}
+	if err := svc.temporalSvc.ExecuteWorkerWithID(
+		ctx,
+		temporal.CLEANUP_VIDEO_STORAGE_QUEUE,
+		videoWorkflow.CleanupVideoStorageWorkflowID(video.ID),
+		svc.videoWorkflowSvc.CleanupVideoStorageWorkflow,
+		cleanupDto,
+	); err != nil {
+		return types.NewServerError("Error in starting video storage cleanup workflow", operationName, err)
+	}
+	return nil
+}
+
+func (svc teacherVideoService) getTeacherVideo(teacher *entities2.User, videoID uint) (*entities2.Video, error) {
+	const operationName = "teacherVideoService.getTeacherVideo"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(videoID, []string{"Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil {
+		return nil, videoError.Video_NotFound
+	}
+	if video.Course == nil || !video.Course.IsTeacher(teacher.ID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	return video, nil
+}
+
+// deleteCaptionObject keeps the file while a caption of a cloned video still uses it
+func (svc teacherVideoService) deleteCaptionObject(ctx context.Context, objectPath string) error {
+	isShared, err := svc.unitOfWork.VideoCaptionRepo().Exist(map[string]any{"url": objectPath})
+	if err != nil {
+		return err
+	}
+	if isShared {
+		return nil
+	}
+	return svc.minioClient.DeleteObject(ctx, "videos", objectPath)
+}
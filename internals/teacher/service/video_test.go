@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	dtoreq "github.com/ladmakhi81/learnup/internals/teacher/dto/req"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"slices"
+	"testing"
+	"time"
+)
+
+type videoUnitOfWork struct {
+	db.UnitOfWork
+	videoRepo *updatedVideoRepo
+}
+
+func (uow videoUnitOfWork) VideoRepo() repositories.VideoRepo {
+	return uow.videoRepo
+}
+
+type updatedVideoRepo struct {
+	repositories.VideoRepo
+	video  *entities.Video
+	fields map[string]any
+}
+
+func (repo *updatedVideoRepo) GetByID(id uint, relations []string) (*entities.Video, error) {
+	return repo.video, nil
+}
+
+func (repo *updatedVideoRepo) GetOne(condition map[string]any, relations []string) (*entities.Video, error) {
+	return nil, nil
+}
+
+func (repo *updatedVideoRepo) UpdateFields(video *entities.Video, fields map[string]any) error {
+	repo.fields = fields
+	return nil
+}
+
+type cancelRecorder struct {
+	contracts.Temporal
+	cancelled []string
+}
+
+func (temporal *cancelRecorder) CancelWorker(ctx context.Context, workflowID string) error {
+	temporal.cancelled = append(temporal.cancelled, workflowID)
+	return nil
+}
+
+func TestUpdateVideoPublishSchedule(t *testing.T) {
+	scheduledAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name               string
+		isPublished        bool
+		publishAt          *time.Time
+		unpublishAt        *time.Time
+		requestedPublished bool
+		expectsCancelling  bool
+	}{
+		{name: "publishing a scheduled video by hand", publishAt: &scheduledAt, requestedPublished: true, expectsCancelling: true},
+		{name: "unpublishing a video waiting to be unpublished", isPublished: true, unpublishAt: &scheduledAt, expectsCancelling: true},
+		{name: "editing text keeps the schedule", publishAt: &scheduledAt},
+		{name: "no schedule to cancel", requestedPublished: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			teacherID := uint(5)
+			video := &entities.Video{
+				IsPublished: test.isPublished,
+				PublishAt:   test.publishAt,
+				UnpublishAt: test.unpublishAt,
+				Course:      &entities.Course{TeacherID: &teacherID},
+			}
+			video.ID = 9
+			videoRepo := &updatedVideoRepo{video: video}
+			temporal := &cancelRecorder{}
+			svc := teacherVideoService{unitOfWork: videoUnitOfWork{videoRepo: videoRepo}, temporalSvc: temporal}
+			teacher := &entities.User{}
+			teacher.ID = teacherID
+			dto := dtoreq.UpdateVideoReqDto{VideoID: video.ID, Title: "Intro", IsPublished: test.requestedPublished}
+			if _, err := svc.UpdateVideo(context.Background(), teacher, dto); err != nil {
+				t.Fatalf("UpdateVideo failed: %v", err)
+			}
+			isCancelled := slices.Contains(temporal.cancelled, "publish-schedule-video-9")
+			_, isCleared := videoRepo.fields["publish_at"]
+			if isCancelled != test.expectsCancelling || isCleared != test.expectsCancelling {
+				t.Errorf("cancelled %v, cleared %v, want %v", isCancelled, isCleared, test.expectsCancelling)
+			}
+			if test.expectsCancelling && (video.PublishAt != nil || video.UnpublishAt != nil) {
+				t.Errorf("schedule left on the video %v %v", video.PublishAt, video.UnpublishAt)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ type TusActionType string
 const (
 	TusActionType_NewCourseVideo       TusActionType = "add-new-course-video"
 	TusActionType_AddIntroductionVideo TusActionType = "add-introduction-video"
+	TusActionType_ReplaceCourseVideo   TusActionType = "replace-course-video"
 )
 
 type TusWebhookDto struct {
@@ -38,3 +39,16 @@ type TusWebhookDto struct {
 		} `json:"HTTPRequest"`
 	} `json:"Event"`
 }
+
+// TusHookResponseDto is read by tusd, a rejected pre-create returns HTTPResponse to the uploader
+// and nothing is stored
+type TusHookResponseDto struct {
+	HTTPResponse *TusHookHTTPResponse `json:"HTTPResponse,omitempty"`
+	RejectUpload bool                 `json:"RejectUpload"`
+}
+
+type TusHookHTTPResponse struct {
+	StatusCode int               `json:"StatusCode"`
+	Body       string            `json:"Body"`
+	Header     map[string]string `json:"Header"`
+}
@@ -0,0 +1,17 @@
+package error
+
+import (
+	"github.com/ladmakhi81/learnup/shared/types"
+)
+
+var (
+	Tus_Unauthorized      = types.NewUnauthorizedError("tus.errors.unauthorized")
+	Tus_InvalidActionType = types.NewBadRequestError("tus.errors.invalid_action_type")
+	Tus_InvalidMetadata   = types.NewBadRequestError("tus.errors.invalid_metadata")
+	Tus_InvalidFileType   = types.NewBadRequestError("tus.errors.invalid_file_type")
+	Tus_SizeRequired      = types.NewBadRequestError("tus.errors.size_required")
+	Tus_FileTooLarge      = types.NewBadRequestError("tus.errors.file_too_large")
+	Tus_VideoProcessing   = types.NewConflictError("tus.errors.video_processing")
+	Tus_UploadNotFound    = types.NewNotFoundError("tus.errors.upload_not_found")
+	Tus_InvalidSecret     = types.NewUnauthorizedError("tus.errors.invalid_secret")
+)
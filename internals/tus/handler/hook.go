@@ -1,35 +1,125 @@
 package handler
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	reqdto "github.com/ladmakhi81/learnup/internals/tus/dto"
+	tusError "github.com/ladmakhi81/learnup/internals/tus/error"
 	"github.com/ladmakhi81/learnup/internals/tus/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
 	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"log"
 	"net/http"
+	"time"
 )
 
 type TusHookHandler struct {
-	tusHookSvc service.TusService
+	tusHookSvc     service.TusService
+	translationSvc contracts.Translator
+	config         *dtos.EnvConfig
 }
 
-func NewTusHookHandler(tusHookSvc service.TusService) *TusHookHandler {
+func NewTusHookHandler(tusHookSvc service.TusService, translationSvc contracts.Translator, config *dtos.EnvConfig) *TusHookHandler {
 	return &TusHookHandler{
-		tusHookSvc: tusHookSvc,
+		tusHookSvc:     tusHookSvc,
+		translationSvc: translationSvc,
+		config:         config,
 	}
 }
 
-func (h TusHookHandler) VideoWebhook(ctx *gin.Context) (*types.ApiResponse, error) {
+// VideoWebhook answers tusd with a bare hook response, tusd reads RejectUpload and HTTPResponse
+// from the body so it can't be wrapped like the other api responses. A failing post hook answers
+// with an error status so tusd delivers it again, which the service turns into a no-op once handled
+func (h TusHookHandler) VideoWebhook(ctx *gin.Context) {
+	if !h.isTrustedHook(ctx) {
+		statusCode, apiError := h.hookError(ctx, tusError.Tus_InvalidSecret)
+		ctx.JSON(statusCode, apiError)
+		return
+	}
 	dto := &reqdto.TusWebhookDto{}
-	if err := ctx.Bind(dto); err != nil {
-		return nil, types.NewServerError(
-			"Error in convert data into tus dto",
-			"TusHookHandler.VideoWebhook",
-			err,
-		)
+	if err := ctx.ShouldBindJSON(dto); err != nil {
+		statusCode, apiError := h.hookError(ctx, tusError.Tus_InvalidMetadata)
+		ctx.JSON(statusCode, apiError)
+		return
 	}
+	var err error
 	switch dto.Type {
+	case reqdto.TusHookType_PreCreate:
+		if err := h.tusHookSvc.PreCreateWebhook(*dto); err != nil {
+			ctx.JSON(http.StatusOK, h.rejectResponse(ctx, err))
+			return
+		}
+	case reqdto.TusHookType_PostCreate:
+		err = h.tusHookSvc.PostCreateWebhook(*dto)
+	case reqdto.TusHookType_PostReceive:
+		err = h.tusHookSvc.PostReceiveWebhook(*dto)
 	case reqdto.TusHookType_PostFinish:
-		go h.tusHookSvc.VideoWebhook(ctx, *dto)
+		err = h.tusHookSvc.VideoWebhook(ctx, *dto)
+	}
+	if err != nil {
+		statusCode, apiError := h.hookError(ctx, err)
+		ctx.JSON(statusCode, apiError)
+		return
+	}
+	ctx.JSON(http.StatusOK, reqdto.TusHookResponseDto{})
+}
+
+// isTrustedHook only lets tusd through, the route is public and the event body decides which
+// course an upload lands in. tusd can't add headers of its own, so besides the header the secret
+// is read from the basic auth it sends for credentials in the hook url, never from the query which
+// ends up in access logs. Without a configured secret every hook is refused
+func (h TusHookHandler) isTrustedHook(ctx *gin.Context) bool {
+	expected := h.config.Video.UploadHookSecret
+	if expected == "" {
+		return false
+	}
+	secret := ctx.GetHeader("X-Tus-Hook-Secret")
+	if secret == "" {
+		_, secret, _ = ctx.Request.BasicAuth()
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) == 1
+}
+
+// rejectResponse turns the error into what the uploader gets back, shaped like any api error
+func (h TusHookHandler) rejectResponse(ctx *gin.Context, err error) reqdto.TusHookResponseDto {
+	statusCode, apiError := h.hookError(ctx, err)
+	body, _ := json.Marshal(apiError)
+	return reqdto.TusHookResponseDto{
+		RejectUpload: true,
+		HTTPResponse: &reqdto.TusHookHTTPResponse{
+			StatusCode: statusCode,
+			Body:       string(body),
+			Header:     map[string]string{"Content-Type": "application/json"},
+		},
+	}
+}
+
+// hookError translates client errors and logs the rest, which only show up as an internal error
+func (h TusHookHandler) hookError(ctx *gin.Context, err error) (int, *types.ApiError) {
+	timestamp := time.Now().Unix()
+	traceID := uuid.New().String()
+	if clientErr, ok := err.(*types.ClientError); ok {
+		message := h.translationSvc.Translate(clientErr.Message)
+		return clientErr.StatusCode, types.NewApiError(clientErr.StatusCode, message, timestamp, traceID)
+	}
+	logMessage := fmt.Sprintf("Timestamp: %v, URL: %s, Message: %s, TraceID: %s", timestamp, ctx.Request.URL.Path, err.Error(), traceID)
+	if serverErr, ok := err.(*types.ServerError); ok {
+		logMessage = fmt.Sprintf(
+			"Timestamp: %v, URL: %s, Location: %s, Message: %s, TraceID: %s",
+			timestamp,
+			ctx.Request.URL.Path,
+			serverErr.Location,
+			serverErr.Message+" - "+serverErr.MainErr.Error(),
+			traceID,
+		)
+	}
+	if logErr := utils.SaveMessageIntoLog("error", logMessage); logErr != nil {
+		log.Printf("Unable to store error in log file ( tus hook handler ): %s\n", logErr)
 	}
-	return types.NewApiResponse(http.StatusOK, gin.H{"message": "hook received"}), nil
+	return http.StatusInternalServerError, types.NewApiError(http.StatusInternalServerError, "Internal Server Error", timestamp, traceID)
 }
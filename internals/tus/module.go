@@ -5,7 +5,7 @@ import (
 	tusHandler "github.com/ladmakhi81/learnup/internals/tus/handler"
 	tusHookService "github.com/ladmakhi81/learnup/internals/tus/service"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
-	"github.com/ladmakhi81/learnup/shared/utils"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
 )
 
 type Module struct {
@@ -13,9 +13,9 @@ type Module struct {
 	translationSvc contracts.Translator
 }
 
-func NewModule(tusHookSvc tusHookService.TusService, translationSvc contracts.Translator) *Module {
+func NewModule(tusHookSvc tusHookService.TusService, translationSvc contracts.Translator, config *dtos.EnvConfig) *Module {
 	return &Module{
-		hookHandler:    tusHandler.NewTusHookHandler(tusHookSvc),
+		hookHandler:    tusHandler.NewTusHookHandler(tusHookSvc, translationSvc, config),
 		translationSvc: translationSvc,
 	}
 }
@@ -23,5 +23,5 @@ func NewModule(tusHookSvc tusHookService.TusService, translationSvc contracts.Tr
 func (m Module) Register(api *gin.RouterGroup) {
 	tusHookApi := api.Group("/tus-hooks")
 
-	tusHookApi.POST("/videos", utils.JsonHandler(m.translationSvc, m.hookHandler.VideoWebhook))
+	tusHookApi.POST("/videos", m.hookHandler.VideoWebhook)
 }
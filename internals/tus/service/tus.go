@@ -2,21 +2,50 @@ package service
 
 import (
 	"context"
-	"fmt"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
 	reqdto "github.com/ladmakhi81/learnup/internals/tus/dto"
+	tusError "github.com/ladmakhi81/learnup/internals/tus/error"
 	dtoreq "github.com/ladmakhi81/learnup/internals/video/dto/req"
+	videoError "github.com/ladmakhi81/learnup/internals/video/error"
 	videoService "github.com/ladmakhi81/learnup/internals/video/service"
 	"github.com/ladmakhi81/learnup/internals/video/workflow"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
 	"github.com/ladmakhi81/learnup/pkg/dtos"
 	"github.com/ladmakhi81/learnup/pkg/temporal"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
 	"github.com/ladmakhi81/learnup/shared/utils"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultMaxUploadSize = 4096
+
+var (
+	// containers ffmpeg decodes reliably, anything else would only fail after the whole upload
+	allowedVideoExtensions = []string{".mp4", ".m4v", ".mov", ".mkv", ".webm", ".avi"}
+	allowedVideoMimeTypes  = []string{
+		"video/mp4",
+		"video/x-m4v",
+		"video/quicktime",
+		"video/x-matroska",
+		"video/webm",
+		"video/x-msvideo",
+	}
 )
 
 type TusService interface {
-	VideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto)
-	AddCourseVideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto)
-	AddIntroductionVideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto)
+	PreCreateWebhook(dto reqdto.TusWebhookDto) error
+	PostCreateWebhook(dto reqdto.TusWebhookDto) error
+	PostReceiveWebhook(dto reqdto.TusWebhookDto) error
+	VideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto) error
+	AddCourseVideoWebhook(ctx context.Context, upload *entities.Upload) (bool, error)
+	AddIntroductionVideoWebhook(ctx context.Context, upload *entities.Upload) (bool, error)
 }
 
 type TusServiceImpl struct {
@@ -24,6 +53,9 @@ type TusServiceImpl struct {
 	logSvc           contracts.Log
 	temporalSvc      contracts.Temporal
 	videoWorkflowSvc workflow.VideoWorkflow
+	unitOfWork       db.UnitOfWork
+	tokenSvc         contracts.Token
+	config           *dtos.EnvConfig
 }
 
 func NewTusServiceImpl(
@@ -31,93 +63,295 @@ func NewTusServiceImpl(
 	logSvc contracts.Log,
 	temporalSvc contracts.Temporal,
 	videoWorkflowSvc workflow.VideoWorkflow,
+	unitOfWork db.UnitOfWork,
+	tokenSvc contracts.Token,
+	config *dtos.EnvConfig,
 ) *TusServiceImpl {
 	return &TusServiceImpl{
 		videoSvc:         videoSvc,
 		logSvc:           logSvc,
 		temporalSvc:      temporalSvc,
 		videoWorkflowSvc: videoWorkflowSvc,
+		unitOfWork:       unitOfWork,
+		tokenSvc:         tokenSvc,
+		config:           config,
 	}
 }
 
-func (tus TusServiceImpl) VideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto) {
-	fmt.Println(1, fmt.Sprintf("%v", reqdto.TusActionType_AddIntroductionVideo) == dto.Event.Upload.MetaData["actionType"], fmt.Sprintf("--%s--", dto.Event.Upload.MetaData["actionType"]))
-	switch dto.Event.Upload.MetaData["actionType"] {
-	case utils.ToString(reqdto.TusActionType_NewCourseVideo):
-		tus.AddCourseVideoWebhook(ctx, dto)
-	case utils.ToString(reqdto.TusActionType_AddIntroductionVideo):
-		tus.AddIntroductionVideoWebhook(ctx, dto)
+// PreCreateWebhook runs before tusd stores anything, the upload is rejected unless the uploader
+// owns the course the metadata points at and the declared file is a video within the size limit
+func (tus TusServiceImpl) PreCreateWebhook(dto reqdto.TusWebhookDto) error {
+	_, err := tus.validateCreate(dto)
+	return err
+}
+
+// validateCreate checks the creation request and builds the upload it may become, the ids
+// only come from metadata that passed the ownership check
+func (tus TusServiceImpl) validateCreate(dto reqdto.TusWebhookDto) (*entities.Upload, error) {
+	const operationName = "TusServiceImpl.validateCreate"
+	header := http.Header(dto.Event.HTTPRequest.Header)
+	claim, err := tus.tokenSvc.DecodeToken(header.Get("Authorization"))
+	if err != nil {
+		return nil, types.NewServerError("Error in decoding token of uploader", operationName, err)
+	}
+	if claim == nil {
+		return nil, tusError.Tus_Unauthorized
+	}
+	actionType := reqdto.TusActionType(metadataValue(dto, "actionType"))
+	if !slices.Contains([]reqdto.TusActionType{
+		reqdto.TusActionType_NewCourseVideo,
+		reqdto.TusActionType_ReplaceCourseVideo,
+		reqdto.TusActionType_AddIntroductionVideo,
+	}, actionType) {
+		return nil, tusError.Tus_InvalidActionType
+	}
+	if err := tus.validateUploadFile(dto); err != nil {
+		return nil, err
+	}
+	courseID, err := utils.ToUint(metadataValue(dto, "courseId"))
+	if err != nil {
+		return nil, tusError.Tus_InvalidMetadata
+	}
+	course, err := tus.unitOfWork.CourseRepo().GetByID(courseID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course by id", operationName, err)
+	}
+	if course == nil {
+		return nil, courseError.Course_NotFound
+	}
+	if !course.IsTeacher(claim.UserID) {
+		return nil, courseError.Course_ForbiddenAccess
+	}
+	upload := &entities.Upload{
+		UploadID:   dto.Event.Upload.ID,
+		ActionType: string(actionType),
+		CourseID:   &course.ID,
+		Size:       dto.Event.Upload.Size,
+		Offset:     dto.Event.Upload.Offset,
+		Status:     entities.UploadStatus_Created,
+	}
+	if actionType == reqdto.TusActionType_AddIntroductionVideo {
+		return upload, nil
+	}
+	videoID, err := utils.ToUint(metadataValue(dto, "videoId"))
+	if err != nil {
+		return nil, tusError.Tus_InvalidMetadata
+	}
+	video, err := tus.unitOfWork.VideoRepo().GetByID(videoID, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil || video.CourseId == nil || *video.CourseId != course.ID {
+		return nil, videoError.Video_NotFound
 	}
+	if err := validateVideoUpload(video, actionType); err != nil {
+		return nil, err
+	}
+	upload.VideoID = &video.ID
+	return upload, nil
 }
 
-func (tus TusServiceImpl) AddCourseVideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto) {
-	objectId, objectIdExist := dto.Event.Upload.Storage["Key"]
-	courseIdParam, courseIdExist := dto.Event.Upload.MetaData["courseId"]
-	videoIdParam, videoIdExist := dto.Event.Upload.MetaData["videoId"]
-	if objectIdExist && courseIdExist && videoIdExist {
-		courseID, courseIDErr := utils.ToUint(courseIdParam.(string))
-		if courseIDErr != nil {
-			tus.logSvc.Error(dtos.LogMessage{Message: "Error in converting course id"})
-			return
-		}
-		videoID, videoIDErr := utils.ToUint(videoIdParam.(string))
-		if videoIDErr != nil {
-			tus.logSvc.Error(dtos.LogMessage{Message: "Error in converting video id"})
-			return
-		}
-		workflowDto := dtoreq.AddNewCourseVideoWorkflowReqDto{
-			CourseID: courseID,
-			ObjectID: objectId.(string),
-			VideoID:  videoID,
-		}
-		workflowErr := tus.temporalSvc.ExecuteWorker(
-			ctx,
-			temporal.ADD_NEW_COURSE_VIDEO_QUEUE,
-			tus.videoWorkflowSvc.AddNewCourseVideoWorkflow,
-			workflowDto,
-		)
-
-		if workflowErr != nil {
-			tus.logSvc.Error(dtos.LogMessage{
-				Message: "Error happen in workflow of video workflow service",
-				Metadata: map[string]any{
-					"error": workflowErr,
-					"key":   objectId,
-				},
-			})
+func (tus TusServiceImpl) validateUploadFile(dto reqdto.TusWebhookDto) error {
+	upload := dto.Event.Upload
+	// a deferred length can't be checked against the limit until the bytes are already stored
+	if upload.SizeIsDeferred || upload.Size <= 0 {
+		return tusError.Tus_SizeRequired
+	}
+	maxUploadSize := tus.config.Video.MaxUploadSize
+	if maxUploadSize <= 0 {
+		maxUploadSize = defaultMaxUploadSize
+	}
+	if upload.Size > int64(maxUploadSize)*1024*1024 {
+		return tusError.Tus_FileTooLarge
+	}
+	fileName := metadataValue(dto, "filename")
+	fileType := metadataValue(dto, "filetype")
+	if fileName == "" && fileType == "" {
+		return tusError.Tus_InvalidFileType
+	}
+	if fileName != "" && !slices.Contains(allowedVideoExtensions, strings.ToLower(filepath.Ext(fileName))) {
+		return tusError.Tus_InvalidFileType
+	}
+	if fileType != "" {
+		mediaType, _, err := mime.ParseMediaType(fileType)
+		if err != nil || !slices.Contains(allowedVideoMimeTypes, strings.ToLower(mediaType)) {
+			return tusError.Tus_InvalidFileType
 		}
 	}
+	return nil
+}
 
-	return
+// validateVideoUpload keeps a new upload from overwriting a processed video and any upload
+// from racing an encode that is still running
+func validateVideoUpload(video *entities.Video, actionType reqdto.TusActionType) error {
+	if video.SourceObjectID != nil && video.FailureReason == nil {
+		return tusError.Tus_VideoProcessing
+	}
+	if actionType == reqdto.TusActionType_NewCourseVideo && video.URL != "" {
+		return videoError.Video_AlreadyProcessed
+	}
+	return nil
 }
 
-func (tus TusServiceImpl) AddIntroductionVideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto) {
-	objectId, objectIdExist := dto.Event.Upload.Storage["Key"]
-	courseIdParam, courseIdExist := dto.Event.Upload.MetaData["courseId"]
-	if objectIdExist && courseIdExist {
-		courseID, courseIDErr := utils.ToUint(courseIdParam.(string))
-		if courseIDErr != nil {
-			tus.logSvc.Error(dtos.LogMessage{Message: "Error in converting course id"})
-			return
-		}
-		workflowDto := dtoreq.AddIntroductionVideoWorkflowReqDto{
-			CourseId: courseID,
-			ObjectId: objectId.(string),
-		}
-		workflowErr := tus.temporalSvc.ExecuteWorker(
-			ctx,
-			temporal.SET_INTRODUCTION_COURSE_QUEUE,
-			tus.videoWorkflowSvc.AddIntroductionVideoWorkflow,
-			workflowDto,
-		)
-		if workflowErr != nil {
-			tus.logSvc.Error(dtos.LogMessage{
-				Message: "Error happen in workflow of video workflow service",
-				Metadata: map[string]any{
-					"error": workflowErr,
-					"key":   objectId,
-				},
-			})
+func metadataValue(dto reqdto.TusWebhookDto, key string) string {
+	value, _ := dto.Event.Upload.MetaData[key].(string)
+	return strings.TrimSpace(value)
+}
+
+// PostCreateWebhook records the upload as soon as tusd assigned it an id, the creation request
+// is checked again since the row is all the later hooks trust
+func (tus TusServiceImpl) PostCreateWebhook(dto reqdto.TusWebhookDto) error {
+	const operationName = "TusServiceImpl.PostCreateWebhook"
+	if dto.Event.Upload.ID == "" {
+		return tusError.Tus_InvalidMetadata
+	}
+	upload, err := tus.unitOfWork.UploadRepo().GetOne(map[string]any{"upload_id": dto.Event.Upload.ID}, nil)
+	if err != nil {
+		return types.NewServerError("Error in fetching upload", operationName, err)
+	}
+	if upload != nil {
+		return nil
+	}
+	upload, err = tus.validateCreate(dto)
+	if err != nil {
+		return err
+	}
+	if objectKey, ok := dto.Event.Upload.Storage["Key"].(string); ok {
+		upload.ObjectKey = &objectKey
+	}
+	if err := tus.unitOfWork.UploadRepo().Create(upload); err != nil {
+		return types.NewServerError("Error in creating upload", operationName, err)
+	}
+	return nil
+}
+
+func (tus TusServiceImpl) PostReceiveWebhook(dto reqdto.TusWebhookDto) error {
+	_, err := tus.recordUpload(dto, entities.UploadStatus_Receiving)
+	return err
+}
+
+// VideoWebhook hands a finished upload to its processing workflow, tusd delivers hooks at least
+// once so a processed upload is skipped and the workflow id is derived from the upload
+func (tus TusServiceImpl) VideoWebhook(ctx context.Context, dto reqdto.TusWebhookDto) error {
+	const operationName = "TusServiceImpl.VideoWebhook"
+	upload, err := tus.recordUpload(dto, entities.UploadStatus_Finished)
+	if err != nil {
+		return err
+	}
+	if upload.Status == entities.UploadStatus_Processed {
+		return nil
+	}
+	var isStarted bool
+	switch reqdto.TusActionType(upload.ActionType) {
+	case reqdto.TusActionType_NewCourseVideo, reqdto.TusActionType_ReplaceCourseVideo:
+		// a replacement runs the same workflow, the video keeps its files until the new ones are ready
+		isStarted, err = tus.AddCourseVideoWebhook(ctx, upload)
+	case reqdto.TusActionType_AddIntroductionVideo:
+		isStarted, err = tus.AddIntroductionVideoWebhook(ctx, upload)
+	default:
+		return tusError.Tus_InvalidActionType
+	}
+	if err != nil {
+		return err
+	}
+	workflowID := workflow.ProcessUploadWorkflowID(upload.UploadID)
+	if !isStarted {
+		// the workflow was started by an earlier delivery that failed before the upload was updated
+		tus.logSvc.Warning(dtos.LogMessage{
+			Message:  "Duplicate finish hook of upload, processing workflow already started",
+			Metadata: map[string]any{"uploadId": upload.UploadID, "workflowId": workflowID},
+		})
+	}
+	now := time.Now()
+	if err := tus.unitOfWork.UploadRepo().UpdateFields(upload, map[string]any{
+		"status":       entities.UploadStatus_Processed,
+		"workflow_id":  workflowID,
+		"processed_at": &now,
+	}); err != nil {
+		return types.NewServerError("Error in marking upload as processed", operationName, err)
+	}
+	return nil
+}
+
+func (tus TusServiceImpl) AddCourseVideoWebhook(ctx context.Context, upload *entities.Upload) (bool, error) {
+	const operationName = "TusServiceImpl.AddCourseVideoWebhook"
+	if upload.ObjectKey == nil || upload.CourseID == nil || upload.VideoID == nil {
+		return false, tusError.Tus_InvalidMetadata
+	}
+	workflowDto := dtoreq.AddNewCourseVideoWorkflowReqDto{
+		CourseID: *upload.CourseID,
+		ObjectID: *upload.ObjectKey,
+		VideoID:  *upload.VideoID,
+	}
+	isStarted, err := tus.temporalSvc.ExecuteWorkerOnce(
+		ctx,
+		temporal.ADD_NEW_COURSE_VIDEO_QUEUE,
+		workflow.ProcessUploadWorkflowID(upload.UploadID),
+		tus.videoWorkflowSvc.AddNewCourseVideoWorkflow,
+		workflowDto,
+	)
+	if err != nil {
+		return false, types.NewServerError("Error in starting video processing workflow", operationName, err)
+	}
+	return isStarted, nil
+}
+
+func (tus TusServiceImpl) AddIntroductionVideoWebhook(ctx context.Context, upload *entities.Upload) (bool, error) {
+	const operationName = "TusServiceImpl.AddIntroductionVideoWebhook"
+	if upload.ObjectKey == nil || upload.CourseID == nil {
+		return false, tusError.Tus_InvalidMetadata
+	}
+	workflowDto := dtoreq.AddIntroductionVideoWorkflowReqDto{
+		CourseId: *upload.CourseID,
+		ObjectId: *upload.ObjectKey,
+	}
+	isStarted, err := tus.temporalSvc.ExecuteWorkerOnce(
+		ctx,
+		temporal.SET_INTRODUCTION_COURSE_QUEUE,
+		workflow.ProcessUploadWorkflowID(upload.UploadID),
+		tus.videoWorkflowSvc.AddIntroductionVideoWorkflow,
+		workflowDto,
+	)
+	if err != nil {
+		return false, types.NewServerError("Error in starting introduction video workflow", operationName, err)
+	}
+	return isStarted, nil
+}
+
+// recordUpload moves an upload recorded by its create hook forward, a hook arriving late or twice
+// never moves it back and an upload no create hook recorded is refused
+func (tus TusServiceImpl) recordUpload(dto reqdto.TusWebhookDto, status entities.UploadStatus) (*entities.Upload, error) {
+	const operationName = "TusServiceImpl.recordUpload"
+	event := dto.Event.Upload
+	if event.ID == "" {
+		return nil, tusError.Tus_InvalidMetadata
+	}
+	upload, err := tus.unitOfWork.UploadRepo().GetOne(map[string]any{"upload_id": event.ID}, nil)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching upload", operationName, err)
+	}
+	if upload == nil {
+		return nil, tusError.Tus_UploadNotFound
+	}
+	now := time.Now()
+	fields := map[string]any{}
+	if event.Offset > upload.Offset {
+		fields["offset"] = event.Offset
+	}
+	if objectKey, ok := event.Storage["Key"].(string); ok && upload.ObjectKey == nil {
+		fields["object_key"] = objectKey
+	}
+	if upload.Status.IsBefore(status) {
+		fields["status"] = status
+		if status == entities.UploadStatus_Finished {
+			fields["finished_at"] = &now
 		}
 	}
+	if len(fields) == 0 {
+		return upload, nil
+	}
+	if err := tus.unitOfWork.UploadRepo().UpdateFields(upload, fields); err != nil {
+		return nil, types.NewServerError("Error in updating upload", operationName, err)
+	}
+	return upload, nil
 }
@@ -5,4 +5,5 @@ type CreateBasicUserReqDto struct {
 	Password  string `json:"password" validate:"required,min=8"`
 	FirstName string `json:"firstName" validate:"required,min=3"`
 	LastName  string `json:"lastName" validate:"required,min=3"`
+	Email     string `json:"email,omitempty" validate:"omitempty,email"`
 }
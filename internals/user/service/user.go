@@ -13,6 +13,7 @@ import (
 type UserSvc interface {
 	CreateBasic(dto dtoreq.CreateBasicUserReqDto) (*entities.User, error)
 	GetLoggedInUser(ctx *gin.Context) (*entities.User, error)
+	GetOptionalLoggedInUser(ctx *gin.Context) (*entities.User, error)
 }
 
 type userService struct {
@@ -42,6 +43,9 @@ func (svc userService) CreateBasic(dto dtoreq.CreateBasicUserReqDto) (*entities.
 		FirstName: dto.FirstName,
 		LastName:  dto.LastName,
 	}
+	if dto.Email != "" {
+		user.Email = &dto.Email
+	}
 	if err := svc.unitOfWork.UserRepo().Create(user); err != nil {
 		return nil, types.NewServerError("Create Basic User Throw Error", operationName, err)
 	}
@@ -62,3 +66,11 @@ func (svc userService) GetLoggedInUser(ctx *gin.Context) (*entities.User, error)
 	}
 	return user, nil
 }
+
+// GetOptionalLoggedInUser is used behind the optional access token, anonymous requests return no user
+func (svc userService) GetOptionalLoggedInUser(ctx *gin.Context) (*entities.User, error) {
+	if _, isAuthenticated := ctx.Get("AUTH"); !isAuthenticated {
+		return nil, nil
+	}
+	return svc.GetLoggedInUser(ctx)
+}
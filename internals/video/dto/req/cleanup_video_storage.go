@@ -0,0 +1,11 @@
+package dtoreq
+
+type CleanupVideoStorageReqDto struct {
+	Prefixes        []string
+	CaptionURLs     []string
+	SourceObjectIDs []string
+}
+
+type SweepOrphanObjectsReqDto struct {
+	GracePeriodHours int
+}
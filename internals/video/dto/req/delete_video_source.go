@@ -0,0 +1,5 @@
+package dtoreq
+
+type DeleteVideoSourceReqDto struct {
+	ObjectId string
+}
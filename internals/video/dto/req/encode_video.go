@@ -2,4 +2,8 @@ package dtoreq
 
 type EncodeVideoReqDto struct {
 	ObjectId string
+	// Encrypt protects course videos with aes-128, public ones like introductions skip it
+	Encrypt bool
+	// VideoID applies the watermark setting of the video course, zero for videos outside the course content
+	VideoID uint
 }
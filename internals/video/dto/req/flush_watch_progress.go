@@ -0,0 +1,5 @@
+package dtoreq
+
+type FlushWatchProgressReqDto struct {
+	BatchSize int
+}
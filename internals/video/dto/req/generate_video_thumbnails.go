@@ -0,0 +1,7 @@
+package dtoreq
+
+type GenerateVideoThumbnailsReqDto struct {
+	ObjectId string
+	VideoID  uint
+	URL      string
+}
@@ -0,0 +1,6 @@
+package dtoreq
+
+type MarkVideoFailedReqDto struct {
+	VideoID uint
+	Reason  string
+}
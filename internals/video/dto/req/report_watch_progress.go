@@ -0,0 +1,18 @@
+package dtoreq
+
+type WatchedRangeReqDto struct {
+	Start float64 `json:"start" validate:"gte=0"`
+	End   float64 `json:"end" validate:"gtefield=Start"`
+}
+
+type WatchProgressEventReqDto struct {
+	VideoID       uint                 `json:"videoId" validate:"required,gte=1"`
+	Position      float64              `json:"position" validate:"gte=0"`
+	WatchedRanges []WatchedRangeReqDto `json:"watchedRanges" validate:"max=100,dive"`
+}
+
+// ReportWatchProgressReqDto is sent by players every few seconds, events of several videos are
+// batched so a player coming back online sends what it collected in one request
+type ReportWatchProgressReqDto struct {
+	Events []WatchProgressEventReqDto `json:"events" validate:"required,min=1,max=50,dive"`
+}
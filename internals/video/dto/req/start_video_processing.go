@@ -0,0 +1,7 @@
+package dtoreq
+
+type StartVideoProcessingReqDto struct {
+	VideoID    uint
+	ObjectID   string
+	WorkflowID string
+}
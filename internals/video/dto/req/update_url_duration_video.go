@@ -1,7 +1,11 @@
 package dtoreq
 
+import "github.com/ladmakhi81/learnup/shared/db/entities"
+
 type UpdateURLAndDurationVideoReqDto struct {
-	URL      string
-	Duration string
-	ID       uint
+	URL           string
+	Duration      string
+	ThumbnailURL  string
+	WatermarkMode entities.CourseWatermarkMode
+	ID            uint
 }
@@ -0,0 +1,5 @@
+package dtoreq
+
+type UpdateVideoThumbnailWorkflowReqDto struct {
+	VideoID uint
+}
@@ -0,0 +1,67 @@
+package dtores
+
+import (
+	"fmt"
+	videoService "github.com/ladmakhi81/learnup/internals/video/service"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"time"
+)
+
+type GetPlaybackCaptionDto struct {
+	Language    string `json:"language"`
+	Label       string `json:"label"`
+	PlaylistURL string `json:"playlistUrl"`
+}
+
+type GetPlaybackMarkerDto struct {
+	NoteID    uint                   `json:"noteId"`
+	Timestamp float64                `json:"timestamp"`
+	Type      entities.VideoNoteType `json:"type"`
+	Content   string                 `json:"content"`
+}
+
+type GetPlaybackResDto struct {
+	VideoID     uint                    `json:"videoId"`
+	Title       string                  `json:"title"`
+	Duration    *string                 `json:"duration"`
+	PlaylistURL string                  `json:"playlistUrl"`
+	PosterURL   *string                 `json:"posterUrl"`
+	SpriteURL   *string                 `json:"spriteUrl"`
+	Captions    []GetPlaybackCaptionDto `json:"captions"`
+	Markers     []GetPlaybackMarkerDto  `json:"markers"`
+	ExpiresAt   time.Time               `json:"expiresAt"`
+}
+
+func NewGetPlaybackResDto(playback *videoService.Playback) GetPlaybackResDto {
+	baseURL := fmt.Sprintf("/api/playback/%s", playback.Token)
+	res := GetPlaybackResDto{
+		VideoID:     playback.Video.ID,
+		Title:       playback.Video.Title,
+		Duration:    playback.Video.Duration,
+		PlaylistURL: baseURL + "/master.m3u8",
+		Captions:    make([]GetPlaybackCaptionDto, len(playback.Captions)),
+		Markers:     make([]GetPlaybackMarkerDto, len(playback.Notes)),
+		ExpiresAt:   playback.ExpiresAt,
+	}
+	for index, caption := range playback.Captions {
+		res.Captions[index] = GetPlaybackCaptionDto{
+			Language:    caption.Language,
+			Label:       caption.Label,
+			PlaylistURL: fmt.Sprintf("%s/captions/%s.m3u8", baseURL, caption.Language),
+		}
+	}
+	for index, note := range playback.Notes {
+		res.Markers[index] = GetPlaybackMarkerDto{
+			NoteID:    note.ID,
+			Timestamp: note.Timestamp,
+			Type:      note.Type,
+			Content:   note.Content,
+		}
+	}
+	if playback.HasThumbnail {
+		spriteURL := baseURL + "/thumbnails/sprite.vtt"
+		res.PosterURL = &playback.PosterURL
+		res.SpriteURL = &spriteURL
+	}
+	return res
+}
@@ -0,0 +1,10 @@
+package dtores
+
+type ReportWatchProgressResDto struct {
+	// events kept for flushing, events of videos the user isn't enrolled in are dropped
+	AcceptedCount int `json:"acceptedCount"`
+}
+
+func NewReportWatchProgressResDto(acceptedCount int) ReportWatchProgressResDto {
+	return ReportWatchProgressResDto{AcceptedCount: acceptedCount}
+}
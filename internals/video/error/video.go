@@ -5,6 +5,17 @@ import (
 )
 
 var (
-	Video_NotFound        = types.NewNotFoundError("video.errors.not_found")
-	Video_TitleDuplicated = types.NewConflictError("video.errors.title_duplicated")
+	Video_NotFound             = types.NewNotFoundError("video.errors.not_found")
+	Video_TitleDuplicated      = types.NewConflictError("video.errors.title_duplicated")
+	Video_NotReady             = types.NewConflictError("video.errors.not_ready")
+	Video_InvalidThumbnailAt   = types.NewBadRequestError("video.errors.invalid_thumbnail_at")
+	Video_AccessDenied         = types.NewForbiddenAccessError("video.errors.access_denied")
+	Video_InvalidPlaybackToken = types.NewForbiddenAccessError("video.errors.invalid_playback_token")
+	Video_PlaybackFileNotFound = types.NewNotFoundError("video.errors.playback_file_not_found")
+	Video_KeyRateLimited       = types.NewTooManyRequestsError("video.errors.key_rate_limited")
+	Video_InvalidCaption       = types.NewBadRequestError("video.errors.invalid_caption")
+	Video_CaptionNotFound      = types.NewNotFoundError("video.errors.caption_not_found")
+	Video_AlreadyProcessed     = types.NewConflictError("video.errors.already_processed")
+	Video_SourceNotFound       = types.NewConflictError("video.errors.source_not_found")
+	Video_Processing           = types.NewConflictError("video.errors.processing")
 )
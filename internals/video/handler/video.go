@@ -3,6 +3,8 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 	userService "github.com/ladmakhi81/learnup/internals/user/service"
+	dtoreq "github.com/ladmakhi81/learnup/internals/video/dto/req"
+	dtores "github.com/ladmakhi81/learnup/internals/video/dto/res"
 	videoService "github.com/ladmakhi81/learnup/internals/video/service"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
 	"github.com/ladmakhi81/learnup/shared/types"
@@ -15,6 +17,8 @@ type Handler struct {
 	videoSvc       videoService.VideoService
 	translationSvc contracts.Translator
 	userSvc        userService.UserSvc
+	playbackSvc    videoService.PlaybackService
+	progressSvc    videoService.WatchProgressService
 }
 
 func NewHandler(
@@ -22,12 +26,16 @@ func NewHandler(
 	videoSvc videoService.VideoService,
 	translationSvc contracts.Translator,
 	userSvc userService.UserSvc,
+	playbackSvc videoService.PlaybackService,
+	progressSvc videoService.WatchProgressService,
 ) *Handler {
 	return &Handler{
 		validationSvc:  validationSvc,
 		videoSvc:       videoSvc,
 		translationSvc: translationSvc,
 		userSvc:        userSvc,
+		playbackSvc:    playbackSvc,
+		progressSvc:    progressSvc,
 	}
 }
 
@@ -61,3 +69,118 @@ func (h Handler) VerifyVideo(ctx *gin.Context) (*types.ApiResponse, error) {
 	}
 	return types.NewApiResponse(http.StatusOK, nil), nil
 }
+
+// GetPlayback godoc
+//
+//	@Summary	Get a short lived playback url of a video
+//	@Tags		videos
+//	@Produce	json
+//	@Param		video-id	path		int	true	"Video ID"
+//	@Success	200			{object}	types.ApiResponse{data=dtores.GetPlaybackResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	401			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/videos/{video-id}/playback [get]
+//	@Security	BearerAuth
+func (h Handler) GetPlayback(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	playback, err := h.playbackSvc.CreatePlayback(ctx, user, videoID, ctx.ClientIP())
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetPlaybackResDto(playback)), nil
+}
+
+// GetPreviewPlayback godoc
+//
+//	@Summary	Get a short lived playback url of a free preview lesson, the access token is optional
+//	@Tags		videos
+//	@Produce	json
+//	@Param		video-id	path		int	true	"Video ID"
+//	@Success	200			{object}	types.ApiResponse{data=dtores.GetPlaybackResDto}
+//	@Failure	400			{object}	types.ApiError
+//	@Failure	403			{object}	types.ApiError
+//	@Failure	404			{object}	types.ApiError
+//	@Failure	409			{object}	types.ApiError
+//	@Failure	500			{object}	types.ApiError
+//	@Router		/catalog/videos/{video-id}/playback [get]
+func (h Handler) GetPreviewPlayback(ctx *gin.Context) (*types.ApiResponse, error) {
+	videoID, err := utils.ToUint(ctx.Param("video-id"))
+	if err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("video.errors.invalid_id"),
+		)
+	}
+	user, err := h.userSvc.GetOptionalLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	playback, err := h.playbackSvc.CreatePlayback(ctx, user, videoID, ctx.ClientIP())
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewGetPlaybackResDto(playback)), nil
+}
+
+// ReportProgress godoc
+//
+//	@Summary	Report watch progress of videos, players call it every few seconds
+//	@Tags		videos
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		dtoreq.ReportWatchProgressReqDto	true	" "
+//	@Success	200		{object}	types.ApiResponse{data=dtores.ReportWatchProgressResDto}
+//	@Failure	400		{object}	types.ApiError
+//	@Failure	401		{object}	types.ApiError
+//	@Failure	500		{object}	types.ApiError
+//	@Router		/videos/progress [post]
+//	@Security	BearerAuth
+func (h Handler) ReportProgress(ctx *gin.Context) (*types.ApiResponse, error) {
+	dto := &dtoreq.ReportWatchProgressReqDto{}
+	if err := ctx.Bind(dto); err != nil {
+		return nil, types.NewBadRequestError(
+			h.translationSvc.Translate("common.errors.invalid_request_body"),
+		)
+	}
+	if err := h.validationSvc.Validate(dto); err != nil {
+		return nil, err
+	}
+	user, err := h.userSvc.GetLoggedInUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acceptedCount, err := h.progressSvc.Report(user, *dto)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewApiResponse(http.StatusOK, dtores.NewReportWatchProgressResDto(acceptedCount)), nil
+}
+
+// ServePlaybackFile godoc
+//
+//	@Summary	Serve playlists and files of a playback token
+//	@Tags		videos
+//	@Produce	application/vnd.apple.mpegurl
+//	@Param		token		path	string	true	"Playback token"
+//	@Param		file-path	path	string	true	"File path inside the video"
+//	@Success	200
+//	@Success	302
+//	@Failure	403	{object}	types.ApiError
+//	@Failure	404	{object}	types.ApiError
+//	@Failure	500	{object}	types.ApiError
+//	@Router		/playback/{token}/{file-path} [get]
+func (h Handler) ServePlaybackFile(ctx *gin.Context) (*types.FileResponse, error) {
+	return h.playbackSvc.ServeFile(ctx, ctx.Param("token"), ctx.Param("file-path"))
+}
@@ -19,6 +19,8 @@ type Module struct {
 func NewModule(
 	userSvc userService.UserSvc,
 	videoSvc videoService.VideoService,
+	playbackSvc videoService.PlaybackService,
+	progressSvc videoService.WatchProgressService,
 	validationSvc contracts.Validation,
 	middleware *middleware.Middleware,
 	translationSvc contracts.Translator,
@@ -29,6 +31,8 @@ func NewModule(
 			videoSvc,
 			translationSvc,
 			userSvc,
+			playbackSvc,
+			progressSvc,
 		),
 		middleware:     middleware,
 		translationSvc: translationSvc,
@@ -39,4 +43,13 @@ func (m Module) Register(api *gin.RouterGroup) {
 	videosApi := api.Group("/videos")
 	videosApi.Use(m.middleware.CheckAccessToken())
 	videosApi.PATCH("/:video-id/verify", utils.JsonHandler(m.translationSvc, m.videoHandler.VerifyVideo))
+	videosApi.GET("/:video-id/playback", utils.JsonHandler(m.translationSvc, m.videoHandler.GetPlayback))
+	videosApi.POST("/progress", utils.JsonHandler(m.translationSvc, m.videoHandler.ReportProgress))
+
+	catalogApi := api.Group("/catalog")
+	catalogApi.Use(m.middleware.OptionalAccessToken())
+	catalogApi.GET("/videos/:video-id/playback", utils.JsonHandler(m.translationSvc, m.videoHandler.GetPreviewPlayback))
+
+	// players can't attach the access token, the signed token in the path authorizes these requests
+	api.GET("/playback/:token/*file-path", utils.FileHandler(m.translationSvc, m.videoHandler.ServePlaybackFile))
 }
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	dtoreq "github.com/ladmakhi81/learnup/internals/video/dto/req"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	videosBucket                  = "videos"
+	defaultOrphanGracePeriodHours = 48
+)
+
+// CleanupStorage removes the files a deleted or replaced video left behind, prefixes and
+// captions can be shared with clones and published versions so they are only removed
+// once nothing references them anymore
+func (svc videoService) CleanupStorage(ctx context.Context, dto dtoreq.CleanupVideoStorageReqDto) error {
+	const operationName = "videoService.CleanupStorage"
+	for _, prefix := range dto.Prefixes {
+		if prefix == "" {
+			continue
+		}
+		if _, err := svc.deletePrefix(ctx, prefix); err != nil {
+			return types.NewServerError("Error in deleting encoded files of video", operationName, err)
+		}
+	}
+	for _, captionURL := range dto.CaptionURLs {
+		if _, err := svc.deleteCaption(ctx, captionURL); err != nil {
+			return types.NewServerError("Error in deleting caption of video", operationName, err)
+		}
+	}
+	for _, objectID := range dto.SourceObjectIDs {
+		if _, err := svc.deleteSourceObject(ctx, objectID); err != nil {
+			return types.NewServerError("Error in deleting source of video", operationName, err)
+		}
+	}
+	return nil
+}
+
+// SweepOrphans removes objects nothing points at, like abandoned uploads or files of a cleanup
+// that never ran, everything younger than the grace period is kept since it can belong to an
+// upload or a processing run that has not saved its result yet
+func (svc videoService) SweepOrphans(ctx context.Context, dto dtoreq.SweepOrphanObjectsReqDto) (int, error) {
+	const operationName = "videoService.SweepOrphans"
+	gracePeriod := dto.GracePeriodHours
+	if gracePeriod <= 0 {
+		gracePeriod = defaultOrphanGracePeriodHours
+	}
+	cutoff := time.Now().Add(-time.Duration(gracePeriod) * time.Hour)
+	objects, err := svc.minioClient.ListObjects(ctx, videosBucket, "", false)
+	if err != nil {
+		return 0, types.NewServerError("Error in listing video objects", operationName, err)
+	}
+	deleted := 0
+	sweptUploads := make(map[string]bool)
+	for _, object := range objects {
+		name := strings.TrimSuffix(object.Key, "/")
+		var isDeleted bool
+		switch {
+		case object.IsPrefix && name == playbackCaptionsDir:
+			count, err := svc.sweepCaptions(ctx, cutoff)
+			if err != nil {
+				return deleted, types.NewServerError("Error in sweeping orphan captions", operationName, err)
+			}
+			deleted += count
+			continue
+		case object.IsPrefix:
+			isDeleted, err = svc.sweepPrefix(ctx, name, cutoff)
+		default:
+			// tus keeps the upload metadata next to it, both go away together
+			name = strings.TrimSuffix(name, ".info")
+			if object.LastModified.After(cutoff) || sweptUploads[name] {
+				continue
+			}
+			sweptUploads[name] = true
+			isDeleted, err = svc.deleteSourceObject(ctx, name)
+		}
+		if err != nil {
+			return deleted, types.NewServerError(fmt.Sprintf("Error in sweeping %s", name), operationName, err)
+		}
+		if isDeleted {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (svc videoService) sweepPrefix(ctx context.Context, prefix string, cutoff time.Time) (bool, error) {
+	objects, err := svc.minioClient.ListObjects(ctx, videosBucket, prefix+"/", true)
+	if err != nil {
+		return false, err
+	}
+	for _, object := range objects {
+		if object.LastModified.After(cutoff) {
+			return false, nil
+		}
+	}
+	return svc.deletePrefix(ctx, prefix)
+}
+
+func (svc videoService) sweepCaptions(ctx context.Context, cutoff time.Time) (int, error) {
+	objects, err := svc.minioClient.ListObjects(ctx, videosBucket, playbackCaptionsDir+"/", true)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, object := range objects {
+		if object.LastModified.After(cutoff) {
+			continue
+		}
+		isDeleted, err := svc.deleteCaption(ctx, object.Key)
+		if err != nil {
+			return deleted, err
+		}
+		if isDeleted {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (svc videoService) deletePrefix(ctx context.Context, prefix string) (bool, error) {
+	isReferenced, err := svc.unitOfWork.VideoRepo().IsPrefixReferenced(prefix)
+	if err != nil {
+		return false, err
+	}
+	if isReferenced {
+		return false, nil
+	}
+	if err := svc.minioClient.DeleteObjectsByPrefix(ctx, videosBucket, prefix+"/"); err != nil {
+		return false, err
+	}
+	videoKey, err := svc.unitOfWork.VideoKeyRepo().GetOne(map[string]any{"prefix": prefix}, nil)
+	if err != nil {
+		return false, err
+	}
+	if videoKey != nil {
+		if err := svc.unitOfWork.VideoKeyRepo().Delete(videoKey); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (svc videoService) deleteCaption(ctx context.Context, captionURL string) (bool, error) {
+	isReferenced, err := svc.unitOfWork.VideoCaptionRepo().Exist(map[string]any{"url": captionURL})
+	if err != nil {
+		return false, err
+	}
+	if isReferenced {
+		return false, nil
+	}
+	if err := svc.minioClient.DeleteObject(ctx, videosBucket, captionURL); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteSourceObject keeps uploads a video still waits on, either a running processing or a
+// failed one the teacher can reprocess
+func (svc videoService) deleteSourceObject(ctx context.Context, objectID string) (bool, error) {
+	objectID = path.Clean(objectID)
+	isReferenced, err := svc.unitOfWork.VideoRepo().Exist(map[string]any{"source_object_id": objectID})
+	if err != nil {
+		return false, err
+	}
+	if isReferenced {
+		return false, nil
+	}
+	if err := svc.minioClient.DeleteObject(ctx, videosBucket, objectID); err != nil {
+		return false, err
+	}
+	if err := svc.minioClient.DeleteObject(ctx, videosBucket, objectID+".info"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
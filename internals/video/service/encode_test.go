@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	dtoreq "github.com/ladmakhi81/learnup/internals/video/dto/req"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/pkg/transcoder"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStorage keeps what the encode uploads, methods the encode never calls are left to the
+// embedded nil interface and panic if reached
+type memoryStorage struct {
+	contracts.Storage
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (storage *memoryStorage) GetPresignedURL(ctx context.Context, bucketName string, objectPath string, expires time.Duration) (string, error) {
+	return "http://storage.test/" + bucketName + "/" + objectPath, nil
+}
+
+func (storage *memoryStorage) PutObject(ctx context.Context, bucketName string, objectPath string, contentType string, reader io.Reader, size int64) (*dtos.UploadResult, error) {
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return nil, err
+	}
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+	storage.objects[objectPath] = contentType
+	return &dtos.UploadResult{}, nil
+}
+
+type silentTemporal struct {
+	contracts.Temporal
+}
+
+func (silentTemporal) RecordHeartbeat(ctx context.Context, details ...any) {}
+
+func TestEncodeWithFakeTranscoder(t *testing.T) {
+	tests := []struct {
+		name            string
+		container       string
+		renditions      string
+		segmentLength   int
+		expectedObjects []string
+	}{
+		{
+			name:          "hls ladder",
+			container:     "hls",
+			renditions:    "360p,720p",
+			segmentLength: 10,
+			expectedObjects: []string{
+				"master.m3u8",
+				"360p/playlist.m3u8",
+				"360p/segment0.ts",
+				"360p/segment2.ts",
+				"720p/playlist.m3u8",
+				"720p/segment2.ts",
+			},
+		},
+		{
+			name:          "cmaf keeps init segments",
+			container:     "cmaf",
+			renditions:    "480p",
+			segmentLength: 6,
+			expectedObjects: []string{
+				"master.m3u8",
+				"480p/init.mp4",
+				"480p/playlist.m3u8",
+				"480p/segment0.m4s",
+				"480p/segment4.m4s",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scratchDir := t.TempDir()
+			config := &dtos.EnvConfig{
+				Video: dtos.VideoEnvConfig{Renditions: test.renditions, ScratchDir: scratchDir},
+				Transcoder: dtos.TranscoderEnvConfig{
+					Backend:       transcoder.Backend_Fake,
+					Container:     test.container,
+					SegmentLength: test.segmentLength,
+				},
+			}
+			transcoderSvc, err := transcoder.NewTranscoderSvc(config)
+			if err != nil {
+				t.Fatalf("building fake transcoder: %v", err)
+			}
+			storage := &memoryStorage{objects: make(map[string]string)}
+			svc := videoService{
+				minioClient:   storage,
+				transcoderSvc: transcoderSvc,
+				temporalSvc:   silentTemporal{},
+				config:        config,
+			}
+			result, err := svc.Encode(context.Background(), dtoreq.EncodeVideoReqDto{ObjectId: "source.mp4"})
+			if err != nil {
+				t.Fatalf("encode failed: %v", err)
+			}
+			if result.URL == "" || result.WatermarkMode != entities.CourseWatermarkMode_None {
+				t.Fatalf("unexpected result %+v", result)
+			}
+			for _, object := range test.expectedObjects {
+				objectPath := path.Join(result.URL, object)
+				contentType, isUploaded := storage.objects[objectPath]
+				if !isUploaded {
+					t.Errorf("%s was not uploaded", objectPath)
+					continue
+				}
+				if contentType != encodedContentTypes[path.Ext(object)] {
+					t.Errorf("%s uploaded as %s", objectPath, contentType)
+				}
+			}
+			for objectPath := range storage.objects {
+				if !strings.HasPrefix(objectPath, result.URL+"/") {
+					t.Errorf("%s is outside of the encoded tree", objectPath)
+				}
+				if strings.Contains(objectPath, "source") {
+					t.Errorf("source was copied into the tree as %s", objectPath)
+				}
+			}
+			leftovers, err := os.ReadDir(scratchDir)
+			if err != nil {
+				t.Fatalf("reading scratch dir: %v", err)
+			}
+			if len(leftovers) != 0 {
+				names := make([]string, len(leftovers))
+				for index, entry := range leftovers {
+					names[index] = entry.Name()
+				}
+				t.Errorf("scratch dir not cleaned up: %v", names)
+			}
+		})
+	}
+}
@@ -0,0 +1,578 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	videoError "github.com/ladmakhi81/learnup/internals/video/error"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"gorm.io/gorm"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// playbackKeyPath and playbackCaptionsDir never clash with objects, every encoded file
+// lives in a rendition or thumbnails directory
+const (
+	playbackKeyPath     = "/key"
+	playbackCaptionsDir = "captions"
+	subtitleGroupID     = "subs"
+)
+
+var keyURIPattern = regexp.MustCompile(`URI="[^"]*"`)
+
+const (
+	defaultPlaybackTTL  = time.Hour
+	defaultKeyRateLimit = 20
+	keyRateLimitWindow  = time.Minute
+	// presigned urls can't live longer than a week in minio
+	maxPresignedTTL = 7 * 24 * time.Hour
+	// a viewer replaying a preview within the window is counted once
+	previewViewWindow = 30 * time.Minute
+)
+
+type Playback struct {
+	Token        string
+	Video        *entities.Video
+	ExpiresAt    time.Time
+	PosterURL    string
+	HasThumbnail bool
+	Captions     []*entities.VideoCaption
+	// notes and bookmarks of the user, players show them as markers on the timeline
+	Notes []*entities.VideoNote
+}
+
+type PlaybackService interface {
+	// CreatePlayback accepts a nil user for anonymous viewers of free previews, clientIP tells
+	// those viewers apart when preview views are counted
+	CreatePlayback(ctx context.Context, user *entities.User, videoID uint, clientIP string) (*Playback, error)
+	ServeFile(ctx context.Context, token string, filePath string) (*types.FileResponse, error)
+}
+
+type playbackService struct {
+	unitOfWork  db.UnitOfWork
+	minioClient contracts.Storage
+	cacheSvc    contracts.Cache
+	config      *dtos.EnvConfig
+}
+
+func NewPlaybackSvc(
+	unitOfWork db.UnitOfWork,
+	minioClient contracts.Storage,
+	cacheSvc contracts.Cache,
+	config *dtos.EnvConfig,
+) PlaybackService {
+	return &playbackService{
+		unitOfWork:  unitOfWork,
+		minioClient: minioClient,
+		cacheSvc:    cacheSvc,
+		config:      config,
+	}
+}
+
+// playbackClaim is carried in the url path, so playlists and their relative
+// references resolve without any authorization header
+type playbackClaim struct {
+	SessionID string `json:"s"`
+	VideoID   uint   `json:"v"`
+	UserID    uint   `json:"u"`
+	Prefix    string `json:"p"`
+	Duration  int    `json:"d"`
+	ExpiresAt int64  `json:"e"`
+	Forensic  bool   `json:"f,omitempty"`
+}
+
+func (svc playbackService) CreatePlayback(ctx context.Context, user *entities.User, videoID uint, clientIP string) (*Playback, error) {
+	const operationName = "playbackService.CreatePlayback"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(videoID, []string{"Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil || video.Course == nil {
+		return nil, videoError.Video_NotFound
+	}
+	playableVideo, isPreview, err := svc.resolveVideo(user, video)
+	if err != nil {
+		return nil, err
+	}
+	if playableVideo.Status != entities.VideoStatus_Done || playableVideo.URL == "" {
+		return nil, videoError.Video_NotReady
+	}
+	ttl := svc.playbackTTL()
+	claim := playbackClaim{
+		SessionID: uuid.NewString(),
+		VideoID:   playableVideo.ID,
+		Forensic:  playableVideo.WatermarkMode == entities.CourseWatermarkMode_Forensic,
+		Prefix:    playableVideo.URL,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+	if user != nil {
+		claim.UserID = user.ID
+	}
+	if playableVideo.Duration != nil {
+		duration, err := utils.ClockToSeconds(*playableVideo.Duration)
+		if err == nil {
+			claim.Duration = int(duration)
+		}
+	}
+	token, err := svc.signClaim(claim)
+	if err != nil {
+		return nil, types.NewServerError("Error in signing playback token", operationName, err)
+	}
+	captions, err := svc.getCaptions(playableVideo.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching captions of video", operationName, err)
+	}
+	var notes []*entities.VideoNote
+	if user != nil {
+		notes, err = svc.unitOfWork.VideoNoteRepo().GetAllByVideoID(user.ID, playableVideo.ID)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching notes of video", operationName, err)
+		}
+	}
+	if isPreview {
+		if err := svc.recordPreviewView(user, clientIP, video.Course.ID, playableVideo.ID, claim.SessionID); err != nil {
+			return nil, err
+		}
+	}
+	playback := &Playback{
+		Token:        token,
+		Video:        playableVideo,
+		ExpiresAt:    time.Unix(claim.ExpiresAt, 0),
+		HasThumbnail: playableVideo.ThumbnailURL != nil,
+		Captions:     captions,
+		Notes:        notes,
+	}
+	if playableVideo.ThumbnailURL != nil {
+		posterURL, err := svc.minioClient.GetPresignedURL(ctx, "videos", *playableVideo.ThumbnailURL, ttl)
+		if err != nil {
+			return nil, types.NewServerError("Error in presigning poster url", operationName, err)
+		}
+		playback.PosterURL = posterURL
+	}
+	return playback, nil
+}
+
+// ServeFile returns playlists and vtt files of the token video with every object reference
+// replaced by a presigned url, any other file is redirected to its presigned url
+func (svc playbackService) ServeFile(ctx context.Context, token string, filePath string) (*types.FileResponse, error) {
+	const operationName = "playbackService.ServeFile"
+	claim, err := svc.verifyClaim(token)
+	if err != nil {
+		return nil, videoError.Video_InvalidPlaybackToken
+	}
+	// segments are fetched while watching, so they outlive the token by the video duration
+	segmentTTL := time.Until(time.Unix(claim.ExpiresAt, 0)) + time.Duration(claim.Duration)*time.Second
+	segmentTTL = min(segmentTTL, maxPresignedTTL)
+	cleanPath := path.Clean("/" + filePath)
+	if cleanPath == playbackKeyPath {
+		return svc.serveKey(claim)
+	}
+	if path.Dir(cleanPath) == "/"+playbackCaptionsDir && path.Ext(cleanPath) == ".m3u8" {
+		return svc.serveCaptionPlaylist(ctx, claim, strings.TrimSuffix(path.Base(cleanPath), ".m3u8"), segmentTTL)
+	}
+	objectPath, ok := resolveObjectPath(claim.Prefix, filePath)
+	if !ok {
+		return nil, videoError.Video_PlaybackFileNotFound
+	}
+	switch filepath.Ext(objectPath) {
+	case ".m3u8":
+		content, err := svc.minioClient.GetFile(ctx, "videos", objectPath)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching playlist from storage", operationName, err)
+		}
+		if path.Dir(objectPath) == claim.Prefix {
+			captions, err := svc.getCaptions(claim.VideoID)
+			if err != nil {
+				return nil, types.NewServerError("Error in fetching captions of video", operationName, err)
+			}
+			content = addSubtitleGroup(content, captions)
+		}
+		content, err = svc.rewritePlaylist(ctx, token, claim, path.Dir(objectPath), content, segmentTTL)
+		if err != nil {
+			return nil, types.NewServerError("Error in rewriting playlist", operationName, err)
+		}
+		return types.NewFileResponse("application/vnd.apple.mpegurl", content), nil
+	case ".vtt":
+		content, err := svc.minioClient.GetFile(ctx, "videos", objectPath)
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching vtt from storage", operationName, err)
+		}
+		content, err = svc.rewriteVtt(ctx, path.Dir(objectPath), content, segmentTTL)
+		if err != nil {
+			return nil, types.NewServerError("Error in rewriting vtt", operationName, err)
+		}
+		return types.NewFileResponse("text/vtt", content), nil
+	default:
+		presignedURL, err := svc.minioClient.GetPresignedURL(ctx, "videos", objectPath, segmentTTL)
+		if err != nil {
+			return nil, types.NewServerError("Error in presigning file url", operationName, err)
+		}
+		return types.NewRedirectFileResponse(presignedURL), nil
+	}
+}
+
+// recordPreviewView counts a preview once per viewer in the window, logged in viewers are told
+// apart by their user and anonymous ones by their ip, every playback request opens a new session
+func (svc playbackService) recordPreviewView(user *entities.User, clientIP string, courseID, videoID uint, sessionID string) error {
+	const operationName = "playbackService.recordPreviewView"
+	viewer := "ip:" + clientIP
+	if user != nil {
+		viewer = fmt.Sprintf("user:%d", user.ID)
+	}
+	views, err := svc.cacheSvc.Increment(fmt.Sprintf("preview-view:%d:%s", videoID, viewer), previewViewWindow)
+	if err != nil {
+		return types.NewServerError("Error in counting preview views", operationName, err)
+	}
+	if views > 1 {
+		return nil
+	}
+	previewView := &entities.VideoPreviewView{
+		VideoID:   videoID,
+		CourseID:  courseID,
+		SessionID: sessionID,
+	}
+	if user != nil {
+		previewView.UserID = &user.ID
+	}
+	if err := svc.unitOfWork.VideoPreviewViewRepo().Create(previewView); err != nil {
+		return types.NewServerError("Error in recording preview view", operationName, err)
+	}
+	return nil
+}
+
+// resolveVideo applies the access rules, the teacher watches anything of the course, others only
+// published and verified videos, private ones need an active enrollment and are served from its version.
+// Anonymous users have no user and only watch free previews of courses listed in the catalog,
+// isPreview reports that access was granted by the preview flag
+func (svc playbackService) resolveVideo(user *entities.User, video *entities.Video) (playableVideo *entities.Video, isPreview bool, err error) {
+	const operationName = "playbackService.resolveVideo"
+	if user != nil && video.Course.IsTeacher(user.ID) {
+		return video, false, nil
+	}
+	if !video.IsPublished || !video.IsVerified {
+		return nil, false, videoError.Video_NotFound
+	}
+	if user == nil {
+		if isPreviewable(video) {
+			return video, true, nil
+		}
+		return nil, false, videoError.Video_AccessDenied
+	}
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(video.Course.ID, user.ID)
+	if err != nil {
+		return nil, false, types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	isParticipant := participant != nil && participant.IsActive()
+	if !isParticipant {
+		if video.AccessLevel == entities.VideoAccessLevel_Public {
+			return video, false, nil
+		}
+		if isPreviewable(video) {
+			return video, true, nil
+		}
+		return nil, false, videoError.Video_AccessDenied
+	}
+	if participant.CourseVersion == 0 {
+		return video, false, nil
+	}
+	courseVersion, err := svc.unitOfWork.CourseVersionRepo().GetOne(
+		map[string]any{"course_id": video.Course.ID, "version": participant.CourseVersion},
+		nil,
+	)
+	if err != nil {
+		return nil, false, types.NewServerError("Error in fetching version of course", operationName, err)
+	}
+	if courseVersion == nil {
+		return nil, false, courseError.Course_VersionNotFound
+	}
+	for _, versionVideo := range courseVersion.GetVideos() {
+		if versionVideo.ID == video.ID {
+			return versionVideo, false, nil
+		}
+	}
+	return nil, false, videoError.Video_NotFound
+}
+
+func (svc playbackService) rewritePlaylist(
+	ctx context.Context,
+	token string,
+	claim *playbackClaim,
+	dir string,
+	content []byte,
+	ttl time.Duration,
+) ([]byte, error) {
+	var result bytes.Buffer
+	keyURI := fmt.Sprintf(`URI="/api/playback/%s%s"`, token, playbackKeyPath)
+	segmentIndex := 0
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#EXT-X-KEY:") {
+			result.WriteString(keyURIPattern.ReplaceAllLiteralString(line, keyURI) + "\n")
+			continue
+		}
+		// init segments of cmaf renditions are shared by both forensic variants
+		if strings.HasPrefix(line, "#EXT-X-MAP:") {
+			match := mapURIPattern.FindStringSubmatch(line)
+			if match == nil {
+				result.WriteString(line + "\n")
+				continue
+			}
+			presignedURL, err := svc.minioClient.GetPresignedURL(ctx, "videos", path.Join(dir, match[1]), ttl)
+			if err != nil {
+				return nil, err
+			}
+			result.WriteString(mapURIPattern.ReplaceAllLiteralString(line, fmt.Sprintf(`URI="%s"`, presignedURL)) + "\n")
+			continue
+		}
+		// nested playlists stay relative and come back through the playback route
+		if line == "" || strings.HasPrefix(line, "#") || filepath.Ext(line) == ".m3u8" {
+			result.WriteString(line + "\n")
+			continue
+		}
+		objectPath := path.Join(dir, line)
+		if claim.Forensic && forensicBit(claim.UserID, segmentIndex) == 1 {
+			relativePath := strings.TrimPrefix(objectPath, claim.Prefix+"/")
+			objectPath = path.Join(claim.Prefix, dtos.WatermarkVariantDir, relativePath)
+		}
+		segmentIndex++
+		presignedURL, err := svc.minioClient.GetPresignedURL(ctx, "videos", objectPath, ttl)
+		if err != nil {
+			return nil, err
+		}
+		result.WriteString(presignedURL + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+// rewriteVtt only touches image references of thumbnail sprites, subtitle cues are left alone
+func (svc playbackService) rewriteVtt(ctx context.Context, dir string, content []byte, ttl time.Duration) ([]byte, error) {
+	var result bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		reference, fragment, _ := strings.Cut(strings.TrimSpace(line), "#")
+		switch filepath.Ext(reference) {
+		case ".jpg", ".png":
+			presignedURL, err := svc.minioClient.GetPresignedURL(ctx, "videos", path.Join(dir, reference), ttl)
+			if err != nil {
+				return nil, err
+			}
+			if fragment != "" {
+				presignedURL += "#" + fragment
+			}
+			result.WriteString(presignedURL + "\n")
+		default:
+			result.WriteString(line + "\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+// serveCaptionPlaylist wraps the whole webvtt file of a language in a single segment playlist,
+// hls players only pick subtitles up from media playlists
+func (svc playbackService) serveCaptionPlaylist(
+	ctx context.Context,
+	claim *playbackClaim,
+	language string,
+	ttl time.Duration,
+) (*types.FileResponse, error) {
+	const operationName = "playbackService.serveCaptionPlaylist"
+	caption, err := svc.unitOfWork.VideoCaptionRepo().GetOne(
+		map[string]any{"video_id": claim.VideoID, "language": language},
+		nil,
+	)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching caption of video", operationName, err)
+	}
+	if caption == nil {
+		return nil, videoError.Video_PlaybackFileNotFound
+	}
+	presignedURL, err := svc.minioClient.GetPresignedURL(ctx, "videos", caption.URL, ttl)
+	if err != nil {
+		return nil, types.NewServerError("Error in presigning caption url", operationName, err)
+	}
+	duration := max(claim.Duration, 1)
+	var content bytes.Buffer
+	content.WriteString("#EXTM3U\n")
+	content.WriteString("#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&content, "#EXT-X-TARGETDURATION:%d\n", duration)
+	content.WriteString("#EXT-X-MEDIA-SEQUENCE:0\n")
+	content.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	fmt.Fprintf(&content, "#EXTINF:%d.000,\n", duration)
+	content.WriteString(presignedURL + "\n")
+	content.WriteString("#EXT-X-ENDLIST\n")
+	return types.NewFileResponse("application/vnd.apple.mpegurl", content.Bytes()), nil
+}
+
+func (svc playbackService) getCaptions(videoID uint) ([]*entities.VideoCaption, error) {
+	order := "language asc"
+	return svc.unitOfWork.VideoCaptionRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"video_id": videoID},
+		Order:      &order,
+	})
+}
+
+// serveKey releases the aes key of the token video, access is checked again since the
+// enrollment may have ended after the token was issued
+func (svc playbackService) serveKey(claim *playbackClaim) (*types.FileResponse, error) {
+	const operationName = "playbackService.serveKey"
+	rateLimitKey := fmt.Sprintf("playback-key:%s:%d", claim.SessionID, time.Now().Unix()/int64(keyRateLimitWindow.Seconds()))
+	requests, err := svc.cacheSvc.Increment(rateLimitKey, keyRateLimitWindow)
+	if err != nil {
+		return nil, types.NewServerError("Error in counting key requests", operationName, err)
+	}
+	if requests > int64(svc.keyRateLimit()) {
+		return nil, videoError.Video_KeyRateLimited
+	}
+	video, err := svc.unitOfWork.VideoRepo().GetByID(claim.VideoID, []string{"Course"})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil || video.Course == nil {
+		return nil, videoError.Video_NotFound
+	}
+	// anonymous preview tokens carry no user
+	var user *entities.User
+	if claim.UserID != 0 {
+		user = &entities.User{Model: gorm.Model{ID: claim.UserID}}
+	}
+	playableVideo, _, err := svc.resolveVideo(user, video)
+	if err != nil {
+		return nil, err
+	}
+	if playableVideo.URL != claim.Prefix {
+		return nil, videoError.Video_AccessDenied
+	}
+	key, err := loadVideoKey(svc.unitOfWork, svc.config.Video.KeyEncryptionSecret, claim.Prefix)
+	if err != nil {
+		return nil, types.NewServerError("Error in loading video key", operationName, err)
+	}
+	if key == nil {
+		return nil, videoError.Video_PlaybackFileNotFound
+	}
+	return types.NewFileResponse("application/octet-stream", key), nil
+}
+
+func (svc playbackService) keyRateLimit() int {
+	if svc.config.Video.KeyRateLimit <= 0 {
+		return defaultKeyRateLimit
+	}
+	return svc.config.Video.KeyRateLimit
+}
+
+func (svc playbackService) playbackTTL() time.Duration {
+	if svc.config.Video.PlaybackTTL <= 0 {
+		return defaultPlaybackTTL
+	}
+	return time.Duration(svc.config.Video.PlaybackTTL) * time.Second
+}
+
+func (svc playbackService) signClaim(claim playbackClaim) (string, error) {
+	payload, err := json.Marshal(claim)
+	if err != nil {
+		return "", err
+	}
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + svc.signature(encodedPayload), nil
+}
+
+func (svc playbackService) verifyClaim(token string) (*playbackClaim, error) {
+	encodedPayload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(svc.signature(encodedPayload))) {
+		return nil, fmt.Errorf("invalid playback token signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, err
+	}
+	claim := &playbackClaim{}
+	if err := json.Unmarshal(payload, claim); err != nil {
+		return nil, err
+	}
+	if time.Now().Unix() > claim.ExpiresAt {
+		return nil, fmt.Errorf("playback token expired")
+	}
+	return claim, nil
+}
+
+func (svc playbackService) signature(encodedPayload string) string {
+	mac := hmac.New(sha256.New, []byte("playback:"+svc.config.App.TokenSecretKey))
+	mac.Write([]byte(encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// isPreviewable only opens preview lessons of courses the catalog lists, a preview flag set on a
+// draft course or one the admin hasn't verified grants nothing
+func isPreviewable(video *entities.Video) bool {
+	return video.IsPreview && video.Course.IsPublished && video.Course.IsVerifiedByAdmin
+}
+
+// forensicBit picks the a (0) or b (1) variant of a segment, the id of the viewer is spelled
+// in binary every 32 segments so a leaked copy tells who it was played for
+func forensicBit(userID uint, segmentIndex int) int {
+	return int((uint32(userID) >> (segmentIndex % 32)) & 1)
+}
+
+// addSubtitleGroup declares the caption tracks in the master playlist and attaches them to every
+// variant, the stored master is shared by cloned videos so captions are only added while serving
+func addSubtitleGroup(content []byte, captions []*entities.VideoCaption) []byte {
+	if len(captions) == 0 {
+		return content
+	}
+	var result bytes.Buffer
+	isGroupWritten := false
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		if strings.HasPrefix(line, "#EXT-X-STREAM-INF:") {
+			if !isGroupWritten {
+				for _, caption := range captions {
+					fmt.Fprintf(
+						&result,
+						`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="%s",NAME="%s",LANGUAGE="%s",DEFAULT=NO,AUTOSELECT=YES,URI="%s/%s.m3u8"`+"\n",
+						subtitleGroupID,
+						strings.ReplaceAll(caption.Label, `"`, "'"),
+						caption.Language,
+						playbackCaptionsDir,
+						caption.Language,
+					)
+				}
+				isGroupWritten = true
+			}
+			line += fmt.Sprintf(`,SUBTITLES="%s"`, subtitleGroupID)
+		}
+		result.WriteString(line + "\n")
+	}
+	return result.Bytes()
+}
+
+// resolveObjectPath keeps requested files inside the video prefix
+func resolveObjectPath(prefix string, filePath string) (string, bool) {
+	cleanPath := path.Clean("/" + filePath)
+	if cleanPath == "/" {
+		return "", false
+	}
+	return path.Join(prefix, cleanPath), true
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"testing"
+)
+
+func TestResolveObjectPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		filePath   string
+		expected   string
+		isResolved bool
+	}{
+		{name: "master playlist", filePath: "master.m3u8", expected: "prefix/master.m3u8", isResolved: true},
+		{name: "segment of a rendition", filePath: "720p/segment3.ts", expected: "prefix/720p/segment3.ts", isResolved: true},
+		{name: "leading slash", filePath: "/360p/playlist.m3u8", expected: "prefix/360p/playlist.m3u8", isResolved: true},
+		{name: "parent directories stay inside the prefix", filePath: "../../other/master.m3u8", expected: "prefix/other/master.m3u8", isResolved: true},
+		{name: "dot segments are cleaned", filePath: "720p/./../480p//segment0.ts", expected: "prefix/480p/segment0.ts", isResolved: true},
+		{name: "empty path", filePath: ""},
+		{name: "root only", filePath: "/"},
+		{name: "climbing to the root", filePath: "720p/../.."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objectPath, isResolved := resolveObjectPath("prefix", test.filePath)
+			if isResolved != test.isResolved || objectPath != test.expected {
+				t.Errorf("resolveObjectPath(%q) = %q, %v, want %q, %v", test.filePath, objectPath, isResolved, test.expected, test.isResolved)
+			}
+		})
+	}
+}
+
+func TestForensicBit(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       uint
+		segmentIndex int
+		expected     int
+	}{
+		{name: "lowest bit set", userID: 0b101, segmentIndex: 0, expected: 1},
+		{name: "bit not set", userID: 0b101, segmentIndex: 1, expected: 0},
+		{name: "higher bit set", userID: 0b101, segmentIndex: 2, expected: 1},
+		{name: "past the id", userID: 0b101, segmentIndex: 3, expected: 0},
+		{name: "spelling repeats every 32 segments", userID: 0b101, segmentIndex: 34, expected: 1},
+		{name: "highest bit", userID: 1 << 31, segmentIndex: 31, expected: 1},
+		{name: "anonymous viewer always gets a", userID: 0, segmentIndex: 7, expected: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if bit := forensicBit(test.userID, test.segmentIndex); bit != test.expected {
+				t.Errorf("forensicBit(%b, %d) = %d, want %d", test.userID, test.segmentIndex, bit, test.expected)
+			}
+		})
+	}
+}
+
+// TestForensicBitSpellsUserID reads a leaked copy back, the variants of 32 segments in a row
+// are the bits of the viewer id
+func TestForensicBitSpellsUserID(t *testing.T) {
+	for _, userID := range []uint{1, 42, 123456, 1<<32 - 1} {
+		var decoded uint32
+		for segmentIndex := 64; segmentIndex < 96; segmentIndex++ {
+			decoded |= uint32(forensicBit(userID, segmentIndex)) << (segmentIndex % 32)
+		}
+		if uint(decoded) != userID {
+			t.Errorf("decoded %d, want %d", decoded, userID)
+		}
+	}
+}
+
+func TestAddSubtitleGroup(t *testing.T) {
+	master := "#EXTM3U\n#EXT-X-VERSION:6\n#EXT-X-STREAM-INF:BANDWIDTH=856000,RESOLUTION=640x360\n360p/playlist.m3u8\n#EXT-X-STREAM-INF:BANDWIDTH=2996000,RESOLUTION=1280x720\n720p/playlist.m3u8\n"
+	tests := []struct {
+		name     string
+		captions []*entities.VideoCaption
+		expected string
+	}{
+		{
+			name:     "no captions leaves the master untouched",
+			expected: master,
+		},
+		{
+			name: "captions are declared once and attached to every variant",
+			captions: []*entities.VideoCaption{
+				{Language: "en", Label: "English"},
+				{Language: "fa", Label: `Persian "fa"`},
+			},
+			expected: "#EXTM3U\n#EXT-X-VERSION:6\n" +
+				`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="English",LANGUAGE="en",DEFAULT=NO,AUTOSELECT=YES,URI="captions/en.m3u8"` + "\n" +
+				`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="Persian 'fa'",LANGUAGE="fa",DEFAULT=NO,AUTOSELECT=YES,URI="captions/fa.m3u8"` + "\n" +
+				`#EXT-X-STREAM-INF:BANDWIDTH=856000,RESOLUTION=640x360,SUBTITLES="subs"` + "\n360p/playlist.m3u8\n" +
+				`#EXT-X-STREAM-INF:BANDWIDTH=2996000,RESOLUTION=1280x720,SUBTITLES="subs"` + "\n720p/playlist.m3u8\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := addSubtitleGroup([]byte(master), test.captions)
+			if string(result) != test.expected {
+				t.Errorf("got\n%s\nwant\n%s", result, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval stays well below the heartbeat timeout of processing tasks in the workflow
+const heartbeatInterval = 10 * time.Second
+
+type ProcessingStep string
+
+const (
+	ProcessingStep_Queued     ProcessingStep = "queued"
+	ProcessingStep_Probing    ProcessingStep = "probing"
+	ProcessingStep_Encoding   ProcessingStep = "encoding"
+	ProcessingStep_Uploading  ProcessingStep = "uploading"
+	ProcessingStep_Thumbnails ProcessingStep = "thumbnails"
+	ProcessingStep_Done       ProcessingStep = "done"
+	ProcessingStep_Failed     ProcessingStep = "failed"
+)
+
+// activityProgress heartbeats the running activity with its latest step until stopped, a long
+// ffmpeg run would otherwise look like a lost worker and the heartbeat carries the progress to teachers
+type activityProgress struct {
+	temporalSvc contracts.Temporal
+	ctx         context.Context
+	mu          sync.Mutex
+	progress    dtos.TaskProgress
+	done        chan struct{}
+}
+
+func (svc videoService) trackProgress(ctx context.Context, step ProcessingStep) *activityProgress {
+	progress := &activityProgress{
+		temporalSvc: svc.temporalSvc,
+		ctx:         ctx,
+		progress:    dtos.TaskProgress{Step: string(step)},
+		done:        make(chan struct{}),
+	}
+	progress.heartbeat()
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-progress.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				progress.heartbeat()
+			}
+		}
+	}()
+	return progress
+}
+
+func (progress *activityProgress) Update(step ProcessingStep, percent int) {
+	progress.mu.Lock()
+	progress.progress = dtos.TaskProgress{Step: string(step), Percent: percent}
+	progress.mu.Unlock()
+	progress.heartbeat()
+}
+
+func (progress *activityProgress) Stop() {
+	close(progress.done)
+}
+
+func (progress *activityProgress) heartbeat() {
+	progress.mu.Lock()
+	details := progress.progress
+	progress.mu.Unlock()
+	progress.temporalSvc.RecordHeartbeat(progress.ctx, details)
+}
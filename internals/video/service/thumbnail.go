@@ -0,0 +1,274 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	dtoreq "github.com/ladmakhi81/learnup/internals/video/dto/req"
+	videoError "github.com/ladmakhi81/learnup/internals/video/error"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultThumbnailAt lets ffmpeg pick the poster frame when the teacher has not chosen one
+const defaultThumbnailAt = -1
+
+var (
+	bandwidthPattern = regexp.MustCompile(`(?:^|[:,])BANDWIDTH=(\d+)`)
+	mapURIPattern    = regexp.MustCompile(`URI="([^"]+)"`)
+)
+
+// GenerateThumbnails cuts the poster frame and the seek preview sprite from the uploaded source,
+// both are stored under the thumbnails directory of the encoded video
+func (svc videoService) GenerateThumbnails(ctx context.Context, dto dtoreq.GenerateVideoThumbnailsReqDto) (string, error) {
+	const operationName = "videoService.GenerateThumbnails"
+	defer svc.trackProgress(ctx, ProcessingStep_Thumbnails).Stop()
+	video, err := svc.unitOfWork.VideoRepo().GetByID(dto.VideoID, nil)
+	if err != nil {
+		return "", types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil {
+		return "", videoError.Video_NotFound
+	}
+	thumbnailAt := float64(defaultThumbnailAt)
+	if video.ThumbnailAt != nil {
+		thumbnailAt = *video.ThumbnailAt
+	}
+	sourceURL, err := svc.sourceURL(ctx, dto.ObjectId)
+	if err != nil {
+		return "", types.NewServerError("Error in presigning source url", operationName, err)
+	}
+	poster, err := svc.transcoderSvc.ExtractFrame(sourceURL, thumbnailAt)
+	if err != nil {
+		return "", types.NewServerError("Error in extracting poster frame", operationName, err)
+	}
+	posterPath := path.Join(dto.URL, "thumbnails", "poster.jpg")
+	if _, err := svc.minioClient.UploadFileByContent(ctx, "videos", posterPath, "image/jpeg", poster); err != nil {
+		return "", types.NewServerError("Error in storing poster frame into storage", operationName, err)
+	}
+	spriteLocation, err := svc.transcoderSvc.GenerateThumbnailSprite(sourceURL)
+	if err != nil {
+		return "", types.NewServerError("Error in generating thumbnail sprite", operationName, err)
+	}
+	defer os.RemoveAll(spriteLocation)
+	spriteFiles := map[string]string{
+		"sprite.jpg": "image/jpeg",
+		"sprite.vtt": "text/vtt",
+	}
+	for fileName, contentType := range spriteFiles {
+		if err := svc.uploadFile(
+			ctx,
+			path.Join(spriteLocation, fileName),
+			path.Join(dto.URL, "thumbnails", fileName),
+			contentType,
+		); err != nil {
+			return "", types.NewServerError("Error in storing thumbnail sprite into storage", operationName, err)
+		}
+	}
+	return posterPath, nil
+}
+
+// RegenerateThumbnail replaces the poster with the teacher chosen frame, the source is gone
+// by now so the frame is cut from the matching segment of the best rendition
+func (svc videoService) RegenerateThumbnail(ctx context.Context, videoID uint) (string, error) {
+	const operationName = "videoService.RegenerateThumbnail"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(videoID, nil)
+	if err != nil {
+		return "", types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil {
+		return "", videoError.Video_NotFound
+	}
+	if video.Status != entities.VideoStatus_Done || video.URL == "" {
+		return "", videoError.Video_NotReady
+	}
+	var thumbnailAt float64
+	if video.ThumbnailAt != nil {
+		thumbnailAt = *video.ThumbnailAt
+	}
+	segment, err := svc.findSegment(ctx, video.URL, thumbnailAt)
+	if err != nil {
+		return "", types.NewServerError("Error in finding segment of thumbnail frame", operationName, err)
+	}
+	segmentContent, err := svc.minioClient.GetFile(ctx, "videos", segment.path)
+	if err != nil {
+		return "", types.NewServerError("Error in get file from minio", operationName, err)
+	}
+	if segment.isEncrypted {
+		key, err := loadVideoKey(svc.unitOfWork, svc.config.Video.KeyEncryptionSecret, video.URL)
+		if err != nil {
+			return "", types.NewServerError("Error in loading video key", operationName, err)
+		}
+		if key == nil {
+			return "", types.NewServerError("Error in loading video key", operationName, fmt.Errorf("key of %s not found", video.URL))
+		}
+		segmentContent, err = decryptSegment(key, segmentContent, segment.sequence)
+		if err != nil {
+			return "", types.NewServerError("Error in decrypting segment", operationName, err)
+		}
+	}
+	// fragmented mp4 segments only decode after the init segment of their rendition
+	if segment.initPath != "" {
+		initContent, err := svc.minioClient.GetFile(ctx, "videos", segment.initPath)
+		if err != nil {
+			return "", types.NewServerError("Error in get file from minio", operationName, err)
+		}
+		segmentContent = append(initContent, segmentContent...)
+	}
+	// the decrypted segment is only a few seconds long, it is the one copy written to scratch space
+	segmentFile, err := os.CreateTemp(svc.config.Video.ScratchDir, "segment-*")
+	if err != nil {
+		return "", types.NewServerError("Error in creating segment file", operationName, err)
+	}
+	defer os.Remove(segmentFile.Name())
+	_, err = segmentFile.Write(segmentContent)
+	if closeErr := segmentFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", types.NewServerError("Error in writing segment file", operationName, err)
+	}
+	poster, err := svc.transcoderSvc.ExtractFrame(segmentFile.Name(), segment.offset)
+	if err != nil {
+		return "", types.NewServerError("Error in extracting poster frame", operationName, err)
+	}
+	// a new name busts cached posters, the old one may still be used by cloned courses and versions
+	posterPath := path.Join(video.URL, "thumbnails", fmt.Sprintf("poster-%d.jpg", time.Now().Unix()))
+	if _, err := svc.minioClient.UploadFileByContent(ctx, "videos", posterPath, "image/jpeg", poster); err != nil {
+		return "", types.NewServerError("Error in storing poster frame into storage", operationName, err)
+	}
+	if err := svc.unitOfWork.VideoRepo().UpdateFields(video, map[string]any{"thumbnail_url": posterPath}); err != nil {
+		return "", types.NewServerError("Error in updating thumbnail of video", operationName, err)
+	}
+	return posterPath, nil
+}
+
+type hlsSegment struct {
+	path        string
+	initPath    string
+	offset      float64
+	sequence    int
+	isEncrypted bool
+}
+
+// findSegment returns the segment containing timestamp and the offset inside it
+func (svc videoService) findSegment(ctx context.Context, prefix string, timestamp float64) (*hlsSegment, error) {
+	master, err := svc.minioClient.GetFile(ctx, "videos", path.Join(prefix, "master.m3u8"))
+	if err != nil {
+		return nil, err
+	}
+	variant := highestVariant(master)
+	if variant == "" {
+		return nil, fmt.Errorf("no variant found in master playlist of %s", prefix)
+	}
+	variantPath := path.Join(prefix, variant)
+	playlist, err := svc.minioClient.GetFile(ctx, "videos", variantPath)
+	if err != nil {
+		return nil, err
+	}
+	var start, duration float64
+	var segment *hlsSegment
+	sequence := 0
+	isEncrypted := false
+	initPath := ""
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:") {
+			sequence, _ = strconv.Atoi(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"))
+			continue
+		}
+		if strings.HasPrefix(line, "#EXT-X-KEY:") {
+			isEncrypted = strings.Contains(line, "METHOD=AES-128")
+			continue
+		}
+		if strings.HasPrefix(line, "#EXT-X-MAP:") {
+			if match := mapURIPattern.FindStringSubmatch(line); match != nil {
+				initPath = path.Join(path.Dir(variantPath), match[1])
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#EXTINF:") {
+			value := strings.SplitN(strings.TrimPrefix(line, "#EXTINF:"), ",", 2)[0]
+			duration, _ = strconv.ParseFloat(value, 64)
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		segment = &hlsSegment{
+			path:        path.Join(path.Dir(variantPath), line),
+			initPath:    initPath,
+			sequence:    sequence,
+			isEncrypted: isEncrypted,
+		}
+		if timestamp < start+duration {
+			segment.offset = timestamp - start
+			return segment, nil
+		}
+		start += duration
+		sequence++
+	}
+	if segment == nil {
+		return nil, fmt.Errorf("no segment found in playlist %s", variantPath)
+	}
+	return segment, nil
+}
+
+// decryptSegment reverses the aes-128 encryption of ffmpeg, without an explicit iv
+// the media sequence number of the segment is used as iv
+func decryptSegment(key []byte, content []byte, sequence int) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 || len(content)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("encrypted segment size is not a multiple of block size")
+	}
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], uint64(sequence))
+	plain := make([]byte, len(content))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, content)
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("invalid padding of decrypted segment")
+	}
+	return plain[:len(plain)-padding], nil
+}
+
+func highestVariant(master []byte) string {
+	var variant string
+	var highestBandwidth, bandwidth int
+	scanner := bufio.NewScanner(bytes.NewReader(master))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#EXT-X-STREAM-INF:") {
+			bandwidth = 0
+			if match := bandwidthPattern.FindStringSubmatch(line); match != nil {
+				bandwidth, _ = strconv.Atoi(match[1])
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if variant == "" || bandwidth > highestBandwidth {
+			variant = line
+			highestBandwidth = bandwidth
+		}
+	}
+	return variant
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"golang.org/x/sync/errgroup"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync/atomic"
+)
+
+const defaultUploadConcurrency = 4
+
+var encodedContentTypes = map[string]string{
+	".ts":   "video/mp2t",
+	".m4s":  "video/iso.segment",
+	".mp4":  "video/mp4",
+	".m3u8": "application/vnd.apple.mpegurl",
+	".jpg":  "image/jpeg",
+	".vtt":  "text/vtt",
+}
+
+// treeUploader streams the files of an encoded tree into storage while the encoder is still
+// writing it, every file is removed from scratch space once it is stored so a long lecture
+// never needs the whole tree on disk
+type treeUploader struct {
+	svc        videoService
+	group      *errgroup.Group
+	ctx        context.Context
+	prefix     string
+	queued     atomic.Int64
+	uploaded   atomic.Int64
+	onUploaded func(uploaded int, total int)
+}
+
+func (svc videoService) newTreeUploader(ctx context.Context, prefix string, onUploaded func(uploaded int, total int)) *treeUploader {
+	group, groupCtx := errgroup.WithContext(ctx)
+	concurrency := svc.config.Video.UploadConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultUploadConcurrency
+	}
+	group.SetLimit(concurrency)
+	return &treeUploader{
+		svc:        svc,
+		group:      group,
+		ctx:        groupCtx,
+		prefix:     prefix,
+		onUploaded: onUploaded,
+	}
+}
+
+// Upload blocks while every slot is busy, so the encoder slows down instead of filling the
+// disk when storage is slower than encoding
+func (uploader *treeUploader) Upload(localPath string, relativePath string) error {
+	// the failed upload itself is returned by Wait
+	if err := uploader.ctx.Err(); err != nil {
+		return err
+	}
+	uploader.queued.Add(1)
+	uploader.group.Go(func() error {
+		objectPath := path.Join(uploader.prefix, relativePath)
+		if err := uploader.svc.uploadFile(uploader.ctx, localPath, objectPath, encodedContentTypes[path.Ext(relativePath)]); err != nil {
+			return err
+		}
+		if err := os.Remove(localPath); err != nil {
+			return err
+		}
+		if uploader.onUploaded != nil {
+			uploader.onUploaded(int(uploader.uploaded.Add(1)), int(uploader.queued.Load()))
+		}
+		return nil
+	})
+	return nil
+}
+
+func (uploader *treeUploader) UploadSegment(segment dtos.EncodedSegment) error {
+	return uploader.Upload(segment.Path, segment.RelativePath)
+}
+
+// UploadTree queues whatever the encoder left in the directory, playlists are only final
+// once the encoder exits
+func (uploader *treeUploader) UploadTree(root string) error {
+	return filepath.WalkDir(root, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if dirEntry.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		return uploader.Upload(filePath, filepath.ToSlash(relativePath))
+	})
+}
+
+func (uploader *treeUploader) Wait() error {
+	return uploader.group.Wait()
+}
+
+// uploadFile streams a local file into storage without reading it into memory
+func (svc videoService) uploadFile(ctx context.Context, localPath string, objectPath string, contentType string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	_, err = svc.minioClient.PutObject(ctx, videosBucket, objectPath, contentType, file, info.Size())
+	return err
+}
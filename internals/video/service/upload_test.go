@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+var errStorageDown = errors.New("storage is down")
+
+// flakyStorage stores every object except the one it is told to fail
+type flakyStorage struct {
+	contracts.Storage
+	mu          sync.Mutex
+	failingPath string
+	objects     map[string]string
+}
+
+func (storage *flakyStorage) PutObject(ctx context.Context, bucketName string, objectPath string, contentType string, reader io.Reader, size int64) (*dtos.UploadResult, error) {
+	if objectPath == storage.failingPath {
+		return nil, errStorageDown
+	}
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return nil, err
+	}
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+	storage.objects[objectPath] = contentType
+	return &dtos.UploadResult{}, nil
+}
+
+func TestTreeUploader(t *testing.T) {
+	files := []string{"master.m3u8", "360p/playlist.m3u8", "360p/segment0.ts", "360p/segment1.ts"}
+	tests := []struct {
+		name        string
+		failingPath string
+		missingFile string
+		expectedErr error
+	}{
+		{name: "every file is stored and removed from scratch space"},
+		{name: "a failed upload is returned by wait", failingPath: "videos/1/360p/segment1.ts", expectedErr: errStorageDown},
+		{name: "a file removed before its upload", missingFile: "360p/segment0.ts", expectedErr: os.ErrNotExist},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, file := range files {
+				filePath := filepath.Join(root, file)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filePath, []byte(file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			storage := &flakyStorage{failingPath: test.failingPath, objects: make(map[string]string)}
+			svc := videoService{
+				minioClient: storage,
+				config:      &dtos.EnvConfig{Video: dtos.VideoEnvConfig{UploadConcurrency: 1}},
+			}
+			var lastUploaded, lastTotal int
+			uploader := svc.newTreeUploader(context.Background(), "videos/1", func(uploaded int, total int) {
+				lastUploaded, lastTotal = uploaded, total
+			})
+			for _, file := range files {
+				if file == test.missingFile {
+					if err := os.Remove(filepath.Join(root, file)); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := uploader.Upload(filepath.Join(root, file), file); err != nil && test.expectedErr == nil {
+					t.Fatalf("queueing %s: %v", file, err)
+				}
+			}
+			err := uploader.Wait()
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Wait = %v, want %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				// the encoder stops as soon as one file can't be stored
+				if err := uploader.Upload(filepath.Join(root, files[0]), files[0]); !errors.Is(err, context.Canceled) {
+					t.Errorf("Upload after a failure = %v, want %v", err, context.Canceled)
+				}
+				return
+			}
+			if lastUploaded != len(files) || lastTotal != len(files) {
+				t.Errorf("progress = %d/%d, want %d/%d", lastUploaded, lastTotal, len(files), len(files))
+			}
+			for _, file := range files {
+				contentType, isUploaded := storage.objects["videos/1/"+file]
+				if !isUploaded || contentType != encodedContentTypes[filepath.Ext(file)] {
+					t.Errorf("%s stored = %v as %q", file, isUploaded, contentType)
+				}
+				if _, err := os.Stat(filepath.Join(root, file)); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("%s left in scratch space", file)
+				}
+			}
+		})
+	}
+}
@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"github.com/google/uuid"
 	courseError "github.com/ladmakhi81/learnup/internals/course/error"
 	dtoreq "github.com/ladmakhi81/learnup/internals/video/dto/req"
 	videoError "github.com/ladmakhi81/learnup/internals/video/error"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
 	"github.com/ladmakhi81/learnup/shared/db"
 	"github.com/ladmakhi81/learnup/shared/db/entities"
 	"github.com/ladmakhi81/learnup/shared/db/repositories"
@@ -16,38 +18,65 @@ import (
 	"log"
 	"math"
 	"os"
-	"path"
-	"path/filepath"
 	"strconv"
+	"time"
 )
 
+// HlsKeyURI is the placeholder written into #EXT-X-KEY, playback replaces it with the key endpoint
+const HlsKeyURI = "video.key"
+
+const maxFailureReasonLen = 1000
+
+// ffmpeg reads the source over http for as long as the encode runs, the url has to outlive it
+const sourceURLTTL = 24 * time.Hour
+
+// EncodeResult carries the watermark the tree was made with, the workflow saves it together
+// with the url so a replaced video never mixes the old files with the new setting
+type EncodeResult struct {
+	URL           string
+	WatermarkMode entities.CourseWatermarkMode
+}
+
 type VideoService interface {
 	UpdateURLAndDuration(dto dtoreq.UpdateURLAndDurationVideoReqDto) (*entities.Video, error)
 	CreateCompleteUploadVideoNotification(videoID uint) error
-	Encode(ctx context.Context, dto dtoreq.EncodeVideoReqDto) (string, error)
+	StartProcessing(dto dtoreq.StartVideoProcessingReqDto) (string, error)
+	MarkFailed(dto dtoreq.MarkVideoFailedReqDto) error
+	Encode(ctx context.Context, dto dtoreq.EncodeVideoReqDto) (*EncodeResult, error)
+	GenerateThumbnails(ctx context.Context, dto dtoreq.GenerateVideoThumbnailsReqDto) (string, error)
+	RegenerateThumbnail(ctx context.Context, videoID uint) (string, error)
+	DeleteSource(ctx context.Context, dto dtoreq.DeleteVideoSourceReqDto) error
+	CleanupStorage(ctx context.Context, dto dtoreq.CleanupVideoStorageReqDto) error
+	SweepOrphans(ctx context.Context, dto dtoreq.SweepOrphanObjectsReqDto) (int, error)
 	CalculateDuration(ctx context.Context, dto dtoreq.CalculateVideoDurationReqDto) (string, error)
 	Verify(admin *entities.User, videoId uint) error
 	FindVideosByCourseID(courseID uint) ([]*entities.Video, error)
 }
 
 type videoService struct {
-	unitOfWork  db.UnitOfWork
-	minioClient contracts.Storage
-	ffmpegSvc   contracts.Ffmpeg
-	logSvc      contracts.Log
+	unitOfWork    db.UnitOfWork
+	minioClient   contracts.Storage
+	transcoderSvc contracts.Transcoder
+	logSvc        contracts.Log
+	temporalSvc   contracts.Temporal
+	config        *dtos.EnvConfig
 }
 
 func NewVideoSvc(
 	unitOfWork db.UnitOfWork,
 	minioClient contracts.Storage,
-	ffmpegSvc contracts.Ffmpeg,
+	transcoderSvc contracts.Transcoder,
 	logSvc contracts.Log,
+	temporalSvc contracts.Temporal,
+	config *dtos.EnvConfig,
 ) VideoService {
 	return &videoService{
-		unitOfWork:  unitOfWork,
-		minioClient: minioClient,
-		ffmpegSvc:   ffmpegSvc,
-		logSvc:      logSvc,
+		unitOfWork:    unitOfWork,
+		minioClient:   minioClient,
+		transcoderSvc: transcoderSvc,
+		logSvc:        logSvc,
+		temporalSvc:   temporalSvc,
+		config:        config,
 	}
 }
 
@@ -73,6 +102,82 @@ func (svc videoService) CreateCompleteUploadVideoNotification(videoID uint) erro
 	return nil
 }
 
+// StartProcessing remembers the uploaded object so a failed video can be processed again from it,
+// a processed video keeps playing its current files while a replacement is processed and their
+// prefix is returned to be cleaned up after the swap
+func (svc videoService) StartProcessing(dto dtoreq.StartVideoProcessingReqDto) (string, error) {
+	const operationName = "videoService.StartProcessing"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(dto.VideoID, nil)
+	if err != nil {
+		return "", types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil {
+		return "", videoError.Video_NotFound
+	}
+	fields := map[string]any{
+		"failure_reason":         nil,
+		"source_object_id":       dto.ObjectID,
+		"processing_workflow_id": dto.WorkflowID,
+	}
+	isReplacement := video.Status == entities.VideoStatus_Done && video.URL != ""
+	if !isReplacement {
+		fields["status"] = entities.VideoStatus_Pending
+	}
+	if err := svc.unitOfWork.VideoRepo().UpdateFields(video, fields); err != nil {
+		return "", types.NewServerError("Error in updating processing state of video", operationName, err)
+	}
+	if isReplacement {
+		return video.URL, nil
+	}
+	return "", nil
+}
+
+// MarkFailed is the compensation of the processing workflow, the uploaded source is kept for reprocessing
+func (svc videoService) MarkFailed(dto dtoreq.MarkVideoFailedReqDto) error {
+	const operationName = "videoService.MarkFailed"
+	video, err := svc.unitOfWork.VideoRepo().GetByID(dto.VideoID, []string{"Course"})
+	if err != nil {
+		return types.NewServerError("Error in fetching video by id", operationName, err)
+	}
+	if video == nil {
+		return videoError.Video_NotFound
+	}
+	reason := dto.Reason
+	if len(reason) > maxFailureReasonLen {
+		reason = reason[:maxFailureReasonLen]
+	}
+	_, err = db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		fields := map[string]any{"failure_reason": reason}
+		// a failed replacement leaves the previous files playing
+		if video.Status != entities.VideoStatus_Done {
+			fields["status"] = entities.VideoStatus_Fail
+		}
+		if err := tx.VideoRepo().UpdateFields(video, fields); err != nil {
+			return nil, types.NewServerError("Error in marking video as failed", operationName, err)
+		}
+		if video.Course == nil {
+			return nil, nil
+		}
+		notification := &entities.Notification{
+			Type: entities.NotificationType_VideoProcessingFailed,
+			Metadata: map[string]any{
+				"videoId":     video.ID,
+				"videoTitle":  video.Title,
+				"courseId":    video.Course.ID,
+				"courseTitle": video.Course.Name,
+				"reason":      reason,
+			},
+			IsSeen: false,
+			UserID: video.Course.TeacherID,
+		}
+		if err := tx.NotificationRepo().Create(notification); err != nil {
+			return nil, types.NewServerError("Error in creating notification", operationName, err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
 func (svc videoService) UpdateURLAndDuration(dto dtoreq.UpdateURLAndDurationVideoReqDto) (*entities.Video, error) {
 	const operationName = "videoService.UpdateURLAndDuration"
 	video, err := svc.unitOfWork.VideoRepo().GetByID(dto.ID, nil)
@@ -84,7 +189,15 @@ func (svc videoService) UpdateURLAndDuration(dto dtoreq.UpdateURLAndDurationVide
 	}
 	video.URL = dto.URL
 	video.Duration = &dto.Duration
+	if dto.ThumbnailURL != "" {
+		video.ThumbnailURL = &dto.ThumbnailURL
+	}
+	if dto.WatermarkMode != "" {
+		video.WatermarkMode = dto.WatermarkMode
+	}
 	video.Status = entities.VideoStatus_Done
+	video.FailureReason = nil
+	video.SourceObjectID = nil
 	if err := svc.unitOfWork.VideoRepo().Update(video); err != nil {
 		return nil, types.NewServerError("Error in updating the video", operationName, err)
 	}
@@ -93,11 +206,12 @@ func (svc videoService) UpdateURLAndDuration(dto dtoreq.UpdateURLAndDurationVide
 
 func (svc videoService) CalculateDuration(ctx context.Context, dto dtoreq.CalculateVideoDurationReqDto) (string, error) {
 	const operationName = "videoService.CalculateDuration"
-	file, err := svc.minioClient.GetFileReader(ctx, "videos", dto.ObjectId)
+	defer svc.trackProgress(ctx, ProcessingStep_Probing).Stop()
+	sourceURL, err := svc.sourceURL(ctx, dto.ObjectId)
 	if err != nil {
-		return "", types.NewServerError("Error in get file from minio", operationName, err)
+		return "", types.NewServerError("Error in presigning source url", operationName, err)
 	}
-	durationStr, err := svc.ffmpegSvc.GetVideoDuration(file)
+	durationStr, err := svc.transcoderSvc.GetVideoDuration(sourceURL)
 	if err != nil {
 		return "", types.NewServerError("Error in calculating video duration", operationName, err)
 	}
@@ -111,58 +225,93 @@ func (svc videoService) CalculateDuration(ctx context.Context, dto dtoreq.Calcul
 	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds), nil
 }
 
-func (svc videoService) Encode(ctx context.Context, dto dtoreq.EncodeVideoReqDto) (string, error) {
+func (svc videoService) Encode(ctx context.Context, dto dtoreq.EncodeVideoReqDto) (*EncodeResult, error) {
 	const operationName = "videoService.Encode"
+	progress := svc.trackProgress(ctx, ProcessingStep_Encoding)
+	defer progress.Stop()
 	log.Println("encode function execute")
 	// encode
-	file, err := svc.minioClient.GetFileReader(ctx, "videos", dto.ObjectId)
-	if err != nil {
-		return "", types.NewServerError("Error in get file from minio", operationName, err)
-	}
-	storeLocation, err := svc.ffmpegSvc.EncodeVideo(file)
+	sourceURL, err := svc.sourceURL(ctx, dto.ObjectId)
 	if err != nil {
-		return "", types.NewServerError("Error in encoding video file", operationName, err)
+		return nil, types.NewServerError("Error in presigning source url", operationName, err)
 	}
-
-	// move from local to minio
-	dirFiles, err := os.ReadDir(storeLocation)
-	if err != nil {
-		return "", types.NewServerError("Error in finding directories of encoded files", operationName, err)
+	options := dtos.EncodeOptions{
+		OnProgress: func(percent int) {
+			progress.Update(ProcessingStep_Encoding, percent)
+		},
 	}
-	contentTypes := map[string]string{
-		".ts":   "video/mp2t",
-		".m3u8": "application/vnd.apple.mpegurl",
+	if dto.Encrypt {
+		key := make([]byte, 16)
+		if _, err := rand.Read(key); err != nil {
+			return nil, types.NewServerError("Error in generating video key", operationName, err)
+		}
+		options.Encryption = &dtos.HlsEncryption{Key: key, KeyURI: HlsKeyURI}
 	}
-	encodedFilePath := uuid.NewString()
-	for _, dirFile := range dirFiles {
-		file, err := os.ReadFile(path.Join(storeLocation, dirFile.Name()))
+	watermarkMode := entities.CourseWatermarkMode_None
+	if dto.VideoID != 0 {
+		course, err := svc.unitOfWork.CourseRepo().GetByVideoID(dto.VideoID)
 		if err != nil {
-			return "", types.NewServerError("Error in finding file of directories of encoded files", operationName, err)
+			return nil, types.NewServerError("Error in fetching course by video id", operationName, err)
+		}
+		if course != nil && course.WatermarkMode.IsMarked() {
+			watermarkMode = course.WatermarkMode
+			options.Watermark = svc.buildWatermark(course)
 		}
-		fileExt := filepath.Ext(dirFile.Name())
-		currentContentType := contentTypes[fileExt]
-		if _, err := svc.minioClient.UploadFileByContent(
-			ctx,
-			"videos",
-			fmt.Sprintf("%s/%s", encodedFilePath, dirFile.Name()),
-			currentContentType,
-			file,
-		); err != nil {
-			return "", types.NewServerError("Error in storing encoded video into storage", operationName, err)
+	}
+	// segments move to minio while ffmpeg is still encoding, keeping the layout of the tree
+	// so master.m3u8 can resolve renditions
+	encodedFilePath := uuid.NewString()
+	segmentUploader := svc.newTreeUploader(ctx, encodedFilePath, nil)
+	options.OnSegment = segmentUploader.UploadSegment
+	storeLocation, err := svc.transcoderSvc.EncodeVideo(sourceURL, options)
+	// a failed upload stops the encode, its error says more than the cancelled encode
+	if uploadErr := segmentUploader.Wait(); uploadErr != nil {
+		if err == nil {
+			os.RemoveAll(storeLocation)
 		}
+		return nil, types.NewServerError("Error in storing encoded video into storage", operationName, uploadErr)
 	}
+	if err != nil {
+		return nil, types.NewServerError("Error in encoding video file", operationName, err)
+	}
+	// the uploaded source is kept until DeleteSource since thumbnails are cut from it
+	defer os.RemoveAll(storeLocation)
 
-	// remove unused files
-	if err := os.RemoveAll(storeLocation); err != nil {
-		return "", types.NewServerError("Error in deleting file from disk", operationName, err)
+	// playlists are only final once ffmpeg exits
+	progress.Update(ProcessingStep_Uploading, 0)
+	treeUploader := svc.newTreeUploader(ctx, encodedFilePath, func(uploaded int, total int) {
+		progress.Update(ProcessingStep_Uploading, uploaded*100/total)
+	})
+	if err := treeUploader.UploadTree(storeLocation); err != nil {
+		treeUploader.Wait()
+		return nil, types.NewServerError("Error in storing encoded video into storage", operationName, err)
 	}
-	if err := svc.minioClient.DeleteObject(context.TODO(), "videos", dto.ObjectId); err != nil {
-		return "", types.NewServerError("Error in deleting file from minio", operationName, err)
+	if err := treeUploader.Wait(); err != nil {
+		return nil, types.NewServerError("Error in storing encoded video into storage", operationName, err)
 	}
-	if err := svc.minioClient.DeleteObject(context.TODO(), "videos", fmt.Sprintf("%s.info", dto.ObjectId)); err != nil {
-		return "", types.NewServerError("Error in deleting file from minio", operationName, err)
+
+	if options.Encryption != nil {
+		if err := svc.storeKey(encodedFilePath, options.Encryption.Key); err != nil {
+			return nil, types.NewServerError("Error in storing video key", operationName, err)
+		}
 	}
-	return encodedFilePath, nil
+	return &EncodeResult{URL: encodedFilePath, WatermarkMode: watermarkMode}, nil
+}
+
+// sourceURL hands the uploaded source to the transcoder without copying it to the worker first
+func (svc videoService) sourceURL(ctx context.Context, objectID string) (string, error) {
+	return svc.minioClient.GetPresignedURL(ctx, "videos", objectID, sourceURLTTL)
+}
+
+func (svc videoService) DeleteSource(ctx context.Context, dto dtoreq.DeleteVideoSourceReqDto) error {
+	const operationName = "videoService.DeleteSource"
+	if err := svc.minioClient.DeleteObject(ctx, "videos", dto.ObjectId); err != nil {
+		return types.NewServerError("Error in deleting file from minio", operationName, err)
+	}
+	if err := svc.minioClient.DeleteObject(ctx, "videos", fmt.Sprintf("%s.info", dto.ObjectId)); err != nil {
+		return types.NewServerError("Error in deleting file from minio", operationName, err)
+	}
+	return nil
 }
 
 func (svc videoService) Verify(admin *entities.User, videoId uint) error {
@@ -208,3 +357,40 @@ func (svc videoService) FindVideosByCourseID(courseID uint) ([]*entities.Video,
 	}
 	return videos, nil
 }
+
+// loadVideoKey returns the plain key of an encoded prefix, nil when the prefix is not encrypted
+func loadVideoKey(unitOfWork db.UnitOfWork, secret string, prefix string) ([]byte, error) {
+	videoKey, err := unitOfWork.VideoKeyRepo().GetOne(map[string]any{"prefix": prefix}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if videoKey == nil {
+		return nil, nil
+	}
+	return utils.Decrypt(secret, videoKey.EncryptedKey)
+}
+
+func (svc videoService) buildWatermark(course *entities.Course) *dtos.VideoWatermark {
+	text := fmt.Sprintf("#%d", course.ID)
+	if svc.config.Video.WatermarkText != "" {
+		text = fmt.Sprintf("%s #%d", svc.config.Video.WatermarkText, course.ID)
+	}
+	return &dtos.VideoWatermark{
+		Text:       text,
+		LogoPath:   svc.config.Video.WatermarkLogo,
+		FontPath:   svc.config.Video.WatermarkFont,
+		ABVariants: course.WatermarkMode == entities.CourseWatermarkMode_Forensic,
+	}
+}
+
+// storeKey keeps the key encrypted at rest, re-encoding the same prefix is not possible so it's always new
+func (svc videoService) storeKey(prefix string, key []byte) error {
+	encryptedKey, err := utils.Encrypt(svc.config.Video.KeyEncryptionSecret, key)
+	if err != nil {
+		return err
+	}
+	return svc.unitOfWork.VideoKeyRepo().Create(&entities.VideoKey{
+		Prefix:       prefix,
+		EncryptedKey: encryptedKey,
+	})
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"encoding/json"
+	courseError "github.com/ladmakhi81/learnup/internals/course/error"
+	dtoreq "github.com/ladmakhi81/learnup/internals/video/dto/req"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/db/repositories"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"github.com/ladmakhi81/learnup/shared/utils"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	watchProgressBufferKey        = "video-progress:buffer"
+	defaultWatchProgressBatchSize = 500
+	// batches flushed in one run, anything left waits for the next run
+	maxWatchProgressBatches = 20
+	// share of a video that has to be watched before it counts as completed
+	completionThreshold = 0.9
+	// stopping this close to the end resumes the video from the start
+	resumeEndMargin = 10
+)
+
+// bufferedWatchProgress is what a reported event is kept as in redis until it is flushed
+type bufferedWatchProgress struct {
+	UserID        uint                    `json:"userId"`
+	VideoID       uint                    `json:"videoId"`
+	Position      float64                 `json:"position"`
+	WatchedRanges []entities.WatchedRange `json:"watchedRanges"`
+	ReportedAt    time.Time               `json:"reportedAt"`
+}
+
+type CourseProgress struct {
+	Participant          *entities.CourseParticipant
+	TotalVideos          int
+	CompletedVideos      int
+	TotalQuizzes         int
+	PassedQuizzes        int
+	CompletionPercentage float64
+	ResumeVideoID        *uint
+	ResumePosition       uint
+}
+
+type WatchProgressService interface {
+	Report(user *entities.User, dto dtoreq.ReportWatchProgressReqDto) (int, error)
+	Flush(dto dtoreq.FlushWatchProgressReqDto) (int, error)
+	GetVideoProgresses(user *entities.User, videoIDs []uint) (map[uint]*entities.VideoProgress, error)
+	GetCourseProgress(user *entities.User, courseID uint) (*CourseProgress, error)
+	GetCourseProgresses(user *entities.User) ([]*CourseProgress, error)
+}
+
+type watchProgressService struct {
+	unitOfWork db.UnitOfWork
+	cacheSvc   contracts.Cache
+}
+
+func NewWatchProgressSvc(unitOfWork db.UnitOfWork, cacheSvc contracts.Cache) WatchProgressService {
+	return &watchProgressService{
+		unitOfWork: unitOfWork,
+		cacheSvc:   cacheSvc,
+	}
+}
+
+// Report buffers the events in redis, players report every few seconds and writing each of
+// them would hit the same rows over and over. Only events of enrolled students are kept
+func (svc watchProgressService) Report(user *entities.User, dto dtoreq.ReportWatchProgressReqDto) (int, error) {
+	const operationName = "watchProgressService.Report"
+	videoIDs := make([]uint, 0, len(dto.Events))
+	for _, event := range dto.Events {
+		if !slices.Contains(videoIDs, event.VideoID) {
+			videoIDs = append(videoIDs, event.VideoID)
+		}
+	}
+	videos, err := svc.unitOfWork.VideoRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"id": videoIDs},
+	})
+	if err != nil {
+		return 0, types.NewServerError("Error in fetching reported videos", operationName, err)
+	}
+	trackedVideos := make(map[uint]bool, len(videos))
+	participants := make(map[uint]bool)
+	for _, video := range videos {
+		if video.CourseId == nil {
+			continue
+		}
+		isParticipant, isChecked := participants[*video.CourseId]
+		if !isChecked {
+			participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(*video.CourseId, user.ID)
+			if err != nil {
+				return 0, types.NewServerError("Error in fetching course participant", operationName, err)
+			}
+			isParticipant = participant != nil && participant.IsActive()
+			participants[*video.CourseId] = isParticipant
+		}
+		trackedVideos[video.ID] = isParticipant
+	}
+	reportedAt := time.Now()
+	values := make([]any, 0, len(dto.Events))
+	for _, event := range dto.Events {
+		if !trackedVideos[event.VideoID] {
+			continue
+		}
+		buffered := bufferedWatchProgress{
+			UserID:        user.ID,
+			VideoID:       event.VideoID,
+			Position:      event.Position,
+			WatchedRanges: make([]entities.WatchedRange, len(event.WatchedRanges)),
+			ReportedAt:    reportedAt,
+		}
+		for index, watchedRange := range event.WatchedRanges {
+			buffered.WatchedRanges[index] = entities.WatchedRange{Start: watchedRange.Start, End: watchedRange.End}
+		}
+		value, err := json.Marshal(buffered)
+		if err != nil {
+			return 0, types.NewServerError("Error in encoding watch progress", operationName, err)
+		}
+		values = append(values, string(value))
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+	if err := svc.cacheSvc.PushList(watchProgressBufferKey, values...); err != nil {
+		return 0, types.NewServerError("Error in buffering watch progress", operationName, err)
+	}
+	return len(values), nil
+}
+
+// Flush folds the buffered events into the progress rows, a batch that fails is pushed back
+// and merged again on the next run since merging the same ranges twice changes nothing
+func (svc watchProgressService) Flush(dto dtoreq.FlushWatchProgressReqDto) (int, error) {
+	const operationName = "watchProgressService.Flush"
+	batchSize := dto.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultWatchProgressBatchSize
+	}
+	flushedCount := 0
+	for batch := 0; batch < maxWatchProgressBatches; batch++ {
+		values, err := svc.cacheSvc.PopList(watchProgressBufferKey, int64(batchSize))
+		if err != nil {
+			return flushedCount, types.NewServerError("Error in reading buffered watch progress", operationName, err)
+		}
+		if len(values) == 0 {
+			break
+		}
+		if err := svc.flushBatch(values); err != nil {
+			pushBack := make([]any, len(values))
+			for index, value := range values {
+				pushBack[index] = value
+			}
+			if pushErr := svc.cacheSvc.PushList(watchProgressBufferKey, pushBack...); pushErr != nil {
+				return flushedCount, types.NewServerError("Error in restoring buffered watch progress", operationName, pushErr)
+			}
+			return flushedCount, err
+		}
+		flushedCount += len(values)
+		if len(values) < batchSize {
+			break
+		}
+	}
+	return flushedCount, nil
+}
+
+func (svc watchProgressService) GetVideoProgresses(user *entities.User, videoIDs []uint) (map[uint]*entities.VideoProgress, error) {
+	const operationName = "watchProgressService.GetVideoProgresses"
+	result := make(map[uint]*entities.VideoProgress, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return result, nil
+	}
+	progresses, err := svc.unitOfWork.VideoProgressRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"user_id": user.ID, "video_id": videoIDs},
+	})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video progresses", operationName, err)
+	}
+	for _, progress := range progresses {
+		result[progress.VideoID] = progress
+	}
+	return result, nil
+}
+
+func (svc watchProgressService) GetCourseProgress(user *entities.User, courseID uint) (*CourseProgress, error) {
+	const operationName = "watchProgressService.GetCourseProgress"
+	participant, err := svc.unitOfWork.CourseParticipantRepo().GetOne(courseID, user.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching course participant", operationName, err)
+	}
+	if participant == nil || !participant.IsActive() {
+		return nil, courseError.Course_NotParticipant
+	}
+	return svc.courseProgress(user, participant)
+}
+
+// GetCourseProgresses is the dashboard of the student, the course watched last comes first
+func (svc watchProgressService) GetCourseProgresses(user *entities.User) ([]*CourseProgress, error) {
+	const operationName = "watchProgressService.GetCourseProgresses"
+	participants, err := svc.unitOfWork.CourseParticipantRepo().GetAllActiveByStudentID(user.ID)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching courses of student", operationName, err)
+	}
+	result := make([]*CourseProgress, 0, len(participants))
+	for _, participant := range participants {
+		progress, err := svc.courseProgress(user, participant)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, progress)
+	}
+	return result, nil
+}
+
+func (svc watchProgressService) courseProgress(user *entities.User, participant *entities.CourseParticipant) (*CourseProgress, error) {
+	const operationName = "watchProgressService.courseProgress"
+	videos, err := svc.participantVideos(participant)
+	if err != nil {
+		return nil, err
+	}
+	progresses, err := svc.unitOfWork.VideoProgressRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"user_id": user.ID, "course_id": participant.CourseID},
+	})
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching video progresses of course", operationName, err)
+	}
+	videoProgresses := make(map[uint]*entities.VideoProgress, len(progresses))
+	for _, progress := range progresses {
+		videoProgresses[progress.VideoID] = progress
+	}
+	result := &CourseProgress{
+		Participant: participant,
+		TotalVideos: len(videos),
+	}
+	// resume the unfinished video watched last, or else the first one not watched yet
+	var lastWatched *entities.VideoProgress
+	for _, video := range videos {
+		progress := videoProgresses[video.ID]
+		if progress == nil {
+			if result.ResumeVideoID == nil {
+				result.ResumeVideoID = &video.ID
+			}
+			continue
+		}
+		if progress.IsCompleted {
+			result.CompletedVideos++
+			continue
+		}
+		if lastWatched == nil || progress.UpdatedAt.After(lastWatched.UpdatedAt) {
+			lastWatched = progress
+		}
+	}
+	if lastWatched != nil {
+		result.ResumeVideoID = &lastWatched.VideoID
+		result.ResumePosition = lastWatched.LastPosition
+	}
+	if err := svc.quizProgress(user, participant, videos, result); err != nil {
+		return nil, err
+	}
+	// a passed quiz weighs as much as a watched video
+	if total := result.TotalVideos + result.TotalQuizzes; total > 0 {
+		percentage := float64(result.CompletedVideos+result.PassedQuizzes) * 100 / float64(total)
+		result.CompletionPercentage = math.Round(percentage*100) / 100
+	}
+	return result, nil
+}
+
+// quizProgress counts the published quizzes that are course wide or belong to a video the student can watch
+func (svc watchProgressService) quizProgress(user *entities.User, participant *entities.CourseParticipant, videos []*entities.Video, result *CourseProgress) error {
+	const operationName = "watchProgressService.quizProgress"
+	quizzes, err := svc.unitOfWork.QuizRepo().GetAllByCourseID(participant.CourseID, true)
+	if err != nil {
+		return types.NewServerError("Error in fetching quizzes of course", operationName, err)
+	}
+	videoIDs := make(map[uint]bool, len(videos))
+	for _, video := range videos {
+		videoIDs[video.ID] = true
+	}
+	quizIDs := make([]uint, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		if quiz.VideoID == nil || videoIDs[*quiz.VideoID] {
+			quizIDs = append(quizIDs, quiz.ID)
+		}
+	}
+	summaries, err := svc.unitOfWork.QuizAttemptRepo().GetSummaries(user.ID, quizIDs)
+	if err != nil {
+		return types.NewServerError("Error in fetching quiz attempts of course", operationName, err)
+	}
+	result.TotalQuizzes = len(quizIDs)
+	for _, summary := range summaries {
+		if summary.IsPassed {
+			result.PassedQuizzes++
+		}
+	}
+	return nil
+}
+
+// participantVideos are the watchable videos of the course version the student is enrolled in
+func (svc watchProgressService) participantVideos(participant *entities.CourseParticipant) ([]*entities.Video, error) {
+	const operationName = "watchProgressService.participantVideos"
+	if participant.CourseVersion == 0 {
+		order := "id asc"
+		videos, err := svc.unitOfWork.VideoRepo().GetAll(repositories.GetAllOptions{
+			Conditions: map[string]any{"course_id": participant.CourseID, "is_published": true, "is_verified": true},
+			Order:      &order,
+		})
+		if err != nil {
+			return nil, types.NewServerError("Error in fetching videos of course", operationName, err)
+		}
+		return videos, nil
+	}
+	courseVersion, err := svc.unitOfWork.CourseVersionRepo().GetOne(
+		map[string]any{"course_id": participant.CourseID, "version": participant.CourseVersion},
+		nil,
+	)
+	if err != nil {
+		return nil, types.NewServerError("Error in fetching version of course", operationName, err)
+	}
+	if courseVersion == nil {
+		return nil, courseError.Course_VersionNotFound
+	}
+	videos := make([]*entities.Video, 0)
+	for _, video := range courseVersion.GetVideos() {
+		if video.IsPublished && video.IsVerified {
+			videos = append(videos, video)
+		}
+	}
+	return videos, nil
+}
+
+func (svc watchProgressService) flushBatch(values []string) error {
+	const operationName = "watchProgressService.flushBatch"
+	type progressKey struct {
+		userID  uint
+		videoID uint
+	}
+	type participantKey struct {
+		courseID  uint
+		studentID uint
+	}
+	events := make(map[progressKey][]bufferedWatchProgress)
+	keys := make([]progressKey, 0)
+	videoIDs := make([]uint, 0)
+	for _, value := range values {
+		var buffered bufferedWatchProgress
+		// a malformed event would fail every run, it is dropped instead
+		if err := json.Unmarshal([]byte(value), &buffered); err != nil {
+			continue
+		}
+		key := progressKey{userID: buffered.UserID, videoID: buffered.VideoID}
+		if _, isGrouped := events[key]; !isGrouped {
+			keys = append(keys, key)
+		}
+		events[key] = append(events[key], buffered)
+		if !slices.Contains(videoIDs, buffered.VideoID) {
+			videoIDs = append(videoIDs, buffered.VideoID)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	videos, err := svc.unitOfWork.VideoRepo().GetAll(repositories.GetAllOptions{
+		Conditions: map[string]any{"id": videoIDs},
+	})
+	if err != nil {
+		return types.NewServerError("Error in fetching videos of watch progress", operationName, err)
+	}
+	videosByID := make(map[uint]*entities.Video, len(videos))
+	for _, video := range videos {
+		videosByID[video.ID] = video
+	}
+	_, err = db.WithTx(svc.unitOfWork, func(tx db.UnitOfWorkTx) (any, error) {
+		lastWatchDates := make(map[participantKey]time.Time)
+		for _, key := range keys {
+			video := videosByID[key.videoID]
+			// the video was deleted while its events were buffered
+			if video == nil || video.CourseId == nil {
+				continue
+			}
+			videoEvents := events[key]
+			sort.SliceStable(videoEvents, func(i, j int) bool {
+				return videoEvents[i].ReportedAt.Before(videoEvents[j].ReportedAt)
+			})
+			progress, err := tx.VideoProgressRepo().GetOne(map[string]any{"user_id": key.userID, "video_id": key.videoID}, nil)
+			if err != nil {
+				return nil, types.NewServerError("Error in fetching video progress", operationName, err)
+			}
+			if progress == nil {
+				progress = &entities.VideoProgress{
+					UserID:   key.userID,
+					VideoID:  key.videoID,
+					CourseID: *video.CourseId,
+				}
+				applyWatchProgress(progress, video, videoEvents)
+				if err := tx.VideoProgressRepo().Create(progress); err != nil {
+					return nil, types.NewServerError("Error in creating video progress", operationName, err)
+				}
+			} else {
+				applyWatchProgress(progress, video, videoEvents)
+				if err := tx.VideoProgressRepo().Update(progress); err != nil {
+					return nil, types.NewServerError("Error in updating video progress", operationName, err)
+				}
+			}
+			participant := participantKey{courseID: *video.CourseId, studentID: key.userID}
+			if watchedAt := videoEvents[len(videoEvents)-1].ReportedAt; watchedAt.After(lastWatchDates[participant]) {
+				lastWatchDates[participant] = watchedAt
+			}
+		}
+		for participant, watchedAt := range lastWatchDates {
+			if err := tx.CourseParticipantRepo().UpdateLastVideoWatchDate(participant.courseID, participant.studentID, watchedAt); err != nil {
+				return nil, types.NewServerError("Error in updating last video watch date", operationName, err)
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// applyWatchProgress merges the events into the progress, watched time is the union of the
+// ranges so seeking back and watching again doesn't count twice
+func applyWatchProgress(progress *entities.VideoProgress, video *entities.Video, events []bufferedWatchProgress) {
+	var duration float64
+	if video.Duration != nil {
+		duration, _ = utils.ClockToSeconds(*video.Duration)
+	}
+	watchedRanges := progress.WatchedRanges
+	for _, event := range events {
+		watchedRanges = append(watchedRanges, event.WatchedRanges...)
+	}
+	progress.WatchedRanges = mergeWatchedRanges(watchedRanges, duration)
+	var watchedSeconds float64
+	for _, watchedRange := range progress.WatchedRanges {
+		watchedSeconds += watchedRange.End - watchedRange.Start
+	}
+	progress.WatchedSeconds = uint(math.Round(watchedSeconds))
+	lastEvent := events[len(events)-1]
+	position := lastEvent.Position
+	if duration > 0 {
+		position = min(position, duration)
+		if !progress.IsCompleted && watchedSeconds >= duration*completionThreshold {
+			progress.IsCompleted = true
+			progress.CompletedAt = &lastEvent.ReportedAt
+		}
+		if position >= duration-resumeEndMargin {
+			position = 0
+		}
+	}
+	progress.LastPosition = uint(position)
+}
+
+// mergeWatchedRanges sorts the ranges and joins the overlapping ones, ranges past the end of
+// the video are cut since players report positions slightly after it
+func mergeWatchedRanges(watchedRanges []entities.WatchedRange, duration float64) []entities.WatchedRange {
+	ranges := make([]entities.WatchedRange, 0, len(watchedRanges))
+	for _, watchedRange := range watchedRanges {
+		if duration > 0 {
+			watchedRange.End = min(watchedRange.End, duration)
+		}
+		watchedRange.Start = max(watchedRange.Start, 0)
+		if watchedRange.End > watchedRange.Start {
+			ranges = append(ranges, watchedRange)
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+	merged := make([]entities.WatchedRange, 0, len(ranges))
+	for _, watchedRange := range ranges {
+		last := len(merged) - 1
+		if last >= 0 && watchedRange.Start <= merged[last].End {
+			merged[last].End = max(merged[last].End, watchedRange.End)
+			continue
+		}
+		merged = append(merged, watchedRange)
+	}
+	return merged
+}
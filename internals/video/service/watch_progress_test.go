@@ -0,0 +1,159 @@
+package service
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMergeWatchedRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		ranges   []entities.WatchedRange
+		duration float64
+		expected []entities.WatchedRange
+	}{
+		{
+			name:     "empty",
+			expected: []entities.WatchedRange{},
+		},
+		{
+			name:     "overlapping and unsorted ranges are joined",
+			ranges:   []entities.WatchedRange{{Start: 30, End: 50}, {Start: 0, End: 10}, {Start: 5, End: 20}, {Start: 45, End: 60}},
+			expected: []entities.WatchedRange{{Start: 0, End: 20}, {Start: 30, End: 60}},
+		},
+		{
+			name:     "touching ranges are joined",
+			ranges:   []entities.WatchedRange{{Start: 0, End: 10}, {Start: 10, End: 20}},
+			expected: []entities.WatchedRange{{Start: 0, End: 20}},
+		},
+		{
+			name:     "contained range is dropped",
+			ranges:   []entities.WatchedRange{{Start: 0, End: 100}, {Start: 20, End: 30}},
+			expected: []entities.WatchedRange{{Start: 0, End: 100}},
+		},
+		{
+			name:     "ranges are cut at the end of the video",
+			ranges:   []entities.WatchedRange{{Start: 50, End: 65}, {Start: 70, End: 80}},
+			duration: 60,
+			expected: []entities.WatchedRange{{Start: 50, End: 60}},
+		},
+		{
+			name:     "negative starts and empty ranges are dropped",
+			ranges:   []entities.WatchedRange{{Start: -5, End: 5}, {Start: 20, End: 20}, {Start: 30, End: 25}},
+			expected: []entities.WatchedRange{{Start: 0, End: 5}},
+		},
+		{
+			name:     "unknown duration keeps long ranges",
+			ranges:   []entities.WatchedRange{{Start: 0, End: 5000}},
+			expected: []entities.WatchedRange{{Start: 0, End: 5000}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := mergeWatchedRanges(test.ranges, test.duration)
+			if !slices.Equal(merged, test.expected) {
+				t.Errorf("got %v, want %v", merged, test.expected)
+			}
+		})
+	}
+}
+
+func TestApplyWatchProgress(t *testing.T) {
+	reportedAt := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	completedAt := reportedAt.Add(-time.Hour)
+	tests := []struct {
+		name              string
+		duration          string
+		progress          entities.VideoProgress
+		events            []bufferedWatchProgress
+		expectedSeconds   uint
+		expectedPosition  uint
+		expectedCompleted bool
+		expectedAt        *time.Time
+	}{
+		{
+			name:     "partial watch resumes at the last position",
+			duration: "00:10:00",
+			events: []bufferedWatchProgress{
+				{Position: 60, WatchedRanges: []entities.WatchedRange{{Start: 0, End: 60}}, ReportedAt: reportedAt},
+				{Position: 120, WatchedRanges: []entities.WatchedRange{{Start: 60, End: 120}}, ReportedAt: reportedAt},
+			},
+			expectedSeconds:  120,
+			expectedPosition: 120,
+		},
+		{
+			name:     "rewatching the same part doesn't count twice",
+			duration: "00:10:00",
+			progress: entities.VideoProgress{WatchedRanges: []entities.WatchedRange{{Start: 0, End: 100}}},
+			events: []bufferedWatchProgress{
+				{Position: 80, WatchedRanges: []entities.WatchedRange{{Start: 20, End: 80}}, ReportedAt: reportedAt},
+			},
+			expectedSeconds:  100,
+			expectedPosition: 80,
+		},
+		{
+			name:     "watching most of the video completes it and restarts near the end",
+			duration: "00:01:40",
+			events: []bufferedWatchProgress{
+				{Position: 95, WatchedRanges: []entities.WatchedRange{{Start: 0, End: 95}}, ReportedAt: reportedAt},
+			},
+			expectedSeconds:   95,
+			expectedPosition:  0,
+			expectedCompleted: true,
+			expectedAt:        &reportedAt,
+		},
+		{
+			name:     "skipping to the end doesn't complete it",
+			duration: "00:01:40",
+			events: []bufferedWatchProgress{
+				{Position: 99, WatchedRanges: []entities.WatchedRange{{Start: 90, End: 99}}, ReportedAt: reportedAt},
+			},
+			expectedSeconds:  9,
+			expectedPosition: 0,
+		},
+		{
+			name:     "completion date is kept",
+			duration: "00:01:40",
+			progress: entities.VideoProgress{IsCompleted: true, CompletedAt: &completedAt},
+			events: []bufferedWatchProgress{
+				{Position: 95, WatchedRanges: []entities.WatchedRange{{Start: 0, End: 95}}, ReportedAt: reportedAt},
+			},
+			expectedSeconds:   95,
+			expectedPosition:  0,
+			expectedCompleted: true,
+			expectedAt:        &completedAt,
+		},
+		{
+			name: "unknown duration never completes",
+			events: []bufferedWatchProgress{
+				{Position: 500, WatchedRanges: []entities.WatchedRange{{Start: 0, End: 500}}, ReportedAt: reportedAt},
+			},
+			expectedSeconds:  500,
+			expectedPosition: 500,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			video := &entities.Video{}
+			if test.duration != "" {
+				video.Duration = &test.duration
+			}
+			progress := test.progress
+			applyWatchProgress(&progress, video, test.events)
+			if progress.WatchedSeconds != test.expectedSeconds {
+				t.Errorf("watched seconds = %d, want %d", progress.WatchedSeconds, test.expectedSeconds)
+			}
+			if progress.LastPosition != test.expectedPosition {
+				t.Errorf("last position = %d, want %d", progress.LastPosition, test.expectedPosition)
+			}
+			if progress.IsCompleted != test.expectedCompleted {
+				t.Errorf("completed = %v, want %v", progress.IsCompleted, test.expectedCompleted)
+			}
+			if test.expectedAt != nil && (progress.CompletedAt == nil || !progress.CompletedAt.Equal(*test.expectedAt)) {
+				t.Errorf("completed at = %v, want %v", progress.CompletedAt, test.expectedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	videoService "github.com/ladmakhi81/learnup/internals/video/service"
+	"github.com/ladmakhi81/learnup/internals/websocket"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"github.com/ladmakhi81/learnup/shared/types"
+	"time"
+)
+
+const (
+	VideoProcessingEventType = "video-processing"
+	progressPollInterval     = 3 * time.Second
+)
+
+type VideoProcessingEvent struct {
+	VideoID       uint                        `json:"videoId"`
+	CourseID      uint                        `json:"courseId"`
+	Step          videoService.ProcessingStep `json:"step"`
+	Percent       int                         `json:"percent"`
+	FailureReason *string                     `json:"failureReason,omitempty"`
+}
+
+type trackedVideo struct {
+	teacherID uint
+	lastEvent VideoProcessingEvent
+}
+
+type ProgressWatcher interface {
+	Watch(ctx context.Context)
+}
+
+// progressWatcher reads the heartbeats of processing workflows, the workers may run in
+// another process so the progress can't be pushed from the activities themselves
+type progressWatcher struct {
+	unitOfWork  db.UnitOfWork
+	temporalSvc contracts.Temporal
+	wsManager   *websocket.WsManager
+	logSvc      contracts.Log
+	videos      map[uint]*trackedVideo
+}
+
+func NewProgressWatcher(
+	unitOfWork db.UnitOfWork,
+	temporalSvc contracts.Temporal,
+	wsManager *websocket.WsManager,
+	logSvc contracts.Log,
+) ProgressWatcher {
+	return &progressWatcher{
+		unitOfWork:  unitOfWork,
+		temporalSvc: temporalSvc,
+		wsManager:   wsManager,
+		logSvc:      logSvc,
+		videos:      make(map[uint]*trackedVideo),
+	}
+}
+
+// Watch pushes processing progress of videos to their teachers until ctx is done
+func (watcher *progressWatcher) Watch(ctx context.Context) {
+	ticker := time.NewTicker(progressPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := watcher.poll(ctx); err != nil {
+				watcher.logSvc.Error(dtos.LogMessage{
+					Message: "Error in watching video processing progress",
+					Metadata: map[string]any{
+						"error": err.Error(),
+					},
+				})
+			}
+		}
+	}
+}
+
+func (watcher *progressWatcher) poll(ctx context.Context) error {
+	const operationName = "progressWatcher.poll"
+	videos, err := watcher.unitOfWork.VideoRepo().FetchProcessing()
+	if err != nil {
+		return types.NewServerError("Error in fetching processing videos", operationName, err)
+	}
+	pendingVideos := make(map[uint]bool, len(videos))
+	for _, video := range videos {
+		if video.Course == nil || video.Course.TeacherID == nil {
+			continue
+		}
+		pendingVideos[video.ID] = true
+		if !watcher.wsManager.IsConnected(*video.Course.TeacherID) {
+			continue
+		}
+		progress, err := watcher.temporalSvc.DescribeWorkflowProgress(ctx, VideoProcessingWorkflowID(video))
+		if err != nil {
+			return types.NewServerError("Error in describing video processing workflow", operationName, err)
+		}
+		if progress == nil || !progress.IsRunning {
+			continue
+		}
+		tracked, isTracked := watcher.videos[video.ID]
+		event := VideoProcessingEvent{
+			VideoID:  video.ID,
+			CourseID: video.Course.ID,
+			Step:     videoService.ProcessingStep_Queued,
+		}
+		if progress.IsStarted && progress.Progress != nil {
+			event.Step = videoService.ProcessingStep(progress.Progress.Step)
+			event.Percent = progress.Progress.Percent
+		} else if isTracked {
+			// between two activities the last step is kept instead of going back to queued
+			event = tracked.lastEvent
+		}
+		watcher.publish(*video.Course.TeacherID, event)
+	}
+	// videos that left processing since the last poll are either done or failed, a replaced
+	// video stays done while its new file is processed so the failure reason decides
+	for videoID, tracked := range watcher.videos {
+		if pendingVideos[videoID] {
+			continue
+		}
+		delete(watcher.videos, videoID)
+		video, err := watcher.unitOfWork.VideoRepo().GetByID(videoID, nil)
+		if err != nil {
+			return types.NewServerError("Error in fetching video by id", operationName, err)
+		}
+		if video == nil {
+			continue
+		}
+		event := VideoProcessingEvent{
+			VideoID:  video.ID,
+			CourseID: tracked.lastEvent.CourseID,
+		}
+		switch {
+		case video.FailureReason != nil:
+			event.Step = videoService.ProcessingStep_Failed
+			event.FailureReason = video.FailureReason
+		case video.Status == entities.VideoStatus_Done && video.SourceObjectID == nil:
+			event.Step = videoService.ProcessingStep_Done
+			event.Percent = 100
+		default:
+			continue
+		}
+		watcher.send(tracked.teacherID, event)
+	}
+	return nil
+}
+
+// publish only sends events that changed since the last one of the video
+func (watcher *progressWatcher) publish(teacherID uint, event VideoProcessingEvent) {
+	tracked, isTracked := watcher.videos[event.VideoID]
+	if isTracked && tracked.lastEvent == event {
+		return
+	}
+	watcher.videos[event.VideoID] = &trackedVideo{teacherID: teacherID, lastEvent: event}
+	watcher.send(teacherID, event)
+}
+
+func (watcher *progressWatcher) send(teacherID uint, event VideoProcessingEvent) {
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = watcher.wsManager.PublishMessage(teacherID, VideoProcessingEventType, data)
+	}
+	if err != nil {
+		watcher.logSvc.Error(dtos.LogMessage{
+			Message: "Error in publishing video processing progress",
+			Metadata: map[string]any{
+				"videoId": event.VideoID,
+				"error":   err.Error(),
+			},
+		})
+	}
+}
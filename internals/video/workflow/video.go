@@ -1,18 +1,34 @@
 package workflow
 
 import (
+	"errors"
+	"fmt"
 	courseDtoReq "github.com/ladmakhi81/learnup/internals/course/dto/req"
 	courseService "github.com/ladmakhi81/learnup/internals/course/service"
 	videoDtoReq "github.com/ladmakhi81/learnup/internals/video/dto/req"
 	videoService "github.com/ladmakhi81/learnup/internals/video/service"
 	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
 	videoEntity "github.com/ladmakhi81/learnup/shared/db/entities"
+	temporalSdk "go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+	"time"
 )
 
 type VideoWorkflow interface {
 	AddNewCourseVideoWorkflow(ctx workflow.Context, dto videoDtoReq.AddNewCourseVideoWorkflowReqDto) error
 	AddIntroductionVideoWorkflow(ctx workflow.Context, dto videoDtoReq.AddIntroductionVideoWorkflowReqDto) error
+	UpdateVideoThumbnailWorkflow(ctx workflow.Context, dto videoDtoReq.UpdateVideoThumbnailWorkflowReqDto) error
+	CleanupVideoStorageWorkflow(ctx workflow.Context, dto videoDtoReq.CleanupVideoStorageReqDto) error
+	SweepOrphanVideoObjectsWorkflow(ctx workflow.Context, dto videoDtoReq.SweepOrphanObjectsReqDto) error
+}
+
+// processingTaskOptions is used for ffmpeg steps, they heartbeat while running so a lost
+// worker is noticed within a minute instead of at the end of the timeout
+var processingTaskOptions = dtos.TaskOptions{
+	StartToCloseTimeout: 6 * time.Hour,
+	HeartbeatTimeout:    time.Minute,
+	MaximumAttempts:     3,
 }
 
 type VideoWorkflowImpl struct {
@@ -33,63 +49,152 @@ func NewVideoWorkflowImpl(
 	}
 }
 
+// ProcessCourseVideoWorkflowID is stable per video, processing a video again replaces the running workflow
+func ProcessCourseVideoWorkflowID(videoID uint) string {
+	return fmt.Sprintf("process-course-video-%d", videoID)
+}
+
+// ProcessUploadWorkflowID is derived from the tus upload, a hook delivered twice names the same workflow
+func ProcessUploadWorkflowID(uploadID string) string {
+	return fmt.Sprintf("process-upload-%s", uploadID)
+}
+
+// VideoProcessingWorkflowID is the workflow that last started processing the video
+func VideoProcessingWorkflowID(video *videoEntity.Video) string {
+	if video.ProcessingWorkflowID != nil && *video.ProcessingWorkflowID != "" {
+		return *video.ProcessingWorkflowID
+	}
+	return ProcessCourseVideoWorkflowID(video.ID)
+}
+
 func (svc VideoWorkflowImpl) AddNewCourseVideoWorkflow(ctx workflow.Context, dto videoDtoReq.AddNewCourseVideoWorkflowReqDto) error {
+	// remember the source for reprocessing, a replaced video gives back the files it played so far
+	var previousURL string
+	startProcessingDto := videoDtoReq.StartVideoProcessingReqDto{
+		VideoID:    dto.VideoID,
+		ObjectID:   dto.ObjectID,
+		WorkflowID: workflow.GetInfo(ctx).WorkflowExecution.ID,
+	}
+	startProcessingErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.StartProcessing, startProcessingDto, &previousURL)
+	if startProcessingErr != nil {
+		return startProcessingErr
+	}
+	video, processErr := svc.processCourseVideo(ctx, dto)
+	if processErr != nil {
+		// compensation, runs even when the workflow is cancelled
+		compensationCtx, _ := workflow.NewDisconnectedContext(ctx)
+		markFailedDto := videoDtoReq.MarkVideoFailedReqDto{
+			VideoID: dto.VideoID,
+			Reason:  failureReason(processErr),
+		}
+		markFailedErr := svc.temporalSvc.ExecuteTask(compensationCtx, svc.videoSvc.MarkFailed, markFailedDto, nil)
+		if markFailedErr != nil {
+			return markFailedErr
+		}
+		return processErr
+	}
+	// remove uploaded source
+	deleteSourceDto := videoDtoReq.DeleteVideoSourceReqDto{
+		ObjectId: dto.ObjectID,
+	}
+	deleteSourceErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.DeleteSource, deleteSourceDto, nil)
+	if deleteSourceErr != nil {
+		return deleteSourceErr
+	}
+	// remove the replaced files once the new ones are served
+	if previousURL != "" && previousURL != video.URL {
+		cleanupDto := videoDtoReq.CleanupVideoStorageReqDto{
+			Prefixes: []string{previousURL},
+		}
+		cleanupErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.CleanupStorage, cleanupDto, nil)
+		if cleanupErr != nil {
+			return cleanupErr
+		}
+	}
+	// teacher notification
+	teacherNotificationErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.CreateCompleteUploadVideoNotification, video.ID, nil)
+	if teacherNotificationErr != nil {
+		return teacherNotificationErr
+	}
+	return nil
+}
+
+// processCourseVideo runs every step that leaves the video unplayable when it fails
+func (svc VideoWorkflowImpl) processCourseVideo(ctx workflow.Context, dto videoDtoReq.AddNewCourseVideoWorkflowReqDto) (*videoEntity.Video, error) {
 	// calculate duration
 	calculateDurationDto := videoDtoReq.CalculateVideoDurationReqDto{
 		ObjectId: dto.ObjectID,
 	}
 	var videoDuration string
-	calculateDurationErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.CalculateDuration, calculateDurationDto, &videoDuration)
+	calculateDurationErr := svc.temporalSvc.ExecuteTaskWithOptions(ctx, processingTaskOptions, svc.videoSvc.CalculateDuration, calculateDurationDto, &videoDuration)
 	if calculateDurationErr != nil {
-		return calculateDurationErr
+		return nil, calculateDurationErr
 	}
 	// encode
-	var videoURL string
+	var encodeResult videoService.EncodeResult
 	encodeVideoDto := videoDtoReq.EncodeVideoReqDto{
 		ObjectId: dto.ObjectID,
+		Encrypt:  true,
+		VideoID:  dto.VideoID,
 	}
-	encodeErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.Encode, encodeVideoDto, &videoURL)
+	encodeErr := svc.temporalSvc.ExecuteTaskWithOptions(ctx, processingTaskOptions, svc.videoSvc.Encode, encodeVideoDto, &encodeResult)
 	if encodeErr != nil {
-		return encodeErr
+		return nil, encodeErr
+	}
+	// poster and seek preview sprite
+	var thumbnailURL string
+	thumbnailsDto := videoDtoReq.GenerateVideoThumbnailsReqDto{
+		ObjectId: dto.ObjectID,
+		VideoID:  dto.VideoID,
+		URL:      encodeResult.URL,
+	}
+	thumbnailsErr := svc.temporalSvc.ExecuteTaskWithOptions(ctx, processingTaskOptions, svc.videoSvc.GenerateThumbnails, thumbnailsDto, &thumbnailURL)
+	if thumbnailsErr != nil {
+		return nil, thumbnailsErr
 	}
 	// update url and duration
 	var video *videoEntity.Video
 	updateVideoDto := videoDtoReq.UpdateURLAndDurationVideoReqDto{
-		Duration: videoDuration,
-		URL:      videoURL,
-		ID:       dto.VideoID,
+		Duration:      videoDuration,
+		URL:           encodeResult.URL,
+		ThumbnailURL:  thumbnailURL,
+		WatermarkMode: encodeResult.WatermarkMode,
+		ID:            dto.VideoID,
 	}
 	updateErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.UpdateURLAndDuration, updateVideoDto, &video)
 	if updateErr != nil {
-		return updateErr
+		return nil, updateErr
 	}
-	// teacher notification
-	teacherNotificationErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.CreateCompleteUploadVideoNotification, video.ID, nil)
-	if teacherNotificationErr != nil {
-		return teacherNotificationErr
-	}
-	return nil
+	return video, nil
 }
 
 func (svc VideoWorkflowImpl) AddIntroductionVideoWorkflow(ctx workflow.Context, dto videoDtoReq.AddIntroductionVideoWorkflowReqDto) error {
 	// encode
-	var videoURL string
+	var encodeResult videoService.EncodeResult
 	encodeVideoDto := videoDtoReq.EncodeVideoReqDto{
 		ObjectId: dto.ObjectId,
 	}
-	encodeErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.Encode, encodeVideoDto, &videoURL)
+	encodeErr := svc.temporalSvc.ExecuteTaskWithOptions(ctx, processingTaskOptions, svc.videoSvc.Encode, encodeVideoDto, &encodeResult)
 	if encodeErr != nil {
 		return encodeErr
 	}
 	// update video introduction url
 	updateIntroductionUrlDto := courseDtoReq.UpdateIntroductionURLReqDto{
-		URL:      videoURL,
+		URL:      encodeResult.URL,
 		CourseId: dto.CourseId,
 	}
 	updateErr := svc.temporalSvc.ExecuteTask(ctx, svc.courseSvc.UpdateIntroductionURL, updateIntroductionUrlDto, nil)
 	if updateErr != nil {
 		return updateErr
 	}
+	// remove uploaded source
+	deleteSourceDto := videoDtoReq.DeleteVideoSourceReqDto{
+		ObjectId: dto.ObjectId,
+	}
+	deleteSourceErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.DeleteSource, deleteSourceDto, nil)
+	if deleteSourceErr != nil {
+		return deleteSourceErr
+	}
 	// create notification
 	notificationErr := svc.temporalSvc.ExecuteTask(ctx, svc.courseSvc.CreateCompleteIntroductionVideoNotification, dto.CourseId, nil)
 	if notificationErr != nil {
@@ -97,3 +202,59 @@ func (svc VideoWorkflowImpl) AddIntroductionVideoWorkflow(ctx workflow.Context,
 	}
 	return nil
 }
+
+// UpdateVideoThumbnailWorkflowID is stable per video, picking another frame
+// replaces the running workflow instead of racing with it
+func UpdateVideoThumbnailWorkflowID(videoID uint) string {
+	return fmt.Sprintf("update-video-thumbnail-%d", videoID)
+}
+
+func (svc VideoWorkflowImpl) UpdateVideoThumbnailWorkflow(ctx workflow.Context, dto videoDtoReq.UpdateVideoThumbnailWorkflowReqDto) error {
+	// cut the chosen frame from encoded segments
+	regenerateErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.RegenerateThumbnail, dto.VideoID, nil)
+	if regenerateErr != nil {
+		return regenerateErr
+	}
+	return nil
+}
+
+// CleanupVideoStorageWorkflowID is stable per video, deleting a video cleans it up only once
+func CleanupVideoStorageWorkflowID(videoID uint) string {
+	return fmt.Sprintf("cleanup-video-storage-%d", videoID)
+}
+
+// SweepOrphanVideoObjectsWorkflowID is fixed, there is a single cron schedule for the sweep
+const SweepOrphanVideoObjectsWorkflowID = "sweep-orphan-video-objects"
+
+func (svc VideoWorkflowImpl) CleanupVideoStorageWorkflow(ctx workflow.Context, dto videoDtoReq.CleanupVideoStorageReqDto) error {
+	// delete files nothing references anymore
+	cleanupErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.CleanupStorage, dto, nil)
+	if cleanupErr != nil {
+		return cleanupErr
+	}
+	return nil
+}
+
+func (svc VideoWorkflowImpl) SweepOrphanVideoObjectsWorkflow(ctx workflow.Context, dto videoDtoReq.SweepOrphanObjectsReqDto) error {
+	// delete abandoned uploads and files cleanup missed
+	var deletedCount int
+	sweepErr := svc.temporalSvc.ExecuteTask(ctx, svc.videoSvc.SweepOrphans, dto, &deletedCount)
+	if sweepErr != nil {
+		return sweepErr
+	}
+	workflow.GetLogger(ctx).Info("orphan video objects swept", "count", deletedCount)
+	return nil
+}
+
+// failureReason keeps the message of the failing activity without the temporal wrapping
+func failureReason(err error) string {
+	var applicationErr *temporalSdk.ApplicationError
+	if errors.As(err, &applicationErr) {
+		return applicationErr.Message()
+	}
+	var timeoutErr *temporalSdk.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return "processing timed out"
+	}
+	return err.Error()
+}
@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	enumspb "go.temporal.io/api/enums/v1"
+	temporalSdk "go.temporal.io/sdk/temporal"
+	"testing"
+)
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "activity error keeps only its message",
+			err:      temporalSdk.NewApplicationError("moov atom not found", "EncodeError"),
+			expected: "moov atom not found",
+		},
+		{
+			name:     "wrapped activity error",
+			err:      fmt.Errorf("encode: %w", temporalSdk.NewApplicationError("disk full", "")),
+			expected: "disk full",
+		},
+		{
+			name:     "timeout hides the timer details",
+			err:      temporalSdk.NewTimeoutError(enumspb.TIMEOUT_TYPE_HEARTBEAT, nil),
+			expected: "processing timed out",
+		},
+		{
+			name:     "anything else keeps its text",
+			err:      errors.New("workflow canceled"),
+			expected: "workflow canceled",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if reason := failureReason(test.err); reason != test.expected {
+				t.Errorf("failureReason = %q, want %q", reason, test.expected)
+			}
+		})
+	}
+}
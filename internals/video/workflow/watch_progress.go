@@ -0,0 +1,40 @@
+package workflow
+
+import (
+	videoDtoReq "github.com/ladmakhi81/learnup/internals/video/dto/req"
+	videoService "github.com/ladmakhi81/learnup/internals/video/service"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"go.temporal.io/sdk/workflow"
+)
+
+const FlushWatchProgressWorkflowID = "flush-watch-progress"
+
+type WatchProgressWorkflow interface {
+	FlushWatchProgressWorkflow(ctx workflow.Context, dto videoDtoReq.FlushWatchProgressReqDto) error
+}
+
+type WatchProgressWorkflowImpl struct {
+	watchProgressSvc videoService.WatchProgressService
+	temporalSvc      contracts.Temporal
+}
+
+func NewWatchProgressWorkflowImpl(
+	watchProgressSvc videoService.WatchProgressService,
+	temporalSvc contracts.Temporal,
+) *WatchProgressWorkflowImpl {
+	return &WatchProgressWorkflowImpl{
+		watchProgressSvc: watchProgressSvc,
+		temporalSvc:      temporalSvc,
+	}
+}
+
+func (svc WatchProgressWorkflowImpl) FlushWatchProgressWorkflow(ctx workflow.Context, dto videoDtoReq.FlushWatchProgressReqDto) error {
+	// move the progress players reported since the last run from redis into the database
+	var flushedCount int
+	flushErr := svc.temporalSvc.ExecuteTask(ctx, svc.watchProgressSvc.Flush, dto, &flushedCount)
+	if flushErr != nil {
+		return flushErr
+	}
+	workflow.GetLogger(ctx).Info("watch progress flushed", "count", flushedCount)
+	return nil
+}
@@ -34,7 +34,7 @@ func Handler(manager *WsManager, tokenSvc contracts.Token) gin.HandlerFunc {
 		}
 		manager.AddConnection(claim.UserID, conn)
 		defer func() {
-			manager.RemoveConnection(1)
+			manager.RemoveConnection(claim.UserID)
 			conn.Close()
 		}()
 
@@ -29,10 +29,21 @@ func (manager *WsManager) RemoveConnection(userID uint) {
 	delete(manager.connections, userID)
 }
 
+func (manager *WsManager) IsConnected(userID uint) bool {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	_, ok := manager.connections[userID]
+	return ok
+}
+
+// PublishMessage drops the message when the user has no open connection
 func (manager *WsManager) PublishMessage(userID uint, eventType string, data json.RawMessage) error {
 	manager.mu.Lock()
 	defer manager.mu.Unlock()
-	conn := manager.connections[userID]
+	conn, ok := manager.connections[userID]
+	if !ok {
+		return nil
+	}
 	payload := NewWsMessagePayload(eventType, data)
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -0,0 +1,91 @@
+package clamavv1
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// clamd refuses streams above its StreamMaxLength, chunks stay well below any sane setting
+	streamChunkSize = 64 << 10
+	scanTimeout     = 2 * time.Minute
+)
+
+// ClamavSvc talks to clamd over its INSTREAM command, no file has to be shared with the daemon
+type ClamavSvc struct {
+	address string
+}
+
+func NewClamavSvc(config *dtos.EnvConfig) *ClamavSvc {
+	return &ClamavSvc{
+		address: config.Resource.ClamavAddress,
+	}
+}
+
+func (svc ClamavSvc) Scan(ctx context.Context, content []byte) (*dtos.VirusScanResult, error) {
+	const location = "ClamavSvc.Scan"
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", svc.address)
+	if err != nil {
+		return nil, dtos.NewVirusScanError("Error: happen in connecting to clamd: "+err.Error(), location)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(scanTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, dtos.NewVirusScanError("Error: happen in setting clamd deadline: "+err.Error(), location)
+	}
+	if err := writeStream(conn, content); err != nil {
+		return nil, dtos.NewVirusScanError("Error: happen in streaming file to clamd: "+err.Error(), location)
+	}
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return nil, dtos.NewVirusScanError("Error: happen in reading clamd reply: "+err.Error(), location)
+	}
+	return parseReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+func writeStream(conn net.Conn, content []byte) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+	size := make([]byte, 4)
+	for offset := 0; offset < len(content); offset += streamChunkSize {
+		chunk := content[offset:min(offset+streamChunkSize, len(content))]
+		binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+		if _, err := conn.Write(size); err != nil {
+			return err
+		}
+		if _, err := conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	// a zero length chunk ends the stream
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parseReply reads "stream: OK" or "stream: <signature> FOUND", anything else is a clamd error
+func parseReply(reply string) (*dtos.VirusScanResult, error) {
+	const location = "ClamavSvc.parseReply"
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return &dtos.VirusScanResult{Scanned: true}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &dtos.VirusScanResult{
+			Scanned:   true,
+			Infected:  true,
+			Signature: strings.TrimSuffix(result, " FOUND"),
+		}, nil
+	default:
+		return nil, dtos.NewVirusScanError("Error: clamd could not scan the file: "+reply, location)
+	}
+}
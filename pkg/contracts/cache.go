@@ -1,8 +1,13 @@
 package contracts
 
+import "time"
+
 type Cache interface {
 	SetVal(key string, val any) error
 	SetHashVal(key, id string, val any) error
 	GetHashVal(key, id string) (string, error)
 	GetVal(key string) (string, error)
+	Increment(key string, ttl time.Duration) (int64, error)
+	PushList(key string, values ...any) error
+	PopList(key string, count int64) ([]string, error)
 }
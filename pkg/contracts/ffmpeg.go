@@ -1,8 +0,0 @@
-package contracts
-
-import "io"
-
-type Ffmpeg interface {
-	EncodeVideo(videoReader io.Reader) (string, error)
-	GetVideoDuration(videoReader io.Reader) (string, error)
-}
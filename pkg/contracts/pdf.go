@@ -0,0 +1,7 @@
+package contracts
+
+import "github.com/ladmakhi81/learnup/pkg/dtos"
+
+type Pdf interface {
+	GenerateDocument(document dtos.PdfDocument) ([]byte, error)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/ladmakhi81/learnup/pkg/dtos"
 	"io"
+	"time"
 )
 
 type Storage interface {
@@ -11,7 +12,12 @@ type Storage interface {
 	CreateBucket(ctx context.Context, bucketName string) error
 	DeleteBucket(ctx context.Context, bucketName string) error
 	UploadFileByContent(ctx context.Context, bucketName string, objectPath string, contentType string, fileContents []byte) (*dtos.UploadResult, error)
+	PutObject(ctx context.Context, bucketName string, objectPath string, contentType string, reader io.Reader, size int64) (*dtos.UploadResult, error)
 	GetFile(ctx context.Context, bucketName string, fileName string) ([]byte, error)
 	GetFileReader(ctx context.Context, bucketName string, fileName string) (io.Reader, error)
 	DeleteObject(ctx context.Context, bucketName string, objectId string) error
+	DeleteObjectsByPrefix(ctx context.Context, bucketName string, prefix string) error
+	ListObjects(ctx context.Context, bucketName string, prefix string, recursive bool) ([]dtos.StorageObject, error)
+	GetPresignedURL(ctx context.Context, bucketName string, objectPath string, expires time.Duration) (string, error)
+	GetPresignedDownloadURL(ctx context.Context, bucketName string, objectPath string, fileName string, expires time.Duration) (string, error)
 }
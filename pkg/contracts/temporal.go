@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"context"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
 	"go.temporal.io/sdk/workflow"
 )
 
@@ -9,5 +10,12 @@ type Temporal interface {
 	Init() error
 	AddWorker(queueName string, workflowFn any, activitiesFn ...any) error
 	ExecuteWorker(ctx context.Context, queueName string, workflowFn any, data any) error
+	ExecuteWorkerWithID(ctx context.Context, queueName string, workflowID string, workflowFn any, data any) error
+	ExecuteWorkerOnce(ctx context.Context, queueName string, workflowID string, workflowFn any, data any) (bool, error)
+	ScheduleWorker(ctx context.Context, queueName string, workflowID string, cronSchedule string, workflowFn any, data any) error
+	CancelWorker(ctx context.Context, workflowID string) error
 	ExecuteTask(ctx workflow.Context, activityFn any, data any, result any) error
+	ExecuteTaskWithOptions(ctx workflow.Context, options dtos.TaskOptions, activityFn any, data any, result any) error
+	RecordHeartbeat(ctx context.Context, details ...any)
+	DescribeWorkflowProgress(ctx context.Context, workflowID string) (*dtos.WorkflowProgress, error)
 }
@@ -0,0 +1,15 @@
+package contracts
+
+import (
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+)
+
+// Transcoder turns uploaded sources into a streamable tree following the configured
+// profile. Sources are anything ffmpeg opens, usually a presigned url read over http,
+// so only the encoded output ever lands in the scratch directory
+type Transcoder interface {
+	EncodeVideo(source string, options dtos.EncodeOptions) (string, error)
+	GetVideoDuration(source string) (string, error)
+	ExtractFrame(source string, timestamp float64) ([]byte, error)
+	GenerateThumbnailSprite(source string) (string, error)
+}
@@ -0,0 +1,10 @@
+package contracts
+
+import (
+	"context"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+)
+
+type VirusScanner interface {
+	Scan(ctx context.Context, content []byte) (*dtos.VirusScanResult, error)
+}
@@ -5,6 +5,8 @@ type MinioEnvConfig struct {
 	AccessKey string `koanf:"access_key"`
 	SecretKey string `koanf:"secret_key"`
 	Region    string `koanf:"region"`
+	// PublicURL is the host browsers reach minio by, presigned urls are signed for it
+	PublicURL string `koanf:"public_url"`
 }
 
 type RedisEnvConfig struct {
@@ -54,14 +56,81 @@ type StripeEnvConfig struct {
 	CallbackURL string `koanf:"callback_url"`
 }
 
+type VideoEnvConfig struct {
+	// comma separated ladder, a preset name (360p) or name:height:video_bitrate:audio_bitrate
+	Renditions string `koanf:"renditions"`
+	// seconds a playback token stays valid
+	PlaybackTTL int `koanf:"playback_ttl"`
+	// secret hls keys are encrypted with before being stored
+	KeyEncryptionSecret string `koanf:"key_encryption_secret"`
+	// key requests allowed per playback session in a minute
+	KeyRateLimit int `koanf:"key_rate_limit"`
+	// watermark of courses with watermarking enabled, the logo is a png path on the worker
+	WatermarkText string `koanf:"watermark_text"`
+	WatermarkLogo string `koanf:"watermark_logo"`
+	WatermarkFont string `koanf:"watermark_font"`
+	// cron of the sweeper removing objects no row references, and hours an object is left alone after upload
+	OrphanSweepCron   string `koanf:"orphan_sweep_cron"`
+	OrphanGracePeriod int    `koanf:"orphan_grace_period"`
+	// directory encodes and thumbnails are written into, and encoded files uploaded at once
+	ScratchDir        string `koanf:"scratch_dir"`
+	UploadConcurrency int    `koanf:"upload_concurrency"`
+	// megabytes a single tus upload may declare, checked before any byte is stored
+	MaxUploadSize int `koanf:"max_upload_size"`
+	// secret tusd sends along every hook, a hook without it is rejected
+	UploadHookSecret string `koanf:"upload_hook_secret"`
+	// cron of moving buffered watch progress into the database, and events moved per batch
+	ProgressFlushCron      string `koanf:"progress_flush_cron"`
+	ProgressFlushBatchSize int    `koanf:"progress_flush_batch_size"`
+}
+
+// TranscoderEnvConfig is the declarative encoding profile, empty values keep the defaults
+type TranscoderEnvConfig struct {
+	// ffmpeg runs the local binary, fake writes a placeholder tree without transcoding
+	Backend string `koanf:"backend"`
+	// hls or cmaf
+	Container     string `koanf:"container"`
+	VideoCodec    string `koanf:"video_codec"`
+	AudioCodec    string `koanf:"audio_codec"`
+	Preset        string `koanf:"preset"`
+	CodecProfile  string `koanf:"codec_profile"`
+	SegmentLength int    `koanf:"segment_length"`
+	AudioChannels int    `koanf:"audio_channels"`
+}
+
+type PdfEnvConfig struct {
+	// ttf font exported documents are written with, non latin text needs one
+	FontPath string `koanf:"font_path"`
+}
+
+type ResourceEnvConfig struct {
+	// megabytes a single lesson resource may have, and seconds a download url stays valid
+	MaxUploadSize int `koanf:"max_upload_size"`
+	DownloadTTL   int `koanf:"download_ttl"`
+	// none stores files unscanned, clamav streams every upload to the clamd address first
+	ScannerBackend string `koanf:"scanner_backend"`
+	ClamavAddress  string `koanf:"clamav_address"`
+}
+
+type AssignmentEnvConfig struct {
+	// megabytes a single submitted file may have, and seconds a submission download url stays valid
+	MaxSubmissionSize int `koanf:"max_submission_size"`
+	DownloadTTL       int `koanf:"download_ttl"`
+}
+
 type EnvConfig struct {
-	Minio    MinioEnvConfig    `koanf:"minio"`
-	Redis    RedisEnvConfig    `koanf:"redis"`
-	MainDB   MainDBEnvConfig   `koanf:"main_db"`
-	Smtp     SmtpEnvConfig     `koanf:"smtp"`
-	App      AppEnvConfig      `koanf:"app"`
-	Temporal TemporalEnvConfig `koanf:"temporal"`
-	Zarinpal ZarinpalEnvConfig `koanf:"zarinpal"`
-	Zibal    ZibalEnvConfig    `koanf:"zibal"`
-	Stripe   StripeEnvConfig   `koanf:"stripe"`
+	Minio      MinioEnvConfig      `koanf:"minio"`
+	Redis      RedisEnvConfig      `koanf:"redis"`
+	MainDB     MainDBEnvConfig     `koanf:"main_db"`
+	Smtp       SmtpEnvConfig       `koanf:"smtp"`
+	App        AppEnvConfig        `koanf:"app"`
+	Temporal   TemporalEnvConfig   `koanf:"temporal"`
+	Zarinpal   ZarinpalEnvConfig   `koanf:"zarinpal"`
+	Zibal      ZibalEnvConfig      `koanf:"zibal"`
+	Stripe     StripeEnvConfig     `koanf:"stripe"`
+	Video      VideoEnvConfig      `koanf:"video"`
+	Transcoder TranscoderEnvConfig `koanf:"transcoder"`
+	Pdf        PdfEnvConfig        `koanf:"pdf"`
+	Resource   ResourceEnvConfig   `koanf:"resource"`
+	Assignment AssignmentEnvConfig `koanf:"assignment"`
 }
@@ -0,0 +1,28 @@
+package dtos
+
+type PdfError struct {
+	Message  string
+	Location string
+}
+
+func (e PdfError) Error() string {
+	return e.Message
+}
+
+func NewPdfError(message string, location string) *PdfError {
+	return &PdfError{
+		Message:  message,
+		Location: location,
+	}
+}
+
+// PdfDocument is a plain text document, sections start with a heading followed by their paragraphs
+type PdfDocument struct {
+	Title    string
+	Sections []PdfSection
+}
+
+type PdfSection struct {
+	Heading    string
+	Paragraphs []string
+}
@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 type StorageError struct {
 	Message  string
 	Location string
@@ -27,3 +29,11 @@ func NewUploadResult(objectID string, size int64) *UploadResult {
 		Size:     size,
 	}
 }
+
+// StorageObject is a listed object, IsPrefix marks a directory of a non recursive listing
+type StorageObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	IsPrefix     bool
+}
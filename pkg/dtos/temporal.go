@@ -0,0 +1,26 @@
+package dtos
+
+import "time"
+
+// TaskOptions tunes a single activity call, zero values fall back to the defaults of ExecuteTask
+type TaskOptions struct {
+	StartToCloseTimeout time.Duration
+	HeartbeatTimeout    time.Duration
+	MaximumAttempts     int32
+	InitialInterval     time.Duration
+	MaximumInterval     time.Duration
+}
+
+// TaskProgress is sent as heartbeat details, so whoever watches the workflow can read it
+type TaskProgress struct {
+	Step    string
+	Percent int
+}
+
+// WorkflowProgress describes the activity a running workflow is waiting on
+type WorkflowProgress struct {
+	IsRunning bool
+	IsStarted bool
+	Activity  string
+	Progress  *TaskProgress
+}
@@ -0,0 +1,73 @@
+package dtos
+
+type TranscodeContainer string
+
+const (
+	// TranscodeContainer_HLS writes mpeg-ts segments
+	TranscodeContainer_HLS TranscodeContainer = "hls"
+	// TranscodeContainer_CMAF writes fragmented mp4 segments with an init segment per rendition,
+	// the same segments can be described by a dash manifest
+	TranscodeContainer_CMAF TranscodeContainer = "cmaf"
+)
+
+// TranscodeProfile is what every encode produces, backends translate it into their own arguments
+type TranscodeProfile struct {
+	Container     TranscodeContainer
+	VideoCodec    string
+	AudioCodec    string
+	Preset        string
+	CodecProfile  string
+	SegmentLength int
+	AudioChannels int
+	Renditions    []VideoRendition
+}
+
+// SegmentExtension is the extension of media segments, playlists and init segments are not segments
+func (profile TranscodeProfile) SegmentExtension() string {
+	if profile.Container == TranscodeContainer_CMAF {
+		return ".m4s"
+	}
+	return ".ts"
+}
+
+type VideoRendition struct {
+	Name         string
+	Height       int
+	VideoBitrate string
+	MaxRate      string
+	BufSize      string
+	AudioBitrate string
+}
+
+// HlsEncryption makes the encoder write aes-128 encrypted segments, KeyURI is written
+// as is into #EXT-X-KEY and resolved later by whoever serves the playlist
+type HlsEncryption struct {
+	Key    []byte
+	KeyURI string
+}
+
+// WatermarkVariantDir holds the b copy of the hls tree when a/b variants are encoded
+const WatermarkVariantDir = "variant-b"
+
+type VideoWatermark struct {
+	Text     string
+	LogoPath string
+	FontPath string
+	// ABVariants encodes a second, slightly different marked tree to fingerprint viewers
+	ABVariants bool
+}
+
+type EncodeOptions struct {
+	Encryption *HlsEncryption
+	Watermark  *VideoWatermark
+	// OnProgress receives the overall percentage, it is called from the goroutine reading ffmpeg output
+	OnProgress func(percent int)
+	// OnSegment receives every segment once it is complete while the encode is still running, the
+	// encoder never reads a finished segment again so the callback may remove it, an error stops the encode
+	OnSegment func(segment EncodedSegment) error
+}
+
+type EncodedSegment struct {
+	Path         string
+	RelativePath string
+}
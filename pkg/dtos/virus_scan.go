@@ -0,0 +1,24 @@
+package dtos
+
+type VirusScanError struct {
+	Message  string
+	Location string
+}
+
+func (e VirusScanError) Error() string {
+	return e.Message
+}
+
+func NewVirusScanError(message string, location string) *VirusScanError {
+	return &VirusScanError{
+		Message:  message,
+		Location: location,
+	}
+}
+
+// VirusScanResult reports Scanned false when no scanner looked at the file, Signature names what was found
+type VirusScanResult struct {
+	Scanned   bool
+	Infected  bool
+	Signature string
+}
@@ -0,0 +1,63 @@
+package ffmpegv1
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// progressWriter reads the key=value report ffmpeg writes with -progress and turns
+// the encoded time into a percentage of the source duration
+type progressWriter struct {
+	duration   float64
+	onProgress func(percent int)
+	buffer     []byte
+	lastReport int
+}
+
+func newProgressWriter(duration float64, onProgress func(percent int)) *progressWriter {
+	return &progressWriter{
+		duration:   duration,
+		onProgress: onProgress,
+		lastReport: -1,
+	}
+}
+
+func (w *progressWriter) Write(data []byte) (int, error) {
+	w.buffer = append(w.buffer, data...)
+	for {
+		lineEnd := bytes.IndexByte(w.buffer, '\n')
+		if lineEnd < 0 {
+			break
+		}
+		w.handleLine(strings.TrimSpace(string(w.buffer[:lineEnd])))
+		w.buffer = w.buffer[lineEnd+1:]
+	}
+	return len(data), nil
+}
+
+func (w *progressWriter) handleLine(line string) {
+	key, value, found := strings.Cut(line, "=")
+	if !found || w.duration <= 0 {
+		return
+	}
+	percent := w.lastReport
+	switch key {
+	// out_time_ms is in microseconds as well, it is kept for older ffmpeg builds
+	case "out_time_us", "out_time_ms":
+		microseconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return
+		}
+		percent = int(microseconds / 1e6 / w.duration * 100)
+	case "progress":
+		if value == "end" {
+			percent = 100
+		}
+	}
+	percent = max(0, min(percent, 100))
+	if percent != w.lastReport {
+		w.lastReport = percent
+		w.onProgress(percent)
+	}
+}
@@ -0,0 +1,109 @@
+package ffmpegv1
+
+import (
+	"context"
+	"fmt"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const segmentPollInterval = time.Second
+
+// segmentWatcher hands out segments as soon as ffmpeg finishes them, a segment is only
+// listed in its playlist once it is complete and temp_file makes the playlist rewrite atomic
+type segmentWatcher struct {
+	outputDir string
+	extension string
+	onSegment func(segment dtos.EncodedSegment) error
+	handled   map[string]bool
+}
+
+// runWatchingSegments runs ffmpeg while polling the output tree, a failing callback kills
+// ffmpeg instead of encoding a tree that will never be stored
+func runWatchingSegments(
+	stream *ffmpeg.Stream,
+	outputDir string,
+	extension string,
+	onSegment func(segment dtos.EncodedSegment) error,
+) error {
+	runCtx, cancel := context.WithCancel(stream.Context)
+	defer cancel()
+	stream.Context = runCtx
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.Run()
+	}()
+	watcher := &segmentWatcher{
+		outputDir: outputDir,
+		extension: extension,
+		onSegment: onSegment,
+		handled:   make(map[string]bool),
+	}
+	ticker := time.NewTicker(segmentPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return fmt.Errorf("Error in encode video by ffmpeg: %s", err.Error())
+			}
+			// segments finished since the last poll
+			return watcher.scan()
+		case <-ticker.C:
+			if err := watcher.scan(); err != nil {
+				cancel()
+				<-done
+				return err
+			}
+		}
+	}
+}
+
+func (w *segmentWatcher) scan() error {
+	return filepath.WalkDir(w.outputDir, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			// a rendition directory can show up between listing and reading it
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if dirEntry.IsDir() || filepath.Ext(filePath) != ".m3u8" {
+			return nil
+		}
+		playlist, err := os.ReadFile(filePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("Error in reading playlist: %w", err)
+		}
+		for _, line := range strings.Split(string(playlist), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || filepath.Ext(line) != w.extension {
+				continue
+			}
+			segmentPath := filepath.Join(filepath.Dir(filePath), line)
+			if w.handled[segmentPath] {
+				continue
+			}
+			relativePath, err := filepath.Rel(w.outputDir, segmentPath)
+			if err != nil {
+				return err
+			}
+			w.handled[segmentPath] = true
+			if err := w.onSegment(dtos.EncodedSegment{
+				Path:         segmentPath,
+				RelativePath: filepath.ToSlash(relativePath),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -4,52 +4,179 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
 	ffmpeg "github.com/u2takey/ffmpeg-go"
-	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+// FfmpegSvc transcodes with the ffmpeg binary installed on the worker
 type FfmpegSvc struct {
+	profile    *dtos.TranscodeProfile
+	scratchDir string
 }
 
-func NewFfmpegSvc() *FfmpegSvc {
-	return &FfmpegSvc{}
+func NewFfmpegSvc(config *dtos.EnvConfig, profile *dtos.TranscodeProfile) *FfmpegSvc {
+	scratchDir := config.Video.ScratchDir
+	if scratchDir == "" {
+		scratchDir = os.TempDir()
+	}
+	return &FfmpegSvc{profile: profile, scratchDir: scratchDir}
 }
 
-func (svc FfmpegSvc) EncodeVideo(videoReader io.Reader) (string, error) {
-	tmpDirID := uuid.NewString()
-	tmpDir := fmt.Sprintf("/tmp/%s", tmpDirID)
-	if err := os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+// newScratchDir gives every run a directory of its own, callers remove it on every path
+func (svc FfmpegSvc) newScratchDir() (string, error) {
+	dir := filepath.Join(svc.scratchDir, uuid.NewString())
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		return "", fmt.Errorf("Error in creating directory: %s", err.Error())
 	}
-	playlistLocation := tmpDir + "/playlist.m3u8"
-	segmentsLocation := tmpDir + "/segment%d.ts"
-	err := ffmpeg.Input("pipe:0").
-		Output(playlistLocation,
-			ffmpeg.KwArgs{
-				"c:v":                  "h264",
-				"b:v":                  "1500k",
-				"c:a":                  "aac",
-				"preset":               "fast",
-				"profile:v":            "baseline",
-				"level":                "3.0",
-				"hls_time":             "10",
-				"hls_list_size":        "0",
-				"hls_segment_filename": segmentsLocation,
-				"hls_flags":            "independent_segments",
-				"hls_playlist_type":    "vod",
-			}).
-		WithInput(videoReader).
-		Run()
+	return dir, nil
+}
+
+// EncodeVideo encodes the video into an adaptive bitrate hls tree, every rendition lives
+// in its own directory (360p/playlist.m3u8) and master.m3u8 at the root references them.
+// Segments handed to OnSegment may already be gone from the returned directory
+func (svc FfmpegSvc) EncodeVideo(source string, options dtos.EncodeOptions) (outputDir string, err error) {
+	tmpDir, err := svc.newScratchDir()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+	info, err := probeSource(source)
 	if err != nil {
-		fmt.Println(err)
-		return "", fmt.Errorf("Error in encode video by ffmpeg: %s", err.Error())
+		return "", err
+	}
+	keyInfoLocation := ""
+	if options.Encryption != nil {
+		// the key must never land in the output tree, everything in it gets uploaded
+		defer os.RemoveAll(tmpDir + "-key")
+		keyInfoLocation, err = writeKeyInfo(tmpDir+"-key", options.Encryption)
+		if err != nil {
+			return "", err
+		}
+	}
+	renditions := svc.selectRenditions(info.height)
+	watermarkFilter := buildWatermarkFilter(options.Watermark, 0)
+	hasVariants := options.Watermark != nil && options.Watermark.ABVariants
+	passes := 1
+	if hasVariants {
+		passes = 2
+	}
+	if err := svc.encodeTree(source, tmpDir, info, renditions, watermarkFilter, keyInfoLocation, passProgress(options.OnProgress, 0, passes), options.OnSegment); err != nil {
+		return "", err
+	}
+	// the b variant shares segmentation with the main tree, so segments can be mixed per viewer
+	if hasVariants {
+		variantFilter := buildWatermarkFilter(options.Watermark, 1)
+		variantDir := tmpDir + "/" + dtos.WatermarkVariantDir
+		if err := svc.encodeTree(source, variantDir, info, renditions, variantFilter, keyInfoLocation, passProgress(options.OnProgress, 1, passes), variantSegments(options.OnSegment)); err != nil {
+			return "", err
+		}
 	}
 	return tmpDir, nil
 }
 
-func (svc FfmpegSvc) GetVideoDuration(videoReader io.Reader) (string, error) {
-	output, err := ffmpeg.ProbeReader(videoReader, ffmpeg.KwArgs{
+// encodeTree turns the profile into ffmpeg arguments, keyframes are forced on segment
+// boundaries so every rendition (and the b variant) is cut at the same timestamps
+func (svc FfmpegSvc) encodeTree(
+	source string,
+	outputDir string,
+	info *sourceInfo,
+	renditions []dtos.VideoRendition,
+	watermarkFilter string,
+	keyInfoLocation string,
+	onProgress func(percent int),
+	onSegment func(segment dtos.EncodedSegment) error,
+) error {
+	profile := svc.profile
+	playlistLocation := outputDir + "/%v/playlist.m3u8"
+	segmentsLocation := outputDir + "/%v/segment%d" + profile.SegmentExtension()
+	kwargs := ffmpeg.KwArgs{
+		"filter_complex":       buildFilterComplex(renditions, watermarkFilter),
+		"map":                  buildMaps(renditions, info.hasAudio),
+		"var_stream_map":       buildVarStreamMap(renditions, info.hasAudio),
+		"c:v":                  profile.VideoCodec,
+		"force_key_frames":     fmt.Sprintf("expr:gte(t,n_forced*%d)", profile.SegmentLength),
+		"sc_threshold":         "0",
+		"f":                    "hls",
+		"hls_time":             strconv.Itoa(profile.SegmentLength),
+		"hls_list_size":        "0",
+		"hls_segment_filename": segmentsLocation,
+		"hls_flags":            "independent_segments+temp_file",
+		"hls_playlist_type":    "vod",
+		"master_pl_name":       "master.m3u8",
+	}
+	if profile.Preset != "" {
+		kwargs["preset"] = profile.Preset
+	}
+	if profile.CodecProfile != "" {
+		kwargs["profile:v"] = profile.CodecProfile
+	}
+	if profile.Container == dtos.TranscodeContainer_CMAF {
+		// ffmpeg names the init segment of every rendition itself, playlists point to it with #EXT-X-MAP
+		kwargs["hls_segment_type"] = "fmp4"
+	}
+	if keyInfoLocation != "" {
+		kwargs["hls_key_info_file"] = keyInfoLocation
+	}
+	if info.hasAudio {
+		kwargs["c:a"] = profile.AudioCodec
+		kwargs["ac"] = strconv.Itoa(profile.AudioChannels)
+	}
+	for index, rendition := range renditions {
+		kwargs[fmt.Sprintf("b:v:%d", index)] = rendition.VideoBitrate
+		kwargs[fmt.Sprintf("maxrate:v:%d", index)] = rendition.MaxRate
+		kwargs[fmt.Sprintf("bufsize:v:%d", index)] = rendition.BufSize
+		if info.hasAudio {
+			kwargs[fmt.Sprintf("b:a:%d", index)] = rendition.AudioBitrate
+		}
+	}
+	stream := ffmpeg.Input(source, sourceArgs(source)).
+		Output(playlistLocation, kwargs)
+	if onProgress != nil {
+		stream = stream.
+			GlobalArgs("-progress", "pipe:1", "-nostats").
+			WithOutput(newProgressWriter(info.duration, onProgress))
+	}
+	if onSegment == nil {
+		if err := stream.Run(); err != nil {
+			return fmt.Errorf("Error in encode video by ffmpeg: %s", err.Error())
+		}
+		return nil
+	}
+	return runWatchingSegments(stream, outputDir, profile.SegmentExtension(), onSegment)
+}
+
+// passProgress maps the progress of one encoding pass into its share of the whole encode
+func passProgress(onProgress func(percent int), pass int, passes int) func(percent int) {
+	if onProgress == nil {
+		return nil
+	}
+	return func(percent int) {
+		onProgress((pass*100 + percent) / passes)
+	}
+}
+
+// variantSegments keeps segment paths of the b variant relative to the root of the tree
+func variantSegments(onSegment func(segment dtos.EncodedSegment) error) func(segment dtos.EncodedSegment) error {
+	if onSegment == nil {
+		return nil
+	}
+	return func(segment dtos.EncodedSegment) error {
+		segment.RelativePath = path.Join(dtos.WatermarkVariantDir, segment.RelativePath)
+		return onSegment(segment)
+	}
+}
+
+func (svc FfmpegSvc) GetVideoDuration(source string) (string, error) {
+	output, err := ffmpeg.Probe(source, ffmpeg.KwArgs{
 		"v":            "error",
 		"show_entries": "format=duration",
 		"of":           "json",
@@ -67,3 +194,137 @@ func (svc FfmpegSvc) GetVideoDuration(videoReader io.Reader) (string, error) {
 	}
 	return result.Format.Duration, nil
 }
+
+// selectRenditions never upscales, a source smaller than the whole ladder still gets the lowest rendition
+func (svc FfmpegSvc) selectRenditions(sourceHeight int) []dtos.VideoRendition {
+	renditions := make([]dtos.VideoRendition, 0, len(svc.profile.Renditions))
+	for _, rendition := range svc.profile.Renditions {
+		if rendition.Height <= sourceHeight {
+			renditions = append(renditions, rendition)
+		}
+	}
+	if len(renditions) == 0 {
+		lowest := svc.profile.Renditions[0]
+		lowest.Height = sourceHeight - sourceHeight%2
+		renditions = append(renditions, lowest)
+	}
+	return renditions
+}
+
+func writeKeyInfo(dir string, encryption *dtos.HlsEncryption) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("Error in creating key directory: %w", err)
+	}
+	keyLocation := dir + "/video.key"
+	if err := os.WriteFile(keyLocation, encryption.Key, 0600); err != nil {
+		return "", fmt.Errorf("Error in writing video key: %w", err)
+	}
+	keyInfoLocation := dir + "/video.keyinfo"
+	keyInfo := fmt.Sprintf("%s\n%s\n", encryption.KeyURI, keyLocation)
+	if err := os.WriteFile(keyInfoLocation, []byte(keyInfo), 0600); err != nil {
+		return "", fmt.Errorf("Error in writing video key info: %w", err)
+	}
+	return keyInfoLocation, nil
+}
+
+type sourceInfo struct {
+	width    int
+	height   int
+	duration float64
+	hasAudio bool
+}
+
+// sourceArgs lets ffmpeg pick a dropped connection up where it stopped instead of failing a
+// long encode, local files take no options
+func sourceArgs(source string) ffmpeg.KwArgs {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return ffmpeg.KwArgs{}
+	}
+	return ffmpeg.KwArgs{
+		"reconnect":           "1",
+		"reconnect_streamed":  "1",
+		"reconnect_delay_max": "30",
+	}
+}
+
+func probeSource(source string) (*sourceInfo, error) {
+	output, err := ffmpeg.Probe(source, ffmpeg.KwArgs{
+		"v":            "error",
+		"show_entries": "stream=codec_type,width,height:format=duration",
+		"of":           "json",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error in probing the video: %w", err)
+	}
+	var result struct {
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return nil, fmt.Errorf("Error in converting result of probing the video: %w", err)
+	}
+	info := &sourceInfo{}
+	// duration is missing for some containers, callers fall back to the start of the video
+	info.duration, _ = strconv.ParseFloat(result.Format.Duration, 64)
+	for _, stream := range result.Streams {
+		switch stream.CodecType {
+		case "video":
+			if info.height == 0 {
+				info.width = stream.Width
+				info.height = stream.Height
+			}
+		case "audio":
+			info.hasAudio = true
+		}
+	}
+	if info.height == 0 {
+		return nil, fmt.Errorf("Error in probing the video: no video stream found")
+	}
+	return info, nil
+}
+
+// buildFilterComplex marks the source once before splitting, so every rendition carries the same watermark
+func buildFilterComplex(renditions []dtos.VideoRendition, watermarkFilter string) string {
+	splitOutputs := ""
+	scales := make([]string, len(renditions))
+	for index, rendition := range renditions {
+		splitOutputs += fmt.Sprintf("[v%d]", index)
+		scales[index] = fmt.Sprintf("[v%d]scale=-2:%d[v%dout]", index, rendition.Height, index)
+	}
+	input := "[0:v]"
+	if watermarkFilter != "" {
+		input = watermarkFilter + "[marked];[marked]"
+	}
+	return fmt.Sprintf("%ssplit=%d%s;%s", input, len(renditions), splitOutputs, strings.Join(scales, ";"))
+}
+
+func buildMaps(renditions []dtos.VideoRendition, hasAudio bool) []string {
+	maps := make([]string, 0, len(renditions)*2)
+	for index := range renditions {
+		maps = append(maps, fmt.Sprintf("[v%dout]", index))
+	}
+	if hasAudio {
+		for range renditions {
+			maps = append(maps, "0:a:0")
+		}
+	}
+	return maps
+}
+
+func buildVarStreamMap(renditions []dtos.VideoRendition, hasAudio bool) string {
+	streams := make([]string, len(renditions))
+	for index, rendition := range renditions {
+		if hasAudio {
+			streams[index] = fmt.Sprintf("v:%d,a:%d,name:%s", index, index, rendition.Name)
+		} else {
+			streams[index] = fmt.Sprintf("v:%d,name:%s", index, rendition.Name)
+		}
+	}
+	return strings.Join(streams, " ")
+}
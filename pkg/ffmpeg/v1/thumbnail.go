@@ -0,0 +1,123 @@
+package ffmpegv1
+
+import (
+	"fmt"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	spriteTileWidth   = 160
+	spriteColumns     = 10
+	spriteMaxTiles    = 200
+	spriteMinInterval = 5
+)
+
+// ExtractFrame returns a jpeg of the frame at timestamp (in seconds), a timestamp past
+// the end of the video falls back to the first tenth of it
+func (svc FfmpegSvc) ExtractFrame(source string, timestamp float64) ([]byte, error) {
+	info, err := probeSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp < 0 || (info.duration > 0 && timestamp >= info.duration) {
+		timestamp = info.duration / 10
+	}
+	tmpDir, err := svc.newScratchDir()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	inputArgs := sourceArgs(source)
+	inputArgs["ss"] = formatSeconds(timestamp)
+	frameLocation := tmpDir + "/frame.jpg"
+	err = ffmpeg.Input(source, inputArgs).
+		Output(frameLocation, ffmpeg.KwArgs{
+			"frames:v": "1",
+			"q:v":      "2",
+		}).
+		OverWriteOutput().
+		Run()
+	if err != nil {
+		return nil, fmt.Errorf("Error in extracting frame by ffmpeg: %s", err.Error())
+	}
+	frame, err := os.ReadFile(frameLocation)
+	if err != nil {
+		return nil, fmt.Errorf("Error in reading extracted frame: %w", err)
+	}
+	return frame, nil
+}
+
+// GenerateThumbnailSprite tiles evenly spaced frames into sprite.jpg and describes every tile
+// in sprite.vtt, players use the vtt cues to show a preview while seeking
+func (svc FfmpegSvc) GenerateThumbnailSprite(source string) (string, error) {
+	info, err := probeSource(source)
+	if err != nil {
+		return "", err
+	}
+	if info.duration <= 0 || info.width == 0 {
+		return "", fmt.Errorf("Error in generating thumbnail sprite: unknown duration or dimension")
+	}
+	tmpDir, err := svc.newScratchDir()
+	if err != nil {
+		return "", err
+	}
+	interval := int(math.Ceil(info.duration / spriteMaxTiles))
+	if interval < spriteMinInterval {
+		interval = spriteMinInterval
+	}
+	tiles := int(math.Ceil(info.duration / float64(interval)))
+	columns := min(tiles, spriteColumns)
+	rows := int(math.Ceil(float64(tiles) / float64(columns)))
+	tileHeight := int(math.Round(float64(spriteTileWidth*info.height)/float64(info.width))) / 2 * 2
+	err = ffmpeg.Input(source, sourceArgs(source)).
+		Output(tmpDir+"/sprite.jpg", ffmpeg.KwArgs{
+			"vf":       fmt.Sprintf("fps=1/%d,scale=%d:%d,tile=%dx%d", interval, spriteTileWidth, tileHeight, columns, rows),
+			"frames:v": "1",
+			"q:v":      "5",
+		}).
+		OverWriteOutput().
+		Run()
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return "", fmt.Errorf("Error in generating thumbnail sprite by ffmpeg: %s", err.Error())
+	}
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n")
+	for tile := 0; tile < tiles; tile++ {
+		start := float64(tile * interval)
+		end := math.Min(float64((tile+1)*interval), info.duration)
+		fmt.Fprintf(
+			&vtt,
+			"\n%s --> %s\nsprite.jpg#xywh=%d,%d,%d,%d\n",
+			formatVttTime(start),
+			formatVttTime(end),
+			(tile%columns)*spriteTileWidth,
+			(tile/columns)*tileHeight,
+			spriteTileWidth,
+			tileHeight,
+		)
+	}
+	if err := os.WriteFile(tmpDir+"/sprite.vtt", []byte(vtt.String()), 0644); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", fmt.Errorf("Error in writing thumbnail sprite vtt: %w", err)
+	}
+	return tmpDir, nil
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+func formatVttTime(seconds float64) string {
+	milliseconds := int(math.Round(seconds * 1000))
+	return fmt.Sprintf(
+		"%02d:%02d:%02d.%03d",
+		milliseconds/3600000,
+		milliseconds/60000%60,
+		milliseconds/1000%60,
+		milliseconds%1000,
+	)
+}
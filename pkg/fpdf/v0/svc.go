@@ -0,0 +1,76 @@
+package fpdfv0
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/go-pdf/fpdf"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+)
+
+const (
+	pdfFontFamily   = "document"
+	pdfLineHeight   = 6
+	pdfTitleSize    = 18
+	pdfHeadingSize  = 13
+	pdfParagraphGap = 2
+	pdfBodySize     = 11
+)
+
+type FpdfSvc struct {
+	fontPath string
+}
+
+// NewFpdfSvc uses the ttf font of the config when set, the core fonts only cover latin text.
+// Right to left scripts come out with unjoined letters in reverse order even with a font
+func NewFpdfSvc(config *dtos.EnvConfig) *FpdfSvc {
+	return &FpdfSvc{
+		fontPath: config.Pdf.FontPath,
+	}
+}
+
+func (svc FpdfSvc) GenerateDocument(document dtos.PdfDocument) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	family, translate := svc.setupFont(pdf)
+	pdf.SetTitle(document.Title, true)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(family, "", 8)
+		pdf.CellFormat(0, 8, translate(fmt.Sprintf("%d / {nb}", pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont(family, "", pdfTitleSize)
+	pdf.MultiCell(0, pdfLineHeight*1.5, translate(document.Title), "", "L", false)
+	pdf.Ln(pdfLineHeight)
+	for _, section := range document.Sections {
+		pdf.SetFont(family, "", pdfHeadingSize)
+		pdf.MultiCell(0, pdfLineHeight, translate(section.Heading), "", "L", false)
+		pdf.Ln(pdfParagraphGap)
+		pdf.SetFont(family, "", pdfBodySize)
+		for _, paragraph := range section.Paragraphs {
+			pdf.MultiCell(0, pdfLineHeight, translate(paragraph), "", "L", false)
+			pdf.Ln(pdfParagraphGap)
+		}
+		pdf.Ln(pdfLineHeight)
+	}
+
+	var result bytes.Buffer
+	if err := pdf.Output(&result); err != nil {
+		return nil, dtos.NewPdfError(
+			"Error: happen in generating pdf document: "+err.Error(),
+			"FpdfSvc.GenerateDocument",
+		)
+	}
+	return result.Bytes(), nil
+}
+
+// setupFont returns the family text is written with, core fonts need the text in their own code page
+func (svc FpdfSvc) setupFont(pdf *fpdf.Fpdf) (string, func(string) string) {
+	if svc.fontPath != "" {
+		pdf.AddUTF8Font(pdfFontFamily, "", svc.fontPath)
+		return pdfFontFamily, func(text string) string { return text }
+	}
+	return "Helvetica", pdf.UnicodeTranslatorFromDescriptor("")
+}
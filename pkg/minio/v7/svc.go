@@ -8,15 +8,19 @@ import (
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
 )
 
 type MinioClientSvc struct {
-	minio  *minio.Client
-	region string
+	minio       *minio.Client
+	publicMinio *minio.Client
+	region      string
 }
 
-func setupMinioClient(config *dtos.EnvConfig) (*minio.Client, error) {
-	endpoint := config.Minio.URL
+func setupMinioClient(config *dtos.EnvConfig, endpoint string) (*minio.Client, error) {
 	accessKey := config.Minio.AccessKey
 	secretKey := config.Minio.SecretKey
 	region := config.Minio.Region
@@ -28,11 +32,19 @@ func setupMinioClient(config *dtos.EnvConfig) (*minio.Client, error) {
 }
 
 func NewMinioClientSvc(config *dtos.EnvConfig) (*MinioClientSvc, error) {
-	client, err := setupMinioClient(config)
+	client, err := setupMinioClient(config, config.Minio.URL)
 	if err != nil {
 		return nil, err
 	}
-	return &MinioClientSvc{minio: client, region: config.Minio.Region}, nil
+	// presigning never calls the server, so the public client only needs the public host
+	publicClient := client
+	if config.Minio.PublicURL != "" {
+		publicClient, err = setupMinioClient(config, config.Minio.PublicURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &MinioClientSvc{minio: client, publicMinio: publicClient, region: config.Minio.Region}, nil
 }
 
 func (svc MinioClientSvc) BucketExist(
@@ -138,6 +150,35 @@ func (svc MinioClientSvc) UploadFileByContent(
 	return dtos.NewUploadResult(info.Key, info.Size), nil
 }
 
+// PutObject streams the reader into the bucket, a size of -1 makes minio upload it in
+// parts without knowing the length up front
+func (svc MinioClientSvc) PutObject(
+	ctx context.Context,
+	bucketName string,
+	objectPath string,
+	contentType string,
+	reader io.Reader,
+	size int64,
+) (*dtos.UploadResult, error) {
+	info, err := svc.minio.PutObject(
+		ctx,
+		bucketName,
+		objectPath,
+		reader,
+		size,
+		minio.PutObjectOptions{
+			ContentType: contentType,
+		},
+	)
+	if err != nil {
+		return nil, dtos.NewStorageError(
+			fmt.Sprintf("Error: happen in uploading minio object - %s", err.Error()),
+			"MinioClientSvc.PutObject",
+		)
+	}
+	return dtos.NewUploadResult(info.Key, info.Size), nil
+}
+
 func (svc MinioClientSvc) GetFile(
 	ctx context.Context,
 	bucketName,
@@ -165,15 +206,15 @@ func (svc MinioClientSvc) GetFile(
 			"MinioClientSvc.GetFile",
 		)
 	}
-	fileContents := make([]byte, 2025)
-	contentLength, err := object.Read(fileContents)
+	defer object.Close()
+	fileContents, err := io.ReadAll(object)
 	if err != nil {
 		return nil, dtos.NewStorageError(
 			"Error: happen in reading file contents",
 			"MinioClientSvc.GetFile",
 		)
 	}
-	return fileContents[:contentLength], nil
+	return fileContents, nil
 }
 
 func (svc MinioClientSvc) GetFileReader(
@@ -225,3 +266,98 @@ func (svc MinioClientSvc) DeleteObject(
 	}
 	return nil
 }
+
+// DeleteObjectsByPrefix removes every object under the prefix, the prefix should end with a slash
+// so a sibling sharing the beginning of its name is not removed too
+func (svc MinioClientSvc) DeleteObjectsByPrefix(
+	ctx context.Context,
+	bucketName string,
+	prefix string,
+) error {
+	objects := svc.minio.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	})
+	for removeErr := range svc.minio.RemoveObjects(ctx, bucketName, objects, minio.RemoveObjectsOptions{}) {
+		return dtos.NewStorageError(
+			fmt.Sprintf("Error: happen in deleting objects by prefix - %s", removeErr.Err.Error()),
+			"MinioClientSvc.DeleteObjectsByPrefix",
+		)
+	}
+	return nil
+}
+
+func (svc MinioClientSvc) ListObjects(
+	ctx context.Context,
+	bucketName string,
+	prefix string,
+	recursive bool,
+) ([]dtos.StorageObject, error) {
+	objects := make([]dtos.StorageObject, 0)
+	for object := range svc.minio.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: recursive,
+	}) {
+		if object.Err != nil {
+			return nil, dtos.NewStorageError(
+				fmt.Sprintf("Error: happen in listing objects - %s", object.Err.Error()),
+				"MinioClientSvc.ListObjects",
+			)
+		}
+		objects = append(objects, dtos.StorageObject{
+			Key:          object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+			IsPrefix:     strings.HasSuffix(object.Key, "/"),
+		})
+	}
+	return objects, nil
+}
+
+func (svc MinioClientSvc) GetPresignedURL(
+	ctx context.Context,
+	bucketName string,
+	objectPath string,
+	expires time.Duration,
+) (string, error) {
+	presignedURL, err := svc.publicMinio.PresignedGetObject(
+		ctx,
+		bucketName,
+		objectPath,
+		expires,
+		nil,
+	)
+	if err != nil {
+		return "", dtos.NewStorageError(
+			"Error: happen in presigning object url",
+			"MinioClientSvc.GetPresignedURL",
+		)
+	}
+	return presignedURL.String(), nil
+}
+
+// GetPresignedDownloadURL makes browsers save the object under fileName instead of opening it
+func (svc MinioClientSvc) GetPresignedDownloadURL(
+	ctx context.Context,
+	bucketName string,
+	objectPath string,
+	fileName string,
+	expires time.Duration,
+) (string, error) {
+	reqParams := make(url.Values)
+	reqParams.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	presignedURL, err := svc.publicMinio.PresignedGetObject(
+		ctx,
+		bucketName,
+		objectPath,
+		expires,
+		reqParams,
+	)
+	if err != nil {
+		return "", dtos.NewStorageError(
+			"Error: happen in presigning download url",
+			"MinioClientSvc.GetPresignedDownloadURL",
+		)
+	}
+	return presignedURL.String(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/go-redis/redis"
 	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"time"
 )
 
 type RedisClientSvc struct {
@@ -73,3 +74,40 @@ func (svc RedisClientSvc) GetVal(key string) (string, error) {
 	}
 	return val, nil
 }
+
+// Increment bumps the counter of key and returns the new value, ttl only applies to a new counter
+func (svc RedisClientSvc) Increment(key string, ttl time.Duration) (int64, error) {
+	val, err := svc.redis.Incr(key).Result()
+	if err != nil {
+		return 0, dtos.NewCacheError("Error: happen in increment value", "RedisClientSvc.Increment")
+	}
+	if val == 1 {
+		if err := svc.redis.Expire(key, ttl).Err(); err != nil {
+			return 0, dtos.NewCacheError("Error: happen in set expiration", "RedisClientSvc.Increment")
+		}
+	}
+	return val, nil
+}
+
+// PushList appends the values to the end of the list of key
+func (svc RedisClientSvc) PushList(key string, values ...any) error {
+	if err := svc.redis.RPush(key, values...).Err(); err != nil {
+		return dtos.NewCacheError("Error: happen in push list", "RedisClientSvc.PushList")
+	}
+	return nil
+}
+
+// PopList removes and returns up to count values from the head of the list, reading and trimming
+// in one transaction so concurrent callers never get the same value
+func (svc RedisClientSvc) PopList(key string, count int64) ([]string, error) {
+	var values *redis.StringSliceCmd
+	_, err := svc.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		values = pipe.LRange(key, 0, count-1)
+		pipe.LTrim(key, count, -1)
+		return nil
+	})
+	if err != nil {
+		return nil, dtos.NewCacheError("Error: happen in pop list", "RedisClientSvc.PopList")
+	}
+	return values.Val(), nil
+}
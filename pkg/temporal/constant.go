@@ -3,4 +3,10 @@ package temporal
 const (
 	ADD_NEW_COURSE_VIDEO_QUEUE    = "ADD_NEW_COURSE_VIDEO_QUEUE"
 	SET_INTRODUCTION_COURSE_QUEUE = "SET_INTRODUCTION_COURSE_QUEUE"
+	PUBLISH_SCHEDULE_QUEUE        = "PUBLISH_SCHEDULE_QUEUE"
+	COURSE_ANNOUNCEMENT_QUEUE     = "COURSE_ANNOUNCEMENT_QUEUE"
+	UPDATE_VIDEO_THUMBNAIL_QUEUE  = "UPDATE_VIDEO_THUMBNAIL_QUEUE"
+	CLEANUP_VIDEO_STORAGE_QUEUE   = "CLEANUP_VIDEO_STORAGE_QUEUE"
+	SWEEP_ORPHAN_VIDEO_QUEUE      = "SWEEP_ORPHAN_VIDEO_QUEUE"
+	FLUSH_WATCH_PROGRESS_QUEUE    = "FLUSH_WATCH_PROGRESS_QUEUE"
 )
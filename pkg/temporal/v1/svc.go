@@ -1,14 +1,30 @@
 package temporalv1
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"github.com/ladmakhi81/learnup/pkg/dtos"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
 	"time"
 )
 
+const (
+	defaultTaskTimeout         = time.Hour
+	defaultTaskAttempts        = 5
+	defaultTaskInitialInterval = 5 * time.Second
+	defaultTaskMaximumInterval = 5 * time.Minute
+	// heartbeat details carry progress shown to users, so they reach the server at least this often
+	heartbeatThrottleInterval = 5 * time.Second
+)
+
 type TemporalSvc struct {
 	client client.Client
 	config *dtos.EnvConfig
@@ -32,7 +48,9 @@ func (svc *TemporalSvc) Init() error {
 }
 
 func (svc *TemporalSvc) AddWorker(queueName string, workflowFn any, activitiesFn ...any) error {
-	w := worker.New(svc.client, queueName, worker.Options{})
+	w := worker.New(svc.client, queueName, worker.Options{
+		MaxHeartbeatThrottleInterval: heartbeatThrottleInterval,
+	})
 	w.RegisterWorkflow(workflowFn)
 	for _, activity := range activitiesFn {
 		w.RegisterActivity(activity)
@@ -56,10 +74,135 @@ func (svc *TemporalSvc) ExecuteWorker(ctx context.Context, queueName string, wor
 	return nil
 }
 
+// ExecuteWorkerWithID starts the workflow under a fixed id, a running execution
+// with the same id is terminated and replaced by the new one.
+func (svc *TemporalSvc) ExecuteWorkerWithID(ctx context.Context, queueName string, workflowID string, workflowFn any, data any) error {
+	_, err := svc.client.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:                       workflowID,
+			TaskQueue:                queueName,
+			WorkflowIDConflictPolicy: enumspb.WORKFLOW_ID_CONFLICT_POLICY_TERMINATE_EXISTING,
+		},
+		workflowFn,
+		data,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ExecuteWorkerOnce starts the workflow only if no execution with the id ever ran, false is
+// returned when one is running or already closed so redelivered events start nothing
+func (svc *TemporalSvc) ExecuteWorkerOnce(ctx context.Context, queueName string, workflowID string, workflowFn any, data any) (bool, error) {
+	_, err := svc.client.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:                       workflowID,
+			TaskQueue:                queueName,
+			WorkflowIDReusePolicy:    enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+			WorkflowIDConflictPolicy: enumspb.WORKFLOW_ID_CONFLICT_POLICY_FAIL,
+			// without it the sdk hands back the existing run as if it had been started
+			WorkflowExecutionErrorWhenAlreadyStarted: true,
+		},
+		workflowFn,
+		data,
+	)
+	if err != nil {
+		var alreadyStartedErr *serviceerror.WorkflowExecutionAlreadyStarted
+		if errors.As(err, &alreadyStartedErr) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ScheduleWorker runs the workflow on the cron schedule, starting it again replaces the
+// previous schedule so a changed cron takes effect on the next boot
+func (svc *TemporalSvc) ScheduleWorker(ctx context.Context, queueName string, workflowID string, cronSchedule string, workflowFn any, data any) error {
+	_, err := svc.client.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:                       workflowID,
+			TaskQueue:                queueName,
+			CronSchedule:             cronSchedule,
+			WorkflowIDConflictPolicy: enumspb.WORKFLOW_ID_CONFLICT_POLICY_TERMINATE_EXISTING,
+		},
+		workflowFn,
+		data,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (svc *TemporalSvc) CancelWorker(ctx context.Context, workflowID string) error {
+	err := svc.client.CancelWorkflow(ctx, workflowID, "")
+	if err != nil {
+		var notFoundErr *serviceerror.NotFound
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
 func (svc *TemporalSvc) ExecuteTask(ctx workflow.Context, activityFn any, data any, result any) error {
+	return svc.ExecuteTaskWithOptions(ctx, dtos.TaskOptions{}, activityFn, data, result)
+}
+
+// ExecuteTaskWithOptions retries failed activities with exponential backoff, client errors are
+// not retried since the same input is rejected again
+func (svc *TemporalSvc) ExecuteTaskWithOptions(ctx workflow.Context, options dtos.TaskOptions, activityFn any, data any, result any) error {
 	ao := workflow.ActivityOptions{
-		StartToCloseTimeout: time.Hour * 24,
+		StartToCloseTimeout: cmp.Or(options.StartToCloseTimeout, defaultTaskTimeout),
+		HeartbeatTimeout:    options.HeartbeatTimeout,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:        cmp.Or(options.InitialInterval, defaultTaskInitialInterval),
+			BackoffCoefficient:     2,
+			MaximumInterval:        cmp.Or(options.MaximumInterval, defaultTaskMaximumInterval),
+			MaximumAttempts:        cmp.Or(options.MaximumAttempts, defaultTaskAttempts),
+			NonRetryableErrorTypes: []string{"ClientError"},
+		},
 	}
 	ctx = workflow.WithActivityOptions(ctx, ao)
 	return workflow.ExecuteActivity(ctx, activityFn, data).Get(ctx, result)
 }
+
+// RecordHeartbeat must be called from inside an activity
+func (svc *TemporalSvc) RecordHeartbeat(ctx context.Context, details ...any) {
+	activity.RecordHeartbeat(ctx, details...)
+}
+
+// DescribeWorkflowProgress returns nil when no workflow with the id exists
+func (svc *TemporalSvc) DescribeWorkflowProgress(ctx context.Context, workflowID string) (*dtos.WorkflowProgress, error) {
+	description, err := svc.client.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		var notFoundErr *serviceerror.NotFound
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	progress := &dtos.WorkflowProgress{
+		IsRunning: description.GetWorkflowExecutionInfo().GetStatus() == enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+	}
+	pendingActivities := description.GetPendingActivities()
+	if !progress.IsRunning || len(pendingActivities) == 0 {
+		return progress, nil
+	}
+	pendingActivity := pendingActivities[0]
+	progress.Activity = pendingActivity.GetActivityType().GetName()
+	progress.IsStarted = pendingActivity.GetState() == enumspb.PENDING_ACTIVITY_STATE_STARTED
+	if details := pendingActivity.GetHeartbeatDetails(); details != nil {
+		taskProgress := &dtos.TaskProgress{}
+		if err := converter.GetDefaultDataConverter().FromPayloads(details, taskProgress); err == nil {
+			progress.Progress = taskProgress
+		}
+	}
+	return progress, nil
+}
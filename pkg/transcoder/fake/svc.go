@@ -0,0 +1,257 @@
+package faketranscoder
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// every source is reported as this long, whatever its content
+	fakeDuration    = 30
+	frameWidth      = 320
+	frameHeight     = 180
+	spriteTileWidth = 160
+	spriteInterval  = 5
+)
+
+// FakeTranscoderSvc writes a small but well formed tree instead of transcoding, so the processing
+// pipeline runs on machines without ffmpeg and tests don't depend on real media
+type FakeTranscoderSvc struct {
+	profile    *dtos.TranscodeProfile
+	scratchDir string
+}
+
+func NewFakeTranscoderSvc(config *dtos.EnvConfig, profile *dtos.TranscodeProfile) *FakeTranscoderSvc {
+	scratchDir := config.Video.ScratchDir
+	if scratchDir == "" {
+		scratchDir = os.TempDir()
+	}
+	return &FakeTranscoderSvc{profile: profile, scratchDir: scratchDir}
+}
+
+// EncodeVideo lays the tree out like the ffmpeg backend, segments hold placeholder bytes that
+// are still encrypted with the given key so playback and key delivery can be exercised
+func (svc FakeTranscoderSvc) EncodeVideo(source string, options dtos.EncodeOptions) (outputDir string, err error) {
+	if err := checkSource(source); err != nil {
+		return "", err
+	}
+	tmpDir, err := svc.newScratchDir()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+	passes := []string{""}
+	if options.Watermark != nil && options.Watermark.ABVariants {
+		passes = append(passes, dtos.WatermarkVariantDir)
+	}
+	for index, relativeRoot := range passes {
+		if err := svc.writeTree(tmpDir, relativeRoot, options); err != nil {
+			return "", err
+		}
+		if options.OnProgress != nil {
+			options.OnProgress((index + 1) * 100 / len(passes))
+		}
+	}
+	return tmpDir, nil
+}
+
+func (svc FakeTranscoderSvc) GetVideoDuration(source string) (string, error) {
+	if err := checkSource(source); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d.000000", fakeDuration), nil
+}
+
+func (svc FakeTranscoderSvc) ExtractFrame(source string, timestamp float64) ([]byte, error) {
+	if err := checkSource(source); err != nil {
+		return nil, err
+	}
+	var frame bytes.Buffer
+	if err := jpeg.Encode(&frame, newFrame(frameWidth, frameHeight, timestamp), nil); err != nil {
+		return nil, fmt.Errorf("Error in encoding fake frame: %w", err)
+	}
+	return frame.Bytes(), nil
+}
+
+func (svc FakeTranscoderSvc) GenerateThumbnailSprite(source string) (outputDir string, err error) {
+	if err := checkSource(source); err != nil {
+		return "", err
+	}
+	tmpDir, err := svc.newScratchDir()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+	tiles := fakeDuration / spriteInterval
+	tileHeight := spriteTileWidth * frameHeight / frameWidth
+	sprite, err := os.Create(filepath.Join(tmpDir, "sprite.jpg"))
+	if err != nil {
+		return "", fmt.Errorf("Error in creating fake sprite: %w", err)
+	}
+	defer sprite.Close()
+	if err := jpeg.Encode(sprite, newFrame(spriteTileWidth*tiles, tileHeight, 0), nil); err != nil {
+		return "", fmt.Errorf("Error in encoding fake sprite: %w", err)
+	}
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n")
+	for tile := 0; tile < tiles; tile++ {
+		fmt.Fprintf(
+			&vtt,
+			"\n%s --> %s\nsprite.jpg#xywh=%d,0,%d,%d\n",
+			formatVttTime(tile*spriteInterval),
+			formatVttTime((tile+1)*spriteInterval),
+			tile*spriteTileWidth,
+			spriteTileWidth,
+			tileHeight,
+		)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "sprite.vtt"), []byte(vtt.String()), 0644); err != nil {
+		return "", fmt.Errorf("Error in writing fake sprite vtt: %w", err)
+	}
+	return tmpDir, nil
+}
+
+func (svc FakeTranscoderSvc) writeTree(rootDir string, relativeRoot string, options dtos.EncodeOptions) error {
+	treeDir := filepath.Join(rootDir, relativeRoot)
+	segmentCount := (fakeDuration + svc.profile.SegmentLength - 1) / svc.profile.SegmentLength
+	var master strings.Builder
+	master.WriteString("#EXTM3U\n#EXT-X-VERSION:6\n#EXT-X-INDEPENDENT-SEGMENTS\n")
+	for _, rendition := range svc.profile.Renditions {
+		renditionDir := filepath.Join(treeDir, rendition.Name)
+		if err := os.MkdirAll(renditionDir, os.ModePerm); err != nil {
+			return fmt.Errorf("Error in creating directory: %w", err)
+		}
+		var playlist strings.Builder
+		fmt.Fprintf(&playlist, "#EXTM3U\n#EXT-X-VERSION:6\n#EXT-X-TARGETDURATION:%d\n#EXT-X-MEDIA-SEQUENCE:0\n#EXT-X-PLAYLIST-TYPE:VOD\n", svc.profile.SegmentLength)
+		if svc.profile.Container == dtos.TranscodeContainer_CMAF {
+			if err := os.WriteFile(filepath.Join(renditionDir, "init.mp4"), []byte("fake init segment"), 0644); err != nil {
+				return fmt.Errorf("Error in writing fake init segment: %w", err)
+			}
+			playlist.WriteString("#EXT-X-MAP:URI=\"init.mp4\"\n")
+		}
+		if options.Encryption != nil {
+			fmt.Fprintf(&playlist, "#EXT-X-KEY:METHOD=AES-128,URI=\"%s\"\n", options.Encryption.KeyURI)
+		}
+		for sequence := 0; sequence < segmentCount; sequence++ {
+			segmentName := fmt.Sprintf("segment%d%s", sequence, svc.profile.SegmentExtension())
+			content := []byte(fmt.Sprintf("fake segment %s %s %d", relativeRoot, rendition.Name, sequence))
+			if options.Encryption != nil {
+				encrypted, err := encryptSegment(options.Encryption.Key, content, sequence)
+				if err != nil {
+					return err
+				}
+				content = encrypted
+			}
+			segmentPath := filepath.Join(renditionDir, segmentName)
+			if err := os.WriteFile(segmentPath, content, 0644); err != nil {
+				return fmt.Errorf("Error in writing fake segment: %w", err)
+			}
+			length := min(svc.profile.SegmentLength, fakeDuration-sequence*svc.profile.SegmentLength)
+			fmt.Fprintf(&playlist, "#EXTINF:%d.000000,\n%s\n", length, segmentName)
+			if options.OnSegment != nil {
+				if err := options.OnSegment(dtos.EncodedSegment{
+					Path:         segmentPath,
+					RelativePath: path.Join(relativeRoot, rendition.Name, segmentName),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		playlist.WriteString("#EXT-X-ENDLIST\n")
+		if err := os.WriteFile(filepath.Join(renditionDir, "playlist.m3u8"), []byte(playlist.String()), 0644); err != nil {
+			return fmt.Errorf("Error in writing fake playlist: %w", err)
+		}
+		fmt.Fprintf(
+			&master,
+			"#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n%s/playlist.m3u8\n",
+			bandwidth(rendition),
+			rendition.Height*16/9/2*2,
+			rendition.Height,
+			rendition.Name,
+		)
+	}
+	if err := os.WriteFile(filepath.Join(treeDir, "master.m3u8"), []byte(master.String()), 0644); err != nil {
+		return fmt.Errorf("Error in writing fake master playlist: %w", err)
+	}
+	return nil
+}
+
+func (svc FakeTranscoderSvc) newScratchDir() (string, error) {
+	dir := filepath.Join(svc.scratchDir, uuid.NewString())
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("Error in creating directory: %s", err.Error())
+	}
+	return dir, nil
+}
+
+// encryptSegment matches ffmpeg, aes-128-cbc with pkcs7 padding and the media sequence as iv
+func encryptSegment(key []byte, content []byte, sequence int) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("Error in encrypting fake segment: %w", err)
+	}
+	padding := aes.BlockSize - len(content)%aes.BlockSize
+	plain := append(content, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], uint64(sequence))
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, plain)
+	return encrypted, nil
+}
+
+func newFrame(width int, height int, timestamp float64) image.Image {
+	frame := image.NewRGBA(image.Rect(0, 0, width, height))
+	shade := uint8(int(timestamp*8) % 256)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			frame.Set(x, y, color.RGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: shade, A: 255})
+		}
+	}
+	return frame
+}
+
+// bandwidth reads the peak rate of a rendition (856k) as bits per second
+func bandwidth(rendition dtos.VideoRendition) int {
+	kbps, _ := strconv.Atoi(strings.TrimSuffix(rendition.MaxRate, "k"))
+	return kbps * 1000
+}
+
+func formatVttTime(seconds int) string {
+	return fmt.Sprintf("%02d:%02d:%02d.000", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// checkSource fails like a real encode would on a missing local source, urls aren't fetched
+// since the fake never reads any media
+func checkSource(source string) error {
+	if source == "" {
+		return fmt.Errorf("Error in reading source: no source given")
+	}
+	if strings.Contains(source, "://") {
+		return nil
+	}
+	if _, err := os.Stat(source); err != nil {
+		return fmt.Errorf("Error in reading source: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package transcoder
+
+import (
+	"fmt"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"slices"
+	"strings"
+)
+
+const (
+	maxSegmentLength = 60
+	maxAudioChannels = 8
+)
+
+// defaultProfile is what encoding produced before the profile was configurable
+var defaultProfile = dtos.TranscodeProfile{
+	Container:     dtos.TranscodeContainer_HLS,
+	VideoCodec:    "h264",
+	AudioCodec:    "aac",
+	Preset:        "fast",
+	CodecProfile:  "main",
+	SegmentLength: 10,
+	AudioChannels: 2,
+}
+
+var (
+	// there is no dash, playback signs, encrypts and watermarks hls playlists only, so cmaf
+	// segments are listed in an hls playlist until an mpd gets the same treatment
+	supportedContainers = []dtos.TranscodeContainer{dtos.TranscodeContainer_HLS, dtos.TranscodeContainer_CMAF}
+	// mpeg-ts can't carry av1 or opus, fragmented mp4 can carry all of them
+	tsVideoCodecs   = []string{"h264", "hevc"}
+	tsAudioCodecs   = []string{"aac", "mp3", "ac3"}
+	cmafVideoCodecs = []string{"h264", "hevc", "av1"}
+	cmafAudioCodecs = []string{"aac", "ac3", "opus"}
+)
+
+// NewProfile reads the transcoding profile from config and fails on values no player could
+// play, a bad profile should stop the worker at boot and not every encode
+func NewProfile(config *dtos.EnvConfig) (*dtos.TranscodeProfile, error) {
+	profile := defaultProfile
+	transcoderConfig := config.Transcoder
+	if value := strings.TrimSpace(transcoderConfig.Container); value != "" {
+		profile.Container = dtos.TranscodeContainer(strings.ToLower(value))
+	}
+	if value := strings.TrimSpace(transcoderConfig.VideoCodec); value != "" {
+		profile.VideoCodec = strings.ToLower(value)
+	}
+	if value := strings.TrimSpace(transcoderConfig.AudioCodec); value != "" {
+		profile.AudioCodec = strings.ToLower(value)
+	}
+	if value := strings.TrimSpace(transcoderConfig.Preset); value != "" {
+		profile.Preset = value
+	}
+	if value := strings.TrimSpace(transcoderConfig.CodecProfile); value != "" {
+		profile.CodecProfile = value
+	}
+	if transcoderConfig.SegmentLength != 0 {
+		profile.SegmentLength = transcoderConfig.SegmentLength
+	}
+	if transcoderConfig.AudioChannels != 0 {
+		profile.AudioChannels = transcoderConfig.AudioChannels
+	}
+	renditions, err := parseRenditions(config.Video.Renditions)
+	if err != nil {
+		return nil, err
+	}
+	profile.Renditions = renditions
+	if err := validateProfile(profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func validateProfile(profile dtos.TranscodeProfile) error {
+	if !slices.Contains(supportedContainers, profile.Container) {
+		return fmt.Errorf("Error in transcoding profile: unsupported container %s", profile.Container)
+	}
+	videoCodecs, audioCodecs := tsVideoCodecs, tsAudioCodecs
+	if profile.Container == dtos.TranscodeContainer_CMAF {
+		videoCodecs, audioCodecs = cmafVideoCodecs, cmafAudioCodecs
+	}
+	if !slices.Contains(videoCodecs, profile.VideoCodec) {
+		return fmt.Errorf("Error in transcoding profile: video codec %s can't be used with %s", profile.VideoCodec, profile.Container)
+	}
+	if !slices.Contains(audioCodecs, profile.AudioCodec) {
+		return fmt.Errorf("Error in transcoding profile: audio codec %s can't be used with %s", profile.AudioCodec, profile.Container)
+	}
+	if profile.SegmentLength < 1 || profile.SegmentLength > maxSegmentLength {
+		return fmt.Errorf("Error in transcoding profile: segment length should be between 1 and %d seconds", maxSegmentLength)
+	}
+	if profile.AudioChannels < 1 || profile.AudioChannels > maxAudioChannels {
+		return fmt.Errorf("Error in transcoding profile: audio channels should be between 1 and %d", maxAudioChannels)
+	}
+	return nil
+}
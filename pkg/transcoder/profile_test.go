@@ -0,0 +1,97 @@
+package transcoder
+
+import (
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"testing"
+)
+
+func TestNewProfile(t *testing.T) {
+	tests := []struct {
+		name               string
+		video              dtos.VideoEnvConfig
+		transcoder         dtos.TranscoderEnvConfig
+		wantErr            bool
+		expectedContainer  dtos.TranscodeContainer
+		expectedRenditions []string
+	}{
+		{
+			name:               "defaults",
+			expectedContainer:  dtos.TranscodeContainer_HLS,
+			expectedRenditions: []string{"360p", "480p", "720p", "1080p"},
+		},
+		{
+			name:               "custom ladder is sorted by height",
+			video:              dtos.VideoEnvConfig{Renditions: "720p, 540p:540:2000:128 ,360p"},
+			expectedContainer:  dtos.TranscodeContainer_HLS,
+			expectedRenditions: []string{"360p", "540p", "720p"},
+		},
+		{
+			name:               "cmaf with av1",
+			transcoder:         dtos.TranscoderEnvConfig{Container: "CMAF", VideoCodec: "av1", AudioCodec: "opus"},
+			expectedContainer:  dtos.TranscodeContainer_CMAF,
+			expectedRenditions: []string{"360p", "480p", "720p", "1080p"},
+		},
+		{
+			name:       "ts can't carry av1",
+			transcoder: dtos.TranscoderEnvConfig{VideoCodec: "av1"},
+			wantErr:    true,
+		},
+		{
+			name:       "dash is not supported",
+			transcoder: dtos.TranscoderEnvConfig{Container: "dash"},
+			wantErr:    true,
+		},
+		{
+			name:       "segment too long",
+			transcoder: dtos.TranscoderEnvConfig{SegmentLength: maxSegmentLength + 1},
+			wantErr:    true,
+		},
+		{
+			name:    "unknown preset rendition",
+			video:   dtos.VideoEnvConfig{Renditions: "4k"},
+			wantErr: true,
+		},
+		{
+			name:    "odd height",
+			video:   dtos.VideoEnvConfig{Renditions: "541p:541:2000:128"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicated rendition",
+			video:   dtos.VideoEnvConfig{Renditions: "360p,360p"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile, err := NewProfile(&dtos.EnvConfig{Video: test.video, Transcoder: test.transcoder})
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got profile %+v", profile)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if profile.Container != test.expectedContainer {
+				t.Errorf("container = %s, want %s", profile.Container, test.expectedContainer)
+			}
+			if len(profile.Renditions) != len(test.expectedRenditions) {
+				t.Fatalf("renditions = %+v, want %v", profile.Renditions, test.expectedRenditions)
+			}
+			for index, name := range test.expectedRenditions {
+				if profile.Renditions[index].Name != name {
+					t.Errorf("rendition %d = %s, want %s", index, profile.Renditions[index].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTranscoderSvcUnknownBackend(t *testing.T) {
+	_, err := NewTranscoderSvc(&dtos.EnvConfig{Transcoder: dtos.TranscoderEnvConfig{Backend: "gpu"}})
+	if err == nil {
+		t.Fatal("expected an unknown backend to fail")
+	}
+}
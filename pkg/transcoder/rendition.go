@@ -0,0 +1,85 @@
+package transcoder
+
+import (
+	"fmt"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var defaultRenditions = []dtos.VideoRendition{
+	{Name: "360p", Height: 360, VideoBitrate: "800k", MaxRate: "856k", BufSize: "1200k", AudioBitrate: "96k"},
+	{Name: "480p", Height: 480, VideoBitrate: "1400k", MaxRate: "1498k", BufSize: "2100k", AudioBitrate: "128k"},
+	{Name: "720p", Height: 720, VideoBitrate: "2800k", MaxRate: "2996k", BufSize: "4200k", AudioBitrate: "128k"},
+	{Name: "1080p", Height: 1080, VideoBitrate: "5000k", MaxRate: "5350k", BufSize: "7500k", AudioBitrate: "192k"},
+}
+
+// parseRenditions reads the ladder from config, every item is either a preset name (720p)
+// or a custom rendition as name:height:video_kbps:audio_kbps (540p:540:2000:128)
+func parseRenditions(value string) ([]dtos.VideoRendition, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultRenditions, nil
+	}
+	items := strings.Split(value, ",")
+	renditions := make([]dtos.VideoRendition, 0, len(items))
+	names := make(map[string]bool)
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		rendition, err := parseRendition(item)
+		if err != nil {
+			return nil, err
+		}
+		if names[rendition.Name] {
+			return nil, fmt.Errorf("Error in parsing video renditions: duplicated rendition %s", rendition.Name)
+		}
+		names[rendition.Name] = true
+		renditions = append(renditions, *rendition)
+	}
+	if len(renditions) == 0 {
+		return defaultRenditions, nil
+	}
+	sort.Slice(renditions, func(i, j int) bool {
+		return renditions[i].Height < renditions[j].Height
+	})
+	return renditions, nil
+}
+
+func parseRendition(item string) (*dtos.VideoRendition, error) {
+	parts := strings.Split(item, ":")
+	if len(parts) == 1 {
+		for _, rendition := range defaultRenditions {
+			if rendition.Name == item {
+				return &rendition, nil
+			}
+		}
+		return nil, fmt.Errorf("Error in parsing video renditions: unknown rendition %s", item)
+	}
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("Error in parsing video renditions: invalid rendition %s", item)
+	}
+	values := make([]int, 3)
+	for index, part := range parts[1:] {
+		parsedValue, err := strconv.Atoi(part)
+		if err != nil || parsedValue <= 0 {
+			return nil, fmt.Errorf("Error in parsing video renditions: invalid rendition %s", item)
+		}
+		values[index] = parsedValue
+	}
+	height, videoKbps, audioKbps := values[0], values[1], values[2]
+	if height%2 != 0 {
+		return nil, fmt.Errorf("Error in parsing video renditions: height of %s should be even", item)
+	}
+	return &dtos.VideoRendition{
+		Name:         parts[0],
+		Height:       height,
+		VideoBitrate: fmt.Sprintf("%dk", videoKbps),
+		MaxRate:      fmt.Sprintf("%dk", videoKbps*107/100),
+		BufSize:      fmt.Sprintf("%dk", videoKbps*3/2),
+		AudioBitrate: fmt.Sprintf("%dk", audioKbps),
+	}, nil
+}
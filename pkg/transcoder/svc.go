@@ -0,0 +1,31 @@
+package transcoder
+
+import (
+	"fmt"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/pkg/ffmpeg/v1"
+	"github.com/ladmakhi81/learnup/pkg/transcoder/fake"
+	"strings"
+)
+
+const (
+	Backend_Ffmpeg = "ffmpeg"
+	Backend_Fake   = "fake"
+)
+
+// NewTranscoderSvc builds the backend chosen in config with the configured profile
+func NewTranscoderSvc(config *dtos.EnvConfig) (contracts.Transcoder, error) {
+	profile, err := NewProfile(config)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(strings.TrimSpace(config.Transcoder.Backend)) {
+	case "", Backend_Ffmpeg:
+		return ffmpegv1.NewFfmpegSvc(config, profile), nil
+	case Backend_Fake:
+		return faketranscoder.NewFakeTranscoderSvc(config, profile), nil
+	default:
+		return nil, fmt.Errorf("Error in creating transcoder: unknown backend %s", config.Transcoder.Backend)
+	}
+}
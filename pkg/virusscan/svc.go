@@ -0,0 +1,30 @@
+package virusscan
+
+import (
+	"fmt"
+	"github.com/ladmakhi81/learnup/pkg/clamav/v1"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/pkg/virusscan/noop"
+	"strings"
+)
+
+const (
+	Backend_None   = "none"
+	Backend_Clamav = "clamav"
+)
+
+// NewVirusScannerSvc builds the scanner chosen in config, uploads are checked by it before being stored
+func NewVirusScannerSvc(config *dtos.EnvConfig) (contracts.VirusScanner, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Resource.ScannerBackend)) {
+	case "", Backend_None:
+		return noopscanner.NewNoopScannerSvc(), nil
+	case Backend_Clamav:
+		if config.Resource.ClamavAddress == "" {
+			return nil, fmt.Errorf("Error in creating virus scanner: clamav address is empty")
+		}
+		return clamavv1.NewClamavSvc(config), nil
+	default:
+		return nil, fmt.Errorf("Error in creating virus scanner: unknown backend %s", config.Resource.ScannerBackend)
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"github.com/ladmakhi81/learnup/pkg/dtos"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"time"
@@ -42,6 +43,9 @@ func (db *Database) Connect() error {
 		entities = append(entities, entity)
 	}
 
+	if err := dedupeCourseParticipants(coreDb); err != nil {
+		return err
+	}
 	if err := coreDb.Debug().AutoMigrate(entities...); err != nil {
 		return err
 	}
@@ -61,3 +65,44 @@ func (db *Database) getConnection() string {
 		dbHost, dbUser, dbPassword, dbName, dbPort,
 	)
 }
+
+// dedupeCourseParticipants keeps one row per course and student so AutoMigrate can build the
+// unique index over students enrolled twice before it existed, the earliest enrollment stays
+// with the longest access of its duplicates
+func dedupeCourseParticipants(coreDb *gorm.DB) error {
+	migrator := coreDb.Migrator()
+	participant := &entities.CourseParticipant{}
+	if !migrator.HasTable(participant) || migrator.HasIndex(participant, "idx_course_participant_student") {
+		return nil
+	}
+	return coreDb.Transaction(func(tx *gorm.DB) error {
+		// a seat without expiry outlasts every dated one
+		if migrator.HasColumn(participant, "expires_at") {
+			err := tx.Exec(`
+				UPDATE _course_participants AS participant
+				SET expires_at = duplicate.expires_at
+				FROM (
+					SELECT course_id, student_id,
+						CASE WHEN bool_or(expires_at IS NULL) THEN NULL ELSE max(expires_at) END AS expires_at
+					FROM _course_participants
+					GROUP BY course_id, student_id
+					HAVING count(*) > 1
+				) AS duplicate
+				WHERE participant.course_id = duplicate.course_id AND participant.student_id = duplicate.student_id
+			`).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Exec(`
+			DELETE FROM _course_participants
+			WHERE ctid IN (
+				SELECT ctid FROM (
+					SELECT ctid, row_number() OVER (PARTITION BY course_id, student_id ORDER BY created_at) AS position
+					FROM _course_participants
+				) AS ranked
+				WHERE position > 1
+			)
+		`).Error
+	})
+}
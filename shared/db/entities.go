@@ -6,22 +6,40 @@ import (
 
 func LoadEntities() map[string]any {
 	return map[string]any{
-		"user":               &entities.User{},
-		"category":           &entities.Category{},
-		"course":             &entities.Course{},
-		"video":              &entities.Video{},
-		"notification":       &entities.Notification{},
-		"comment":            &entities.Comment{},
-		"like":               &entities.Like{},
-		"question":           &entities.Question{},
-		"question_answer":    &entities.QuestionAnswer{},
-		"cart":               &entities.Cart{},
-		"order":              &entities.Order{},
-		"order_items":        &entities.OrderItem{},
-		"payment":            &entities.Payment{},
-		"transaction":        &entities.Transaction{},
-		"course_forum":       &entities.CourseForum{},
-		"course_participant": &entities.CourseParticipant{},
-		"course_message":     &entities.ForumMessage{},
+		"user":                     &entities.User{},
+		"category":                 &entities.Category{},
+		"course":                   &entities.Course{},
+		"video":                    &entities.Video{},
+		"notification":             &entities.Notification{},
+		"comment":                  &entities.Comment{},
+		"like":                     &entities.Like{},
+		"question":                 &entities.Question{},
+		"question_answer":          &entities.QuestionAnswer{},
+		"cart":                     &entities.Cart{},
+		"order":                    &entities.Order{},
+		"order_items":              &entities.OrderItem{},
+		"payment":                  &entities.Payment{},
+		"transaction":              &entities.Transaction{},
+		"course_forum":             &entities.CourseForum{},
+		"course_participant":       &entities.CourseParticipant{},
+		"course_message":           &entities.ForumMessage{},
+		"course_version":           &entities.CourseVersion{},
+		"video_progress":           &entities.VideoProgress{},
+		"course_announcement":      &entities.CourseAnnouncement{},
+		"enrollment_audit":         &entities.EnrollmentAudit{},
+		"video_key":                &entities.VideoKey{},
+		"video_caption":            &entities.VideoCaption{},
+		"upload":                   &entities.Upload{},
+		"video_note":               &entities.VideoNote{},
+		"video_preview_view":       &entities.VideoPreviewView{},
+		"course_resource":          &entities.CourseResource{},
+		"course_resource_download": &entities.CourseResourceDownload{},
+		"quiz":                     &entities.Quiz{},
+		"quiz_question":            &entities.QuizQuestion{},
+		"quiz_attempt":             &entities.QuizAttempt{},
+		"quiz_answer":              &entities.QuizAnswer{},
+		"assignment":               &entities.Assignment{},
+		"assignment_criterion":     &entities.AssignmentCriterion{},
+		"assignment_submission":    &entities.AssignmentSubmission{},
 	}
 }
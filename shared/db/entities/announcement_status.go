@@ -0,0 +1,9 @@
+package entities
+
+type AnnouncementStatus string
+
+const (
+	AnnouncementStatus_Scheduled AnnouncementStatus = "scheduled"
+	AnnouncementStatus_Sent      AnnouncementStatus = "sent"
+	AnnouncementStatus_Cancelled AnnouncementStatus = "cancelled"
+)
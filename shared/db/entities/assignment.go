@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"math"
+	"time"
+)
+
+type Assignment struct {
+	gorm.Model
+	CourseID    uint    `gorm:"column:course_id;type:int;not null;index;"`
+	Course      *Course `gorm:"foreignKey:course_id;"`
+	Title       string  `gorm:"column:title;type:varchar(255);not null;"`
+	Description string  `gorm:"column:description;type:text;"`
+	// assignments without a due date can be submitted at any time
+	DueAt                *time.Time `gorm:"column:due_at;type:timestamp;"`
+	AllowLateSubmissions bool       `gorm:"column:allow_late_submissions;type:boolean;default:false;"`
+	// last moment a late submission is accepted, no cutoff when it's empty
+	LateUntil *time.Time `gorm:"column:late_until;type:timestamp;"`
+	// percent of the max score deducted for every started day a submission is late
+	LatePenalty float64                `gorm:"column:late_penalty;type:double precision;not null;default:0;"`
+	IsPublished bool                   `gorm:"column:is_published;type:boolean;default:false;"`
+	Criteria    []*AssignmentCriterion `gorm:"foreignKey:assignment_id"`
+}
+
+func (Assignment) TableName() string {
+	return "_assignments"
+}
+
+// MaxScore is the sum of the rubric, Criteria has to be loaded
+func (assignment Assignment) MaxScore() float64 {
+	var maxScore float64
+	for _, criterion := range assignment.Criteria {
+		maxScore += criterion.Points
+	}
+	return maxScore
+}
+
+// LateDays counts every started day after the due date, a day and a minute late is two days
+func (assignment Assignment) LateDays(at time.Time) uint {
+	if assignment.DueAt == nil || !at.After(*assignment.DueAt) {
+		return 0
+	}
+	return uint(math.Ceil(at.Sub(*assignment.DueAt).Hours() / 24))
+}
+
+// AcceptsSubmissionAt applies the late submission rules
+func (assignment Assignment) AcceptsSubmissionAt(at time.Time) bool {
+	if assignment.LateDays(at) == 0 {
+		return true
+	}
+	if !assignment.AllowLateSubmissions {
+		return false
+	}
+	return assignment.LateUntil == nil || !at.After(*assignment.LateUntil)
+}
+
+// AssignmentCriterion is a line of the rubric, submissions are graded on every criterion
+type AssignmentCriterion struct {
+	gorm.Model
+	AssignmentID uint        `gorm:"column:assignment_id;type:int;not null;index;"`
+	Assignment   *Assignment `gorm:"foreignKey:assignment_id;"`
+	Title        string      `gorm:"column:title;type:varchar(255);not null;"`
+	Description  string      `gorm:"column:description;type:text;"`
+	Points       float64     `gorm:"column:points;type:double precision;not null;"`
+	Position     uint        `gorm:"column:position;type:int;not null;default:0;"`
+}
+
+func (AssignmentCriterion) TableName() string {
+	return "_assignment_criteria"
+}
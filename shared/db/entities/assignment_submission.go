@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type AssignmentSubmissionStatus string
+
+const (
+	AssignmentSubmissionStatus_Submitted AssignmentSubmissionStatus = "submitted"
+	AssignmentSubmissionStatus_Graded    AssignmentSubmissionStatus = "graded"
+	// AssignmentSubmissionStatus_ResubmissionRequested is graded, the student may hand in another attempt
+	AssignmentSubmissionStatus_ResubmissionRequested AssignmentSubmissionStatus = "resubmission_requested"
+)
+
+// AssignmentCriterionScore is the grade of a single rubric criterion
+type AssignmentCriterionScore struct {
+	CriterionID uint    `json:"criterionId"`
+	Score       float64 `json:"score"`
+	Comment     string  `json:"comment"`
+}
+
+// AssignmentSubmission is one attempt of a student, a resubmission is a new row with the next attempt number
+type AssignmentSubmission struct {
+	gorm.Model
+	AssignmentID uint        `gorm:"column:assignment_id;type:int;not null;uniqueIndex:idx_assignment_submission_attempt;"`
+	Assignment   *Assignment `gorm:"foreignKey:assignment_id;"`
+	UserID       uint        `gorm:"column:user_id;type:int;not null;uniqueIndex:idx_assignment_submission_attempt;index;"`
+	User         *User       `gorm:"foreignKey:user_id;"`
+	Attempt      uint        `gorm:"column:attempt;type:int;not null;uniqueIndex:idx_assignment_submission_attempt;"`
+	CourseID     uint        `gorm:"column:course_id;type:int;not null;index;"`
+	Course       *Course     `gorm:"foreignKey:course_id;"`
+	Text         string      `gorm:"column:text;type:text;"`
+	// the file fields stay empty for text only submissions
+	FileName    string                     `gorm:"column:file_name;type:varchar(255);"`
+	ContentType string                     `gorm:"column:content_type;type:varchar(255);"`
+	Size        int64                      `gorm:"column:size;type:bigint;not null;default:0;"`
+	ObjectPath  string                     `gorm:"column:object_path;type:text;"`
+	ScanStatus  ResourceScanStatus         `gorm:"column:scan_status;type:varchar(255);"`
+	SubmittedAt time.Time                  `gorm:"column:submitted_at;type:timestamp;not null;"`
+	LateDays    uint                       `gorm:"column:late_days;type:int;not null;default:0;"`
+	Status      AssignmentSubmissionStatus `gorm:"column:status;type:varchar(255);not null;index;"`
+	// grading fields, Score is the rubric total and FinalScore is what is left after the late penalty
+	CriterionScores []AssignmentCriterionScore `gorm:"column:criterion_scores;type:text;serializer:json"`
+	Score           *float64                   `gorm:"column:score;type:double precision;"`
+	Penalty         float64                    `gorm:"column:penalty;type:double precision;not null;default:0;"`
+	FinalScore      *float64                   `gorm:"column:final_score;type:double precision;"`
+	MaxScore        float64                    `gorm:"column:max_score;type:double precision;not null;default:0;"`
+	Feedback        string                     `gorm:"column:feedback;type:text;"`
+	GradedAt        *time.Time                 `gorm:"column:graded_at;type:timestamp;"`
+	GradedByID      *uint                      `gorm:"column:graded_by_id;type:int;"`
+	GradedBy        *User                      `gorm:"foreignKey:graded_by_id;"`
+}
+
+func (AssignmentSubmission) TableName() string {
+	return "_assignment_submissions"
+}
+
+func (submission AssignmentSubmission) HasFile() bool {
+	return submission.ObjectPath != ""
+}
+
+func (submission AssignmentSubmission) IsGraded() bool {
+	return submission.Status != AssignmentSubmissionStatus_Submitted
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssignmentLateRules(t *testing.T) {
+	dueAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lateUntil := dueAt.Add(72 * time.Hour)
+	tests := []struct {
+		name             string
+		assignment       Assignment
+		at               time.Time
+		expectedLateDays uint
+		expectedAccepts  bool
+	}{
+		{
+			name:            "no due date is never late",
+			assignment:      Assignment{},
+			at:              dueAt.Add(1000 * time.Hour),
+			expectedAccepts: true,
+		},
+		{
+			name:            "before the due date",
+			assignment:      Assignment{DueAt: &dueAt},
+			at:              dueAt.Add(-time.Minute),
+			expectedAccepts: true,
+		},
+		{
+			name:            "right at the due date",
+			assignment:      Assignment{DueAt: &dueAt},
+			at:              dueAt,
+			expectedAccepts: true,
+		},
+		{
+			name:             "a minute late without late submissions",
+			assignment:       Assignment{DueAt: &dueAt},
+			at:               dueAt.Add(time.Minute),
+			expectedLateDays: 1,
+		},
+		{
+			name:             "a day and a minute late counts two days",
+			assignment:       Assignment{DueAt: &dueAt, AllowLateSubmissions: true},
+			at:               dueAt.Add(24*time.Hour + time.Minute),
+			expectedLateDays: 2,
+			expectedAccepts:  true,
+		},
+		{
+			name:             "exactly a day late",
+			assignment:       Assignment{DueAt: &dueAt, AllowLateSubmissions: true},
+			at:               dueAt.Add(24 * time.Hour),
+			expectedLateDays: 1,
+			expectedAccepts:  true,
+		},
+		{
+			name:             "late until is inclusive",
+			assignment:       Assignment{DueAt: &dueAt, AllowLateSubmissions: true, LateUntil: &lateUntil},
+			at:               lateUntil,
+			expectedLateDays: 3,
+			expectedAccepts:  true,
+		},
+		{
+			name:             "after late until",
+			assignment:       Assignment{DueAt: &dueAt, AllowLateSubmissions: true, LateUntil: &lateUntil},
+			at:               lateUntil.Add(time.Second),
+			expectedLateDays: 4,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if lateDays := test.assignment.LateDays(test.at); lateDays != test.expectedLateDays {
+				t.Errorf("LateDays = %d, want %d", lateDays, test.expectedLateDays)
+			}
+			if accepts := test.assignment.AcceptsSubmissionAt(test.at); accepts != test.expectedAccepts {
+				t.Errorf("AcceptsSubmissionAt = %v, want %v", accepts, test.expectedAccepts)
+			}
+		})
+	}
+}
+
+func TestAssignmentMaxScore(t *testing.T) {
+	assignment := Assignment{Criteria: []*AssignmentCriterion{{Points: 4}, {Points: 2.5}, {Points: 3.5}}}
+	if maxScore := assignment.MaxScore(); maxScore != 10 {
+		t.Errorf("MaxScore = %v, want 10", maxScore)
+	}
+}
@@ -37,6 +37,11 @@ type Course struct {
 	Participants                []*CourseParticipant    `gorm:"foreignKey:course_id"`
 	ForumID                     *uint                   `gorm:"column:forum_id;type:int;"`
 	Forum                       *CourseForum            `gorm:"foreignKey:forum_id"`
+	PublishAt                   *time.Time              `gorm:"column:publish_at;type:timestamp;default:null"`
+	UnpublishAt                 *time.Time              `gorm:"column:unpublish_at;type:timestamp;default:null"`
+	CurrentVersion              uint                    `gorm:"column:current_version;type:int;not null;default:0"`
+	WatermarkMode               CourseWatermarkMode     `gorm:"column:watermark_mode;type:varchar(255);not null;default:'none'"`
+	Versions                    []*CourseVersion        `gorm:"foreignKey:course_id"`
 }
 
 func (Course) TableName() string {
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type CourseAnnouncement struct {
+	gorm.Model
+	CourseID        uint               `gorm:"column:course_id;type:int;not null;index"`
+	Course          *Course            `gorm:"foreignKey:course_id"`
+	TeacherID       uint               `gorm:"column:teacher_id;type:int;not null;index"`
+	Teacher         *User              `gorm:"foreignKey:teacher_id"`
+	Title           string             `gorm:"column:title;type:varchar(255);not null"`
+	Content         string             `gorm:"column:content;type:text;not null"`
+	SendEmail       bool               `gorm:"column:send_email;type:boolean;default:false"`
+	Status          AnnouncementStatus `gorm:"column:status;type:varchar(255);not null;default:'scheduled'"`
+	ScheduledAt     *time.Time         `gorm:"column:scheduled_at;type:timestamp;default:null"`
+	SentAt          *time.Time         `gorm:"column:sent_at;type:timestamp;default:null"`
+	RecipientsCount int                `gorm:"column:recipients_count;type:int;not null;default:0"`
+}
+
+func (CourseAnnouncement) TableName() string {
+	return "_course_announcements"
+}
+
+func (announcement CourseAnnouncement) IsSent() bool {
+	return announcement.Status == AnnouncementStatus_Sent
+}
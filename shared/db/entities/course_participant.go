@@ -5,14 +5,22 @@ import (
 )
 
 type CourseParticipant struct {
-	CourseID           uint       `gorm:"column:course_id;type:int;index;"`
-	StudentID          uint       `gorm:"column:student_id;type:int;index;"`
-	Student            *User      `gorm:"foreignKey:student_id"`
-	TeacherID          uint       `gorm:"column:teacher_id;type:int;not null"`
-	LastVideoWatchDate *time.Time `gorm:"column:last_video_watch_date;type:timestamp;default:null"`
+	CourseID           uint             `gorm:"column:course_id;type:int;index;uniqueIndex:idx_course_participant_student;"`
+	Course             *Course          `gorm:"foreignKey:course_id"`
+	StudentID          uint             `gorm:"column:student_id;type:int;index;uniqueIndex:idx_course_participant_student;"`
+	Student            *User            `gorm:"foreignKey:student_id"`
+	TeacherID          uint             `gorm:"column:teacher_id;type:int;not null"`
+	LastVideoWatchDate *time.Time       `gorm:"column:last_video_watch_date;type:timestamp;default:null"`
+	CourseVersion      uint             `gorm:"column:course_version;type:int;not null;default:0"`
+	Source             EnrollmentAction `gorm:"column:source;type:varchar(255);not null;default:'purchase'"`
+	ExpiresAt          *time.Time       `gorm:"column:expires_at;type:timestamp;default:null"`
 	CreatedAt          time.Time
 }
 
 func (CourseParticipant) TableName() string {
 	return "_course_participants"
 }
+
+func (participant CourseParticipant) IsActive() bool {
+	return participant.ExpiresAt == nil || participant.ExpiresAt.After(time.Now())
+}
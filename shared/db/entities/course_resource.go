@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type ResourceScanStatus string
+
+const (
+	// ResourceScanStatus_Clean passed the virus scanner, infected files are rejected before being stored
+	ResourceScanStatus_Clean ResourceScanStatus = "clean"
+	// ResourceScanStatus_Unscanned is stored while no scanner is configured
+	ResourceScanStatus_Unscanned ResourceScanStatus = "unscanned"
+)
+
+// CourseResource is a downloadable file of a course, VideoID attaches it to a single lesson
+// and a resource without video belongs to the whole course
+type CourseResource struct {
+	gorm.Model
+	CourseID      uint               `gorm:"column:course_id;type:int;not null;index;"`
+	Course        *Course            `gorm:"foreignKey:course_id;"`
+	VideoID       *uint              `gorm:"column:video_id;type:int;index;"`
+	Video         *Video             `gorm:"foreignKey:video_id;"`
+	Title         string             `gorm:"column:title;type:varchar(255);not null;"`
+	FileName      string             `gorm:"column:file_name;type:varchar(255);not null;"`
+	ContentType   string             `gorm:"column:content_type;type:varchar(255);not null;"`
+	Size          int64              `gorm:"column:size;type:bigint;not null;"`
+	ObjectPath    string             `gorm:"column:object_path;type:text;not null;"`
+	ScanStatus    ResourceScanStatus `gorm:"column:scan_status;type:varchar(255);not null;"`
+	ScannedAt     *time.Time         `gorm:"column:scanned_at;type:timestamp;"`
+	DownloadCount int                `gorm:"column:download_count;type:int;not null;default:0;"`
+}
+
+func (CourseResource) TableName() string {
+	return "_course_resources"
+}
+
+type CourseResourceDownload struct {
+	gorm.Model
+	ResourceID       uint            `gorm:"column:resource_id;type:int;not null;uniqueIndex:idx_resource_download_user;"`
+	Resource         *CourseResource `gorm:"foreignKey:resource_id;"`
+	UserID           uint            `gorm:"column:user_id;type:int;not null;uniqueIndex:idx_resource_download_user;"`
+	User             *User           `gorm:"foreignKey:user_id;"`
+	Count            int             `gorm:"column:count;type:int;not null;default:0;"`
+	LastDownloadedAt time.Time       `gorm:"column:last_downloaded_at;type:timestamp;not null;"`
+}
+
+func (CourseResourceDownload) TableName() string {
+	return "_course_resource_downloads"
+}
@@ -0,0 +1,104 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+)
+
+type CourseVersionVideo struct {
+	VideoID       uint                `json:"videoId"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	AccessLevel   VideoAccessLevel    `json:"accessLevel"`
+	Duration      *string             `json:"duration"`
+	URL           string              `json:"url"`
+	ThumbnailURL  *string             `json:"thumbnailUrl"`
+	WatermarkMode CourseWatermarkMode `json:"watermarkMode"`
+	IsPreview     bool                `json:"isPreview"`
+}
+
+type CourseVersionSnapshot struct {
+	Name              string               `json:"name"`
+	Description       string               `json:"description"`
+	Prerequisite      string               `json:"prerequisite"`
+	Level             CourseLevel          `json:"level"`
+	Tags              []string             `json:"tags"`
+	Image             string               `json:"image"`
+	ThumbnailImage    string               `json:"thumbnailImage"`
+	IntroductionVideo string               `json:"introductionVideo"`
+	Videos            []CourseVersionVideo `json:"videos"`
+}
+
+type CourseVersion struct {
+	gorm.Model
+	CourseID      uint                  `gorm:"column:course_id;type:int;not null;index"`
+	Course        *Course               `gorm:"foreignKey:course_id"`
+	Version       uint                  `gorm:"column:version;type:int;not null"`
+	Changelog     string                `gorm:"column:changelog;type:text;"`
+	PublishedByID uint                  `gorm:"column:published_by_id;type:int;not null"`
+	PublishedBy   *User                 `gorm:"foreignKey:published_by_id"`
+	Snapshot      CourseVersionSnapshot `gorm:"column:snapshot;type:text;serializer:json;not null"`
+}
+
+func (CourseVersion) TableName() string {
+	return "_course_versions"
+}
+
+// NewCourseVersion freezes the course metadata and its encoded videos, students
+// of this version keep watching these urls even if the course changes later
+func NewCourseVersion(course *Course, videos []*Video, version uint, publishedByID uint, changelog string) *CourseVersion {
+	snapshotVideos := make([]CourseVersionVideo, 0, len(videos))
+	for _, video := range videos {
+		if video.Status != VideoStatus_Done {
+			continue
+		}
+		snapshotVideos = append(snapshotVideos, CourseVersionVideo{
+			VideoID:       video.ID,
+			Title:         video.Title,
+			Description:   video.Description,
+			AccessLevel:   video.AccessLevel,
+			Duration:      video.Duration,
+			URL:           video.URL,
+			ThumbnailURL:  video.ThumbnailURL,
+			WatermarkMode: video.WatermarkMode,
+			IsPreview:     video.IsPreview,
+		})
+	}
+	return &CourseVersion{
+		CourseID:      course.ID,
+		Version:       version,
+		Changelog:     changelog,
+		PublishedByID: publishedByID,
+		Snapshot: CourseVersionSnapshot{
+			Name:              course.Name,
+			Description:       course.Description,
+			Prerequisite:      course.Prerequisite,
+			Level:             course.Level,
+			Tags:              course.Tags,
+			Image:             course.Image,
+			ThumbnailImage:    course.ThumbnailImage,
+			IntroductionVideo: course.IntroductionVideo,
+			Videos:            snapshotVideos,
+		},
+	}
+}
+
+func (courseVersion CourseVersion) GetVideos() []*Video {
+	videos := make([]*Video, len(courseVersion.Snapshot.Videos))
+	for index, snapshotVideo := range courseVersion.Snapshot.Videos {
+		videos[index] = &Video{
+			Model:         gorm.Model{ID: snapshotVideo.VideoID},
+			CourseId:      &courseVersion.CourseID,
+			Title:         snapshotVideo.Title,
+			Description:   snapshotVideo.Description,
+			AccessLevel:   snapshotVideo.AccessLevel,
+			Duration:      snapshotVideo.Duration,
+			URL:           snapshotVideo.URL,
+			ThumbnailURL:  snapshotVideo.ThumbnailURL,
+			WatermarkMode: snapshotVideo.WatermarkMode,
+			IsPreview:     snapshotVideo.IsPreview,
+			Status:        VideoStatus_Done,
+			IsPublished:   true,
+		}
+	}
+	return videos
+}
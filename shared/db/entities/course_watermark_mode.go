@@ -0,0 +1,15 @@
+package entities
+
+type CourseWatermarkMode string
+
+const (
+	CourseWatermarkMode_None CourseWatermarkMode = "none"
+	// CourseWatermarkMode_Static stamps the platform logo and the course identifier
+	CourseWatermarkMode_Static CourseWatermarkMode = "static"
+	// CourseWatermarkMode_Forensic also encodes a/b variants, each viewer gets a unique mix of them
+	CourseWatermarkMode_Forensic CourseWatermarkMode = "forensic"
+)
+
+func (mode CourseWatermarkMode) IsMarked() bool {
+	return mode == CourseWatermarkMode_Static || mode == CourseWatermarkMode_Forensic
+}
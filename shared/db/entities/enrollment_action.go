@@ -0,0 +1,12 @@
+package entities
+
+type EnrollmentAction string
+
+const (
+	EnrollmentAction_Purchase     EnrollmentAction = "purchase"
+	EnrollmentAction_Free         EnrollmentAction = "free"
+	EnrollmentAction_Add          EnrollmentAction = "add"
+	EnrollmentAction_BulkAdd      EnrollmentAction = "bulk-add"
+	EnrollmentAction_Remove       EnrollmentAction = "remove"
+	EnrollmentAction_UpdateExpiry EnrollmentAction = "update-expiry"
+)
@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type EnrollmentAudit struct {
+	gorm.Model
+	CourseID  uint             `gorm:"column:course_id;type:int;not null;index"`
+	Course    *Course          `gorm:"foreignKey:course_id"`
+	StudentID uint             `gorm:"column:student_id;type:int;not null;index"`
+	Student   *User            `gorm:"foreignKey:student_id"`
+	ActorID   uint             `gorm:"column:actor_id;type:int;not null;index"`
+	Actor     *User            `gorm:"foreignKey:actor_id"`
+	Action    EnrollmentAction `gorm:"column:action;type:varchar(255);not null"`
+	Reason    string           `gorm:"column:reason;type:text;not null"`
+	ExpiresAt *time.Time       `gorm:"column:expires_at;type:timestamp;default:null"`
+}
+
+func (EnrollmentAudit) TableName() string {
+	return "_enrollment_audits"
+}
@@ -14,6 +14,9 @@ type Notification struct {
 	UserID   *uint            `gorm:"column:user_id;type:int;index;not null;"`
 	User     *User            `gorm:"foreignKey:user_id;"`
 	Metadata any              `gorm:"column:metadata;type:text;serializer:json;not null;"`
+
+	AnnouncementID *uint               `gorm:"column:announcement_id;type:int;index;default:null;"`
+	Announcement   *CourseAnnouncement `gorm:"foreignKey:announcement_id;"`
 }
 
 func (Notification) TableName() string {
@@ -4,6 +4,16 @@ type NotificationType string
 
 const (
 	NotificationType_CompleteVideoUpload                   = "complete-video-upload"
+	NotificationType_VideoProcessingFailed                 = "video-processing-failed"
 	NotificationType_CompleteIntroductionCourseVideoUpload = "complete-introduction-course-video-upload"
 	NotificationType_CourseVerified                        = "course-verified"
+	NotificationType_CoursePublished                       = "course-published"
+	NotificationType_CourseUnpublished                     = "course-unpublished"
+	NotificationType_VideoPublished                        = "video-published"
+	NotificationType_VideoUnpublished                      = "video-unpublished"
+	NotificationType_CourseVersionPublished                = "course-version-published"
+	NotificationType_CourseAnnouncement                    = "course-announcement"
+	NotificationType_EnrollmentGranted                     = "enrollment-granted"
+	NotificationType_EnrollmentRevoked                     = "enrollment-revoked"
+	NotificationType_AssignmentGraded                      = "assignment-graded"
 )
@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"slices"
+	"strings"
+)
+
+type QuizQuestionType string
+
+const (
+	QuizQuestionType_SingleChoice   QuizQuestionType = "single_choice"
+	QuizQuestionType_MultipleChoice QuizQuestionType = "multiple_choice"
+	QuizQuestionType_TrueFalse      QuizQuestionType = "true_false"
+	QuizQuestionType_ShortAnswer    QuizQuestionType = "short_answer"
+)
+
+// TrueFalseOptions are the fixed options of true/false questions, the correct option is an index into them
+var TrueFalseOptions = []string{"true", "false"}
+
+type Quiz struct {
+	gorm.Model
+	CourseID uint    `gorm:"column:course_id;type:int;not null;index;"`
+	Course   *Course `gorm:"foreignKey:course_id;"`
+	// lesson the quiz follows, course wide quizzes have none
+	VideoID     *uint  `gorm:"column:video_id;type:int;index;"`
+	Video       *Video `gorm:"foreignKey:video_id;"`
+	Title       string `gorm:"column:title;type:varchar(255);not null;"`
+	Description string `gorm:"column:description;type:text;"`
+	// seconds an attempt may take, zero means no limit
+	TimeLimit uint `gorm:"column:time_limit;type:int;not null;default:0;"`
+	// zero means students can attempt as often as they want
+	MaxAttempts      uint            `gorm:"column:max_attempts;type:int;not null;default:0;"`
+	PassingScore     float64         `gorm:"column:passing_score;type:double precision;not null;"`
+	ShuffleQuestions bool            `gorm:"column:shuffle_questions;type:boolean;default:false;"`
+	IsPublished      bool            `gorm:"column:is_published;type:boolean;default:false;"`
+	Questions        []*QuizQuestion `gorm:"foreignKey:quiz_id"`
+}
+
+func (Quiz) TableName() string {
+	return "_quizzes"
+}
+
+// QuizQuestion keeps its answer key next to the question, choice questions are graded by
+// option index and short answers by their accepted answers
+type QuizQuestion struct {
+	gorm.Model
+	QuizID          uint             `gorm:"column:quiz_id;type:int;not null;index;"`
+	Quiz            *Quiz            `gorm:"foreignKey:quiz_id;"`
+	Type            QuizQuestionType `gorm:"column:type;type:varchar(255);not null;"`
+	Text            string           `gorm:"column:text;type:text;not null;"`
+	Options         []string         `gorm:"column:options;type:text;serializer:json"`
+	CorrectOptions  []int            `gorm:"column:correct_options;type:text;serializer:json"`
+	AcceptedAnswers []string         `gorm:"column:accepted_answers;type:text;serializer:json"`
+	Points          float64          `gorm:"column:points;type:double precision;not null;default:1;"`
+	Position        uint             `gorm:"column:position;type:int;not null;default:0;"`
+}
+
+func (QuizQuestion) TableName() string {
+	return "_quiz_questions"
+}
+
+// IsCorrect grades an answer all or nothing, multiple choice answers need exactly the correct options
+func (question QuizQuestion) IsCorrect(selectedOptions []int, textAnswer string) bool {
+	if question.Type == QuizQuestionType_ShortAnswer {
+		answer := NormalizeQuizAnswer(textAnswer)
+		if answer == "" {
+			return false
+		}
+		for _, acceptedAnswer := range question.AcceptedAnswers {
+			if NormalizeQuizAnswer(acceptedAnswer) == answer {
+				return true
+			}
+		}
+		return false
+	}
+	selected := slices.Clone(selectedOptions)
+	slices.Sort(selected)
+	selected = slices.Compact(selected)
+	correct := slices.Clone(question.CorrectOptions)
+	slices.Sort(correct)
+	return len(selected) > 0 && slices.Equal(selected, correct)
+}
+
+// NormalizeQuizAnswer ignores case and extra whitespace of short answers
+func NormalizeQuizAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
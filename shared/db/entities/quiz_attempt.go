@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type QuizAttemptStatus string
+
+const (
+	QuizAttemptStatus_InProgress QuizAttemptStatus = "in_progress"
+	QuizAttemptStatus_Submitted  QuizAttemptStatus = "submitted"
+	// the time limit passed before the attempt was submitted, it counts with no score
+	QuizAttemptStatus_Expired QuizAttemptStatus = "expired"
+)
+
+type QuizAttempt struct {
+	gorm.Model
+	QuizID   uint              `gorm:"column:quiz_id;type:int;not null;index;uniqueIndex:idx_quiz_attempt_number;"`
+	Quiz     *Quiz             `gorm:"foreignKey:quiz_id;"`
+	UserID   uint              `gorm:"column:user_id;type:int;not null;index;uniqueIndex:idx_quiz_attempt_number;"`
+	User     *User             `gorm:"foreignKey:user_id;"`
+	CourseID uint              `gorm:"column:course_id;type:int;not null;index;"`
+	Number   uint              `gorm:"column:number;type:int;not null;uniqueIndex:idx_quiz_attempt_number;"`
+	Status   QuizAttemptStatus `gorm:"column:status;type:varchar(255);not null;"`
+	// order the questions were handed out in, shuffled when the quiz asks for it
+	QuestionOrder []uint        `gorm:"column:question_order;type:text;serializer:json"`
+	StartedAt     time.Time     `gorm:"column:started_at;type:timestamp;not null;"`
+	ExpiresAt     *time.Time    `gorm:"column:expires_at;type:timestamp;default:null"`
+	SubmittedAt   *time.Time    `gorm:"column:submitted_at;type:timestamp;default:null"`
+	Score         float64       `gorm:"column:score;type:double precision;not null;default:0;"`
+	MaxScore      float64       `gorm:"column:max_score;type:double precision;not null;default:0;"`
+	Percentage    float64       `gorm:"column:percentage;type:double precision;not null;default:0;"`
+	IsPassed      bool          `gorm:"column:is_passed;type:boolean;default:false;"`
+	Answers       []*QuizAnswer `gorm:"foreignKey:attempt_id"`
+}
+
+func (QuizAttempt) TableName() string {
+	return "_quiz_attempts"
+}
+
+// IsExpired allows a small grace period, submissions sent right at the deadline are still in flight
+func (attempt QuizAttempt) IsExpired(grace time.Duration) bool {
+	return attempt.ExpiresAt != nil && time.Now().After(attempt.ExpiresAt.Add(grace))
+}
+
+// QuizAnswer is graded when the attempt is submitted, QuizID is copied so question analytics
+// of a quiz don't join through attempts
+type QuizAnswer struct {
+	gorm.Model
+	AttemptID       uint          `gorm:"column:attempt_id;type:int;not null;uniqueIndex:idx_quiz_answer_question;"`
+	Attempt         *QuizAttempt  `gorm:"foreignKey:attempt_id;"`
+	QuestionID      uint          `gorm:"column:question_id;type:int;not null;index;uniqueIndex:idx_quiz_answer_question;"`
+	Question        *QuizQuestion `gorm:"foreignKey:question_id;"`
+	QuizID          uint          `gorm:"column:quiz_id;type:int;not null;index;"`
+	SelectedOptions []int         `gorm:"column:selected_options;type:text;serializer:json"`
+	TextAnswer      string        `gorm:"column:text_answer;type:text;"`
+	IsCorrect       bool          `gorm:"column:is_correct;type:boolean;default:false;"`
+	Points          float64       `gorm:"column:points;type:double precision;not null;default:0;"`
+}
+
+func (QuizAnswer) TableName() string {
+	return "_quiz_answers"
+}
@@ -0,0 +1,40 @@
+package entities
+
+import "testing"
+
+func TestQuizQuestionIsCorrect(t *testing.T) {
+	singleChoice := QuizQuestion{Type: QuizQuestionType_SingleChoice, CorrectOptions: []int{2}}
+	multipleChoice := QuizQuestion{Type: QuizQuestionType_MultipleChoice, CorrectOptions: []int{3, 0}}
+	trueFalse := QuizQuestion{Type: QuizQuestionType_TrueFalse, CorrectOptions: []int{0}}
+	shortAnswer := QuizQuestion{Type: QuizQuestionType_ShortAnswer, AcceptedAnswers: []string{"Go Routine", "goroutine"}}
+	tests := []struct {
+		name            string
+		question        QuizQuestion
+		selectedOptions []int
+		textAnswer      string
+		expected        bool
+	}{
+		{name: "single choice correct", question: singleChoice, selectedOptions: []int{2}, expected: true},
+		{name: "single choice wrong", question: singleChoice, selectedOptions: []int{1}},
+		{name: "single choice nothing selected", question: singleChoice},
+		{name: "single choice extra option", question: singleChoice, selectedOptions: []int{2, 1}},
+		{name: "multiple choice in any order", question: multipleChoice, selectedOptions: []int{0, 3}, expected: true},
+		{name: "multiple choice repeated option", question: multipleChoice, selectedOptions: []int{3, 0, 3}, expected: true},
+		{name: "multiple choice partial", question: multipleChoice, selectedOptions: []int{3}},
+		{name: "multiple choice too many", question: multipleChoice, selectedOptions: []int{0, 1, 3}},
+		{name: "true false correct", question: trueFalse, selectedOptions: []int{0}, expected: true},
+		{name: "true false wrong", question: trueFalse, selectedOptions: []int{1}},
+		{name: "short answer ignores case and spacing", question: shortAnswer, textAnswer: "  go   ROUTINE ", expected: true},
+		{name: "short answer second accepted answer", question: shortAnswer, textAnswer: "Goroutine", expected: true},
+		{name: "short answer wrong", question: shortAnswer, textAnswer: "thread"},
+		{name: "short answer blank", question: shortAnswer, textAnswer: "   "},
+		{name: "short answer ignores selected options", question: shortAnswer, selectedOptions: []int{0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if isCorrect := test.question.IsCorrect(test.selectedOptions, test.textAnswer); isCorrect != test.expected {
+				t.Errorf("IsCorrect(%v, %q) = %v, want %v", test.selectedOptions, test.textAnswer, isCorrect, test.expected)
+			}
+		})
+	}
+}
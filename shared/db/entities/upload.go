@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Upload follows a tus upload through its hooks, UploadID is the id tusd gave the upload and
+// the processing workflow is named after it so a redelivered hook can't process it twice
+type Upload struct {
+	gorm.Model
+	UploadID    string       `gorm:"column:upload_id;type:varchar(255);not null;uniqueIndex"`
+	ActionType  string       `gorm:"column:action_type;type:varchar(255);not null"`
+	CourseID    *uint        `gorm:"column:course_id;type:int;index"`
+	VideoID     *uint        `gorm:"column:video_id;type:int;index"`
+	ObjectKey   *string      `gorm:"column:object_key;type:varchar(255)"`
+	Size        int64        `gorm:"column:size;type:bigint;not null;default:0"`
+	Offset      int64        `gorm:"column:offset;type:bigint;not null;default:0"`
+	Status      UploadStatus `gorm:"column:status;type:varchar(255);not null;index"`
+	WorkflowID  *string      `gorm:"column:workflow_id;type:varchar(255)"`
+	FinishedAt  *time.Time   `gorm:"column:finished_at;type:timestamp"`
+	ProcessedAt *time.Time   `gorm:"column:processed_at;type:timestamp"`
+}
+
+func (Upload) TableName() string {
+	return "_uploads"
+}
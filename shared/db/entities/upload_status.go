@@ -0,0 +1,25 @@
+package entities
+
+import "slices"
+
+type UploadStatus string
+
+const (
+	UploadStatus_Created   UploadStatus = "created"
+	UploadStatus_Receiving UploadStatus = "receiving"
+	UploadStatus_Finished  UploadStatus = "finished"
+	UploadStatus_Processed UploadStatus = "processed"
+)
+
+var uploadStatusOrder = []UploadStatus{
+	UploadStatus_Created,
+	UploadStatus_Receiving,
+	UploadStatus_Finished,
+	UploadStatus_Processed,
+}
+
+// IsBefore tells whether the upload can still move to the other status, hooks may arrive late
+// or twice and an upload never goes back in its lifecycle
+func (status UploadStatus) IsBefore(other UploadStatus) bool {
+	return slices.Index(uploadStatusOrder, status) < slices.Index(uploadStatusOrder, other)
+}
@@ -11,6 +11,7 @@ type User struct {
 	FirstName string               `gorm:"column:first_name"`
 	LastName  string               `gorm:"column:last_name"`
 	Phone     string               `gorm:"index;column:phone_number"`
+	Email     *string              `gorm:"index;column:email;default:null"`
 	Password  string               `gorm:"not null;column:password"`
 	Courses   []*CourseParticipant `gorm:"foreignKey:student_id"`
 	Forums    []*CourseForum       `gorm:"foreignKey:teacher_id"`
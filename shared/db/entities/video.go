@@ -8,19 +8,30 @@ import (
 type Video struct {
 	gorm.Model
 
-	CourseId     *uint            `gorm:"column:course_id;type:int;index;"`
-	Course       *Course          `gorm:"foreignKey:course_id"`
-	Title        string           `gorm:"column:title;type:varchar(255);index;not null;"`
-	Description  string           `gorm:"column:description;type:text;not null;"`
-	AccessLevel  VideoAccessLevel `gorm:"column:access_level;type:varchar(255);not null;"`
-	IsPublished  bool             `gorm:"column:is_published;type:boolean;default:false;"`
-	IsVerified   bool             `gorm:"column:is_verified;type:boolean;default:false;"`
-	VerifiedDate *time.Time       `gorm:"column:verified_date;type:timestamp;"`
-	VerifiedById *uint            `gorm:"column:verified_by_id;type:int;index;"`
-	VerifiedBy   *User            `gorm:"foreignKey:verified_by_id;"`
-	Duration     *string          `gorm:"column:duration;type:text;"`
-	Status       VideoStatus      `gorm:"column:status;type:varchar(255);"`
-	URL          string           `gorm:"column:video_url;type:text;"`
+	CourseId       *uint            `gorm:"column:course_id;type:int;index;"`
+	Course         *Course          `gorm:"foreignKey:course_id"`
+	Title          string           `gorm:"column:title;type:varchar(255);index;not null;"`
+	Description    string           `gorm:"column:description;type:text;not null;"`
+	AccessLevel    VideoAccessLevel `gorm:"column:access_level;type:varchar(255);not null;"`
+	IsPublished    bool             `gorm:"column:is_published;type:boolean;default:false;"`
+	IsVerified     bool             `gorm:"column:is_verified;type:boolean;default:false;"`
+	VerifiedDate   *time.Time       `gorm:"column:verified_date;type:timestamp;"`
+	VerifiedById   *uint            `gorm:"column:verified_by_id;type:int;index;"`
+	VerifiedBy     *User            `gorm:"foreignKey:verified_by_id;"`
+	Duration       *string          `gorm:"column:duration;type:text;"`
+	Status         VideoStatus      `gorm:"column:status;type:varchar(255);"`
+	FailureReason  *string          `gorm:"column:failure_reason;type:text;"`
+	SourceObjectID *string          `gorm:"column:source_object_id;type:varchar(255);"`
+	// workflow processing the source, uploads and reprocessing name their workflows differently
+	ProcessingWorkflowID *string             `gorm:"column:processing_workflow_id;type:varchar(255);"`
+	URL                  string              `gorm:"column:video_url;type:text;"`
+	ThumbnailURL         *string             `gorm:"column:thumbnail_url;type:text;"`
+	ThumbnailAt          *float64            `gorm:"column:thumbnail_at;type:double precision;"`
+	WatermarkMode        CourseWatermarkMode `gorm:"column:watermark_mode;type:varchar(255);not null;default:'none'"`
+	PublishAt            *time.Time          `gorm:"column:publish_at;type:timestamp;default:null"`
+	UnpublishAt          *time.Time          `gorm:"column:unpublish_at;type:timestamp;default:null"`
+	// free preview lessons are watchable by anonymous and non enrolled users
+	IsPreview bool `gorm:"column:is_preview;type:boolean;default:false;"`
 }
 
 func (Video) TableName() string {
@@ -0,0 +1,17 @@
+package entities
+
+import "gorm.io/gorm"
+
+// VideoCaption is a webvtt subtitle track of a video, cloned videos point to the same object
+type VideoCaption struct {
+	gorm.Model
+	VideoID  uint   `gorm:"column:video_id;not null;uniqueIndex:idx_video_caption_language,where:deleted_at IS NULL"`
+	Video    *Video `gorm:"foreignKey:VideoID"`
+	Language string `gorm:"column:language;type:varchar(8);not null;uniqueIndex:idx_video_caption_language,where:deleted_at IS NULL"`
+	Label    string `gorm:"column:label;type:varchar(64);not null"`
+	URL      string `gorm:"column:url;type:varchar(255);not null"`
+}
+
+func (VideoCaption) TableName() string {
+	return "_video_captions"
+}
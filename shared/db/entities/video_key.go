@@ -0,0 +1,15 @@
+package entities
+
+import "gorm.io/gorm"
+
+// VideoKey is the aes-128 key of an encoded video, cloned videos share the encoded
+// files so the key belongs to the storage prefix and not to a video row
+type VideoKey struct {
+	gorm.Model
+	Prefix       string `gorm:"column:prefix;type:varchar(255);not null;uniqueIndex"`
+	EncryptedKey []byte `gorm:"column:encrypted_key;type:bytea;not null"`
+}
+
+func (VideoKey) TableName() string {
+	return "_video_keys"
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "gorm.io/gorm"
+
+type VideoNoteType string
+
+const (
+	VideoNoteType_Note     VideoNoteType = "note"
+	VideoNoteType_Bookmark VideoNoteType = "bookmark"
+)
+
+// VideoNote is private to its user, CourseID is copied from the video so course exports
+// and searches don't join through videos
+type VideoNote struct {
+	gorm.Model
+	UserID    uint          `gorm:"column:user_id;type:int;not null;index;"`
+	User      *User         `gorm:"foreignKey:user_id;"`
+	VideoID   uint          `gorm:"column:video_id;type:int;not null;index;"`
+	Video     *Video        `gorm:"foreignKey:video_id;"`
+	CourseID  uint          `gorm:"column:course_id;type:int;not null;index;"`
+	Course    *Course       `gorm:"foreignKey:course_id;"`
+	Timestamp float64       `gorm:"column:timestamp;type:double precision;not null;"`
+	Type      VideoNoteType `gorm:"column:type;type:varchar(255);not null;"`
+	Content   string        `gorm:"column:content;type:text;"`
+}
+
+func (VideoNote) TableName() string {
+	return "_video_notes"
+}
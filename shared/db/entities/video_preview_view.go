@@ -0,0 +1,20 @@
+package entities
+
+import "gorm.io/gorm"
+
+// VideoPreviewView is recorded once per viewer and window when a preview lesson is played by someone not enrolled,
+// anonymous viewers have no user so only logged in viewers can be followed to an enrollment
+type VideoPreviewView struct {
+	gorm.Model
+	VideoID   uint    `gorm:"column:video_id;type:int;not null;index;"`
+	Video     *Video  `gorm:"foreignKey:video_id;"`
+	CourseID  uint    `gorm:"column:course_id;type:int;not null;index;"`
+	Course    *Course `gorm:"foreignKey:course_id;"`
+	UserID    *uint   `gorm:"column:user_id;type:int;index;"`
+	User      *User   `gorm:"foreignKey:user_id;"`
+	SessionID string  `gorm:"column:session_id;type:varchar(255);not null;"`
+}
+
+func (VideoPreviewView) TableName() string {
+	return "_video_preview_views"
+}
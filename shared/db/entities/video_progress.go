@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// WatchedRange is a span of the video in seconds the student played
+type WatchedRange struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+type VideoProgress struct {
+	gorm.Model
+	UserID         uint           `gorm:"column:user_id;type:int;not null;uniqueIndex:idx_video_progress_user_video"`
+	User           *User          `gorm:"foreignKey:user_id"`
+	VideoID        uint           `gorm:"column:video_id;type:int;not null;uniqueIndex:idx_video_progress_user_video"`
+	Video          *Video         `gorm:"foreignKey:video_id"`
+	CourseID       uint           `gorm:"column:course_id;type:int;not null;index"`
+	Course         *Course        `gorm:"foreignKey:course_id"`
+	WatchedSeconds uint           `gorm:"column:watched_seconds;type:int;not null;default:0"`
+	LastPosition   uint           `gorm:"column:last_position;type:int;not null;default:0"`
+	IsCompleted    bool           `gorm:"column:is_completed;type:boolean;default:false"`
+	CompletedAt    *time.Time     `gorm:"column:completed_at;type:timestamp;default:null"`
+	WatchedRanges  []WatchedRange `gorm:"column:watched_ranges;type:text;serializer:json"`
+}
+
+func (VideoProgress) TableName() string {
+	return "_video_progresses"
+}
@@ -22,45 +22,102 @@ type Repo interface {
 	VideoRepo() repositories.VideoRepo
 	CourseParticipantRepo() repositories.CourseParticipantRepo
 	CourseForumRepo() repositories.CourseForumRepo
+	CourseVersionRepo() repositories.CourseVersionRepo
+	VideoProgressRepo() repositories.VideoProgressRepo
+	CourseAnalyticsRepo() repositories.CourseAnalyticsRepo
+	CourseAnnouncementRepo() repositories.CourseAnnouncementRepo
+	EnrollmentAuditRepo() repositories.EnrollmentAuditRepo
+	VideoKeyRepo() repositories.VideoKeyRepo
+	VideoCaptionRepo() repositories.VideoCaptionRepo
+	UploadRepo() repositories.UploadRepo
+	VideoNoteRepo() repositories.VideoNoteRepo
+	CourseResourceRepo() repositories.CourseResourceRepo
+	CourseResourceDownloadRepo() repositories.CourseResourceDownloadRepo
+	VideoPreviewViewRepo() repositories.VideoPreviewViewRepo
+	QuizRepo() repositories.QuizRepo
+	QuizQuestionRepo() repositories.QuizQuestionRepo
+	QuizAttemptRepo() repositories.QuizAttemptRepo
+	QuizAnswerRepo() repositories.QuizAnswerRepo
+	AssignmentRepo() repositories.AssignmentRepo
+	AssignmentCriterionRepo() repositories.AssignmentCriterionRepo
+	AssignmentSubmissionRepo() repositories.AssignmentSubmissionRepo
 }
 
 type RepoProvider struct {
-	answerRepo            repositories.AnswerRepo
-	cartRepo              repositories.CartRepo
-	categoryRepo          repositories.CategoryRepo
-	commentRepo           repositories.CommentRepo
-	courseRepo            repositories.CourseRepo
-	likeRepo              repositories.LikeRepo
-	notificationRepo      repositories.NotificationRepo
-	orderRepo             repositories.OrderRepo
-	orderItemRepo         repositories.OrderItemRepo
-	paymentRepo           repositories.PaymentRepo
-	questionRepo          repositories.QuestionRepo
-	transactionRepo       repositories.TransactionRepo
-	userRepo              repositories.UserRepo
-	videoRepo             repositories.VideoRepo
-	courseParticipantRepo repositories.CourseParticipantRepo
-	courseForumRepo       repositories.CourseForumRepo
+	answerRepo                 repositories.AnswerRepo
+	cartRepo                   repositories.CartRepo
+	categoryRepo               repositories.CategoryRepo
+	commentRepo                repositories.CommentRepo
+	courseRepo                 repositories.CourseRepo
+	likeRepo                   repositories.LikeRepo
+	notificationRepo           repositories.NotificationRepo
+	orderRepo                  repositories.OrderRepo
+	orderItemRepo              repositories.OrderItemRepo
+	paymentRepo                repositories.PaymentRepo
+	questionRepo               repositories.QuestionRepo
+	transactionRepo            repositories.TransactionRepo
+	userRepo                   repositories.UserRepo
+	videoRepo                  repositories.VideoRepo
+	courseParticipantRepo      repositories.CourseParticipantRepo
+	courseForumRepo            repositories.CourseForumRepo
+	courseVersionRepo          repositories.CourseVersionRepo
+	videoProgressRepo          repositories.VideoProgressRepo
+	courseAnalyticsRepo        repositories.CourseAnalyticsRepo
+	courseAnnouncementRepo     repositories.CourseAnnouncementRepo
+	enrollmentAuditRepo        repositories.EnrollmentAuditRepo
+	videoKeyRepo               repositories.VideoKeyRepo
+	videoCaptionRepo           repositories.VideoCaptionRepo
+	uploadRepo                 repositories.UploadRepo
+	videoNoteRepo              repositories.VideoNoteRepo
+	courseResourceRepo         repositories.CourseResourceRepo
+	courseResourceDownloadRepo repositories.CourseResourceDownloadRepo
+	videoPreviewViewRepo       repositories.VideoPreviewViewRepo
+	quizRepo                   repositories.QuizRepo
+	quizQuestionRepo           repositories.QuizQuestionRepo
+	quizAttemptRepo            repositories.QuizAttemptRepo
+	quizAnswerRepo             repositories.QuizAnswerRepo
+	assignmentRepo             repositories.AssignmentRepo
+	assignmentCriterionRepo    repositories.AssignmentCriterionRepo
+	assignmentSubmissionRepo   repositories.AssignmentSubmissionRepo
 }
 
 func NewRepoProvider(tx *gorm.DB) *RepoProvider {
 	return &RepoProvider{
-		answerRepo:            repositories.NewAnswerRepo(tx),
-		cartRepo:              repositories.NewCartRepo(tx),
-		categoryRepo:          repositories.NewCategoryRepo(tx),
-		commentRepo:           repositories.NewCommentRepo(tx),
-		courseRepo:            repositories.NewCourseRepo(tx),
-		likeRepo:              repositories.NewLikeRepo(tx),
-		notificationRepo:      repositories.NewNotificationRepo(tx),
-		orderRepo:             repositories.NewOrderRepo(tx),
-		orderItemRepo:         repositories.NewOrderItemRepo(tx),
-		paymentRepo:           repositories.NewPaymentRepo(tx),
-		questionRepo:          repositories.NewQuestionRepo(tx),
-		transactionRepo:       repositories.NewTransactionRepo(tx),
-		userRepo:              repositories.NewUserRepo(tx),
-		videoRepo:             repositories.NewVideoRepo(tx),
-		courseParticipantRepo: repositories.NewCourseParticipantRepo(tx),
-		courseForumRepo:       repositories.NewCourseForumRepo(tx),
+		answerRepo:                 repositories.NewAnswerRepo(tx),
+		cartRepo:                   repositories.NewCartRepo(tx),
+		categoryRepo:               repositories.NewCategoryRepo(tx),
+		commentRepo:                repositories.NewCommentRepo(tx),
+		courseRepo:                 repositories.NewCourseRepo(tx),
+		likeRepo:                   repositories.NewLikeRepo(tx),
+		notificationRepo:           repositories.NewNotificationRepo(tx),
+		orderRepo:                  repositories.NewOrderRepo(tx),
+		orderItemRepo:              repositories.NewOrderItemRepo(tx),
+		paymentRepo:                repositories.NewPaymentRepo(tx),
+		questionRepo:               repositories.NewQuestionRepo(tx),
+		transactionRepo:            repositories.NewTransactionRepo(tx),
+		userRepo:                   repositories.NewUserRepo(tx),
+		videoRepo:                  repositories.NewVideoRepo(tx),
+		courseParticipantRepo:      repositories.NewCourseParticipantRepo(tx),
+		courseForumRepo:            repositories.NewCourseForumRepo(tx),
+		courseVersionRepo:          repositories.NewCourseVersionRepo(tx),
+		videoProgressRepo:          repositories.NewVideoProgressRepo(tx),
+		courseAnalyticsRepo:        repositories.NewCourseAnalyticsRepo(tx),
+		courseAnnouncementRepo:     repositories.NewCourseAnnouncementRepo(tx),
+		enrollmentAuditRepo:        repositories.NewEnrollmentAuditRepo(tx),
+		videoKeyRepo:               repositories.NewVideoKeyRepo(tx),
+		videoCaptionRepo:           repositories.NewVideoCaptionRepo(tx),
+		uploadRepo:                 repositories.NewUploadRepo(tx),
+		videoNoteRepo:              repositories.NewVideoNoteRepo(tx),
+		courseResourceRepo:         repositories.NewCourseResourceRepo(tx),
+		courseResourceDownloadRepo: repositories.NewCourseResourceDownloadRepo(tx),
+		videoPreviewViewRepo:       repositories.NewVideoPreviewViewRepo(tx),
+		quizRepo:                   repositories.NewQuizRepo(tx),
+		quizQuestionRepo:           repositories.NewQuizQuestionRepo(tx),
+		quizAttemptRepo:            repositories.NewQuizAttemptRepo(tx),
+		quizAnswerRepo:             repositories.NewQuizAnswerRepo(tx),
+		assignmentRepo:             repositories.NewAssignmentRepo(tx),
+		assignmentCriterionRepo:    repositories.NewAssignmentCriterionRepo(tx),
+		assignmentSubmissionRepo:   repositories.NewAssignmentSubmissionRepo(tx),
 	}
 }
 
@@ -112,3 +169,78 @@ func (svc RepoProvider) CourseParticipantRepo() repositories.CourseParticipantRe
 func (svc RepoProvider) CourseForumRepo() repositories.CourseForumRepo {
 	return svc.courseForumRepo
 }
+func (svc RepoProvider) CourseVersionRepo() repositories.CourseVersionRepo {
+	return svc.courseVersionRepo
+}
+
+func (svc RepoProvider) VideoProgressRepo() repositories.VideoProgressRepo {
+	return svc.videoProgressRepo
+}
+
+func (svc RepoProvider) CourseAnalyticsRepo() repositories.CourseAnalyticsRepo {
+	return svc.courseAnalyticsRepo
+}
+
+func (svc RepoProvider) CourseAnnouncementRepo() repositories.CourseAnnouncementRepo {
+	return svc.courseAnnouncementRepo
+}
+
+func (svc RepoProvider) EnrollmentAuditRepo() repositories.EnrollmentAuditRepo {
+	return svc.enrollmentAuditRepo
+}
+
+func (svc RepoProvider) VideoKeyRepo() repositories.VideoKeyRepo {
+	return svc.videoKeyRepo
+}
+
+func (svc RepoProvider) VideoCaptionRepo() repositories.VideoCaptionRepo {
+	return svc.videoCaptionRepo
+}
+
+func (svc RepoProvider) UploadRepo() repositories.UploadRepo {
+	return svc.uploadRepo
+}
+
+func (svc RepoProvider) VideoNoteRepo() repositories.VideoNoteRepo {
+	return svc.videoNoteRepo
+}
+
+func (svc RepoProvider) CourseResourceRepo() repositories.CourseResourceRepo {
+	return svc.courseResourceRepo
+}
+
+func (svc RepoProvider) CourseResourceDownloadRepo() repositories.CourseResourceDownloadRepo {
+	return svc.courseResourceDownloadRepo
+}
+
+func (svc RepoProvider) VideoPreviewViewRepo() repositories.VideoPreviewViewRepo {
+	return svc.videoPreviewViewRepo
+}
+
+func (svc RepoProvider) QuizRepo() repositories.QuizRepo {
+	return svc.quizRepo
+}
+
+func (svc RepoProvider) QuizQuestionRepo() repositories.QuizQuestionRepo {
+	return svc.quizQuestionRepo
+}
+
+func (svc RepoProvider) QuizAttemptRepo() repositories.QuizAttemptRepo {
+	return svc.quizAttemptRepo
+}
+
+func (svc RepoProvider) QuizAnswerRepo() repositories.QuizAnswerRepo {
+	return svc.quizAnswerRepo
+}
+
+func (svc RepoProvider) AssignmentRepo() repositories.AssignmentRepo {
+	return svc.assignmentRepo
+}
+
+func (svc RepoProvider) AssignmentCriterionRepo() repositories.AssignmentCriterionRepo {
+	return svc.assignmentCriterionRepo
+}
+
+func (svc RepoProvider) AssignmentSubmissionRepo() repositories.AssignmentSubmissionRepo {
+	return svc.assignmentSubmissionRepo
+}
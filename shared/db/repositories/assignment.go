@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type AssignmentRepo interface {
+	Repository[entities.Assignment]
+	GetAllByCourseID(courseID uint, onlyPublished bool) ([]*entities.Assignment, error)
+	GetWithCriteria(id uint) (*entities.Assignment, error)
+}
+
+type AssignmentRepoImpl struct {
+	RepositoryImpl[entities.Assignment]
+}
+
+func NewAssignmentRepo(db *gorm.DB) *AssignmentRepoImpl {
+	return &AssignmentRepoImpl{
+		RepositoryImpl[entities.Assignment]{
+			db: db,
+		},
+	}
+}
+
+// GetAllByCourseID puts the closest due date first, assignments without one come last
+func (repo AssignmentRepoImpl) GetAllByCourseID(courseID uint, onlyPublished bool) ([]*entities.Assignment, error) {
+	var assignments []*entities.Assignment
+	query := repo.db.
+		Preload("Criteria", orderCriteria).
+		Where("course_id = ?", courseID)
+	if onlyPublished {
+		query = query.Where("is_published = ?", true)
+	}
+	tx := query.
+		Order("due_at asc nulls last, id asc").
+		Find(&assignments)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return assignments, nil
+}
+
+func (repo AssignmentRepoImpl) GetWithCriteria(id uint) (*entities.Assignment, error) {
+	var assignment *entities.Assignment
+	tx := repo.db.
+		Preload("Course").
+		Preload("Criteria", orderCriteria).
+		Where("id = ?", id).
+		Limit(1).
+		Find(&assignment)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return nil, nil
+	}
+	return assignment, nil
+}
+
+func orderCriteria(db *gorm.DB) *gorm.DB {
+	return db.Order("position asc, id asc")
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type AssignmentCriterionRepo interface {
+	Repository[entities.AssignmentCriterion]
+	NextPosition(assignmentID uint) (uint, error)
+}
+
+type AssignmentCriterionRepoImpl struct {
+	RepositoryImpl[entities.AssignmentCriterion]
+}
+
+func NewAssignmentCriterionRepo(db *gorm.DB) *AssignmentCriterionRepoImpl {
+	return &AssignmentCriterionRepoImpl{
+		RepositoryImpl[entities.AssignmentCriterion]{
+			db: db,
+		},
+	}
+}
+
+// NextPosition appends new criteria after the existing ones
+func (repo AssignmentCriterionRepoImpl) NextPosition(assignmentID uint) (uint, error) {
+	var position uint
+	tx := repo.db.
+		Model(&entities.AssignmentCriterion{}).
+		Select("COALESCE(MAX(position), 0) + 1").
+		Where("assignment_id = ?", assignmentID).
+		Scan(&position)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return position, nil
+}
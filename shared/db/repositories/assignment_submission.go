@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+// GradingQueueOptions narrows the submissions of the courses of a teacher, nil filters match everything
+type GradingQueueOptions struct {
+	TeacherID    uint
+	CourseID     *uint
+	AssignmentID *uint
+	Status       *entities.AssignmentSubmissionStatus
+	Page         int
+	PageSize     int
+}
+
+type AssignmentSubmissionRepo interface {
+	Repository[entities.AssignmentSubmission]
+	GetLatest(assignmentID, userID uint) (*entities.AssignmentSubmission, error)
+	GetLatestByUserID(userID uint, assignmentIDs []uint) (map[uint]*entities.AssignmentSubmission, error)
+	GetAllByUserID(assignmentID, userID uint) ([]*entities.AssignmentSubmission, error)
+	GetGradingQueue(options GradingQueueOptions) ([]*entities.AssignmentSubmission, int, error)
+}
+
+type AssignmentSubmissionRepoImpl struct {
+	RepositoryImpl[entities.AssignmentSubmission]
+}
+
+func NewAssignmentSubmissionRepo(db *gorm.DB) *AssignmentSubmissionRepoImpl {
+	return &AssignmentSubmissionRepoImpl{
+		RepositoryImpl[entities.AssignmentSubmission]{
+			db: db,
+		},
+	}
+}
+
+func (repo AssignmentSubmissionRepoImpl) GetLatest(assignmentID, userID uint) (*entities.AssignmentSubmission, error) {
+	var submission *entities.AssignmentSubmission
+	tx := repo.db.
+		Where("assignment_id = ? AND user_id = ?", assignmentID, userID).
+		Order("attempt desc").
+		Limit(1).
+		Find(&submission)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return nil, nil
+	}
+	return submission, nil
+}
+
+func (repo AssignmentSubmissionRepoImpl) GetLatestByUserID(userID uint, assignmentIDs []uint) (map[uint]*entities.AssignmentSubmission, error) {
+	result := make(map[uint]*entities.AssignmentSubmission)
+	if len(assignmentIDs) == 0 {
+		return result, nil
+	}
+	var submissions []*entities.AssignmentSubmission
+	tx := repo.db.
+		Where("user_id = ? AND assignment_id IN ?", userID, assignmentIDs).
+		Order("attempt asc").
+		Find(&submissions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	// later attempts overwrite the earlier ones
+	for _, submission := range submissions {
+		result[submission.AssignmentID] = submission
+	}
+	return result, nil
+}
+
+func (repo AssignmentSubmissionRepoImpl) GetAllByUserID(assignmentID, userID uint) ([]*entities.AssignmentSubmission, error) {
+	var submissions []*entities.AssignmentSubmission
+	tx := repo.db.
+		Where("assignment_id = ? AND user_id = ?", assignmentID, userID).
+		Order("attempt desc").
+		Find(&submissions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return submissions, nil
+}
+
+// GetGradingQueue serves the oldest submissions first, submissions of deleted assignments are left out
+func (repo AssignmentSubmissionRepoImpl) GetGradingQueue(options GradingQueueOptions) ([]*entities.AssignmentSubmission, int, error) {
+	var submissions []*entities.AssignmentSubmission
+	var count int64
+	query := repo.db.
+		Model(&entities.AssignmentSubmission{}).
+		Joins("INNER JOIN _courses c ON c.id = _assignment_submissions.course_id").
+		Joins("INNER JOIN _assignments a ON a.id = _assignment_submissions.assignment_id AND a.deleted_at IS NULL").
+		Where("c.teacher_id = ?", options.TeacherID)
+	if options.CourseID != nil {
+		query = query.Where("_assignment_submissions.course_id = ?", *options.CourseID)
+	}
+	if options.AssignmentID != nil {
+		query = query.Where("_assignment_submissions.assignment_id = ?", *options.AssignmentID)
+	}
+	if options.Status != nil {
+		query = query.Where("_assignment_submissions.status = ?", *options.Status)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	tx := query.
+		Preload("User").
+		Preload("Assignment").
+		Order("_assignment_submissions.submitted_at asc").
+		Offset(options.Page * options.PageSize).
+		Limit(options.PageSize).
+		Find(&submissions)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	return submissions, int(count), nil
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AnalyticsBucket string
+
+const (
+	AnalyticsBucket_Day   AnalyticsBucket = "day"
+	AnalyticsBucket_Week  AnalyticsBucket = "week"
+	AnalyticsBucket_Month AnalyticsBucket = "month"
+)
+
+type AnalyticsFilter struct {
+	CourseID uint
+	Bucket   AnalyticsBucket
+	From     time.Time
+	To       time.Time
+}
+
+type TimeSeriesPoint struct {
+	Bucket time.Time
+	Value  float64
+}
+
+type VideoEngagement struct {
+	VideoID             uint
+	Title               string
+	Viewers             int
+	Completions         int
+	AverageWatchSeconds float64
+}
+
+// PreviewConversion follows logged in preview viewers of the period, a viewer converted when
+// they enrolled after their first preview view
+type PreviewConversion struct {
+	AnonymousViews int
+	Viewers        int
+	Conversions    int
+}
+
+type CourseAnalyticsRepo interface {
+	EnrollmentSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error)
+	RevenueSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error)
+	LikeSeries(filter AnalyticsFilter, likeType entities.LikeType) ([]*TimeSeriesPoint, error)
+	CommentSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error)
+	QuestionSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error)
+	VideoEngagements(filter AnalyticsFilter) ([]*VideoEngagement, error)
+	PreviewViewSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error)
+	PreviewConversion(filter AnalyticsFilter) (*PreviewConversion, error)
+	CountParticipants(courseID uint) (int, error)
+}
+
+type courseAnalyticsRepo struct {
+	db *gorm.DB
+}
+
+func NewCourseAnalyticsRepo(db *gorm.DB) CourseAnalyticsRepo {
+	return &courseAnalyticsRepo{
+		db: db,
+	}
+}
+
+func (repo courseAnalyticsRepo) EnrollmentSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error) {
+	return repo.timeSeries(`
+		SELECT date_trunc(@bucket, created_at) AS bucket, COUNT(*) AS value
+		FROM _course_participants
+		WHERE course_id = @courseID AND created_at >= @from AND created_at < @to
+		GROUP BY 1
+	`, filter, nil)
+}
+
+func (repo courseAnalyticsRepo) RevenueSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error) {
+	return repo.timeSeries(`
+		SELECT date_trunc(@bucket, o.status_changed_at) AS bucket, SUM(oi.amount) AS value
+		FROM _order_items oi
+		INNER JOIN _orders o ON o.id = oi.order_id
+		WHERE oi.course_id = @courseID
+			AND oi.deleted_at IS NULL
+			AND o.deleted_at IS NULL
+			AND o.status = @status
+			AND o.status_changed_at >= @from AND o.status_changed_at < @to
+		GROUP BY 1
+	`, filter, map[string]any{"status": entities.OrderStatus_Success})
+}
+
+func (repo courseAnalyticsRepo) LikeSeries(filter AnalyticsFilter, likeType entities.LikeType) ([]*TimeSeriesPoint, error) {
+	return repo.timeSeries(`
+		SELECT date_trunc(@bucket, created_at) AS bucket, COUNT(*) AS value
+		FROM _likes
+		WHERE course_id = @courseID AND type = @type AND created_at >= @from AND created_at < @to
+		GROUP BY 1
+	`, filter, map[string]any{"type": likeType})
+}
+
+func (repo courseAnalyticsRepo) CommentSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error) {
+	return repo.timeSeries(`
+		SELECT date_trunc(@bucket, created_at) AS bucket, COUNT(*) AS value
+		FROM _comments
+		WHERE course_id = @courseID AND deleted_at IS NULL AND created_at >= @from AND created_at < @to
+		GROUP BY 1
+	`, filter, nil)
+}
+
+func (repo courseAnalyticsRepo) QuestionSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error) {
+	return repo.timeSeries(`
+		SELECT date_trunc(@bucket, created_at) AS bucket, COUNT(*) AS value
+		FROM _questions
+		WHERE course_id = @courseID AND deleted_at IS NULL AND created_at >= @from AND created_at < @to
+		GROUP BY 1
+	`, filter, nil)
+}
+
+func (repo courseAnalyticsRepo) VideoEngagements(filter AnalyticsFilter) ([]*VideoEngagement, error) {
+	var engagements []*VideoEngagement
+	tx := repo.db.Raw(`
+		SELECT
+			v.id AS video_id,
+			v.title AS title,
+			COUNT(vp.id) AS viewers,
+			COUNT(vp.id) FILTER (WHERE vp.is_completed) AS completions,
+			COALESCE(AVG(vp.watched_seconds), 0) AS average_watch_seconds
+		FROM _videos v
+		LEFT JOIN _video_progresses vp
+			ON vp.video_id = v.id
+			AND vp.deleted_at IS NULL
+			AND vp.updated_at >= @from AND vp.updated_at < @to
+		WHERE v.course_id = @courseID AND v.deleted_at IS NULL
+		GROUP BY v.id, v.title
+		ORDER BY v.id
+	`, map[string]any{
+		"courseID": filter.CourseID,
+		"from":     filter.From,
+		"to":       filter.To,
+	}).Scan(&engagements)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return engagements, nil
+}
+
+func (repo courseAnalyticsRepo) PreviewViewSeries(filter AnalyticsFilter) ([]*TimeSeriesPoint, error) {
+	return repo.timeSeries(`
+		SELECT date_trunc(@bucket, created_at) AS bucket, COUNT(*) AS value
+		FROM _video_preview_views
+		WHERE course_id = @courseID AND deleted_at IS NULL AND created_at >= @from AND created_at < @to
+		GROUP BY 1
+	`, filter, nil)
+}
+
+func (repo courseAnalyticsRepo) PreviewConversion(filter AnalyticsFilter) (*PreviewConversion, error) {
+	conversion := &PreviewConversion{}
+	tx := repo.db.Raw(`
+		WITH views AS (
+			SELECT user_id, created_at
+			FROM _video_preview_views
+			WHERE course_id = @courseID AND deleted_at IS NULL AND created_at >= @from AND created_at < @to
+		), viewers AS (
+			SELECT user_id, MIN(created_at) AS first_viewed_at
+			FROM views
+			WHERE user_id IS NOT NULL
+			GROUP BY user_id
+		)
+		SELECT
+			(SELECT COUNT(*) FROM views WHERE user_id IS NULL) AS anonymous_views,
+			(SELECT COUNT(*) FROM viewers) AS viewers,
+			(
+				SELECT COUNT(*)
+				FROM viewers
+				WHERE EXISTS (
+					SELECT 1 FROM _course_participants cp
+					WHERE cp.course_id = @courseID
+						AND cp.student_id = viewers.user_id
+						AND cp.created_at >= viewers.first_viewed_at
+				)
+			) AS conversions
+	`, map[string]any{
+		"courseID": filter.CourseID,
+		"from":     filter.From,
+		"to":       filter.To,
+	}).Scan(conversion)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return conversion, nil
+}
+
+func (repo courseAnalyticsRepo) CountParticipants(courseID uint) (int, error) {
+	var count int64
+	tx := repo.db.
+		Model(&entities.CourseParticipant{}).
+		Where("course_id = ?", courseID).
+		Count(&count)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return int(count), nil
+}
+
+// timeSeries fills the aggregated rows of the given query into a continuous
+// series of buckets, so empty days/weeks/months are returned with zero value
+func (repo courseAnalyticsRepo) timeSeries(query string, filter AnalyticsFilter, args map[string]any) ([]*TimeSeriesPoint, error) {
+	var points []*TimeSeriesPoint
+	namedArgs := map[string]any{
+		"courseID": filter.CourseID,
+		"bucket":   string(filter.Bucket),
+		"interval": fmt.Sprintf("1 %s", filter.Bucket),
+		"from":     filter.From,
+		"to":       filter.To,
+	}
+	for key, value := range args {
+		namedArgs[key] = value
+	}
+	tx := repo.db.Raw(`
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc(@bucket, CAST(@from AS timestamp)),
+				CAST(@to AS timestamp) - interval '1 microsecond',
+				CAST(@interval AS interval)
+			) AS bucket
+		), series AS (`+query+`)
+		SELECT buckets.bucket AS bucket, COALESCE(series.value, 0) AS value
+		FROM buckets
+		LEFT JOIN series ON series.bucket = buckets.bucket
+		ORDER BY buckets.bucket
+	`, namedArgs).Scan(&points)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return points, nil
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type CourseAnnouncementRepo interface {
+	Repository[entities.CourseAnnouncement]
+}
+
+type CourseAnnouncementRepoImpl struct {
+	RepositoryImpl[entities.CourseAnnouncement]
+}
+
+func NewCourseAnnouncementRepo(db *gorm.DB) *CourseAnnouncementRepoImpl {
+	return &CourseAnnouncementRepoImpl{
+		RepositoryImpl[entities.CourseAnnouncement]{
+			db: db,
+		},
+	}
+}
@@ -1,12 +1,25 @@
 package repositories
 
 import (
+	"errors"
 	"github.com/ladmakhi81/learnup/shared/db/entities"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type CourseParticipantRepo interface {
 	Create(courseParticipant *entities.CourseParticipant) error
+	CreateOrRenew(courseParticipant *entities.CourseParticipant) (bool, error)
+	GetOne(courseID, studentID uint) (*entities.CourseParticipant, error)
+	GetAllByCourseID(courseID uint) ([]*entities.CourseParticipant, error)
+	UpdateVersion(courseID, studentID, version uint) error
+	UpdateExpiresAt(courseID, studentID uint, expiresAt *time.Time) error
+	Delete(courseID, studentID uint) error
+	GetPaginatedByCourseID(courseID uint, page, pageSize int) ([]*entities.CourseParticipant, int, error)
+	GetStudentIDsByCourseID(courseID uint, studentIDs []uint) ([]uint, error)
+	GetAllActiveByStudentID(studentID uint) ([]*entities.CourseParticipant, error)
+	UpdateLastVideoWatchDate(courseID, studentID uint, watchedAt time.Time) error
 }
 
 type courseParticipantRepo struct {
@@ -22,3 +35,128 @@ func NewCourseParticipantRepo(db *gorm.DB) CourseParticipantRepo {
 func (repo courseParticipantRepo) Create(courseParticipant *entities.CourseParticipant) error {
 	return repo.db.Create(courseParticipant).Error
 }
+
+// CreateOrRenew relies on the unique index of course and student, so concurrent enrollments
+// of the same student do not fail. A participant with a time limited access, expired or not,
+// takes the expiry, source and version of the new enrollment, a lasting one is left untouched
+func (repo courseParticipantRepo) CreateOrRenew(courseParticipant *entities.CourseParticipant) (bool, error) {
+	tx := repo.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "course_id"}, {Name: "student_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"expires_at", "source", "course_version"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "_course_participants.expires_at IS NOT NULL"},
+			}},
+		}).
+		Create(courseParticipant)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
+
+func (repo courseParticipantRepo) GetOne(courseID, studentID uint) (*entities.CourseParticipant, error) {
+	courseParticipant := &entities.CourseParticipant{}
+	tx := repo.db.
+		Where("course_id = ? AND student_id = ?", courseID, studentID).
+		First(courseParticipant)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return courseParticipant, nil
+}
+
+func (repo courseParticipantRepo) GetAllByCourseID(courseID uint) ([]*entities.CourseParticipant, error) {
+	var courseParticipants []*entities.CourseParticipant
+	tx := repo.db.
+		Where("course_id = ? AND (expires_at IS NULL OR expires_at > ?)", courseID, time.Now()).
+		Order("created_at desc").
+		Find(&courseParticipants)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return courseParticipants, nil
+}
+
+func (repo courseParticipantRepo) UpdateVersion(courseID, studentID, version uint) error {
+	return repo.db.
+		Model(&entities.CourseParticipant{}).
+		Where("course_id = ? AND student_id = ?", courseID, studentID).
+		Update("course_version", version).
+		Error
+}
+
+func (repo courseParticipantRepo) UpdateExpiresAt(courseID, studentID uint, expiresAt *time.Time) error {
+	return repo.db.
+		Model(&entities.CourseParticipant{}).
+		Where("course_id = ? AND student_id = ?", courseID, studentID).
+		Update("expires_at", expiresAt).
+		Error
+}
+
+func (repo courseParticipantRepo) Delete(courseID, studentID uint) error {
+	return repo.db.
+		Where("course_id = ? AND student_id = ?", courseID, studentID).
+		Delete(&entities.CourseParticipant{}).
+		Error
+}
+
+func (repo courseParticipantRepo) GetPaginatedByCourseID(courseID uint, page, pageSize int) ([]*entities.CourseParticipant, int, error) {
+	var courseParticipants []*entities.CourseParticipant
+	var count int64
+	query := repo.db.
+		Model(&entities.CourseParticipant{}).
+		Where("course_id = ?", courseID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	tx := query.
+		Preload("Student").
+		Order("created_at desc").
+		Offset(page * pageSize).
+		Limit(pageSize).
+		Find(&courseParticipants)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	return courseParticipants, int(count), nil
+}
+
+// GetStudentIDsByCourseID returns which of the given students are already participants of the course
+func (repo courseParticipantRepo) GetStudentIDsByCourseID(courseID uint, studentIDs []uint) ([]uint, error) {
+	var participantIDs []uint
+	tx := repo.db.
+		Model(&entities.CourseParticipant{}).
+		Where("course_id = ? AND student_id IN ?", courseID, studentIDs).
+		Pluck("student_id", &participantIDs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return participantIDs, nil
+}
+
+func (repo courseParticipantRepo) GetAllActiveByStudentID(studentID uint) ([]*entities.CourseParticipant, error) {
+	var courseParticipants []*entities.CourseParticipant
+	tx := repo.db.
+		Where("student_id = ? AND (expires_at IS NULL OR expires_at > ?)", studentID, time.Now()).
+		Preload("Course").
+		Order("last_video_watch_date desc nulls last, created_at desc").
+		Find(&courseParticipants)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return courseParticipants, nil
+}
+
+// UpdateLastVideoWatchDate never moves the date back, buffered progress can be flushed out of order
+func (repo courseParticipantRepo) UpdateLastVideoWatchDate(courseID, studentID uint, watchedAt time.Time) error {
+	return repo.db.
+		Model(&entities.CourseParticipant{}).
+		Where("course_id = ? AND student_id = ?", courseID, studentID).
+		Where("last_video_watch_date IS NULL OR last_video_watch_date < ?", watchedAt).
+		Update("last_video_watch_date", watchedAt).
+		Error
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type CourseResourceRepo interface {
+	Repository[entities.CourseResource]
+	GetAllByCourseID(courseID uint) ([]*entities.CourseResource, error)
+	IncrementDownloadCount(resourceID uint) error
+}
+
+type CourseResourceRepoImpl struct {
+	RepositoryImpl[entities.CourseResource]
+}
+
+func NewCourseResourceRepo(db *gorm.DB) *CourseResourceRepoImpl {
+	return &CourseResourceRepoImpl{
+		RepositoryImpl[entities.CourseResource]{
+			db: db,
+		},
+	}
+}
+
+// GetAllByCourseID puts the resources of the whole course first, lesson resources follow video by video
+func (repo CourseResourceRepoImpl) GetAllByCourseID(courseID uint) ([]*entities.CourseResource, error) {
+	var resources []*entities.CourseResource
+	tx := repo.db.
+		Preload("Video").
+		Where("course_id = ?", courseID).
+		Order("video_id asc nulls first, created_at asc").
+		Find(&resources)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return resources, nil
+}
+
+func (repo CourseResourceRepoImpl) IncrementDownloadCount(resourceID uint) error {
+	return repo.db.
+		Model(&entities.CourseResource{}).
+		Where("id = ?", resourceID).
+		UpdateColumn("download_count", gorm.Expr("download_count + 1")).
+		Error
+}
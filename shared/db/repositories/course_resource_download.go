@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type CourseResourceDownloadRepo interface {
+	Repository[entities.CourseResourceDownload]
+	Increment(resourceID, userID uint, downloadedAt time.Time) error
+	GetCountsByUserID(userID uint, resourceIDs []uint) (map[uint]int, error)
+	GetPaginatedByResourceID(resourceID uint, page, pageSize int) ([]*entities.CourseResourceDownload, int, error)
+}
+
+type CourseResourceDownloadRepoImpl struct {
+	RepositoryImpl[entities.CourseResourceDownload]
+}
+
+func NewCourseResourceDownloadRepo(db *gorm.DB) *CourseResourceDownloadRepoImpl {
+	return &CourseResourceDownloadRepoImpl{
+		RepositoryImpl[entities.CourseResourceDownload]{
+			db: db,
+		},
+	}
+}
+
+// Increment keeps one row per user and resource, concurrent downloads of the same user add up on the unique index
+func (repo CourseResourceDownloadRepoImpl) Increment(resourceID, userID uint, downloadedAt time.Time) error {
+	download := &entities.CourseResourceDownload{
+		ResourceID:       resourceID,
+		UserID:           userID,
+		Count:            1,
+		LastDownloadedAt: downloadedAt,
+	}
+	return repo.db.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "resource_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"count":              gorm.Expr("_course_resource_downloads.count + 1"),
+				"last_downloaded_at": downloadedAt,
+				"updated_at":         downloadedAt,
+			}),
+		}).
+		Create(download).
+		Error
+}
+
+func (repo CourseResourceDownloadRepoImpl) GetCountsByUserID(userID uint, resourceIDs []uint) (map[uint]int, error) {
+	result := make(map[uint]int)
+	if len(resourceIDs) == 0 {
+		return result, nil
+	}
+	var downloads []*entities.CourseResourceDownload
+	tx := repo.db.
+		Where("user_id = ? AND resource_id IN ?", userID, resourceIDs).
+		Find(&downloads)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	for _, download := range downloads {
+		result[download.ResourceID] = download.Count
+	}
+	return result, nil
+}
+
+func (repo CourseResourceDownloadRepoImpl) GetPaginatedByResourceID(resourceID uint, page, pageSize int) ([]*entities.CourseResourceDownload, int, error) {
+	var downloads []*entities.CourseResourceDownload
+	var count int64
+	query := repo.db.
+		Model(&entities.CourseResourceDownload{}).
+		Where("resource_id = ?", resourceID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	tx := query.
+		Preload("User").
+		Order("count desc, last_downloaded_at desc").
+		Offset(page * pageSize).
+		Limit(pageSize).
+		Find(&downloads)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	return downloads, int(count), nil
+}
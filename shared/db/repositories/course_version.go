@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type CourseVersionRepo interface {
+	Repository[entities.CourseVersion]
+}
+
+type CourseVersionRepoImpl struct {
+	RepositoryImpl[entities.CourseVersion]
+}
+
+func NewCourseVersionRepo(db *gorm.DB) *CourseVersionRepoImpl {
+	return &CourseVersionRepoImpl{
+		RepositoryImpl[entities.CourseVersion]{
+			db: db,
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type EnrollmentAuditRepo interface {
+	Repository[entities.EnrollmentAudit]
+}
+
+type EnrollmentAuditRepoImpl struct {
+	RepositoryImpl[entities.EnrollmentAudit]
+}
+
+func NewEnrollmentAuditRepo(db *gorm.DB) *EnrollmentAuditRepoImpl {
+	return &EnrollmentAuditRepoImpl{
+		RepositoryImpl[entities.EnrollmentAudit]{
+			db: db,
+		},
+	}
+}
@@ -5,8 +5,15 @@ import (
 	"gorm.io/gorm"
 )
 
+type AnnouncementReadStat struct {
+	AnnouncementID uint
+	Recipients     int
+	Seen           int
+}
+
 type NotificationRepo interface {
 	Repository[entities.Notification]
+	GetAnnouncementReadStats(announcementIDs []uint) (map[uint]*AnnouncementReadStat, error)
 }
 
 type NotificationRepoImpl struct {
@@ -20,3 +27,24 @@ func NewNotificationRepo(db *gorm.DB) *NotificationRepoImpl {
 		},
 	}
 }
+
+func (repo NotificationRepoImpl) GetAnnouncementReadStats(announcementIDs []uint) (map[uint]*AnnouncementReadStat, error) {
+	stats := make(map[uint]*AnnouncementReadStat, len(announcementIDs))
+	if len(announcementIDs) == 0 {
+		return stats, nil
+	}
+	var rows []*AnnouncementReadStat
+	tx := repo.db.
+		Model(&entities.Notification{}).
+		Select("announcement_id, COUNT(*) AS recipients, COUNT(*) FILTER (WHERE is_seen) AS seen").
+		Where("announcement_id IN ?", announcementIDs).
+		Group("announcement_id").
+		Scan(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	for _, row := range rows {
+		stats[row.AnnouncementID] = row
+	}
+	return stats, nil
+}
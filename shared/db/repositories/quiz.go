@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type QuizRepo interface {
+	Repository[entities.Quiz]
+	GetAllByCourseID(courseID uint, onlyPublished bool) ([]*entities.Quiz, error)
+}
+
+type QuizRepoImpl struct {
+	RepositoryImpl[entities.Quiz]
+}
+
+func NewQuizRepo(db *gorm.DB) *QuizRepoImpl {
+	return &QuizRepoImpl{
+		RepositoryImpl[entities.Quiz]{
+			db: db,
+		},
+	}
+}
+
+// GetAllByCourseID follows the lessons of the course, course wide quizzes come last
+func (repo QuizRepoImpl) GetAllByCourseID(courseID uint, onlyPublished bool) ([]*entities.Quiz, error) {
+	var quizzes []*entities.Quiz
+	query := repo.db.
+		Preload("Video").
+		Where("course_id = ?", courseID)
+	if onlyPublished {
+		query = query.Where("is_published = ?", true)
+	}
+	tx := query.
+		Order("video_id asc nulls last, id asc").
+		Find(&quizzes)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return quizzes, nil
+}
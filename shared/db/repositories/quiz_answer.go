@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type QuizQuestionStat struct {
+	QuestionID     uint
+	Answers        int
+	CorrectAnswers int
+}
+
+type QuizOptionCount struct {
+	QuestionID uint
+	Option     int
+	Count      int
+}
+
+type QuizAnswerRepo interface {
+	Repository[entities.QuizAnswer]
+	GetQuestionStats(quizID uint) ([]*QuizQuestionStat, error)
+	GetOptionCounts(quizID uint) ([]*QuizOptionCount, error)
+}
+
+type QuizAnswerRepoImpl struct {
+	RepositoryImpl[entities.QuizAnswer]
+}
+
+func NewQuizAnswerRepo(db *gorm.DB) *QuizAnswerRepoImpl {
+	return &QuizAnswerRepoImpl{
+		RepositoryImpl[entities.QuizAnswer]{
+			db: db,
+		},
+	}
+}
+
+func (repo QuizAnswerRepoImpl) GetQuestionStats(quizID uint) ([]*QuizQuestionStat, error) {
+	var stats []*QuizQuestionStat
+	tx := repo.db.
+		Model(&entities.QuizAnswer{}).
+		Select("question_id, COUNT(*) AS answers, COUNT(*) FILTER (WHERE is_correct) AS correct_answers").
+		Where("quiz_id = ?", quizID).
+		Group("question_id").
+		Scan(&stats)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return stats, nil
+}
+
+// GetOptionCounts shows which options were picked, a wrong option picked by many points to a confusing
+// question. Short answers store no options and are skipped
+func (repo QuizAnswerRepoImpl) GetOptionCounts(quizID uint) ([]*QuizOptionCount, error) {
+	var counts []*QuizOptionCount
+	tx := repo.db.Raw(`
+		SELECT a.question_id AS question_id, CAST(selected.option AS int) AS option, COUNT(*) AS count
+		FROM _quiz_answers a
+		CROSS JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(CAST(a.selected_options AS jsonb)) = 'array'
+				THEN CAST(a.selected_options AS jsonb)
+				ELSE CAST('[]' AS jsonb)
+			END
+		) AS selected(option)
+		WHERE a.quiz_id = ? AND a.deleted_at IS NULL
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`, quizID).Scan(&counts)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return counts, nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuizAttemptSummary is how a student stands in a quiz across all of their attempts
+type QuizAttemptSummary struct {
+	QuizID         uint
+	Attempts       int
+	BestPercentage float64
+	IsPassed       bool
+}
+
+type QuizAttemptStats struct {
+	Attempts          int
+	Students          int
+	PassedStudents    int
+	AveragePercentage float64
+}
+
+type QuizAttemptRepo interface {
+	Repository[entities.QuizAttempt]
+	CountByUserID(quizID, userID uint) (int, error)
+	CreateIfNotExists(attempt *entities.QuizAttempt) (bool, error)
+	GetSummaries(userID uint, quizIDs []uint) (map[uint]*QuizAttemptSummary, error)
+	GetStats(quizID uint) (*QuizAttemptStats, error)
+}
+
+type QuizAttemptRepoImpl struct {
+	RepositoryImpl[entities.QuizAttempt]
+}
+
+func NewQuizAttemptRepo(db *gorm.DB) *QuizAttemptRepoImpl {
+	return &QuizAttemptRepoImpl{
+		RepositoryImpl[entities.QuizAttempt]{
+			db: db,
+		},
+	}
+}
+
+func (repo QuizAttemptRepoImpl) CountByUserID(quizID, userID uint) (int, error) {
+	var count int64
+	tx := repo.db.
+		Model(&entities.QuizAttempt{}).
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Count(&count)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return int(count), nil
+}
+
+// CreateIfNotExists relies on the unique index of quiz, user and number, when two attempts are
+// started at once only one of them takes the number
+func (repo QuizAttemptRepoImpl) CreateIfNotExists(attempt *entities.QuizAttempt) (bool, error) {
+	tx := repo.db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(attempt)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
+
+func (repo QuizAttemptRepoImpl) GetSummaries(userID uint, quizIDs []uint) (map[uint]*QuizAttemptSummary, error) {
+	result := make(map[uint]*QuizAttemptSummary)
+	if len(quizIDs) == 0 {
+		return result, nil
+	}
+	var summaries []*QuizAttemptSummary
+	tx := repo.db.
+		Model(&entities.QuizAttempt{}).
+		Select("quiz_id, COUNT(*) AS attempts, COALESCE(MAX(percentage), 0) AS best_percentage, BOOL_OR(is_passed) AS is_passed").
+		Where("user_id = ? AND quiz_id IN ?", userID, quizIDs).
+		Group("quiz_id").
+		Scan(&summaries)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	for _, summary := range summaries {
+		result[summary.QuizID] = summary
+	}
+	return result, nil
+}
+
+// GetStats ignores attempts still in progress, they have no score yet
+func (repo QuizAttemptRepoImpl) GetStats(quizID uint) (*QuizAttemptStats, error) {
+	stats := &QuizAttemptStats{}
+	tx := repo.db.Raw(`
+		SELECT
+			COUNT(*) AS attempts,
+			COUNT(DISTINCT user_id) AS students,
+			COUNT(DISTINCT user_id) FILTER (WHERE is_passed) AS passed_students,
+			COALESCE(AVG(percentage), 0) AS average_percentage
+		FROM _quiz_attempts
+		WHERE quiz_id = ? AND status <> ? AND deleted_at IS NULL
+	`, quizID, entities.QuizAttemptStatus_InProgress).Scan(stats)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return stats, nil
+}
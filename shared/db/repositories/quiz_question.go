@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type QuizQuestionRepo interface {
+	Repository[entities.QuizQuestion]
+	GetAllByQuizID(quizID uint) ([]*entities.QuizQuestion, error)
+	NextPosition(quizID uint) (uint, error)
+}
+
+type QuizQuestionRepoImpl struct {
+	RepositoryImpl[entities.QuizQuestion]
+}
+
+func NewQuizQuestionRepo(db *gorm.DB) *QuizQuestionRepoImpl {
+	return &QuizQuestionRepoImpl{
+		RepositoryImpl[entities.QuizQuestion]{
+			db: db,
+		},
+	}
+}
+
+func (repo QuizQuestionRepoImpl) GetAllByQuizID(quizID uint) ([]*entities.QuizQuestion, error) {
+	var questions []*entities.QuizQuestion
+	tx := repo.db.
+		Where("quiz_id = ?", quizID).
+		Order("position asc, id asc").
+		Find(&questions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return questions, nil
+}
+
+// NextPosition appends new questions after the existing ones
+func (repo QuizQuestionRepoImpl) NextPosition(quizID uint) (uint, error) {
+	var position uint
+	tx := repo.db.
+		Model(&entities.QuizQuestion{}).
+		Select("COALESCE(MAX(position), 0) + 1").
+		Where("quiz_id = ?", quizID).
+		Scan(&position)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return position, nil
+}
@@ -30,6 +30,7 @@ type Repository[T any] interface {
 	GetPaginated(options GetPaginatedOptions) ([]*T, int, error)
 	Exist(condition map[string]any) (bool, error)
 	Update(entity *T) error
+	UpdateFields(entity *T, fields map[string]any) error
 }
 
 type RepositoryImpl[T any] struct {
@@ -144,3 +145,7 @@ func (repo RepositoryImpl[T]) GetByID(id uint, relations []string) (*T, error) {
 func (repo RepositoryImpl[T]) Update(entity *T) error {
 	return repo.db.Updates(entity).Error
 }
+
+func (repo RepositoryImpl[T]) UpdateFields(entity *T, fields map[string]any) error {
+	return repo.db.Model(entity).Updates(fields).Error
+}
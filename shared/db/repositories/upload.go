@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type UploadRepo interface {
+	Repository[entities.Upload]
+}
+
+type UploadRepoImpl struct {
+	RepositoryImpl[entities.Upload]
+}
+
+func NewUploadRepo(db *gorm.DB) *UploadRepoImpl {
+	return &UploadRepoImpl{
+		RepositoryImpl[entities.Upload]{
+			db: db,
+		},
+	}
+}
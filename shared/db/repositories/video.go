@@ -7,6 +7,9 @@ import (
 
 type VideoRepo interface {
 	Repository[entities.Video]
+	FetchProcessing() ([]*entities.Video, error)
+	IsPrefixReferenced(prefix string) (bool, error)
+	GetPreviewsByCourseID(courseID uint) ([]*entities.Video, error)
 }
 
 type VideoRepoImpl struct {
@@ -20,3 +23,54 @@ func NewVideoRepo(db *gorm.DB) *VideoRepoImpl {
 		},
 	}
 }
+
+// FetchProcessing returns videos with a running processing workflow, a failed run
+// keeps its source for reprocessing but has a failure reason
+func (repo VideoRepoImpl) FetchProcessing() ([]*entities.Video, error) {
+	var videos []*entities.Video
+	tx := repo.db.
+		Preload("Course").
+		Where("source_object_id IS NOT NULL AND failure_reason IS NULL").
+		Find(&videos)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return videos, nil
+}
+
+// IsPrefixReferenced checks every place an encoded prefix is kept, cloned videos, course
+// introductions and published version snapshots share prefixes with the video they came from
+func (repo VideoRepoImpl) IsPrefixReferenced(prefix string) (bool, error) {
+	var count int64
+	if err := repo.db.Model(&entities.Video{}).Where("video_url = ?", prefix).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := repo.db.Model(&entities.Course{}).Where("introduction_video = ?", prefix).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	// snapshots are json text, the prefix only shows up quoted as a whole in url and introductionVideo
+	if err := repo.db.Model(&entities.CourseVersion{}).Where("snapshot LIKE ?", `%"`+prefix+`"%`).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetPreviewsByCourseID returns the free preview lessons students can watch before enrolling
+func (repo VideoRepoImpl) GetPreviewsByCourseID(courseID uint) ([]*entities.Video, error) {
+	var videos []*entities.Video
+	tx := repo.db.
+		Where("course_id = ? AND is_preview = ? AND is_published = ? AND is_verified = ? AND status = ?",
+			courseID, true, true, true, entities.VideoStatus_Done).
+		Order("id ASC").
+		Find(&videos)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return videos, nil
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type VideoCaptionRepo interface {
+	Repository[entities.VideoCaption]
+}
+
+type VideoCaptionRepoImpl struct {
+	RepositoryImpl[entities.VideoCaption]
+}
+
+func NewVideoCaptionRepo(db *gorm.DB) *VideoCaptionRepoImpl {
+	return &VideoCaptionRepoImpl{
+		RepositoryImpl[entities.VideoCaption]{
+			db: db,
+		},
+	}
+}
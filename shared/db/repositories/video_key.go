@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type VideoKeyRepo interface {
+	Repository[entities.VideoKey]
+}
+
+type VideoKeyRepoImpl struct {
+	RepositoryImpl[entities.VideoKey]
+}
+
+func NewVideoKeyRepo(db *gorm.DB) *VideoKeyRepoImpl {
+	return &VideoKeyRepoImpl{
+		RepositoryImpl[entities.VideoKey]{
+			db: db,
+		},
+	}
+}
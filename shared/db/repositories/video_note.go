@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// VideoNoteSearchOptions narrows the notes of a user, nil filters and an empty query match everything
+type VideoNoteSearchOptions struct {
+	UserID   uint
+	CourseID *uint
+	VideoID  *uint
+	Type     *entities.VideoNoteType
+	Query    string
+	Page     int
+	PageSize int
+}
+
+type VideoNoteRepo interface {
+	Repository[entities.VideoNote]
+	Search(options VideoNoteSearchOptions) ([]*entities.VideoNote, int, error)
+	GetAllByCourseID(userID, courseID uint) ([]*entities.VideoNote, error)
+	GetAllByVideoID(userID, videoID uint) ([]*entities.VideoNote, error)
+}
+
+type VideoNoteRepoImpl struct {
+	RepositoryImpl[entities.VideoNote]
+}
+
+func NewVideoNoteRepo(db *gorm.DB) *VideoNoteRepoImpl {
+	return &VideoNoteRepoImpl{
+		RepositoryImpl[entities.VideoNote]{
+			db: db,
+		},
+	}
+}
+
+func (repo VideoNoteRepoImpl) Search(options VideoNoteSearchOptions) ([]*entities.VideoNote, int, error) {
+	var notes []*entities.VideoNote
+	var count int64
+	query := repo.db.
+		Model(&entities.VideoNote{}).
+		Where("user_id = ?", options.UserID)
+	if options.CourseID != nil {
+		query = query.Where("course_id = ?", *options.CourseID)
+	}
+	if options.VideoID != nil {
+		query = query.Where("video_id = ?", *options.VideoID)
+	}
+	if options.Type != nil {
+		query = query.Where("type = ?", *options.Type)
+	}
+	if search := strings.TrimSpace(options.Query); search != "" {
+		query = query.Where("content ILIKE ?", "%"+escapeLike(search)+"%")
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	tx := query.
+		Preload("Video").
+		Order("created_at desc").
+		Offset(options.Page * options.PageSize).
+		Limit(options.PageSize).
+		Find(&notes)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	return notes, int(count), nil
+}
+
+// GetAllByCourseID keeps the notes of a video together, ordered by their timestamp
+func (repo VideoNoteRepoImpl) GetAllByCourseID(userID, courseID uint) ([]*entities.VideoNote, error) {
+	var notes []*entities.VideoNote
+	tx := repo.db.
+		Preload("Video").
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		Order("video_id asc, timestamp asc").
+		Find(&notes)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return notes, nil
+}
+
+func (repo VideoNoteRepoImpl) GetAllByVideoID(userID, videoID uint) ([]*entities.VideoNote, error) {
+	var notes []*entities.VideoNote
+	tx := repo.db.
+		Where("user_id = ? AND video_id = ?", userID, videoID).
+		Order("timestamp asc").
+		Find(&notes)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return notes, nil
+}
+
+// escapeLike keeps wildcards typed by the user literal
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
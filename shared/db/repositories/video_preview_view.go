@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type VideoPreviewViewRepo interface {
+	Repository[entities.VideoPreviewView]
+}
+
+type VideoPreviewViewRepoImpl struct {
+	RepositoryImpl[entities.VideoPreviewView]
+}
+
+func NewVideoPreviewViewRepo(db *gorm.DB) *VideoPreviewViewRepoImpl {
+	return &VideoPreviewViewRepoImpl{
+		RepositoryImpl[entities.VideoPreviewView]{
+			db: db,
+		},
+	}
+}
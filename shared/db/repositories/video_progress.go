@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"github.com/ladmakhi81/learnup/shared/db/entities"
+	"gorm.io/gorm"
+)
+
+type VideoProgressRepo interface {
+	Repository[entities.VideoProgress]
+}
+
+type VideoProgressRepoImpl struct {
+	RepositoryImpl[entities.VideoProgress]
+}
+
+func NewVideoProgressRepo(db *gorm.DB) *VideoProgressRepoImpl {
+	return &VideoProgressRepoImpl{
+		RepositoryImpl[entities.VideoProgress]{
+			db: db,
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// OptionalAccessToken lets anonymous requests through, the claim is only set when a valid token is sent
+func (m Middleware) OptionalAccessToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorization := ctx.GetHeader("authorization")
+		claim, err := m.tokenSvc.DecodeToken(authorization)
+		if err == nil && claim != nil {
+			ctx.Set("AUTH", claim)
+		}
+		ctx.Next()
+	}
+}
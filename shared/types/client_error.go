@@ -46,3 +46,17 @@ func NewForbiddenAccessError(message string) *ClientError {
 		Message:    message,
 	}
 }
+
+func NewTooManyRequestsError(message string) *ClientError {
+	return &ClientError{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    message,
+	}
+}
+
+func NewUnauthorizedError(message string) *ClientError {
+	return &ClientError{
+		StatusCode: http.StatusUnauthorized,
+		Message:    message,
+	}
+}
@@ -0,0 +1,29 @@
+package types
+
+// FileResponse is written as raw bytes, or as a redirect when RedirectURL is set,
+// a FileName makes browsers download the content instead of showing it
+type FileResponse struct {
+	ContentType string
+	Content     []byte
+	RedirectURL string
+	FileName    string
+}
+
+func NewFileResponse(contentType string, content []byte) *FileResponse {
+	return &FileResponse{
+		ContentType: contentType,
+		Content:     content,
+	}
+}
+
+func NewRedirectFileResponse(redirectURL string) *FileResponse {
+	return &FileResponse{RedirectURL: redirectURL}
+}
+
+func NewAttachmentFileResponse(contentType string, fileName string, content []byte) *FileResponse {
+	return &FileResponse{
+		ContentType: contentType,
+		Content:     content,
+		FileName:    fileName,
+	}
+}
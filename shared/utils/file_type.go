@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// uploadFileTypes maps the allowed extensions to what their content has to sniff as, a renamed
+// executable sniffs as octet-stream and never passes
+var uploadFileTypes = map[string][]string{
+	".pdf":  {"application/pdf"},
+	".zip":  {"application/zip"},
+	".docx": {"application/zip"},
+	".pptx": {"application/zip"},
+	".xlsx": {"application/zip"},
+	".gz":   {"application/x-gzip"},
+	".tgz":  {"application/x-gzip"},
+	".rar":  {"application/x-rar-compressed"},
+	".png":  {"image/png"},
+	".jpg":  {"image/jpeg"},
+	".jpeg": {"image/jpeg"},
+	".gif":  {"image/gif"},
+	".txt":  {"text/plain"},
+	".md":   {"text/plain"},
+	".csv":  {"text/plain"},
+	".json": {"text/plain"},
+	".sql":  {"text/plain"},
+	".go":   {"text/plain"},
+	".py":   {"text/plain"},
+	".js":   {"text/plain"},
+	".ts":   {"text/plain"},
+	".java": {"text/plain"},
+	".c":    {"text/plain"},
+	".cpp":  {"text/plain"},
+}
+
+// DetectUploadFileType checks the sniffed content against the extension, the returned type comes
+// from the extension so office files aren't served as plain zips
+func DetectUploadFileType(extension string, content []byte) (string, bool) {
+	allowedTypes, ok := uploadFileTypes[extension]
+	if !ok {
+		return "", false
+	}
+	sniffed := http.DetectContentType(content)
+	for _, allowedType := range allowedTypes {
+		if strings.HasPrefix(sniffed, allowedType) {
+			return uploadContentType(extension, sniffed), true
+		}
+	}
+	return "", false
+}
+
+func uploadContentType(extension string, sniffed string) string {
+	switch extension {
+	case ".docx":
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	case ".pptx":
+		return "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	case ".xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return sniffed
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ladmakhi81/learnup/pkg/contracts"
 	"github.com/ladmakhi81/learnup/shared/types"
 	"log"
+	"mime"
 	"net/http"
 	"time"
 )
@@ -24,6 +25,27 @@ func JsonHandler(translationSvc contracts.Translator, fn Handler) gin.HandlerFun
 	}
 }
 
+type FileHandlerFn func(*gin.Context) (*types.FileResponse, error)
+
+func FileHandler(translationSvc contracts.Translator, fn FileHandlerFn) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		resp, err := fn(ctx)
+		if err != nil {
+			errorHandler(ctx, err, translationSvc)
+			return
+		}
+		if resp.RedirectURL != "" {
+			ctx.Redirect(http.StatusFound, resp.RedirectURL)
+			return
+		}
+		ctx.Header("Cache-Control", "no-store")
+		if resp.FileName != "" {
+			ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": resp.FileName}))
+		}
+		ctx.Data(http.StatusOK, resp.ContentType, resp.Content)
+	}
+}
+
 func errorHandler(ctx *gin.Context, err error, translationSvc contracts.Translator) {
 	timestamp := time.Now().Unix()
 	traceId := uuid.New().String()
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"context"
+	"github.com/ladmakhi81/learnup/pkg/contracts"
+)
+
+// EnsureBucket creates the bucket on the first upload into it
+func EnsureBucket(ctx context.Context, storage contracts.Storage, bucket string) error {
+	exists, err := storage.BucketExist(ctx, bucket)
+	if err != nil || exists {
+		return err
+	}
+	if err := storage.CreateBucket(ctx, bucket); err != nil {
+		// another upload may have created it meanwhile
+		if exists, existErr := storage.BucketExist(ctx, bucket); existErr == nil && exists {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	cueTimingPattern  = regexp.MustCompile(`^((?:\d{2,}:)?\d{2}:\d{2}[.,]\d{3})[ \t]+-->[ \t]+((?:\d{2,}:)?\d{2}:\d{2}[.,]\d{3})(.*)$`)
+	blankLinesPattern = regexp.MustCompile(`\n{2,}`)
+)
+
+// ToWebVTT validates a srt or webvtt subtitle and returns it as webvtt, srt cue numbers
+// and positioning are dropped since webvtt has its own settings syntax
+func ToWebVTT(content []byte) ([]byte, error) {
+	if !utf8.Valid(content) {
+		return nil, fmt.Errorf("subtitle is not utf-8 encoded")
+	}
+	text := strings.TrimPrefix(string(content), "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	blocks := blankLinesPattern.Split(strings.Trim(text, "\n"), -1)
+	isWebVTT := strings.HasPrefix(text, "WEBVTT")
+	if isWebVTT {
+		header := strings.SplitN(blocks[0], "\n", 2)[0]
+		if header != "WEBVTT" && !strings.HasPrefix(header, "WEBVTT ") && !strings.HasPrefix(header, "WEBVTT\t") {
+			return nil, fmt.Errorf("invalid webvtt header %q", header)
+		}
+		blocks = blocks[1:]
+	}
+	var result strings.Builder
+	result.WriteString("WEBVTT\n")
+	cues := 0
+	for _, block := range blocks {
+		lines := strings.Split(block, "\n")
+		if isWebVTT && isWebVTTMetadataBlock(lines[0]) {
+			result.WriteString("\n" + block + "\n")
+			continue
+		}
+		timingIndex := 0
+		if !cueTimingPattern.MatchString(lines[0]) {
+			timingIndex = 1
+		}
+		if timingIndex >= len(lines) {
+			return nil, fmt.Errorf("cue %q has no timing", lines[0])
+		}
+		matches := cueTimingPattern.FindStringSubmatch(lines[timingIndex])
+		if matches == nil {
+			return nil, fmt.Errorf("invalid cue timing %q", lines[timingIndex])
+		}
+		start, err := parseCueTime(matches[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseCueTime(matches[2])
+		if err != nil {
+			return nil, err
+		}
+		if end <= start {
+			return nil, fmt.Errorf("cue %q ends before it starts", lines[timingIndex])
+		}
+		payload := lines[timingIndex+1:]
+		for _, line := range payload {
+			if strings.Contains(line, "-->") {
+				return nil, fmt.Errorf("cue text %q contains a timing arrow", line)
+			}
+		}
+		result.WriteString("\n")
+		if isWebVTT && timingIndex == 1 {
+			result.WriteString(lines[0] + "\n")
+		}
+		result.WriteString(formatCueTime(start) + " --> " + formatCueTime(end))
+		if isWebVTT {
+			result.WriteString(matches[3])
+		}
+		result.WriteString("\n")
+		for _, line := range payload {
+			result.WriteString(line + "\n")
+		}
+		cues++
+	}
+	if cues == 0 {
+		return nil, fmt.Errorf("subtitle has no cues")
+	}
+	return []byte(result.String()), nil
+}
+
+func isWebVTTMetadataBlock(line string) bool {
+	for _, keyword := range []string{"NOTE", "STYLE", "REGION"} {
+		if line == keyword || strings.HasPrefix(line, keyword+" ") || strings.HasPrefix(line, keyword+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCueTime accepts both the srt (00:00:01,000) and the webvtt (00:01.000) forms
+func parseCueTime(value string) (time.Duration, error) {
+	parts := strings.Split(strings.Replace(value, ",", ".", 1), ":")
+	var seconds float64
+	for index, part := range parts {
+		if index == len(parts)-1 {
+			fraction, err := strconv.ParseFloat(part, 64)
+			if err != nil || fraction >= 60 {
+				return 0, fmt.Errorf("invalid cue time %s", value)
+			}
+			seconds = seconds*60 + fraction
+			continue
+		}
+		unit, err := strconv.Atoi(part)
+		if err != nil || (index > 0 && unit >= 60) {
+			return 0, fmt.Errorf("invalid cue time %s", value)
+		}
+		seconds = seconds*60 + float64(unit)
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond), nil
+}
+
+func formatCueTime(value time.Duration) string {
+	milliseconds := value.Milliseconds()
+	return fmt.Sprintf(
+		"%02d:%02d:%02d.%03d",
+		milliseconds/3600000,
+		milliseconds/60000%60,
+		milliseconds/1000%60,
+		milliseconds%1000,
+	)
+}
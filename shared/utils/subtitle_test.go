@@ -0,0 +1,85 @@
+package utils
+
+import "testing"
+
+func TestToWebVTT(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "srt cue numbers are dropped and commas become dots",
+			content:  "1\r\n00:00:01,000 --> 00:00:02,500\r\nHello\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nTwo\r\nlines\r\n",
+			expected: "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nHello\n\n00:00:03.000 --> 00:00:04.000\nTwo\nlines\n",
+		},
+		{
+			name:     "byte order mark and extra blank lines",
+			content:  "\uFEFF1\n00:00:01,000 --> 00:00:02,000\nHi\n\n\n\n2\n00:00:02,000 --> 00:00:03,000\nBye\n",
+			expected: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHi\n\n00:00:02.000 --> 00:00:03.000\nBye\n",
+		},
+		{
+			name:     "webvtt keeps identifiers, settings and notes",
+			content:  "WEBVTT - intro\n\nNOTE made by hand\n\nintro\n00:01.000 --> 00:02.000 align:start\nHi\n",
+			expected: "WEBVTT\n\nNOTE made by hand\n\nintro\n00:00:01.000 --> 00:00:02.000 align:start\nHi\n",
+		},
+		{
+			name:     "hours past 99",
+			content:  "1\n100:00:00,000 --> 100:00:01,000\nLate\n",
+			expected: "WEBVTT\n\n100:00:00.000 --> 100:00:01.000\nLate\n",
+		},
+		{
+			name:    "end before start",
+			content: "1\n00:00:02,000 --> 00:00:01,000\nBackwards\n",
+			wantErr: true,
+		},
+		{
+			name:    "minutes out of range",
+			content: "1\n00:61:00,000 --> 00:62:00,000\nText\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing timing",
+			content: "1\nJust text\n",
+			wantErr: true,
+		},
+		{
+			name:    "timing arrow in text",
+			content: "1\n00:00:01,000 --> 00:00:02,000\na --> b\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid webvtt header",
+			content: "WEBVTTX\n\n00:01.000 --> 00:02.000\nHi\n",
+			wantErr: true,
+		},
+		{
+			name:    "no cues",
+			content: "WEBVTT\n\nNOTE nothing here\n",
+			wantErr: true,
+		},
+		{
+			name:    "not utf-8",
+			content: "1\n00:00:01,000 --> 00:00:02,000\n\xff\xfe\n",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := ToWebVTT([]byte(test.content))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("got\n%q\nwant\n%q", result, test.expected)
+			}
+		})
+	}
+}
@@ -1,8 +1,36 @@
 package utils
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 func Now() *time.Time {
 	now := time.Now()
 	return &now
 }
+
+// ClockToSeconds converts the HH:MM:SS durations stored on videos into seconds
+func ClockToSeconds(clock string) (float64, error) {
+	parts := strings.Split(clock, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid clock duration %s", clock)
+	}
+	var seconds float64
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid clock duration %s", clock)
+		}
+		seconds = seconds*60 + float64(value)
+	}
+	return seconds, nil
+}
+
+// SecondsToClock is the reverse of ClockToSeconds, fractions of a second are dropped
+func SecondsToClock(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}